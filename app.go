@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"ritel-app/internal/container"
 	"ritel-app/internal/models"
 	"ritel-app/internal/sync"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,6 +18,7 @@ import (
 type App struct {
 	ctx      context.Context
 	services *container.ServiceContainer
+	user     atomic.Pointer[models.User] // User yang sedang login di aplikasi desktop
 }
 
 // NewApp creates a new App application struct
@@ -46,6 +50,49 @@ func (a *App) shutdown(ctx context.Context) {
 	ClosePrinterLog()
 }
 
+// requirePermission checks that the current role of the user logged in on the desktop app
+// grants at least one of perms, the same check RequirePermission does for the HTTP API
+func (a *App) requirePermission(perms ...string) error {
+	user := a.user.Load()
+	if user == nil {
+		return fmt.Errorf("silakan login terlebih dahulu")
+	}
+	allowed, err := a.services.UserService.HasPermission(user.ID, perms...)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("akses ditolak: membutuhkan hak akses %s", strings.Join(perms, ", "))
+	}
+	return a.sentuhTerminal(user)
+}
+
+func (a *App) sentuhTerminal(user *models.User) error {
+	if kode := a.kodeTerminal(); kode != "" {
+		return a.services.TerminalService.Sentuh(kode, user.ID)
+	}
+	return nil
+}
+
+// aktor returns the signed-in desktop user, recorded as the actor in the audit log
+func (a *App) aktor() models.Aktor {
+	user := a.user.Load()
+	if user == nil {
+		return models.Aktor{}
+	}
+	return models.Aktor{ID: user.ID, Nama: user.NamaLengkap}
+}
+
+// kodeTerminal returns this device's terminal code, or empty when it is not configured
+func (a *App) kodeTerminal() string {
+	kode, err := a.services.TerminalService.KodeTerminal("")
+	if err != nil {
+		log.Printf("[APP] Terminal code unavailable: %v", err)
+		return ""
+	}
+	return kode
+}
+
 // Greet returns a greeting for the given name
 func (a *App) Greet(name string) string {
 	return fmt.Sprintf("Hello %s, It's show time!", name)
@@ -55,7 +102,11 @@ func (a *App) Greet(name string) string {
 
 // CreateProduk creates a new product
 func (a *App) CreateProduk(produk models.Produk) error {
-	return a.services.ProdukService.CreateProduk(&produk)
+	if err := a.requirePermission(models.PermProductEdit); err != nil {
+		return err
+	}
+
+	return a.services.ProdukService.CreateProduk(&produk, a.aktor())
 }
 
 // GetAllProduk retrieves all products
@@ -67,12 +118,20 @@ func (a *App) GetAllProduk() ([]*models.Produk, error) {
 
 // UpdateStok updates product stock
 func (a *App) UpdateStok(req models.UpdateStokRequest) error {
-	return a.services.ProdukService.UpdateStok(&req)
+	if err := a.requirePermission(models.PermStockAdjust); err != nil {
+		return err
+	}
+
+	return a.services.ProdukService.UpdateStok(&req, a.aktor())
 }
 
 // UpdateStokIncrement updates stock by increment/decrement
 func (a *App) UpdateStokIncrement(req models.UpdateStokRequest) error {
-	return a.services.ProdukService.UpdateStokIncrement(&req)
+	if err := a.requirePermission(models.PermStockAdjust); err != nil {
+		return err
+	}
+
+	return a.services.ProdukService.UpdateStokIncrement(&req, a.aktor())
 }
 
 // GetStokHistory retrieves stock history for a product
@@ -115,6 +174,10 @@ func (a *App) GetExpiringBatches(daysThreshold int) ([]*models.Batch, error) {
 
 // DeleteExpiredBatch marks a batch as expired and sets qty to 0
 func (a *App) DeleteExpiredBatch(batchID string) error {
+	if err := a.requirePermission(models.PermBatchManage); err != nil {
+		return err
+	}
+
 	log.Printf("Deleting expired batch: %s", batchID)
 	return a.services.BatchService.DeleteExpiredBatch(batchID)
 }
@@ -127,16 +190,37 @@ func (a *App) GetBatchSummary(produkID int) (map[string]interface{}, error) {
 
 // UpdateBatchStatuses updates status for all batches based on current date
 func (a *App) UpdateBatchStatuses() error {
+	if err := a.requirePermission(models.PermBatchManage); err != nil {
+		return err
+	}
+
 	log.Println("Updating batch statuses")
 	return a.services.BatchService.UpdateBatchStatuses()
 }
 
 func (a *App) UpdateProduk(produk models.Produk) error {
-	return a.services.ProdukService.UpdateProduk(&produk)
+	if err := a.requirePermission(models.PermProductEdit); err != nil {
+		return err
+	}
+	if a.requirePermission(models.PermPriceChange) != nil {
+		berubah, err := a.services.ProdukService.HargaBerubah(&produk)
+		if err != nil {
+			return err
+		}
+		if berubah {
+			return a.requirePermission(models.PermPriceChange)
+		}
+	}
+
+	return a.services.ProdukService.UpdateProduk(&produk, a.aktor())
 }
 
 // ScanBarcode scans a barcode and adds product to cart
 func (a *App) ScanBarcode(barcode string, jumlah int) (*models.ScanBarcodeResponse, error) {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Scanning barcode: %s, quantity: %d", barcode, jumlah)
 	return a.services.ProdukService.ScanBarcode(barcode, jumlah)
 }
@@ -145,27 +229,47 @@ func (a *App) ScanBarcode(barcode string, jumlah int) (*models.ScanBarcodeRespon
 
 // GetKeranjang retrieves all cart items
 func (a *App) GetKeranjang() ([]*models.KeranjangItem, error) {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return nil, err
+	}
+
 	return a.services.ProdukService.GetKeranjang()
 }
 
-// ProcessKeranjang processes cart and updates stock
-func (a *App) ProcessKeranjang() error {
+// ProcessKeranjang receives the cart as a goods receipt, against a purchase order when one is given
+func (a *App) ProcessKeranjang(req models.ProsesKeranjangRequest) (*models.PenerimaanBarang, error) {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return nil, err
+	}
+
 	log.Println("Processing cart items...")
-	return a.services.ProdukService.ProcessKeranjang()
+	return a.services.ProdukService.ProcessKeranjang(&req, a.aktor())
 }
 
 // ClearKeranjang clears the cart
 func (a *App) ClearKeranjang() error {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return err
+	}
+
 	return a.services.ProdukService.ClearKeranjang()
 }
 
 // RemoveFromKeranjang removes an item from cart
 func (a *App) RemoveFromKeranjang(id int) error {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return err
+	}
+
 	return a.services.ProdukService.RemoveFromKeranjang(id)
 }
 
 // UpdateKeranjangJumlah updates quantity in cart
 func (a *App) UpdateKeranjangJumlah(id int, jumlah int) error {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return err
+	}
+
 	return a.services.ProdukService.UpdateKeranjangJumlah(id, jumlah)
 }
 
@@ -173,6 +277,10 @@ func (a *App) UpdateKeranjangJumlah(id int, jumlah int) error {
 
 // CreateKategori creates a new category
 func (a *App) CreateKategori(kategori models.Kategori) error {
+	if err := a.requirePermission(models.PermCategoryManage); err != nil {
+		return err
+	}
+
 	return a.services.KategoriService.CreateKategori(&kategori)
 }
 
@@ -188,28 +296,89 @@ func (a *App) GetKategoriByID(id int) (*models.Kategori, error) {
 
 // UpdateKategori updates a category
 func (a *App) UpdateKategori(kategori models.Kategori) error {
+	if err := a.requirePermission(models.PermCategoryManage); err != nil {
+		return err
+	}
+
 	return a.services.KategoriService.UpdateKategori(&kategori)
 }
 
 // DeleteKategori deletes a category
 func (a *App) DeleteKategori(id int) error {
+	if err := a.requirePermission(models.PermCategoryManage); err != nil {
+		return err
+	}
+
 	return a.services.KategoriService.DeleteKategori(id)
 }
 
 func (a *App) DeleteProduk(id int) error {
-	return a.services.ProdukService.DeleteProduk(id)
+	if err := a.requirePermission(models.PermProductEdit); err != nil {
+		return err
+	}
+
+	return a.services.ProdukService.DeleteProduk(id, a.aktor())
 }
 
 // ==================== TRANSAKSI API ====================
 
+// PreviewTransaksi calculates the server-side totals of a cart without saving it
+func (a *App) PreviewTransaksi(req models.CreateTransaksiRequest) (*models.TransaksiPreview, error) {
+	if err := a.requirePermission(models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
+	return a.services.TransaksiService.PreviewTransaksi(&req)
+}
+
 // CreateTransaksi creates a new transaction
 func (a *App) CreateTransaksi(req models.CreateTransaksiRequest) (*models.TransaksiResponse, error) {
+	if err := a.requirePermission(models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Creating transaction with %d items", len(req.Items))
-	return a.services.TransaksiService.CreateTransaksi(&req)
+	// The desktop app runs on the register, so the drawer in its settings is this terminal's
+	req.LaciLokal = true
+	if req.IdempotencyKey == "" {
+		return a.services.TransaksiService.CreateTransaksi(&req)
+	}
+
+	// The frontend sends one UUID per checkout attempt; a retried call replays the first result
+	const scope = "wails:CreateTransaksi"
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := a.services.IdempotencyService.Begin(scope, req.IdempotencyKey, payload)
+	if err != nil {
+		return &models.TransaksiResponse{Success: false, Message: err.Error()}, nil
+	}
+	if existing != nil {
+		var replayed models.TransaksiResponse
+		if err := json.Unmarshal([]byte(existing.Response), &replayed); err != nil {
+			return nil, fmt.Errorf("failed to replay transaction response: %w", err)
+		}
+		return &replayed, nil
+	}
+
+	result, err := a.services.TransaksiService.CreateTransaksi(&req)
+	if err != nil {
+		_ = a.services.IdempotencyService.Release(scope, req.IdempotencyKey)
+		return nil, err
+	}
+	if body, marshalErr := json.Marshal(result); marshalErr == nil {
+		_ = a.services.IdempotencyService.Complete(scope, req.IdempotencyKey, 200, body)
+	}
+	return result, nil
 }
 
 // GetTransaksiByID retrieves a transaction by ID
 func (a *App) GetTransaksiByID(idStr string) (*models.TransaksiDetail, error) {
+	if err := a.requirePermission(models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
 	// Parse string ID to int64
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -229,11 +398,19 @@ func (a *App) GetTransaksiByID(idStr string) (*models.TransaksiDetail, error) {
 
 // GetTransaksiByNoTransaksi retrieves a transaction by transaction number
 func (a *App) GetTransaksiByNoTransaksi(nomorTransaksi string) (*models.TransaksiDetail, error) {
+	if err := a.requirePermission(models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
 	return a.services.TransaksiService.GetTransaksiByNoTransaksi(nomorTransaksi)
 }
 
 // GetAllTransaksi retrieves all transactions with pagination
 func (a *App) GetAllTransaksi(limit, offset int) ([]*models.Transaksi, error) {
+	if err := a.requirePermission(models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
 	log.Printf("[APP] GetAllTransaksi called with limit: %d, offset: %d", limit, offset)
 	result, err := a.services.TransaksiService.GetAllTransaksi(limit, offset)
 	if err != nil {
@@ -246,6 +423,10 @@ func (a *App) GetAllTransaksi(limit, offset int) ([]*models.Transaksi, error) {
 
 // GetTransaksiByDateRange retrieves transactions within a date range
 func (a *App) GetTransaksiByDateRange(startDateStr, endDateStr string) ([]*models.Transaksi, error) {
+	if err := a.requirePermission(models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
 	start, err := a.parseDate(startDateStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid start date: %w", err)
@@ -260,13 +441,86 @@ func (a *App) GetTransaksiByDateRange(startDateStr, endDateStr string) ([]*model
 
 // GetTodayStats gets statistics for today's transactions
 func (a *App) GetTodayStats() (map[string]interface{}, error) {
+	if err := a.requirePermission(models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
 	return a.services.TransaksiService.GetTodayStats()
 }
 
+// VoidTransaksi cancels a completed transaction with admin approval (password or PIN)
+func (a *App) VoidTransaksi(req models.VoidTransaksiRequest) (*models.TransaksiResponse, error) {
+	if err := a.requirePermission(models.PermTransactionVoid); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] VoidTransaksi called: id=%d, approver=%s", req.TransaksiID, req.ApproverUsername)
+	return a.services.TransaksiService.VoidTransaksi(&req)
+}
+
+// ==================== HELD TRANSAKSI API ====================
+
+// HoldTransaksi parks the current cart as a held draft
+func (a *App) HoldTransaksi(req models.HoldTransaksiRequest) (*models.HeldTransaksi, error) {
+	if err := a.requirePermission(models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] HoldTransaksi called: label=%s, terminal=%s, items=%d", req.Label, req.TerminalID, len(req.Draft.Items))
+	return a.services.HeldTransaksiService.HoldTransaksi(&req)
+}
+
+// GetHeldTransaksi lists held drafts for a terminal and/or staff (empty/zero = all)
+func (a *App) GetHeldTransaksi(terminalID string, staffIDStr string) ([]*models.HeldTransaksi, error) {
+	if err := a.requirePermission(models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
+	var staffID int64
+	if staffIDStr != "" {
+		parsed, err := strconv.ParseInt(staffIDStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
+		}
+		staffID = parsed
+	}
+	return a.services.HeldTransaksiService.GetHeldTransaksi(terminalID, staffID)
+}
+
+// ResumeHeldTransaksi takes a held draft back into checkout
+func (a *App) ResumeHeldTransaksi(idStr string) (*models.HeldTransaksi, error) {
+	if err := a.requirePermission(models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid held transaction ID: %s", idStr)
+	}
+	return a.services.HeldTransaksiService.ResumeHeldTransaksi(id)
+}
+
+// DiscardHeldTransaksi drops a held draft
+func (a *App) DiscardHeldTransaksi(idStr string) error {
+	if err := a.requirePermission(models.PermTransactionCreate); err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid held transaction ID: %s", idStr)
+	}
+	return a.services.HeldTransaksiService.DiscardHeldTransaksi(id)
+}
+
 // ==================== PELANGGAN API ====================
 
 // CreatePelanggan creates a new customer
 func (a *App) CreatePelanggan(req models.CreatePelangganRequest) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage); err != nil {
+		return nil, err
+	}
+
 	pelanggan, err := a.services.PelangganService.CreatePelanggan(&req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create customer: %w", err)
@@ -277,442 +531,1618 @@ func (a *App) CreatePelanggan(req models.CreatePelangganRequest) (*models.Pelang
 
 // GetAllPelanggan retrieves all customers
 func (a *App) GetAllPelanggan() ([]*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage, models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
 	return a.services.PelangganService.GetAllPelanggan()
 }
 
 // GetPelangganByID retrieves a customer by ID
 func (a *App) GetPelangganByID(id int64) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage, models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
 	return a.services.PelangganService.GetPelangganByID(id)
 }
 
 // GetPelangganByTelepon retrieves a customer by phone number
 func (a *App) GetPelangganByTelepon(telepon string) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage, models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
 	return a.services.PelangganService.GetPelangganByTelepon(telepon)
 }
 
 // UpdatePelanggan updates a customer
 func (a *App) UpdatePelanggan(req models.UpdatePelangganRequest) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Updating pelanggan ID: %d", req.ID)
 	return a.services.PelangganService.UpdatePelanggan(&req)
 }
 
 // DeletePelanggan deletes a customer
 func (a *App) DeletePelanggan(id int64) error {
+	if err := a.requirePermission(models.PermCustomerManage); err != nil {
+		return err
+	}
+
 	log.Printf("Deleting pelanggan ID: %d", id)
 	return a.services.PelangganService.DeletePelanggan(id)
 }
 
 // AddPoin adds points to a customer
 func (a *App) AddPoin(req models.AddPoinRequest) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Adding %d points to pelanggan ID: %d", req.Poin, req.PelangganID)
 	return a.services.PelangganService.AddPoin(&req)
 }
 
 // GetPelangganByTipe retrieves customers by type
 func (a *App) GetPelangganByTipe(tipe string) ([]*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage, models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
 	return a.services.PelangganService.GetPelangganByTipe(tipe)
 }
 
-// ==================== SETTINGS API ====================
+// GetSaldoHistory retrieves the store credit movements of a customer
+func (a *App) GetSaldoHistory(pelangganID int64, limit int) ([]*models.SaldoPelangganHistory, error) {
+	if err := a.requirePermission(models.PermCustomerManage, models.PermCustomerBalance); err != nil {
+		return nil, err
+	}
 
-// GetPoinSettings retrieves point system settings
-func (a *App) GetPoinSettings() (*models.PoinSettings, error) {
-	log.Println("Getting poin settings")
-	return a.services.SettingsService.GetPoinSettings()
+	return a.services.PelangganService.GetSaldoHistory(pelangganID, limit)
 }
 
-// UpdatePoinSettings updates point system settings
-func (a *App) UpdatePoinSettings(req models.UpdatePoinSettingsRequest) (*models.PoinSettings, error) {
-	log.Printf("[APP] Updating poin settings. Request: %+v", req)
-	return a.services.SettingsService.UpdatePoinSettings(&req)
+// TopUpSaldo records a store credit deposit for a customer
+func (a *App) TopUpSaldo(req models.TopUpSaldoRequest) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Top up saldo %d for pelanggan ID: %d via %s", req.Jumlah, req.PelangganID, req.Metode)
+	return a.services.PelangganService.TopUpSaldo(&req)
 }
 
-// ==================== HARDWARE API ====================
+// PenyesuaianSaldo manually corrects a customer's store credit
+func (a *App) PenyesuaianSaldo(req models.PenyesuaianSaldoRequest) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerBalance); err != nil {
+		return nil, err
+	}
 
-// DetectHardware detects all connected hardware devices
-func (a *App) DetectHardware() (*models.HardwareListResponse, error) {
-	log.Println("Detecting hardware devices")
-	return a.services.HardwareService.DetectHardware()
+	log.Printf("[APP] Penyesuaian saldo %d for pelanggan ID: %d, alasan: %s", req.Jumlah, req.PelangganID, req.Alasan)
+	return a.services.PelangganService.PenyesuaianSaldo(&req)
 }
 
-// TestScanner tests barcode scanner connection
-func (a *App) TestScanner(port string) (*models.TestHardwareResponse, error) {
-	log.Printf("Testing scanner on port: %s", port)
-	return a.services.HardwareService.TestScanner(port)
-}
+// UpdateKreditPelanggan sets the credit limit and credit term of a customer
+func (a *App) UpdateKreditPelanggan(req models.UpdateKreditPelangganRequest) (*models.Pelanggan, error) {
+	if err := a.requirePermission(models.PermCustomerCredit); err != nil {
+		return nil, err
+	}
 
-// TestPrinter tests printer connection
-func (a *App) TestPrinter(port string) (*models.TestHardwareResponse, error) {
-	log.Printf("Testing printer on port: %s", port)
-	return a.services.HardwareService.TestPrinter(port)
+	log.Printf("[APP] Update kredit pelanggan ID: %d, limit: %d, tempo: %d hari", req.PelangganID, req.LimitKredit, req.TempoHari)
+	return a.services.PelangganService.UpdateKredit(&req)
 }
 
-// TestCashDrawer tests cash drawer connection
-func (a *App) TestCashDrawer(port string) (*models.TestHardwareResponse, error) {
-	log.Printf("Testing cash drawer on port: %s", port)
-	return a.services.HardwareService.TestCashDrawer(port)
-}
+// ==================== PIUTANG API ====================
 
-// ==================== PROMO API ====================
+// GetPiutang retrieves receivables, optionally filtered by customer (0 = all) and status
+func (a *App) GetPiutang(pelangganID int64, status string) ([]*models.Piutang, error) {
+	if err := a.requirePermission(models.PermReceivableCollect, models.PermCustomerCredit); err != nil {
+		return nil, err
+	}
 
-// CreatePromo creates a new promo
-func (a *App) CreatePromo(req models.CreatePromoRequest) (*models.Promo, error) {
-	log.Printf("Creating promo: %s", req.Nama)
-	return a.services.PromoService.CreatePromo(&req)
+	return a.services.PiutangService.GetPiutang(pelangganID, status)
 }
 
-// GetAllPromo retrieves all promos
-func (a *App) GetAllPromo() ([]*models.Promo, error) {
-	return a.services.PromoService.GetAllPromo()
-}
+// BayarPiutang records a credit sale repayment
+func (a *App) BayarPiutang(req models.BayarPiutangRequest) ([]*models.PembayaranPiutang, error) {
+	if err := a.requirePermission(models.PermReceivableCollect); err != nil {
+		return nil, err
+	}
 
-// GetActivePromos retrieves all active promos
-func (a *App) GetActivePromos() ([]*models.Promo, error) {
-	return a.services.PromoService.GetActivePromos()
+	log.Printf("[APP] Bayar piutang %d for pelanggan ID: %d via %s", req.Jumlah, req.PelangganID, req.Metode)
+	return a.services.PiutangService.BayarPiutang(&req)
 }
 
-// GetPromoByID retrieves a promo by ID
-func (a *App) GetPromoByID(idStr string) (*models.Promo, error) {
-	id, err := strconv.Atoi(idStr)
+// GetPembayaranPiutang retrieves repayments received within a date range
+func (a *App) GetPembayaranPiutang(startDate, endDate string) ([]*models.PembayaranPiutang, error) {
+	if err := a.requirePermission(models.PermReceivableCollect, models.PermCustomerCredit); err != nil {
+		return nil, err
+	}
+
+	start, err := a.parseDate(startDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid ID: %s", idStr)
+		return nil, fmt.Errorf("invalid start date: %w", err)
 	}
-	return a.services.PromoService.GetPromoByID(id)
-}
 
-// GetPromoByKode retrieves a promo by code
-func (a *App) GetPromoByKode(kode string) (*models.Promo, error) {
-	return a.services.PromoService.GetPromoByKode(kode)
-}
+	end, err := a.parseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
 
-// UpdatePromo updates a promo
-func (a *App) UpdatePromo(req models.UpdatePromoRequest) (*models.Promo, error) {
-	log.Printf("Updating promo ID: %d", req.ID)
-	return a.services.PromoService.UpdatePromo(&req)
+	return a.services.PiutangService.GetPembayaranPiutang(start, end.Add(24*time.Hour-time.Nanosecond))
 }
 
-// DeletePromo deletes a promo
-func (a *App) DeletePromo(idStr string) error {
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		return fmt.Errorf("invalid ID: %s", idStr)
+// GetLaporanUmurPiutang retrieves the receivable aging report as of a date (empty = now)
+func (a *App) GetLaporanUmurPiutang(tanggal string) (*models.LaporanUmurPiutang, error) {
+	if err := a.requirePermission(models.PermReceivableCollect, models.PermCustomerCredit); err != nil {
+		return nil, err
 	}
-	log.Printf("Deleting promo ID: %d", id)
-	return a.services.PromoService.DeletePromo(id)
-}
 
-// ApplyPromo calculates discount for a promo code
-func (a *App) ApplyPromo(req models.ApplyPromoRequest) (*models.ApplyPromoResponse, error) {
-	log.Printf("Applying promo code: %s", req.Kode)
-	return a.services.PromoService.ApplyPromo(&req)
+	asOf := time.Now()
+	if tanggal != "" {
+		parsed, err := a.parseDate(tanggal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %w", err)
+		}
+		asOf = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return a.services.PiutangService.GetLaporanUmurPiutang(asOf)
 }
 
-// GetPromoForProduct gets active promos for a specific product
+// ==================== SESI KAS API ====================
+
+// BukaSesiKas opens a cash drawer session with a starting float
+func (a *App) BukaSesiKas(req models.BukaSesiKasRequest) (*models.SesiKas, error) {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Buka sesi kas for staff ID: %d, modal awal %d", req.StaffID, req.ModalAwal)
+	return a.services.SesiKasService.BukaSesi(&req)
+}
+
+// CatatMutasiKas records a paid-in or paid-out entry of an open session
+func (a *App) CatatMutasiKas(req models.MutasiKasRequest) (*models.MutasiKas, error) {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Kas %s %d on sesi ID: %d (%s)", req.Tipe, req.Jumlah, req.SesiID, req.Alasan)
+	return a.services.SesiKasService.CatatMutasi(&req)
+}
+
+// TutupSesiKas closes a session with a denomination count and returns its Z-report
+func (a *App) TutupSesiKas(req models.TutupSesiKasRequest) (*models.LaporanSesiKas, error) {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Tutup sesi kas ID: %d", req.SesiID)
+	return a.services.SesiKasService.TutupSesi(&req)
+}
+
+// GetSesiKasAktif retrieves the open session of a staff member (nil when none)
+func (a *App) GetSesiKasAktif(staffID int64) (*models.SesiKas, error) {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return nil, err
+	}
+
+	return a.services.SesiKasService.GetSesiAktif(staffID)
+}
+
+// GetAllSesiKas lists sessions, optionally per staff (0 = all) and status
+func (a *App) GetAllSesiKas(staffID int64, status string, limit int) ([]*models.SesiKas, error) {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return nil, err
+	}
+
+	return a.services.SesiKasService.GetAllSesi(staffID, status, limit)
+}
+
+// GetLaporanSesiKas retrieves the X-report (open session) or Z-report (closed session)
+func (a *App) GetLaporanSesiKas(sesiID int64, jenis string) (*models.LaporanSesiKas, error) {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return nil, err
+	}
+
+	return a.services.SesiKasService.GetLaporan(sesiID, jenis)
+}
+
+// PrintLaporanSesiKas prints the X/Z report of a session
+func (a *App) PrintLaporanSesiKas(req models.PrintLaporanSesiKasRequest) error {
+	if err := a.requirePermission(models.PermCashSession); err != nil {
+		return err
+	}
+
+	log.Printf("[APP] Print laporan %s sesi kas ID: %d", req.Jenis, req.SesiID)
+	return a.services.PrinterService.PrintLaporanSesiKas(&req)
+}
+
+// ==================== LACI KAS API ====================
+
+// BukaLaciNoSale opens the cash drawer without a sale; the reason is logged against the staff member
+func (a *App) BukaLaciNoSale(req models.NoSaleRequest) (*models.LogLaciKas, error) {
+	if err := a.requirePermission(models.PermCashDrawerOpen); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] No sale drawer open by staff ID: %d (%s)", req.StaffID, req.Alasan)
+	return a.services.LaciKasService.NoSale(&req)
+}
+
+// GetLogLaciKas retrieves drawer openings within a date range, optionally per staff (0 = all) and type
+func (a *App) GetLogLaciKas(staffID int64, tipe, startDate, endDate string) ([]*models.LogLaciKas, error) {
+	if err := a.requirePermission(models.PermCashDrawerLog); err != nil {
+		return nil, err
+	}
+
+	start, err := a.parseDate(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	end, err := a.parseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	return a.services.LaciKasService.GetLog(staffID, tipe, start, end.Add(24*time.Hour-time.Nanosecond))
+}
+
+// ==================== OTORISASI SUPERVISOR API ====================
+
+// MintaOtorisasi verifies a supervisor's username and PIN and returns a single-use override token for one action
+func (a *App) MintaOtorisasi(req models.MintaOtorisasiRequest) (*models.OtorisasiResponse, error) {
+	if user := a.user.Load(); user != nil && req.StaffID == 0 {
+		req.StaffID = user.ID
+		req.StaffNama = user.NamaLengkap
+	}
+
+	log.Printf("[APP] Override requested for %s by staff ID: %d", req.Aksi, req.StaffID)
+	return a.services.OtorisasiService.Minta(&req)
+}
+
+// GetRiwayatOtorisasi retrieves issued override tokens within a date range
+func (a *App) GetRiwayatOtorisasi(startDate, endDate string) ([]*models.Otorisasi, error) {
+	if err := a.requirePermission(models.PermOverrideApprove); err != nil {
+		return nil, err
+	}
+
+	start, err := a.parseDate(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	end, err := a.parseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	return a.services.OtorisasiService.GetRiwayat(start, end.Add(24*time.Hour-time.Nanosecond))
+}
+
+// ==================== ABSENSI API ====================
+
+// ClockIn records a staff clock-in; with username and PIN another staff member can clock in at this POS
+func (a *App) ClockIn(req models.AbsensiRequest) (*models.Absensi, error) {
+	if err := a.requirePermission(models.PermAttendanceClock); err != nil {
+		return nil, err
+	}
+
+	a.isiStaffAbsensi(&req)
+	return a.services.AbsensiService.ClockIn(&req)
+}
+
+// ClockOut records a staff clock-out with early leave and overtime against the shift
+func (a *App) ClockOut(req models.AbsensiRequest) (*models.Absensi, error) {
+	if err := a.requirePermission(models.PermAttendanceClock); err != nil {
+		return nil, err
+	}
+
+	a.isiStaffAbsensi(&req)
+	return a.services.AbsensiService.ClockOut(&req)
+}
+
+// MulaiIstirahat starts a break for a clocked-in staff member
+func (a *App) MulaiIstirahat(req models.AbsensiRequest) (*models.Absensi, error) {
+	if err := a.requirePermission(models.PermAttendanceClock); err != nil {
+		return nil, err
+	}
+
+	a.isiStaffAbsensi(&req)
+	return a.services.AbsensiService.MulaiIstirahat(&req)
+}
+
+// SelesaiIstirahat ends the current break
+func (a *App) SelesaiIstirahat(req models.AbsensiRequest) (*models.Absensi, error) {
+	if err := a.requirePermission(models.PermAttendanceClock); err != nil {
+		return nil, err
+	}
+
+	a.isiStaffAbsensi(&req)
+	return a.services.AbsensiService.SelesaiIstirahat(&req)
+}
+
+// isiStaffAbsensi takes the staff member from the desktop session, never from the frontend.
+// Anyone else has to identify with username and PIN, as over HTTP.
+func (a *App) isiStaffAbsensi(req *models.AbsensiRequest) {
+	aktor := a.aktor()
+	req.StaffID, req.StaffNama = aktor.ID, aktor.Nama
+}
+
+// GetAbsensiAktif returns the attendance a staff member has not clocked out of yet
+func (a *App) GetAbsensiAktif(staffID int64) (*models.Absensi, error) {
+	if err := a.requirePermission(models.PermAttendanceClock); err != nil {
+		return nil, err
+	}
+
+	return a.services.AbsensiService.GetAbsensiAktif(staffID)
+}
+
+// ==================== KOMISI API ====================
+
+// GetAllAturanKomisi retrieves all commission rules
+func (a *App) GetAllAturanKomisi() ([]*models.AturanKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.KomisiService.GetAllAturan()
+}
+
+// CreateAturanKomisi creates a commission rule
+func (a *App) CreateAturanKomisi(aturan models.AturanKomisi) (*models.AturanKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.KomisiService.CreateAturan(&aturan); err != nil {
+		return nil, err
+	}
+	return &aturan, nil
+}
+
+// UpdateAturanKomisi updates a commission rule
+func (a *App) UpdateAturanKomisi(aturan models.AturanKomisi) (*models.AturanKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.KomisiService.UpdateAturan(&aturan); err != nil {
+		return nil, err
+	}
+	return &aturan, nil
+}
+
+// DeleteAturanKomisi deletes a commission rule
+func (a *App) DeleteAturanKomisi(id int64) error {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return err
+	}
+
+	return a.services.KomisiService.DeleteAturan(id)
+}
+
+// HitungKomisi previews the commission of all staff for a date range
+func (a *App) HitungKomisi(startDate, endDate string) ([]*models.KomisiStaff, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	start, err := a.parseDate(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	end, err := a.parseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	return a.services.KomisiService.HitungKomisi(start, end.Add(24*time.Hour-time.Nanosecond))
+}
+
+// GetAllPeriodeKomisi retrieves saved commission payouts
+func (a *App) GetAllPeriodeKomisi() ([]*models.PeriodeKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.KomisiService.GetAllPeriode()
+}
+
+// GetPeriodeKomisi retrieves a saved commission payout with its staff lines
+func (a *App) GetPeriodeKomisi(id int64) (*models.PeriodeKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.KomisiService.GetPeriode(id)
+}
+
+// BuatPeriodeKomisi calculates and saves the commission payout of a period
+func (a *App) BuatPeriodeKomisi(req models.BuatPeriodeKomisiRequest) (*models.PeriodeKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Creating commission period %s - %s", req.PeriodeMulai, req.PeriodeSelesai)
+	return a.services.KomisiService.BuatPeriode(&req)
+}
+
+// TandaiKomisiDibayar locks a commission payout as paid
+func (a *App) TandaiKomisiDibayar(id int64) (*models.PeriodeKomisi, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.KomisiService.TandaiDibayar(id)
+}
+
+// DeletePeriodeKomisi deletes a draft commission payout
+func (a *App) DeletePeriodeKomisi(id int64) error {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return err
+	}
+
+	return a.services.KomisiService.DeletePeriode(id)
+}
+
+// ExportKomisiPayroll returns a commission payout as CSV for payroll
+func (a *App) ExportKomisiPayroll(id int64) (string, error) {
+	if err := a.requirePermission(models.PermCommissionManage); err != nil {
+		return "", err
+	}
+
+	return a.services.KomisiService.ExportPayroll(id)
+}
+
+// ==================== TARGET PENJUALAN API ====================
+
+// GetAllTarget retrieves sales targets, optionally of one scope ("toko", "shift", "staff")
+func (a *App) GetAllTarget(lingkup string) ([]*models.TargetPenjualan, error) {
+	return a.services.TargetService.GetAllTarget(lingkup)
+}
+
+// CreateTarget creates a sales target
+func (a *App) CreateTarget(target models.TargetPenjualan) (*models.TargetPenjualan, error) {
+	if err := a.requirePermission(models.PermTargetManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.TargetService.CreateTarget(&target); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// UpdateTarget updates a sales target
+func (a *App) UpdateTarget(target models.TargetPenjualan) (*models.TargetPenjualan, error) {
+	if err := a.requirePermission(models.PermTargetManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.TargetService.UpdateTarget(&target); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// DeleteTarget deletes a sales target
+func (a *App) DeleteTarget(id int64) error {
+	if err := a.requirePermission(models.PermTargetManage); err != nil {
+		return err
+	}
+
+	return a.services.TargetService.DeleteTarget(id)
+}
+
+// ==================== SETTINGS API ====================
+
+// GetPoinSettings retrieves point system settings
+func (a *App) GetPoinSettings() (*models.PoinSettings, error) {
+	log.Println("Getting poin settings")
+	return a.services.SettingsService.GetPoinSettings()
+}
+
+// UpdatePoinSettings updates point system settings
+func (a *App) UpdatePoinSettings(req models.UpdatePoinSettingsRequest) (*models.PoinSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating poin settings. Request: %+v", req)
+	return a.services.SettingsService.UpdatePoinSettings(&req, a.aktor())
+}
+
+// GetPenomoranSettings retrieves the transaction numbering scheme of this terminal
+func (a *App) GetPenomoranSettings() (*models.PenomoranSettings, error) {
+	return a.services.SettingsService.GetPenomoranSettings()
+}
+
+// UpdatePenomoranSettings updates the transaction numbering scheme of this terminal
+func (a *App) UpdatePenomoranSettings(req models.PenomoranSettings) (*models.PenomoranSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating penomoran settings. Request: %+v", req)
+	return a.services.SettingsService.UpdatePenomoranSettings(&req, a.aktor())
+}
+
+// GetPembulatanSettings retrieves the cash rounding policy
+func (a *App) GetPembulatanSettings() (*models.PembulatanSettings, error) {
+	return a.services.SettingsService.GetPembulatanSettings()
+}
+
+// PreviewPembulatan returns the cash-rounded total collected at checkout
+func (a *App) PreviewPembulatan(total, nonTunai int, donasi bool) (*models.PembulatanPreview, error) {
+	if err := a.requirePermission(models.PermSettingsManage, models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
+	return a.services.SettingsService.PreviewPembulatan(total, nonTunai, donasi)
+}
+
+// UpdatePembulatanSettings updates the cash rounding policy
+func (a *App) UpdatePembulatanSettings(req models.PembulatanSettings) (*models.PembulatanSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating pembulatan settings. Request: %+v", req)
+	return a.services.SettingsService.UpdatePembulatanSettings(&req, a.aktor())
+}
+
+// GetPajakSettings retrieves the store tax (PPN) settings
+func (a *App) GetPajakSettings() (*models.PajakSettings, error) {
+	return a.services.SettingsService.GetPajakSettings()
+}
+
+// UpdatePajakSettings updates the store tax (PPN) settings
+func (a *App) UpdatePajakSettings(req models.PajakSettings) (*models.PajakSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating pajak settings. Request: %+v", req)
+	return a.services.SettingsService.UpdatePajakSettings(&req, a.aktor())
+}
+
+// GetLaciKasSettings retrieves the cash drawer settings of this terminal
+func (a *App) GetLaciKasSettings() (*models.LaciKasSettings, error) {
+	return a.services.SettingsService.GetLaciKasSettings()
+}
+
+// UpdateLaciKasSettings updates the cash drawer settings of this terminal
+func (a *App) UpdateLaciKasSettings(req models.LaciKasSettings) (*models.LaciKasSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating laci kas settings. Request: %+v", req)
+	return a.services.SettingsService.UpdateLaciKasSettings(&req, a.aktor())
+}
+
+// GetOtorisasiSettings retrieves the thresholds for supervisor overrides
+func (a *App) GetOtorisasiSettings() (*models.OtorisasiSettings, error) {
+	return a.services.SettingsService.GetOtorisasiSettings()
+}
+
+// UpdateOtorisasiSettings updates the thresholds for supervisor overrides
+func (a *App) UpdateOtorisasiSettings(req models.OtorisasiSettings) (*models.OtorisasiSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating otorisasi settings. Request: %+v", req)
+	return a.services.SettingsService.UpdateOtorisasiSettings(&req, a.aktor())
+}
+
+// GetKeamananSettings retrieves the auto-lock, login lockout and password policy settings
+func (a *App) GetKeamananSettings() (*models.KeamananSettings, error) {
+	return a.services.SettingsService.GetKeamananSettings()
+}
+
+// UpdateKeamananSettings updates the auto-lock, login lockout and password policy settings
+func (a *App) UpdateKeamananSettings(req models.KeamananSettings) (*models.KeamananSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating keamanan settings. Request: %+v", req)
+	return a.services.SettingsService.UpdateKeamananSettings(&req, a.aktor())
+}
+
+// GetPembelianSettings retrieves how product costs are updated on goods receipts
+func (a *App) GetPembelianSettings() (*models.PembelianSettings, error) {
+	return a.services.SettingsService.GetPembelianSettings()
+}
+
+// UpdatePembelianSettings updates how product costs are updated on goods receipts
+func (a *App) UpdatePembelianSettings(req models.PembelianSettings) (*models.PembelianSettings, error) {
+	if err := a.requirePermission(models.PermSettingsManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APP] Updating pembelian settings. Request: %+v", req)
+	return a.services.SettingsService.UpdatePembelianSettings(&req, a.aktor())
+}
+
+// ==================== METODE PEMBAYARAN API ====================
+
+// GetAllMetodePembayaran retrieves the configured payment methods
+func (a *App) GetAllMetodePembayaran(aktifSaja bool) ([]*models.MetodePembayaran, error) {
+	return a.services.MetodePembayaranService.GetAllMetode(aktifSaja)
+}
+
+// CreateMetodePembayaran creates a new payment method
+func (a *App) CreateMetodePembayaran(metode models.MetodePembayaran) error {
+	if err := a.requirePermission(models.PermPaymentMethodManage); err != nil {
+		return err
+	}
+
+	return a.services.MetodePembayaranService.CreateMetode(&metode)
+}
+
+// UpdateMetodePembayaran updates a payment method
+func (a *App) UpdateMetodePembayaran(metode models.MetodePembayaran) error {
+	if err := a.requirePermission(models.PermPaymentMethodManage); err != nil {
+		return err
+	}
+
+	return a.services.MetodePembayaranService.UpdateMetode(&metode)
+}
+
+// DeleteMetodePembayaran deletes a payment method that has never been used
+func (a *App) DeleteMetodePembayaran(id int64) error {
+	if err := a.requirePermission(models.PermPaymentMethodManage); err != nil {
+		return err
+	}
+
+	return a.services.MetodePembayaranService.DeleteMetode(id)
+}
+
+// ==================== HARDWARE API ====================
+
+// DetectHardware detects all connected hardware devices
+func (a *App) DetectHardware() (*models.HardwareListResponse, error) {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return nil, err
+	}
+
+	log.Println("Detecting hardware devices")
+	return a.services.HardwareService.DetectHardware()
+}
+
+// TestScanner tests barcode scanner connection
+func (a *App) TestScanner(port string) (*models.TestHardwareResponse, error) {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Testing scanner on port: %s", port)
+	return a.services.HardwareService.TestScanner(port)
+}
+
+// TestPrinter tests printer connection
+func (a *App) TestPrinter(port string) (*models.TestHardwareResponse, error) {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Testing printer on port: %s", port)
+	return a.services.HardwareService.TestPrinter(port)
+}
+
+// TestCashDrawer tests cash drawer connection
+func (a *App) TestCashDrawer(port string) (*models.TestHardwareResponse, error) {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Testing cash drawer on port: %s", port)
+	return a.services.HardwareService.TestCashDrawer(port)
+}
+
+// ==================== PROMO API ====================
+
+// CreatePromo creates a new promo
+func (a *App) CreatePromo(req models.CreatePromoRequest) (*models.Promo, error) {
+	if err := a.requirePermission(models.PermPromoManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Creating promo: %s", req.Nama)
+	return a.services.PromoService.CreatePromo(&req)
+}
+
+// GetAllPromo retrieves all promos
+func (a *App) GetAllPromo() ([]*models.Promo, error) {
+	return a.services.PromoService.GetAllPromo()
+}
+
+// GetActivePromos retrieves all active promos
+func (a *App) GetActivePromos() ([]*models.Promo, error) {
+	return a.services.PromoService.GetActivePromos()
+}
+
+// GetPromoByID retrieves a promo by ID
+func (a *App) GetPromoByID(idStr string) (*models.Promo, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID: %s", idStr)
+	}
+	return a.services.PromoService.GetPromoByID(id)
+}
+
+// GetPromoByKode retrieves a promo by code
+func (a *App) GetPromoByKode(kode string) (*models.Promo, error) {
+	return a.services.PromoService.GetPromoByKode(kode)
+}
+
+// UpdatePromo updates a promo
+func (a *App) UpdatePromo(req models.UpdatePromoRequest) (*models.Promo, error) {
+	if err := a.requirePermission(models.PermPromoManage); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Updating promo ID: %d", req.ID)
+	return a.services.PromoService.UpdatePromo(&req)
+}
+
+// DeletePromo deletes a promo
+func (a *App) DeletePromo(idStr string) error {
+	if err := a.requirePermission(models.PermPromoManage); err != nil {
+		return err
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", idStr)
+	}
+	log.Printf("Deleting promo ID: %d", id)
+	return a.services.PromoService.DeletePromo(id)
+}
+
+// ApplyPromo calculates discount for a promo code
+func (a *App) ApplyPromo(req models.ApplyPromoRequest) (*models.ApplyPromoResponse, error) {
+	log.Printf("Applying promo code: %s", req.Kode)
+	return a.services.PromoService.ApplyPromo(&req)
+}
+
+// GetPromoForProduct gets active promos for a specific product
 func (a *App) GetPromoForProduct(produkID int) ([]*models.Promo, error) {
 	return a.services.PromoService.GetPromoForProduct(produkID)
 }
 
-// GetPromoProducts gets products associated with a promo
-func (a *App) GetPromoProducts(promoIDStr string) ([]*models.Produk, error) {
-	promoID, err := strconv.Atoi(promoIDStr)
+// GetPromoProducts gets products associated with a promo
+func (a *App) GetPromoProducts(promoIDStr string) ([]*models.Produk, error) {
+	promoID, err := strconv.Atoi(promoIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID: %s", promoIDStr)
+	}
+	return a.services.PromoService.GetPromoProducts(promoID)
+}
+
+// ==================== RETURN API ====================
+
+// CreateReturn creates a new return transaction
+func (a *App) CreateReturn(req models.CreateReturnRequest) error {
+	if err := a.requirePermission(models.PermReturnApprove); err != nil {
+		return err
+	}
+
+	log.Printf("Creating return for transaction: %s", req.NoTransaksi)
+	aktor := a.aktor()
+	req.StaffID, req.StaffNama = aktor.ID, aktor.Nama
+	return a.services.ReturnService.CreateReturn(&req)
+}
+
+// GetAllReturn retrieves all returns
+func (a *App) GetAllReturn() ([]*models.ReturnDetail, error) {
+	if err := a.requirePermission(models.PermReturnApprove, models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturnService.GetAllReturn()
+}
+
+// GetAllReturns is an alias for GetAllReturn (for consistency)
+func (a *App) GetAllReturns() ([]*models.ReturnDetail, error) {
+	if err := a.requirePermission(models.PermReturnApprove, models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturnService.GetAllReturn()
+}
+
+// GetReturnByID retrieves a return by ID
+func (a *App) GetReturnByID(id int) (*models.ReturnDetail, error) {
+	if err := a.requirePermission(models.PermReturnApprove, models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturnService.GetReturnByID(id)
+}
+
+// ==================== PRINTER API ====================
+
+// GetInstalledPrinters retrieves all installed printers
+func (a *App) GetInstalledPrinters() ([]*models.PrinterInfo, error) {
+	log.Println("Getting installed printers...")
+	return a.services.PrinterService.GetInstalledPrinters()
+}
+
+// TestPrint performs a test print
+func (a *App) TestPrint(printerName string) error {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return err
+	}
+
+	log.Printf("Test printing to: %s", printerName)
+	return a.services.PrinterService.TestPrint(printerName)
+}
+
+func (a *App) DebugPromoValidation(promoKode string, items []models.TransaksiItemRequest) (string, error) {
+	return a.services.PromoService.DebugPromoValidation(promoKode, items)
+}
+
+// PrintReceipt prints a transaction receipt
+func (a *App) PrintReceipt(req models.PrintReceiptRequest) error {
+	log.Printf("Printing receipt: %s", req.TransactionNo)
+	return a.services.PrinterService.PrintReceipt(&req)
+}
+
+// GetPrintSettings retrieves current print settings
+func (a *App) GetPrintSettings() (*models.PrintSettings, error) {
+	log.Println("[PRINTER SETTINGS] ========== GET SETTINGS ==========")
+	settings, err := a.services.PrinterService.GetPrintSettings()
+
+	if err != nil {
+		log.Printf("[PRINTER SETTINGS] ERROR: Failed to get settings: %v", err)
+		return nil, err
+	}
+
+	if settings == nil {
+		log.Println("[PRINTER SETTINGS] WARNING: Settings returned nil")
+		return nil, fmt.Errorf("settings not found")
+	}
+
+	log.Printf("[PRINTER SETTINGS] Retrieved: PrinterName=%s, PaperSize=%s",
+		settings.PrinterName, settings.PaperSize)
+	log.Println("[PRINTER SETTINGS] ========== GET COMPLETE ==========")
+
+	return settings, nil
+}
+
+// SavePrintSettings saves print settings
+func (a *App) SavePrintSettings(settings models.PrintSettings) error {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return err
+	}
+
+	log.Println("[PRINTER SETTINGS] ========== SAVE ATTEMPT ==========")
+	log.Printf("[PRINTER SETTINGS] Received settings: PrinterName=%s, PaperSize=%s, AutoPrint=%v",
+		settings.PrinterName, settings.PaperSize, settings.AutoPrint)
+
+	// Log to file
+	logToPrinterFile("========== SET DEFAULT PRINTER ==========")
+	logToPrinterFile(fmt.Sprintf("Printer Name: %s", settings.PrinterName))
+
+	// Validate settings
+	if settings.PrinterName == "" {
+		log.Println("[PRINTER SETTINGS] ERROR: Printer name is empty!")
+		logToPrinterFile("ERROR: Printer name is empty!")
+		return fmt.Errorf("printer name cannot be empty")
+	}
+
+	log.Println("[PRINTER SETTINGS] Calling service.SavePrintSettings...")
+	err := a.services.PrinterService.SavePrintSettings(&settings)
+
+	if err != nil {
+		log.Printf("[PRINTER SETTINGS] ERROR: Save failed: %v", err)
+		logToPrinterFile(fmt.Sprintf("ERROR: Save failed - %v", err))
+		return err
+	}
+
+	log.Println("[PRINTER SETTINGS] SUCCESS: Settings saved to database")
+	logToPrinterFile(fmt.Sprintf("SUCCESS: Printer '%s' berhasil di set sebagai default", settings.PrinterName))
+	logToPrinterFile(fmt.Sprintf("Paper Size: %s, Auto Print: %v", settings.PaperSize, settings.AutoPrint))
+
+	// Verify save by reading back
+	log.Println("[PRINTER SETTINGS] Verifying saved settings...")
+	savedSettings, verifyErr := a.services.PrinterService.GetPrintSettings()
+	if verifyErr != nil {
+		log.Printf("[PRINTER SETTINGS] WARNING: Could not verify settings: %v", verifyErr)
+		logToPrinterFile(fmt.Sprintf("WARNING: Could not verify settings - %v", verifyErr))
+	} else {
+		log.Printf("[PRINTER SETTINGS] Verified: PrinterName=%s", savedSettings.PrinterName)
+		if savedSettings.PrinterName != settings.PrinterName {
+			log.Printf("[PRINTER SETTINGS] WARNING: Saved name mismatch! Expected=%s, Got=%s",
+				settings.PrinterName, savedSettings.PrinterName)
+			logToPrinterFile(fmt.Sprintf("WARNING: Mismatch! Expected=%s, Got=%s", settings.PrinterName, savedSettings.PrinterName))
+		} else {
+			logToPrinterFile("Verification: OK")
+		}
+	}
+
+	logToPrinterFile("========================================")
+	log.Println("[PRINTER SETTINGS] ========== SAVE COMPLETE ==========")
+	return nil
+}
+
+// TestPrintByName sends a test print to the specified printer by name
+func (a *App) TestPrintByName(printerName string) error {
+	if err := a.requirePermission(models.PermHardwareManage); err != nil {
+		return err
+	}
+
+	log.Println("[TEST PRINT] ========== TEST PRINT START ==========")
+	log.Printf("[TEST PRINT] Printer: %s", printerName)
+
+	// Log to file
+	logToPrinterFile("========== TEST PRINT ==========")
+	logToPrinterFile(fmt.Sprintf("Printer: %s", printerName))
+
+	if printerName == "" {
+		log.Println("[TEST PRINT] ERROR: Printer name is empty!")
+		logToPrinterFile("ERROR: Printer name is empty!")
+		return fmt.Errorf("printer name cannot be empty")
+	}
+
+	err := a.services.PrinterService.TestPrint(printerName)
+
+	if err != nil {
+		log.Printf("[TEST PRINT] ERROR: Test print failed: %v", err)
+		logToPrinterFile(fmt.Sprintf("ERROR: Test print failed - %v", err))
+		return err
+	}
+
+	log.Println("[TEST PRINT] SUCCESS: Test print sent successfully")
+	logToPrinterFile(fmt.Sprintf("SUCCESS: Test print ke '%s' berhasil dikirim", printerName))
+	logToPrinterFile("========================================")
+
+	log.Println("[TEST PRINT] ========== TEST PRINT COMPLETE ==========")
+	return nil
+}
+
+// ==================== ANALYTICS API ====================
+
+// GetTopProducts retrieves top selling products within date range
+func (a *App) GetTopProducts(startDate, endDate string, limit int) ([]*models.TopProductsResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting top products from %s to %s (limit: %d)", startDate, endDate, limit)
+	return a.services.AnalyticsService.GetTopProducts(startDate, endDate, limit)
+}
+
+// GetPaymentMethodBreakdown retrieves payment method statistics
+func (a *App) GetPaymentMethodBreakdown(startDate, endDate string) ([]*models.PaymentBreakdownResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting payment breakdown from %s to %s", startDate, endDate)
+	return a.services.AnalyticsService.GetPaymentMethodBreakdown(startDate, endDate)
+}
+
+// GetSalesTrend retrieves sales trend over time
+func (a *App) GetSalesTrend(startDate, endDate string) ([]*models.SalesTrendResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting sales trend from %s to %s", startDate, endDate)
+	return a.services.AnalyticsService.GetSalesTrend(startDate, endDate)
+}
+
+// GetCategoryBreakdown retrieves sales by category
+func (a *App) GetCategoryBreakdown(startDate, endDate string) ([]*models.CategoryBreakdownResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting category breakdown from %s to %s", startDate, endDate)
+	return a.services.AnalyticsService.GetCategoryBreakdown(startDate, endDate)
+}
+
+// GetHourlySales retrieves sales grouped by hour and day
+func (a *App) GetHourlySales(startDate, endDate string) ([]*models.HourlySalesResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting hourly sales from %s to %s", startDate, endDate)
+	return a.services.AnalyticsService.GetHourlySales(startDate, endDate)
+}
+
+// GetSalesInsights retrieves comprehensive sales insights
+func (a *App) GetSalesInsights(startDate, endDate string) (*models.SalesInsightsResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting sales insights from %s to %s", startDate, endDate)
+	return a.services.AnalyticsService.GetSalesInsights(startDate, endDate)
+}
+
+// ==================== PELANGGAN STATS API ====================
+
+func (a *App) GetPelangganWithStats(pelangganIDStr string) (*models.PelangganDetail, error) {
+	if err := a.requirePermission(models.PermCustomerManage, models.PermTransactionCreate); err != nil {
+		return nil, err
+	}
+
+	// Convert string to int64
+	pelangganID, err := strconv.ParseInt(pelangganIDStr, 10, 64)
+	if err != nil {
+		log.Printf("Error converting pelangganID to int64: %v", err)
+		return nil, fmt.Errorf("ID pelanggan tidak valid: %s", pelangganIDStr)
+	}
+
+	log.Printf("Getting customer with stats for ID: %d", pelangganID)
+
+	// Use pelangganService yang sudah memiliki transaksiRepo
+	return a.services.PelangganService.GetPelangganWithStats(pelangganID)
+}
+
+// GetTransaksiByPelanggan retrieves all transactions for a customer
+func (a *App) GetTransaksiByPelanggan(pelangganID int64) ([]*models.Transaksi, error) {
+	if err := a.requirePermission(models.PermTransactionView); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Getting transactions for customer ID: %d", pelangganID)
+	return a.services.TransaksiService.GetTransaksiByPelangganID(pelangganID)
+}
+
+// ==================== USER MANAGEMENT API ====================
+
+// Login authenticates a user
+func (a *App) Login(req models.LoginRequest) (*models.LoginResponse, error) {
+	log.Printf("Login attempt for username: %s", req.Username)
+	req.TerminalID = a.kodeTerminal()
+	req.UserAgent = "desktop"
+	response, err := a.services.UserService.Login(&req)
 	if err != nil {
-		return nil, fmt.Errorf("invalid ID: %s", promoIDStr)
+		log.Printf("[APP LOGIN ERROR] %v", err)
+		return nil, err
 	}
-	return a.services.PromoService.GetPromoProducts(promoID)
+	if response != nil && response.User != nil {
+		log.Printf("[APP LOGIN] Returning response to frontend - User ID: %d, Username: %s, NamaLengkap: %s",
+			response.User.ID, response.User.Username, response.User.NamaLengkap)
+	}
+	if response != nil && response.Success {
+		a.masukTerminal(response)
+	}
+	return response, nil
 }
 
-// ==================== RETURN API ====================
+// LoginPin switches the active user on this terminal with a numeric PIN
+func (a *App) LoginPin(req models.PinLoginRequest) (*models.LoginResponse, error) {
+	log.Printf("PIN login attempt for username: %s", req.Username)
+	req.TerminalID = a.kodeTerminal()
+	req.UserAgent = "desktop"
+	response, err := a.services.UserService.LoginPin(&req)
+	if err != nil {
+		log.Printf("[APP LOGIN ERROR] %v", err)
+		return nil, err
+	}
+	if response != nil && response.Success {
+		a.masukTerminal(response)
+	}
+	return response, nil
+}
 
-// CreateReturn creates a new return transaction
-func (a *App) CreateReturn(req models.CreateReturnRequest) error {
-	log.Printf("Creating return for transaction: %s", req.NoTransaksi)
-	return a.services.ReturnService.CreateReturn(&req)
+// masukTerminal makes the signed-in user the active user of the desktop session and terminal
+func (a *App) masukTerminal(response *models.LoginResponse) {
+	a.user.Store(response.User)
+	if kode := a.kodeTerminal(); kode != "" {
+		a.services.TerminalService.Masuk(kode, response.User)
+		response.TerminalID = kode
+	}
 }
 
-// GetAllReturn retrieves all returns
-func (a *App) GetAllReturn() ([]*models.ReturnDetail, error) {
-	return a.services.ReturnService.GetAllReturn()
+// KunciTerminal locks this terminal; the next cashier signs in with PIN or password
+func (a *App) KunciTerminal() error {
+	kode := a.kodeTerminal()
+	if kode == "" {
+		return fmt.Errorf("kode terminal belum diatur")
+	}
+	if err := a.services.TerminalService.Kunci(kode); err != nil {
+		return err
+	}
+	a.user.Store(nil)
+	return nil
 }
 
-// GetAllReturns is an alias for GetAllReturn (for consistency)
-func (a *App) GetAllReturns() ([]*models.ReturnDetail, error) {
-	return a.services.ReturnService.GetAllReturn()
+// GetStatusTerminal returns the lock screen state of this terminal
+func (a *App) GetStatusTerminal() (*models.StatusTerminal, error) {
+	kode := a.kodeTerminal()
+	if kode == "" {
+		return nil, fmt.Errorf("kode terminal belum diatur")
+	}
+	return a.services.TerminalService.Status(kode)
 }
 
-// GetReturnByID retrieves a return by ID
-func (a *App) GetReturnByID(id int) (*models.ReturnDetail, error) {
-	return a.services.ReturnService.GetReturnByID(id)
+// Logout ends the desktop session
+func (a *App) Logout() {
+	a.user.Store(nil)
 }
 
-// ==================== PRINTER API ====================
+// CreateUser creates a new user (admin or staff)
+func (a *App) CreateUser(req models.CreateUserRequest) error {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return err
+	}
 
-// GetInstalledPrinters retrieves all installed printers
-func (a *App) GetInstalledPrinters() ([]*models.PrinterInfo, error) {
-	log.Println("Getting installed printers...")
-	return a.services.PrinterService.GetInstalledPrinters()
+	log.Printf("Creating new user: %s (role: %s)", req.Username, req.Role)
+	return a.services.UserService.CreateUser(&req, a.aktor())
 }
 
-// TestPrint performs a test print
-func (a *App) TestPrint(printerName string) error {
-	log.Printf("Test printing to: %s", printerName)
-	return a.services.PrinterService.TestPrint(printerName)
+// UpdateUser updates user information
+func (a *App) UpdateUser(req models.UpdateUserRequest) error {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return err
+	}
+
+	log.Printf("Updating user ID: %d", req.ID)
+	return a.services.UserService.UpdateUser(&req, a.aktor())
 }
 
-func (a *App) DebugPromoValidation(promoKode string, items []models.TransaksiItemRequest) (string, error) {
-	return a.services.PromoService.DebugPromoValidation(promoKode, items)
+// DeleteUser soft deletes a user
+func (a *App) DeleteUser(idStr string) error {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %s", idStr)
+	}
+	log.Printf("Deleting user ID: %d", id)
+	return a.services.UserService.DeleteUser(id, a.aktor())
 }
 
-// PrintReceipt prints a transaction receipt
-func (a *App) PrintReceipt(req models.PrintReceiptRequest) error {
-	log.Printf("Printing receipt: %s", req.TransactionNo)
-	return a.services.PrinterService.PrintReceipt(&req)
+// GetAllUsers retrieves all users
+func (a *App) GetAllUsers() ([]*models.User, error) {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	log.Println("Getting all users")
+	return a.services.UserService.GetAllUsers()
 }
 
-// GetPrintSettings retrieves current print settings
-func (a *App) GetPrintSettings() (*models.PrintSettings, error) {
-	log.Println("[PRINTER SETTINGS] ========== GET SETTINGS ==========")
-	settings, err := a.services.PrinterService.GetPrintSettings()
+// GetAllStaff retrieves all staff users
+func (a *App) GetAllStaff() ([]*models.User, error) {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return nil, err
+	}
 
+	log.Println("Getting all staff")
+	return a.services.UserService.GetAllStaff()
+}
+
+// GetUserByID retrieves a user by ID
+func (a *App) GetUserByID(idStr string) (*models.User, error) {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		log.Printf("[PRINTER SETTINGS] ERROR: Failed to get settings: %v", err)
+		return nil, fmt.Errorf("invalid user ID: %s", idStr)
+	}
+	log.Printf("Getting user by ID: %d", id)
+	return a.services.UserService.GetUserByID(id)
+}
+
+// GetLoginHistory retrieves the latest login attempts of a user
+func (a *App) GetLoginHistory(idStr string, limit int) ([]*models.LoginEvent, error) {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
 		return nil, err
 	}
 
-	if settings == nil {
-		log.Println("[PRINTER SETTINGS] WARNING: Settings returned nil")
-		return nil, fmt.Errorf("settings not found")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %s", idStr)
 	}
+	return a.services.UserService.GetLoginHistory(id, limit)
+}
 
-	log.Printf("[PRINTER SETTINGS] Retrieved: PrinterName=%s, PaperSize=%s",
-		settings.PrinterName, settings.PaperSize)
-	log.Println("[PRINTER SETTINGS] ========== GET COMPLETE ==========")
+// ChangePassword changes user password
+func (a *App) ChangePassword(req models.ChangePasswordRequest) error {
+	log.Printf("Changing password for user ID: %d", req.UserID)
+	return a.services.UserService.ChangePassword(&req)
+}
 
-	return settings, nil
+// AdminChangePassword allows admin to change password without old password
+func (a *App) AdminChangePassword(req struct {
+	UserID      int64  `json:"userId"`
+	NewPassword string `json:"newPassword"`
+}) error {
+	if err := a.requirePermission(models.PermUserManage); err != nil {
+		return err
+	}
+
+	log.Printf("Admin changing password for user ID: %d", req.UserID)
+	return a.services.UserService.AdminChangePassword(req.UserID, req.NewPassword, a.aktor())
 }
 
-// SavePrintSettings saves print settings
-func (a *App) SavePrintSettings(settings models.PrintSettings) error {
-	log.Println("[PRINTER SETTINGS] ========== SAVE ATTEMPT ==========")
-	log.Printf("[PRINTER SETTINGS] Received settings: PrinterName=%s, PaperSize=%s, AutoPrint=%v",
-		settings.PrinterName, settings.PaperSize, settings.AutoPrint)
+// ==================== AUDIT API ====================
 
-	// Log to file
-	logToPrinterFile("========== SET DEFAULT PRINTER ==========")
-	logToPrinterFile(fmt.Sprintf("Printer Name: %s", settings.PrinterName))
+// GetAuditLog retrieves audit entries; empty filters are ignored
+func (a *App) GetAuditLog(entitas, entitasID, aktorID, aksi, startDate, endDate string, limit, offset int) ([]*models.AuditLog, error) {
+	if err := a.requirePermission(models.PermAuditView); err != nil {
+		return nil, err
+	}
 
-	// Validate settings
-	if settings.PrinterName == "" {
-		log.Println("[PRINTER SETTINGS] ERROR: Printer name is empty!")
-		logToPrinterFile("ERROR: Printer name is empty!")
-		return fmt.Errorf("printer name cannot be empty")
+	filter := &models.AuditFilter{
+		Entitas:   entitas,
+		EntitasID: entitasID,
+		Aksi:      aksi,
+		Limit:     limit,
+		Offset:    offset,
 	}
 
-	log.Println("[PRINTER SETTINGS] Calling service.SavePrintSettings...")
-	err := a.services.PrinterService.SavePrintSettings(&settings)
+	if aktorID != "" {
+		id, err := strconv.ParseInt(aktorID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actor ID: %s", aktorID)
+		}
+		filter.AktorID = id
+	}
 
-	if err != nil {
-		log.Printf("[PRINTER SETTINGS] ERROR: Save failed: %v", err)
-		logToPrinterFile(fmt.Sprintf("ERROR: Save failed - %v", err))
+	if startDate != "" {
+		start, err := a.parseDate(startDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date: %w", err)
+		}
+		filter.StartDate = start
+	}
+
+	if endDate != "" {
+		end, err := a.parseDate(endDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date: %w", err)
+		}
+		filter.EndDate = end.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return a.services.AuditService.GetAll(filter)
+}
+
+// ==================== SUPPLIER API ====================
+
+// GetAllSupplier retrieves suppliers, optionally only the active ones
+func (a *App) GetAllSupplier(aktifSaja bool) ([]*models.Supplier, error) {
+	return a.services.SupplierService.GetAllSupplier(aktifSaja)
+}
+
+// GetSupplierByID retrieves a supplier by ID
+func (a *App) GetSupplierByID(id int64) (*models.Supplier, error) {
+	return a.services.SupplierService.GetSupplierByID(id)
+}
+
+// CreateSupplier creates a new supplier
+func (a *App) CreateSupplier(supplier models.Supplier) (*models.Supplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.SupplierService.CreateSupplier(&supplier); err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// UpdateSupplier updates a supplier
+func (a *App) UpdateSupplier(supplier models.Supplier) (*models.Supplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.SupplierService.UpdateSupplier(&supplier); err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// DeleteSupplier deletes a supplier that no purchase order refers to
+func (a *App) DeleteSupplier(id int64) error {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
 		return err
 	}
 
-	log.Println("[PRINTER SETTINGS] SUCCESS: Settings saved to database")
-	logToPrinterFile(fmt.Sprintf("SUCCESS: Printer '%s' berhasil di set sebagai default", settings.PrinterName))
-	logToPrinterFile(fmt.Sprintf("Paper Size: %s, Auto Print: %v", settings.PaperSize, settings.AutoPrint))
+	return a.services.SupplierService.DeleteSupplier(id)
+}
 
-	// Verify save by reading back
-	log.Println("[PRINTER SETTINGS] Verifying saved settings...")
-	savedSettings, verifyErr := a.services.PrinterService.GetPrintSettings()
-	if verifyErr != nil {
-		log.Printf("[PRINTER SETTINGS] WARNING: Could not verify settings: %v", verifyErr)
-		logToPrinterFile(fmt.Sprintf("WARNING: Could not verify settings - %v", verifyErr))
-	} else {
-		log.Printf("[PRINTER SETTINGS] Verified: PrinterName=%s", savedSettings.PrinterName)
-		if savedSettings.PrinterName != settings.PrinterName {
-			log.Printf("[PRINTER SETTINGS] WARNING: Saved name mismatch! Expected=%s, Got=%s",
-				settings.PrinterName, savedSettings.PrinterName)
-			logToPrinterFile(fmt.Sprintf("WARNING: Mismatch! Expected=%s, Got=%s", settings.PrinterName, savedSettings.PrinterName))
-		} else {
-			logToPrinterFile("Verification: OK")
+// ==================== PEMBELIAN API ====================
+
+// GetAllPesananPembelian retrieves purchase orders, optionally filtered by status
+func (a *App) GetAllPesananPembelian(status string) ([]*models.PesananPembelian, error) {
+	// Staff penerima barang juga perlu melihat pesanan yang akan diterima
+	if a.requirePermission(models.PermPurchaseManage) != nil {
+		if err := a.requirePermission(models.PermStockReceive); err != nil {
+			return nil, err
 		}
 	}
 
-	logToPrinterFile("========================================")
-	log.Println("[PRINTER SETTINGS] ========== SAVE COMPLETE ==========")
-	return nil
+	return a.services.PembelianService.GetAllPesanan(status)
 }
 
-// TestPrintByName sends a test print to the specified printer by name
-func (a *App) TestPrintByName(printerName string) error {
-	log.Println("[TEST PRINT] ========== TEST PRINT START ==========")
-	log.Printf("[TEST PRINT] Printer: %s", printerName)
+// GetPesananPembelian retrieves a purchase order with its lines and goods receipts
+func (a *App) GetPesananPembelian(id int64) (*models.PesananPembelian, error) {
+	if a.requirePermission(models.PermPurchaseManage) != nil {
+		if err := a.requirePermission(models.PermStockReceive); err != nil {
+			return nil, err
+		}
+	}
 
-	// Log to file
-	logToPrinterFile("========== TEST PRINT ==========")
-	logToPrinterFile(fmt.Sprintf("Printer: %s", printerName))
+	return a.services.PembelianService.GetPesananByID(id)
+}
 
-	if printerName == "" {
-		log.Println("[TEST PRINT] ERROR: Printer name is empty!")
-		logToPrinterFile("ERROR: Printer name is empty!")
-		return fmt.Errorf("printer name cannot be empty")
+// CreatePesananPembelian creates a draft purchase order
+func (a *App) CreatePesananPembelian(req models.PesananPembelianRequest) (*models.PesananPembelian, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
 	}
 
-	err := a.services.PrinterService.TestPrint(printerName)
+	req.DibuatOleh = a.aktor().Nama
+	return a.services.PembelianService.CreatePesanan(&req)
+}
+
+// UpdatePesananPembelian edits a draft purchase order
+func (a *App) UpdatePesananPembelian(id int64, req models.PesananPembelianRequest) (*models.PesananPembelian, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.PembelianService.UpdatePesanan(id, &req)
+}
+
+// UbahStatusPesananPembelian sends, cancels or closes a purchase order
+func (a *App) UbahStatusPesananPembelian(id int64, status string) (*models.PesananPembelian, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.PembelianService.UbahStatusPesanan(id, status)
+}
+
+// TerimaBarang receives a (partial) delivery against a purchase order
+func (a *App) TerimaBarang(req models.PenerimaanRequest) (*models.PenerimaanBarang, error) {
+	if err := a.requirePermission(models.PermStockReceive); err != nil {
+		return nil, err
+	}
+
+	return a.services.PembelianService.TerimaBarang(&req, a.aktor())
+}
 
-	if err != nil {
-		log.Printf("[TEST PRINT] ERROR: Test print failed: %v", err)
-		logToPrinterFile(fmt.Sprintf("ERROR: Test print failed - %v", err))
-		return err
+// GetAllPenerimaan retrieves goods receipts between two dates (YYYY-MM-DD, both optional)
+func (a *App) GetAllPenerimaan(startDate, endDate string) ([]*models.PenerimaanBarang, error) {
+	if a.requirePermission(models.PermPurchaseManage) != nil {
+		if err := a.requirePermission(models.PermStockReceive); err != nil {
+			return nil, err
+		}
 	}
 
-	log.Println("[TEST PRINT] SUCCESS: Test print sent successfully")
-	logToPrinterFile(fmt.Sprintf("SUCCESS: Test print ke '%s' berhasil dikirim", printerName))
-	logToPrinterFile("========================================")
+	var start, end time.Time
+	if startDate != "" {
+		parsed, err := a.parseDate(startDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date: %w", err)
+		}
+		start = parsed
+	}
+	if endDate != "" {
+		parsed, err := a.parseDate(endDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date: %w", err)
+		}
+		end = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
 
-	log.Println("[TEST PRINT] ========== TEST PRINT COMPLETE ==========")
-	return nil
+	return a.services.PembelianService.GetAllPenerimaan(start, end)
 }
 
-// ==================== ANALYTICS API ====================
+// GetPenerimaan retrieves a goods receipt with its lines
+func (a *App) GetPenerimaan(id int64) (*models.PenerimaanBarang, error) {
+	if a.requirePermission(models.PermPurchaseManage) != nil {
+		if err := a.requirePermission(models.PermStockReceive); err != nil {
+			return nil, err
+		}
+	}
 
-// GetTopProducts retrieves top selling products within date range
-func (a *App) GetTopProducts(startDate, endDate string, limit int) ([]*models.TopProductsResponse, error) {
-	log.Printf("Getting top products from %s to %s (limit: %d)", startDate, endDate, limit)
-	return a.services.AnalyticsService.GetTopProducts(startDate, endDate, limit)
+	return a.services.PembelianService.GetPenerimaanByID(id)
 }
 
-// GetPaymentMethodBreakdown retrieves payment method statistics
-func (a *App) GetPaymentMethodBreakdown(startDate, endDate string) ([]*models.PaymentBreakdownResponse, error) {
-	log.Printf("Getting payment breakdown from %s to %s", startDate, endDate)
-	return a.services.AnalyticsService.GetPaymentMethodBreakdown(startDate, endDate)
+// GetAllReturSupplier retrieves supplier returns, optionally filtered by status
+func (a *App) GetAllReturSupplier(status string) ([]*models.ReturSupplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturSupplierService.GetAllRetur(status)
 }
 
-// GetSalesTrend retrieves sales trend over time
-func (a *App) GetSalesTrend(startDate, endDate string) ([]*models.SalesTrendResponse, error) {
-	log.Printf("Getting sales trend from %s to %s", startDate, endDate)
-	return a.services.AnalyticsService.GetSalesTrend(startDate, endDate)
+// GetReturSupplier retrieves a supplier return with its lines
+func (a *App) GetReturSupplier(id int64) (*models.ReturSupplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturSupplierService.GetReturByID(id)
 }
 
-// GetCategoryBreakdown retrieves sales by category
-func (a *App) GetCategoryBreakdown(startDate, endDate string) ([]*models.CategoryBreakdownResponse, error) {
-	log.Printf("Getting category breakdown from %s to %s", startDate, endDate)
-	return a.services.AnalyticsService.GetCategoryBreakdown(startDate, endDate)
+// CreateReturSupplier returns picked batches to a supplier and takes them out of stock
+func (a *App) CreateReturSupplier(req models.ReturSupplierRequest) (*models.ReturSupplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturSupplierService.CreateRetur(&req, a.aktor())
 }
 
-// GetHourlySales retrieves sales grouped by hour and day
-func (a *App) GetHourlySales(startDate, endDate string) ([]*models.HourlySalesResponse, error) {
-	log.Printf("Getting hourly sales from %s to %s", startDate, endDate)
-	return a.services.AnalyticsService.GetHourlySales(startDate, endDate)
+// SelesaikanReturSupplier records the credit note or replacement received for a supplier return
+func (a *App) SelesaikanReturSupplier(id int64, req models.PenyelesaianReturSupplierRequest) (*models.ReturSupplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturSupplierService.SelesaikanRetur(id, &req, a.aktor())
 }
 
-// GetSalesInsights retrieves comprehensive sales insights
-func (a *App) GetSalesInsights(startDate, endDate string) (*models.SalesInsightsResponse, error) {
-	log.Printf("Getting sales insights from %s to %s", startDate, endDate)
-	return a.services.AnalyticsService.GetSalesInsights(startDate, endDate)
+// BatalkanReturSupplier cancels a waiting supplier return and restores its stock
+func (a *App) BatalkanReturSupplier(id int64) (*models.ReturSupplier, error) {
+	if err := a.requirePermission(models.PermPurchaseManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.ReturSupplierService.BatalkanRetur(id, a.aktor())
 }
 
-// ==================== PELANGGAN STATS API ====================
+// ==================== STOK OPNAME API ====================
 
-func (a *App) GetPelangganWithStats(pelangganIDStr string) (*models.PelangganDetail, error) {
-	// Convert string to int64
-	pelangganID, err := strconv.ParseInt(pelangganIDStr, 10, 64)
-	if err != nil {
-		log.Printf("Error converting pelangganID to int64: %v", err)
-		return nil, fmt.Errorf("ID pelanggan tidak valid: %s", pelangganIDStr)
+// GetAllOpname retrieves stock count sessions, optionally filtered by status
+func (a *App) GetAllOpname(status string) ([]*models.StokOpname, error) {
+	if a.requirePermission(models.PermStockOpname) != nil {
+		if err := a.requirePermission(models.PermStockCount); err != nil {
+			return nil, err
+		}
 	}
 
-	log.Printf("Getting customer with stats for ID: %d", pelangganID)
-
-	// Use pelangganService yang sudah memiliki transaksiRepo
-	return a.services.PelangganService.GetPelangganWithStats(pelangganID)
+	return a.services.OpnameService.GetAllOpname(status)
 }
 
-// GetTransaksiByPelanggan retrieves all transactions for a customer
-func (a *App) GetTransaksiByPelanggan(pelangganID int64) ([]*models.Transaksi, error) {
-	log.Printf("Getting transactions for customer ID: %d", pelangganID)
-	return a.services.TransaksiService.GetTransaksiByPelangganID(pelangganID)
-}
+// GetOpname retrieves a stock count session with its lines
+func (a *App) GetOpname(id int64) (*models.StokOpname, error) {
+	if a.requirePermission(models.PermStockOpname) != nil {
+		if err := a.requirePermission(models.PermStockCount); err != nil {
+			return nil, err
+		}
+	}
 
-// ==================== USER MANAGEMENT API ====================
+	return a.services.OpnameService.GetOpnameByID(id)
+}
 
-// Login authenticates a user
-func (a *App) Login(req models.LoginRequest) (*models.LoginResponse, error) {
-	log.Printf("Login attempt for username: %s", req.Username)
-	response, err := a.services.UserService.Login(&req)
-	if err != nil {
-		log.Printf("[APP LOGIN ERROR] %v", err)
+// BukaOpname opens a stock count session over all products or one category
+func (a *App) BukaOpname(req models.BukaOpnameRequest) (*models.StokOpname, error) {
+	if err := a.requirePermission(models.PermStockOpname); err != nil {
 		return nil, err
 	}
-	if response != nil && response.User != nil {
-		log.Printf("[APP LOGIN] Returning response to frontend - User ID: %d, Username: %s, NamaLengkap: %s",
-			response.User.ID, response.User.Username, response.User.NamaLengkap)
+
+	return a.services.OpnameService.BukaOpname(&req, a.aktor())
+}
+
+// CatatHitunganOpname records a counted quantity in a stock count session
+func (a *App) CatatHitunganOpname(id int64, req models.HitungOpnameRequest) (*models.StokOpnameItem, error) {
+	if err := a.requirePermission(models.PermStockCount); err != nil {
+		return nil, err
 	}
-	return response, nil
+
+	return a.services.OpnameService.CatatHitungan(id, &req, a.aktor())
 }
 
-// CreateUser creates a new user (admin or staff)
-func (a *App) CreateUser(req models.CreateUserRequest) error {
-	log.Printf("Creating new user: %s (role: %s)", req.Username, req.Role)
-	return a.services.UserService.CreateUser(&req)
+// GetLaporanSelisihOpname retrieves the variance report of a stock count session
+func (a *App) GetLaporanSelisihOpname(id int64) (*models.LaporanSelisihOpname, error) {
+	if a.requirePermission(models.PermStockOpname) != nil {
+		if err := a.requirePermission(models.PermStockCount); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.services.OpnameService.LaporanSelisih(id)
 }
 
-// UpdateUser updates user information
-func (a *App) UpdateUser(req models.UpdateUserRequest) error {
-	log.Printf("Updating user ID: %d", req.ID)
-	return a.services.UserService.UpdateUser(&req)
+// PostingOpname approves the variances of a stock count session and adjusts stock
+func (a *App) PostingOpname(id int64, req models.PostingOpnameRequest) (*models.LaporanSelisihOpname, error) {
+	if err := a.requirePermission(models.PermStockOpname); err != nil {
+		return nil, err
+	}
+
+	return a.services.OpnameService.PostingOpname(id, &req, a.aktor())
 }
 
-// DeleteUser soft deletes a user
-func (a *App) DeleteUser(idStr string) error {
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid user ID: %s", idStr)
+// BatalkanOpname cancels an open stock count session
+func (a *App) BatalkanOpname(id int64) (*models.StokOpname, error) {
+	if err := a.requirePermission(models.PermStockOpname); err != nil {
+		return nil, err
 	}
-	log.Printf("Deleting user ID: %d", id)
-	return a.services.UserService.DeleteUser(id)
+
+	return a.services.OpnameService.BatalkanOpname(id)
 }
 
-// GetAllUsers retrieves all users
-func (a *App) GetAllUsers() ([]*models.User, error) {
-	log.Println("Getting all users")
-	return a.services.UserService.GetAllUsers()
+// ==================== ROLE API ====================
+
+// GetAllRole retrieves all roles with their permissions
+func (a *App) GetAllRole() ([]*models.Role, error) {
+	// Role list is also needed when assigning a role to a user
+	if a.requirePermission(models.PermRoleManage) != nil {
+		if err := a.requirePermission(models.PermUserManage); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.services.RoleService.GetAllRole()
 }
 
-// GetAllStaff retrieves all staff users
-func (a *App) GetAllStaff() ([]*models.User, error) {
-	log.Println("Getting all staff")
-	return a.services.UserService.GetAllStaff()
+// GetDaftarPermission lists every permission a role can be granted
+func (a *App) GetDaftarPermission() ([]models.Permission, error) {
+	if err := a.requirePermission(models.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	return a.services.RoleService.GetDaftarPermission(), nil
 }
 
-// GetUserByID retrieves a user by ID
-func (a *App) GetUserByID(idStr string) (*models.User, error) {
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %s", idStr)
+// CreateRole creates a custom role
+func (a *App) CreateRole(role models.Role) (*models.Role, error) {
+	if err := a.requirePermission(models.PermRoleManage); err != nil {
+		return nil, err
 	}
-	log.Printf("Getting user by ID: %d", id)
-	return a.services.UserService.GetUserByID(id)
+
+	if err := a.services.RoleService.CreateRole(&role, a.aktor()); err != nil {
+		return nil, err
+	}
+	return &role, nil
 }
 
-// ChangePassword changes user password
-func (a *App) ChangePassword(req models.ChangePasswordRequest) error {
-	log.Printf("Changing password for user ID: %d", req.UserID)
-	return a.services.UserService.ChangePassword(&req)
+// UpdateRole updates the description and permissions of a role
+func (a *App) UpdateRole(role models.Role) (*models.Role, error) {
+	if err := a.requirePermission(models.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	if err := a.services.RoleService.UpdateRole(&role, a.aktor()); err != nil {
+		return nil, err
+	}
+	return &role, nil
 }
 
-// AdminChangePassword allows admin to change password without old password
-func (a *App) AdminChangePassword(req struct {
-	UserID      int64  `json:"userId"`
-	NewPassword string `json:"newPassword"`
-}) error {
-	log.Printf("Admin changing password for user ID: %d", req.UserID)
-	return a.services.UserService.AdminChangePassword(req.UserID, req.NewPassword)
+// DeleteRole deletes a custom role that no user has
+func (a *App) DeleteRole(id int64) error {
+	if err := a.requirePermission(models.PermRoleManage); err != nil {
+		return err
+	}
+
+	return a.services.RoleService.DeleteRole(id, a.aktor())
 }
 
 // ==================== STAFF REPORTS API ====================
 
 // GetStaffReport generates performance report for a specific staff
 func (a *App) GetStaffReport(staffIDStr string, startDate, endDate string) (*models.StaffReport, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
@@ -735,6 +2165,10 @@ func (a *App) GetStaffReport(staffIDStr string, startDate, endDate string) (*mod
 
 // GetStaffReportDetail gets detailed report with transaction list
 func (a *App) GetStaffReportDetail(staffIDStr string, startDate, endDate string) (*models.StaffReportDetailWithItems, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
@@ -757,6 +2191,10 @@ func (a *App) GetStaffReportDetail(staffIDStr string, startDate, endDate string)
 
 // GetAllStaffReports gets reports for all staff
 func (a *App) GetAllStaffReports(startDate, endDate string) ([]*models.StaffReport, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Getting all staff reports from %s to %s", startDate, endDate)
 
 	// Parse dates
@@ -775,12 +2213,20 @@ func (a *App) GetAllStaffReports(startDate, endDate string) ([]*models.StaffRepo
 
 // GetAllStaffReportsWithTrend gets all staff reports with trend comparison
 func (a *App) GetAllStaffReportsWithTrend() ([]*models.StaffReportWithTrend, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	log.Println("Getting all staff reports with trend")
 	return a.services.StaffReportService.GetAllStaffReportsWithTrend()
 }
 
 // GetStaffReportWithTrend gets staff report with trend for specific date range
 func (a *App) GetStaffReportWithTrend(staffIDStr string, startDate, endDate string) (*models.StaffReportWithTrend, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
@@ -805,6 +2251,10 @@ func (a *App) GetStaffReportWithTrend(staffIDStr string, startDate, endDate stri
 
 // ForcePushSync triggers a manual sync from Desktop to Web (Push)
 func (a *App) ForcePushSync() error {
+	if err := a.requirePermission(models.PermSyncManage); err != nil {
+		return err
+	}
+
 	log.Println("[APP] 🚀 Manual Push Sync Triggered")
 	if sync.Engine != nil {
 		return sync.Engine.TriggerInitialSync(true) // Force = true
@@ -814,6 +2264,10 @@ func (a *App) ForcePushSync() error {
 
 // ForcePullSync triggers a manual sync from Web to Desktop (Pull)
 func (a *App) ForcePullSync() error {
+	if err := a.requirePermission(models.PermSyncManage); err != nil {
+		return err
+	}
+
 	log.Println("[APP] 🚀 Manual Pull Sync Triggered")
 	if sync.Engine != nil {
 		return sync.Engine.TriggerForcePull()
@@ -823,16 +2277,28 @@ func (a *App) ForcePullSync() error {
 
 // GetShiftSettings returns current shift configurations
 func (a *App) GetShiftSettings() ([]models.ShiftSetting, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetShiftSettings()
 }
 
 // UpdateShiftSettings updates a shift configuration
 func (a *App) UpdateShiftSettings(id int, startTime, endTime, staffIDs string) error {
+	if err := a.requirePermission(models.PermShiftManage); err != nil {
+		return err
+	}
+
 	return a.services.StaffReportService.UpdateShiftSettings(id, startTime, endTime, staffIDs)
 }
 
 // GetStaffHistoricalData gets historical data for charts
 func (a *App) GetStaffHistoricalData(staffIDStr string) (*models.StaffHistoricalData, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
@@ -842,16 +2308,28 @@ func (a *App) GetStaffHistoricalData(staffIDStr string) (*models.StaffHistorical
 
 // GetComprehensiveStaffReport gets comprehensive analytics for last 30 days
 func (a *App) GetComprehensiveStaffReport() (*models.ComprehensiveStaffReport, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetComprehensiveReport()
 }
 
 // GetShiftProductivity gets sales distribution by shift (morning, afternoon, night)
 func (a *App) GetShiftProductivity() (map[string]int, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetShiftProductivity()
 }
 
 // GetStaffReportWithMonthlyTrend gets staff report with trend vs previous month
 func (a *App) GetStaffReportWithMonthlyTrend(staffIDStr string, startDate, endDate string) (*models.StaffReportWithTrend, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
@@ -873,6 +2351,10 @@ func (a *App) GetStaffReportWithMonthlyTrend(staffIDStr string, startDate, endDa
 
 // GetStaffShiftData gets shift productivity data for a specific staff
 func (a *App) GetStaffShiftData(staffIDStr string, startDate, endDate string) (map[string]map[string]interface{}, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
@@ -892,23 +2374,61 @@ func (a *App) GetStaffShiftData(staffIDStr string, startDate, endDate string) (m
 	return a.services.StaffReportService.GetStaffShiftData(staffID, start, end)
 }
 
+// GetLaporanAbsensi gets the monthly attendance report of a staff member
+func (a *App) GetLaporanAbsensi(staffIDStr string, tahun, bulan int) (*models.LaporanAbsensi, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
+	staffID, err := strconv.ParseInt(staffIDStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staff ID: %s", staffIDStr)
+	}
+	return a.services.StaffReportService.GetLaporanAbsensi(staffID, tahun, bulan)
+}
+
+// GetAllLaporanAbsensi gets the monthly attendance report of all staff
+func (a *App) GetAllLaporanAbsensi(tahun, bulan int) ([]*models.LaporanAbsensi, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
+	return a.services.StaffReportService.GetAllLaporanAbsensi(tahun, bulan)
+}
+
 // GetShiftReports gets overall shift reports for today
 func (a *App) GetShiftReports(dateStr string) (*models.ShiftReportsResponse, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetShiftReports(dateStr)
 }
 
 // GetShiftCashiers gets active cashiers for a shift
 func (a *App) GetShiftCashiers(shift string) ([]*models.ShiftCashier, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetShiftCashiers(shift)
 }
 
 // GetShiftDetail gets detailed report for a specific shift and date
 func (a *App) GetShiftDetail(shift string, dateStr string) (*models.ShiftDetailResponse, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetShiftDetail(shift, dateStr)
 }
 
 // GetMonthlyComparisonTrend gets 30-day comparison with previous 30 days
 func (a *App) GetMonthlyComparisonTrend() (map[string]interface{}, error) {
+	if err := a.requirePermission(models.PermReportStaffView); err != nil {
+		return nil, err
+	}
+
 	return a.services.StaffReportService.GetMonthlyComparisonTrend()
 }
 
@@ -916,6 +2436,9 @@ func (a *App) GetMonthlyComparisonTrend() (map[string]interface{}, error) {
 
 // GetComprehensiveSalesReport gets comprehensive sales report for a date range
 func (a *App) GetComprehensiveSalesReport(startDate, endDate string) (*models.ComprehensiveSalesReport, error) {
+	if err := a.requirePermission(models.PermReportProfitView); err != nil {
+		return nil, err
+	}
 
 	start, err := a.parseDate(startDate)
 	if err != nil {
@@ -930,6 +2453,26 @@ func (a *App) GetComprehensiveSalesReport(startDate, endDate string) (*models.Co
 	return a.services.SalesReportService.GetComprehensiveSalesReport(start, end)
 }
 
+// GetLaporanPajak gets the output tax (PPN) report for a date range
+func (a *App) GetLaporanPajak(startDate, endDate string) (*models.LaporanPajak, error) {
+	if err := a.requirePermission(models.PermReportProfitView); err != nil {
+		return nil, err
+	}
+
+	start, err := a.parseDate(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	end, err := a.parseDate(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	// Include the whole last day
+	return a.services.SalesReportService.GetLaporanPajak(start, end.Add(24*time.Hour-time.Nanosecond))
+}
+
 // ==================== HELPER METHODS ====================
 
 // parseDate parses date string in YYYY-MM-DD format
@@ -941,11 +2484,19 @@ func (a *App) parseDate(dateStr string) (time.Time, error) {
 
 // GetDashboardData returns all main dashboard data (statistik, notifikasi, performa, produk terlaris, aktivitas)
 func (a *App) GetDashboardData() (*models.DashboardData, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
 	return a.services.DashboardService.GetDashboardData()
 }
 
 // GetDashboardSalesChart returns sales trend data for hari/minggu/bulan periods
 func (a *App) GetDashboardSalesChart() (*models.DashboardSalesChartResponse, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
 	salesData, err := a.services.DashboardService.GetSalesChartData()
 	if err != nil {
 		return nil, err
@@ -958,6 +2509,10 @@ func (a *App) GetDashboardSalesChart() (*models.DashboardSalesChartResponse, err
 // Di file App.go Anda
 
 func (a *App) GetDashboardCompositionChart() (map[string]interface{}, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
 	data, err := a.services.DashboardService.GetCompositionChartData()
 	if err != nil {
 		return nil, err
@@ -970,6 +2525,10 @@ func (a *App) GetDashboardCompositionChart() (map[string]interface{}, error) {
 }
 
 func (a *App) GetDashboardCategoryChart() (map[string]interface{}, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
+
 	data, err := a.services.DashboardService.GetCategoryChartData()
 	if err != nil {
 		return nil, err
@@ -983,6 +2542,9 @@ func (a *App) GetDashboardCategoryChart() (map[string]interface{}, error) {
 
 // GetSalesByPeriodForChart gets sales data for the transaction chart based on a filter
 func (a *App) GetSalesByPeriodForChart(filterType string) (map[string]interface{}, error) {
+	if err := a.requirePermission(models.PermReportSalesView); err != nil {
+		return nil, err
+	}
 
 	// Pastikan salesReportService sudah diinisialisasi di struct App
 	if a.services.SalesReportService == nil {
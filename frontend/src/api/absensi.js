@@ -0,0 +1,84 @@
+/**
+ * Absensi API Module
+ * Handles staff clock-in/out and breaks in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const absensiAPI = {
+  /**
+   * Get the attendance the staff member has not clocked out of yet
+   * @param {string} staffId - used in desktop mode; web mode uses the logged-in user
+   * @returns {Promise<object|null>}
+   */
+  getAktif: async (staffId) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/absensi/aktif');
+      return response.data;
+    } else {
+      const { GetAbsensiAktif } = await import('../../wailsjs/go/main/App');
+      return await GetAbsensiAktif(staffId);
+    }
+  },
+
+  /**
+   * Clock in
+   * @param {object} request - { username, pin, catatan } or { staffId, staffNama, catatan }
+   * @returns {Promise<object>}
+   */
+  clockIn: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/absensi/masuk', request);
+      return response.data;
+    } else {
+      const { ClockIn } = await import('../../wailsjs/go/main/App');
+      return await ClockIn(request);
+    }
+  },
+
+  /**
+   * Clock out; an open break is ended first
+   * @param {object} request - { username, pin, catatan } or { staffId, staffNama, catatan }
+   * @returns {Promise<object>}
+   */
+  clockOut: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/absensi/pulang', request);
+      return response.data;
+    } else {
+      const { ClockOut } = await import('../../wailsjs/go/main/App');
+      return await ClockOut(request);
+    }
+  },
+
+  /**
+   * Start a break
+   * @param {object} request - { username, pin } or { staffId, staffNama }
+   * @returns {Promise<object>}
+   */
+  mulaiIstirahat: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/absensi/istirahat/mulai', request);
+      return response.data;
+    } else {
+      const { MulaiIstirahat } = await import('../../wailsjs/go/main/App');
+      return await MulaiIstirahat(request);
+    }
+  },
+
+  /**
+   * End the current break
+   * @param {object} request - { username, pin } or { staffId, staffNama }
+   * @returns {Promise<object>}
+   */
+  selesaiIstirahat: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/absensi/istirahat/selesai', request);
+      return response.data;
+    } else {
+      const { SelesaiIstirahat } = await import('../../wailsjs/go/main/App');
+      return await SelesaiIstirahat(request);
+    }
+  },
+};
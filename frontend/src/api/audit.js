@@ -0,0 +1,49 @@
+/**
+ * Audit API Module
+ * Read-only access to the audit trail of data changes in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const auditAPI = {
+  /**
+   * Get audit entries, newest first. Empty filters are ignored.
+   * @param {object} filter - { entitas, entitasId, aktorId, aksi, startDate, endDate, limit, offset }
+   *   entitas: "produk", "pelanggan", "user", "role" or "settings"
+   *   aksi: "create", "update" or "delete"
+   *   startDate/endDate: YYYY-MM-DD
+   * @returns {Promise<Array>} entries with perubahan: { field: { sebelum, sesudah } }
+   */
+  getAll: async (filter = {}) => {
+    const {
+      entitas = '',
+      entitasId = '',
+      aktorId = '',
+      aksi = '',
+      startDate = '',
+      endDate = '',
+      limit = 100,
+      offset = 0,
+    } = filter;
+
+    if (isWebMode()) {
+      const response = await client.get('/api/audit', {
+        params: {
+          entitas,
+          entitas_id: entitasId,
+          aktor_id: aktorId,
+          aksi,
+          start_date: startDate,
+          end_date: endDate,
+          limit,
+          offset,
+        }
+      });
+      return response.data;
+    } else {
+      const { GetAuditLog } = await import('../../wailsjs/go/main/App');
+      return await GetAuditLog(entitas, String(entitasId), String(aktorId), aksi, startDate, endDate, limit, offset);
+    }
+  },
+};
@@ -14,6 +14,23 @@ if (!isWebMode()) {
   });
 }
 
+const TERMINAL_KEY = 'terminalId';
+
+/**
+ * Terminal code of this browser (web mode). Every browser is its own register, so the code
+ * is generated once and kept across logouts; the server's own code is never assumed.
+ * @returns {string}
+ */
+export const getWebTerminalId = () => {
+  let terminalId = localStorage.getItem(TERMINAL_KEY);
+  if (!terminalId) {
+    const acak = Math.random().toString(36).slice(2, 8).toUpperCase().padEnd(6, '0');
+    terminalId = `W${acak}`;
+    localStorage.setItem(TERMINAL_KEY, terminalId);
+  }
+  return terminalId;
+};
+
 export const authAPI = {
   /**
    * Login user
@@ -27,6 +44,7 @@ export const authAPI = {
       const response = await client.post('/api/auth/login', {
         username,
         password,
+        terminalId: getWebTerminalId(),
       });
 
       if (response.success && response.data.token) {
@@ -48,6 +66,72 @@ export const authAPI = {
     }
   },
 
+  /**
+   * Switch the active user on a shared terminal with a numeric PIN
+   * @param {string} username
+   * @param {string} pin
+   * @param {string} [terminalId] - Defaults to this device's terminal code
+   * @returns {Promise<{success: boolean, user: object, token?: string, terminalId?: string}>}
+   */
+  pinLogin: async (username, pin, terminalId = '') => {
+    if (isWebMode()) {
+      const response = await client.post('/api/auth/pin-login', {
+        username,
+        pin,
+        terminalId: terminalId || getWebTerminalId(),
+      });
+
+      if (response.success && response.data.token) {
+        localStorage.setItem('token', response.data.token);
+        localStorage.setItem('user', JSON.stringify(response.data.user));
+      }
+
+      return response;
+    } else {
+      const { LoginPin } = await import('../../wailsjs/go/main/App');
+      const response = await LoginPin({ username, pin, terminalId });
+
+      if (response.success && response.user) {
+        localStorage.setItem('user', JSON.stringify(response.user));
+      }
+
+      return response;
+    }
+  },
+
+  /**
+   * Lock the terminal so the next cashier can sign in
+   */
+  lock: async () => {
+    if (isWebMode()) {
+      const response = await client.post('/api/auth/lock');
+      localStorage.removeItem('token');
+      localStorage.removeItem('user');
+      return response;
+    } else {
+      const { KunciTerminal } = await import('../../wailsjs/go/main/App');
+      await KunciTerminal();
+      localStorage.removeItem('user');
+    }
+  },
+
+  /**
+   * Get the lock screen state of a terminal
+   * @param {string} [terminalId] - Defaults to this device's terminal code
+   * @returns {Promise<{terminalId: string, terkunci: boolean, username: string, namaLengkap: string}>}
+   */
+  terminalStatus: async (terminalId = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/auth/terminal', {
+        params: { kode: terminalId || getWebTerminalId() },
+      });
+      return response.data;
+    } else {
+      const { GetStatusTerminal } = await import('../../wailsjs/go/main/App');
+      return await GetStatusTerminal();
+    }
+  },
+
   /**
    * Get current user info
    * @returns {Promise<object>}
@@ -100,8 +184,17 @@ export const authAPI = {
    * Logout user
    */
   logout: () => {
+    const terminalId = localStorage.getItem(TERMINAL_KEY);
     localStorage.removeItem('token');
     localStorage.removeItem('user');
     localStorage.clear();
+    if (terminalId) {
+      localStorage.setItem(TERMINAL_KEY, terminalId);
+    }
+
+    // End the desktop session so the bindings stop accepting calls
+    if (!isWebMode()) {
+      import('../../wailsjs/go/main/App').then(({ Logout }) => Logout());
+    }
   },
 };
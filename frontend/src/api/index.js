@@ -19,4 +19,18 @@ export { salesReportAPI } from './sales-report';
 export { printerAPI } from './printer';
 export { hardwareAPI } from './hardware';
 export { settingsAPI } from './settings';
+export { metodePembayaranAPI } from './metode-pembayaran';
+export { piutangAPI } from './piutang';
+export { sesiKasAPI } from './sesi-kas';
+export { laciKasAPI } from './laci-kas';
+export { otorisasiAPI } from './otorisasi';
+export { auditAPI } from './audit';
+export { supplierAPI } from './supplier';
+export { pembelianAPI } from './pembelian';
+export { opnameAPI } from './opname';
+export { returSupplierAPI } from './retur-supplier';
+export { absensiAPI } from './absensi';
+export { komisiAPI } from './komisi';
+export { targetAPI } from './target';
+export { roleAPI } from './role';
 export { syncAPI } from './sync';
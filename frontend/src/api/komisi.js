@@ -0,0 +1,172 @@
+/**
+ * Komisi API Module
+ * Handles staff commission rules, payout periods and payroll export in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const komisiAPI = {
+  /**
+   * Get all commission rules
+   * @returns {Promise<Array>}
+   */
+  getAllAturan: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/komisi/aturan');
+      return response.data;
+    } else {
+      const { GetAllAturanKomisi } = await import('../../wailsjs/go/main/App');
+      return await GetAllAturanKomisi();
+    }
+  },
+
+  /**
+   * Create a commission rule
+   * @param {object} aturan - { nama, tipe, persen, nominalPerUnit, produkIds, kategori, staffIds, tingkat, aktif }
+   * @returns {Promise<object>}
+   */
+  createAturan: async (aturan) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/komisi/aturan', aturan);
+      return response.data;
+    } else {
+      const { CreateAturanKomisi } = await import('../../wailsjs/go/main/App');
+      return await CreateAturanKomisi(aturan);
+    }
+  },
+
+  /**
+   * Update a commission rule
+   * @param {object} aturan - must include id
+   * @returns {Promise<object>}
+   */
+  updateAturan: async (aturan) => {
+    if (isWebMode()) {
+      const response = await client.put(`/api/komisi/aturan/${aturan.id}`, aturan);
+      return response.data;
+    } else {
+      const { UpdateAturanKomisi } = await import('../../wailsjs/go/main/App');
+      return await UpdateAturanKomisi(aturan);
+    }
+  },
+
+  /**
+   * Delete a commission rule
+   * @param {string} id
+   * @returns {Promise<void>}
+   */
+  deleteAturan: async (id) => {
+    if (isWebMode()) {
+      await client.delete(`/api/komisi/aturan/${id}`);
+    } else {
+      const { DeleteAturanKomisi } = await import('../../wailsjs/go/main/App');
+      await DeleteAturanKomisi(id);
+    }
+  },
+
+  /**
+   * Preview the commission of all staff for a date range
+   * @param {string} startDate - YYYY-MM-DD
+   * @param {string} endDate - YYYY-MM-DD
+   * @returns {Promise<Array>}
+   */
+  hitung: async (startDate, endDate) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/komisi/hitung', {
+        params: { start_date: startDate, end_date: endDate }
+      });
+      return response.data;
+    } else {
+      const { HitungKomisi } = await import('../../wailsjs/go/main/App');
+      return await HitungKomisi(startDate, endDate);
+    }
+  },
+
+  /**
+   * Get saved payout periods
+   * @returns {Promise<Array>}
+   */
+  getAllPeriode: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/komisi/periode');
+      return response.data;
+    } else {
+      const { GetAllPeriodeKomisi } = await import('../../wailsjs/go/main/App');
+      return await GetAllPeriodeKomisi();
+    }
+  },
+
+  /**
+   * Get a payout period with its staff lines
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getPeriode: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/komisi/periode/${id}`);
+      return response.data;
+    } else {
+      const { GetPeriodeKomisi } = await import('../../wailsjs/go/main/App');
+      return await GetPeriodeKomisi(id);
+    }
+  },
+
+  /**
+   * Calculate and save the payout of a period as a draft
+   * @param {object} request - { periodeMulai, periodeSelesai, catatan }
+   * @returns {Promise<object>}
+   */
+  buatPeriode: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/komisi/periode', request);
+      return response.data;
+    } else {
+      const { BuatPeriodeKomisi } = await import('../../wailsjs/go/main/App');
+      return await BuatPeriodeKomisi(request);
+    }
+  },
+
+  /**
+   * Mark a draft payout as paid
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  tandaiDibayar: async (id) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/komisi/periode/${id}/bayar`);
+      return response.data;
+    } else {
+      const { TandaiKomisiDibayar } = await import('../../wailsjs/go/main/App');
+      return await TandaiKomisiDibayar(id);
+    }
+  },
+
+  /**
+   * Delete a draft payout
+   * @param {string} id
+   * @returns {Promise<void>}
+   */
+  deletePeriode: async (id) => {
+    if (isWebMode()) {
+      await client.delete(`/api/komisi/periode/${id}`);
+    } else {
+      const { DeletePeriodeKomisi } = await import('../../wailsjs/go/main/App');
+      await DeletePeriodeKomisi(id);
+    }
+  },
+
+  /**
+   * Export a payout as CSV for payroll
+   * @param {string} id
+   * @returns {Promise<string>} CSV content
+   */
+  exportPayroll: async (id) => {
+    if (isWebMode()) {
+      return await client.get(`/api/komisi/periode/${id}/export`, { responseType: 'text' });
+    } else {
+      const { ExportKomisiPayroll } = await import('../../wailsjs/go/main/App');
+      return await ExportKomisiPayroll(id);
+    }
+  },
+};
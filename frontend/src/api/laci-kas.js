@@ -0,0 +1,49 @@
+/**
+ * Laci Kas API Module
+ * Handles "no sale" cash drawer openings and the drawer log in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const laciKasAPI = {
+  /**
+   * Open the cash drawer without a sale
+   * @param {object} request - { alasan, staffId, staffNama, overrideToken }
+   * @returns {Promise<object>}
+   */
+  noSale: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/laci-kas/no-sale', request);
+      return response.data;
+    } else {
+      const { BukaLaciNoSale } = await import('../../wailsjs/go/main/App');
+      return await BukaLaciNoSale(request);
+    }
+  },
+
+  /**
+   * Get drawer openings within a date range
+   * @param {string} startDate - YYYY-MM-DD
+   * @param {string} endDate - YYYY-MM-DD
+   * @param {string} staffId - empty for all staff
+   * @param {string} tipe - "transaksi", "no_sale" or empty
+   * @returns {Promise<Array>}
+   */
+  getLog: async (startDate, endDate, staffId = '', tipe = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/laci-kas/log', {
+        params: {
+          start_date: startDate,
+          end_date: endDate,
+          staff_id: staffId || undefined,
+          tipe: tipe || undefined,
+        }
+      });
+      return response.data;
+    } else {
+      const { GetLogLaciKas } = await import('../../wailsjs/go/main/App');
+      return await GetLogLaciKas(staffId || 0, tipe, startDate, endDate);
+    }
+  },
+};
@@ -0,0 +1,70 @@
+/**
+ * Metode Pembayaran API Module
+ * Handles payment method configuration in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const metodePembayaranAPI = {
+  /**
+   * Get payment methods
+   * @param {boolean} aktifSaja - only return active methods
+   * @returns {Promise<Array>}
+   */
+  getAll: async (aktifSaja = false) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/metode-pembayaran', {
+        params: { aktif: aktifSaja }
+      });
+      return response.data;
+    } else {
+      const { GetAllMetodePembayaran } = await import('../../wailsjs/go/main/App');
+      return await GetAllMetodePembayaran(aktifSaja);
+    }
+  },
+
+  /**
+   * Create new payment method
+   * @param {object} metode
+   * @returns {Promise<object>}
+   */
+  create: async (metode) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/metode-pembayaran', metode);
+      return response.data;
+    } else {
+      const { CreateMetodePembayaran } = await import('../../wailsjs/go/main/App');
+      return await CreateMetodePembayaran(metode);
+    }
+  },
+
+  /**
+   * Update payment method
+   * @param {object} metode
+   * @returns {Promise<object>}
+   */
+  update: async (metode) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/metode-pembayaran', metode);
+      return response.data;
+    } else {
+      const { UpdateMetodePembayaran } = await import('../../wailsjs/go/main/App');
+      return await UpdateMetodePembayaran(metode);
+    }
+  },
+
+  /**
+   * Delete payment method
+   * @param {string} id
+   * @returns {Promise<void>}
+   */
+  delete: async (id) => {
+    if (isWebMode()) {
+      await client.delete(`/api/metode-pembayaran/${id}`);
+    } else {
+      const { DeleteMetodePembayaran } = await import('../../wailsjs/go/main/App');
+      return await DeleteMetodePembayaran(id);
+    }
+  },
+};
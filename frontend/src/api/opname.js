@@ -0,0 +1,118 @@
+/**
+ * Stok Opname API Module
+ * Handles physical stock count sessions in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const opnameAPI = {
+  /**
+   * Get stock count sessions, newest first
+   * @param {string} status - "dibuka", "diposting", "batal" or '' for all
+   * @returns {Promise<Array>}
+   */
+  getAll: async (status = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/opname', {
+        params: { status }
+      });
+      return response.data;
+    } else {
+      const { GetAllOpname } = await import('../../wailsjs/go/main/App');
+      return await GetAllOpname(status);
+    }
+  },
+
+  /**
+   * Get a stock count session with its lines (frozen system qty, counted qty and variance)
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getById: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/opname/${id}`);
+      return response.data;
+    } else {
+      const { GetOpname } = await import('../../wailsjs/go/main/App');
+      return await GetOpname(id);
+    }
+  },
+
+  /**
+   * Open a stock count session; the system quantity is frozen at this moment
+   * @param {object} request - { kategori, catatan } - empty kategori counts all products
+   * @returns {Promise<object>}
+   */
+  buka: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/opname', request);
+      return response.data;
+    } else {
+      const { BukaOpname } = await import('../../wailsjs/go/main/App');
+      return await BukaOpname(request);
+    }
+  },
+
+  /**
+   * Record a counted quantity, from any device
+   * @param {string} id - session ID
+   * @param {object} request - { produkId or barcode, qty, mode: "tambah" | "ganti", perangkat }
+   * @returns {Promise<object>} the updated line
+   */
+  hitung: async (id, request) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/opname/${id}/hitung`, request);
+      return response.data;
+    } else {
+      const { CatatHitunganOpname } = await import('../../wailsjs/go/main/App');
+      return await CatatHitunganOpname(id, request);
+    }
+  },
+
+  /**
+   * Get the variance report valued at purchase cost
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getLaporanSelisih: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/opname/${id}/selisih`);
+      return response.data;
+    } else {
+      const { GetLaporanSelisihOpname } = await import('../../wailsjs/go/main/App');
+      return await GetLaporanSelisihOpname(id);
+    }
+  },
+
+  /**
+   * Approve the variances and adjust stock
+   * @param {string} id
+   * @param {object} request - { nolkanBelumDihitung } - treat uncounted products as 0 instead of skipping them
+   * @returns {Promise<object>} the final variance report
+   */
+  posting: async (id, request = {}) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/opname/${id}/posting`, request);
+      return response.data;
+    } else {
+      const { PostingOpname } = await import('../../wailsjs/go/main/App');
+      return await PostingOpname(id, request);
+    }
+  },
+
+  /**
+   * Cancel an open session without changing stock
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  batal: async (id) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/opname/${id}/batal`);
+      return response.data;
+    } else {
+      const { BatalkanOpname } = await import('../../wailsjs/go/main/App');
+      return await BatalkanOpname(id);
+    }
+  },
+};
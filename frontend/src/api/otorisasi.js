@@ -0,0 +1,45 @@
+/**
+ * Otorisasi API Module
+ * Supervisor override tokens for restricted POS actions in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const otorisasiAPI = {
+  /**
+   * Ask a supervisor to approve one action. The returned token is single-use, expires quickly
+   * and is sent as overrideToken with the restricted request.
+   * @param {object} request - { username, pin, aksi, referensi }
+   *   aksi: "diskon_manual", "ubah_harga", "void", "retur", "buka_laci" or "penyesuaian_stok"
+   *   referensi: nomor transaksi (void, retur) or produk ID (ubah_harga, penyesuaian_stok); empty = any
+   * @returns {Promise<object>} { token, aksi, supervisorNama, expiresAt }
+   */
+  minta: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/otorisasi', request);
+      return response.data;
+    } else {
+      const { MintaOtorisasi } = await import('../../wailsjs/go/main/App');
+      return await MintaOtorisasi(request);
+    }
+  },
+
+  /**
+   * Get issued override tokens within a date range
+   * @param {string} startDate - YYYY-MM-DD
+   * @param {string} endDate - YYYY-MM-DD
+   * @returns {Promise<Array>}
+   */
+  getRiwayat: async (startDate, endDate) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/otorisasi/riwayat', {
+        params: { start_date: startDate, end_date: endDate }
+      });
+      return response.data;
+    } else {
+      const { GetRiwayatOtorisasi } = await import('../../wailsjs/go/main/App');
+      return await GetRiwayatOtorisasi(startDate, endDate);
+    }
+  },
+};
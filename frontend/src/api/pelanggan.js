@@ -109,4 +109,67 @@ export const pelangganAPI = {
       return await AddPoin(request);
     }
   },
+
+  /**
+   * Get store credit history of a customer
+   * @param {string} id
+   * @param {number} limit
+   * @returns {Promise<Array>}
+   */
+  getSaldoHistory: async (id, limit = 100) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/pelanggan/${id}/saldo`, {
+        params: { limit }
+      });
+      return response.data;
+    } else {
+      const { GetSaldoHistory } = await import('../../wailsjs/go/main/App');
+      return await GetSaldoHistory(id, limit);
+    }
+  },
+
+  /**
+   * Top up store credit
+   * @param {object} request - { pelangganId, jumlah, metode, referensi }
+   * @returns {Promise<object>}
+   */
+  topUpSaldo: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/pelanggan/saldo/topup', request);
+      return response.data;
+    } else {
+      const { TopUpSaldo } = await import('../../wailsjs/go/main/App');
+      return await TopUpSaldo(request);
+    }
+  },
+
+  /**
+   * Manually adjust store credit (admin only)
+   * @param {object} request - { pelangganId, jumlah, alasan }
+   * @returns {Promise<object>}
+   */
+  penyesuaianSaldo: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/pelanggan/saldo/penyesuaian', request);
+      return response.data;
+    } else {
+      const { PenyesuaianSaldo } = await import('../../wailsjs/go/main/App');
+      return await PenyesuaianSaldo(request);
+    }
+  },
+
+  /**
+   * Set credit limit and credit term (admin only)
+   * @param {object} request - { pelangganId, limitKredit, tempoHari }
+   * @returns {Promise<object>}
+   */
+  updateKredit: async (request) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/pelanggan/kredit', request);
+      return response.data;
+    } else {
+      const { UpdateKreditPelanggan } = await import('../../wailsjs/go/main/App');
+      return await UpdateKreditPelanggan(request);
+    }
+  },
 };
@@ -0,0 +1,137 @@
+/**
+ * Pembelian API Module
+ * Handles purchase orders and goods receipts in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const pembelianAPI = {
+  /**
+   * Get purchase orders, newest first
+   * @param {string} status - "draft", "dipesan", "sebagian", "selesai", "batal" or '' for all
+   * @returns {Promise<Array>}
+   */
+  getAll: async (status = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/pembelian', {
+        params: { status }
+      });
+      return response.data;
+    } else {
+      const { GetAllPesananPembelian } = await import('../../wailsjs/go/main/App');
+      return await GetAllPesananPembelian(status);
+    }
+  },
+
+  /**
+   * Get a purchase order with its lines and goods receipts
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getById: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/pembelian/${id}`);
+      return response.data;
+    } else {
+      const { GetPesananPembelian } = await import('../../wailsjs/go/main/App');
+      return await GetPesananPembelian(id);
+    }
+  },
+
+  /**
+   * Create a draft purchase order
+   * @param {object} request - { supplierId, tanggalDiharapkan, catatan, items: [{ produkId, qty, hargaBeli }] }
+   * @returns {Promise<object>}
+   */
+  create: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/pembelian', request);
+      return response.data;
+    } else {
+      const { CreatePesananPembelian } = await import('../../wailsjs/go/main/App');
+      return await CreatePesananPembelian(request);
+    }
+  },
+
+  /**
+   * Edit a draft purchase order
+   * @param {string} id
+   * @param {object} request - same shape as create
+   * @returns {Promise<object>}
+   */
+  update: async (id, request) => {
+    if (isWebMode()) {
+      const response = await client.put(`/api/pembelian/${id}`, request);
+      return response.data;
+    } else {
+      const { UpdatePesananPembelian } = await import('../../wailsjs/go/main/App');
+      return await UpdatePesananPembelian(id, request);
+    }
+  },
+
+  /**
+   * Send ("dipesan"), cancel ("batal") or close ("selesai") a purchase order
+   * @param {string} id
+   * @param {string} status
+   * @returns {Promise<object>}
+   */
+  ubahStatus: async (id, status) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/pembelian/${id}/status`, { status });
+      return response.data;
+    } else {
+      const { UbahStatusPesananPembelian } = await import('../../wailsjs/go/main/App');
+      return await UbahStatusPesananPembelian(id, status);
+    }
+  },
+
+  /**
+   * Receive a (partial) delivery against a purchase order
+   * @param {string} id - purchase order ID
+   * @param {object} request - { noFaktur, catatan, items: [{ produkId, qty, hargaBeli, tanggalKadaluarsa, masaSimpanHari }] }
+   * @returns {Promise<object>} the goods receipt
+   */
+  terimaBarang: async (id, request) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/pembelian/${id}/terima`, request);
+      return response.data;
+    } else {
+      const { TerimaBarang } = await import('../../wailsjs/go/main/App');
+      return await TerimaBarang({ ...request, pesananId: String(id) });
+    }
+  },
+
+  /**
+   * Get goods receipts between two dates (defaults to the last 30 days)
+   * @param {string} startDate - YYYY-MM-DD or ''
+   * @param {string} endDate - YYYY-MM-DD or ''
+   * @returns {Promise<Array>}
+   */
+  getAllPenerimaan: async (startDate = '', endDate = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/penerimaan', {
+        params: { start_date: startDate, end_date: endDate }
+      });
+      return response.data;
+    } else {
+      const { GetAllPenerimaan } = await import('../../wailsjs/go/main/App');
+      return await GetAllPenerimaan(startDate, endDate);
+    }
+  },
+
+  /**
+   * Get a goods receipt with its lines
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getPenerimaan: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/penerimaan/${id}`);
+      return response.data;
+    } else {
+      const { GetPenerimaan } = await import('../../wailsjs/go/main/App');
+      return await GetPenerimaan(id);
+    }
+  },
+};
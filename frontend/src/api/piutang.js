@@ -0,0 +1,77 @@
+/**
+ * Piutang API Module
+ * Handles credit sales (tempo), repayments and the aging report in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const piutangAPI = {
+  /**
+   * Get receivables
+   * @param {string} pelangganId - empty for all customers
+   * @param {string} status - "belum_lunas", "lunas", "void" or empty
+   * @returns {Promise<Array>}
+   */
+  getAll: async (pelangganId = '', status = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/piutang', {
+        params: { pelanggan_id: pelangganId || undefined, status: status || undefined }
+      });
+      return response.data;
+    } else {
+      const { GetPiutang } = await import('../../wailsjs/go/main/App');
+      return await GetPiutang(pelangganId || 0, status);
+    }
+  },
+
+  /**
+   * Record a repayment
+   * @param {object} request - { pelangganId, piutangId?, jumlah, metode, referensi }
+   * @returns {Promise<Array>}
+   */
+  bayar: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/piutang/bayar', request);
+      return response.data;
+    } else {
+      const { BayarPiutang } = await import('../../wailsjs/go/main/App');
+      return await BayarPiutang(request);
+    }
+  },
+
+  /**
+   * Get repayments received within a date range
+   * @param {string} startDate - YYYY-MM-DD
+   * @param {string} endDate - YYYY-MM-DD
+   * @returns {Promise<Array>}
+   */
+  getPembayaran: async (startDate, endDate) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/piutang/pembayaran', {
+        params: { start_date: startDate, end_date: endDate }
+      });
+      return response.data;
+    } else {
+      const { GetPembayaranPiutang } = await import('../../wailsjs/go/main/App');
+      return await GetPembayaranPiutang(startDate, endDate);
+    }
+  },
+
+  /**
+   * Get the receivable aging report (0-30/31-60/61-90/90+ days)
+   * @param {string} tanggal - YYYY-MM-DD, empty for today
+   * @returns {Promise<object>}
+   */
+  getLaporanUmur: async (tanggal = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/piutang/umur', {
+        params: { tanggal: tanggal || undefined }
+      });
+      return response.data;
+    } else {
+      const { GetLaporanUmurPiutang } = await import('../../wailsjs/go/main/App');
+      return await GetLaporanUmurPiutang(tanggal);
+    }
+  },
+};
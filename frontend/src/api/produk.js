@@ -140,13 +140,15 @@ export const produkAPI = {
   },
 
   /**
-   * Process cart (add/update/remove items)
-   * @param {object} request
-   * @returns {Promise<void>}
+   * Process cart as a goods receipt and add the scanned items to stock
+   * @param {object} request - { pesananId, supplierId, noFaktur, catatan, items: [{ produkId, hargaBeli, tanggalKadaluarsa, masaSimpanHari }] }
+   *   with pesananId the cart is received against that purchase order
+   * @returns {Promise<object>} the goods receipt
    */
-  processKeranjang: async (request) => {
+  processKeranjang: async (request = {}) => {
     if (isWebMode()) {
-      await client.post('/api/produk/keranjang/process', request);
+      const response = await client.post('/api/produk/keranjang/process', request);
+      return response.data;
     } else {
       const { ProcessKeranjang } = await import('../../wailsjs/go/main/App');
       return await ProcessKeranjang(request);
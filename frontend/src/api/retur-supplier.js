@@ -0,0 +1,88 @@
+/**
+ * Retur Supplier API Module
+ * Handles returns of expired or damaged goods to suppliers in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const returSupplierAPI = {
+  /**
+   * Get supplier returns, newest first
+   * @param {string} status - "menunggu", "selesai", "batal" or '' for all
+   * @returns {Promise<Array>}
+   */
+  getAll: async (status = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/retur-supplier', {
+        params: { status }
+      });
+      return response.data;
+    } else {
+      const { GetAllReturSupplier } = await import('../../wailsjs/go/main/App');
+      return await GetAllReturSupplier(status);
+    }
+  },
+
+  /**
+   * Get a supplier return with its lines
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getById: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/retur-supplier/${id}`);
+      return response.data;
+    } else {
+      const { GetReturSupplier } = await import('../../wailsjs/go/main/App');
+      return await GetReturSupplier(id);
+    }
+  },
+
+  /**
+   * Return picked batches to a supplier; stock is deducted immediately
+   * @param {object} request - { supplierId, alasan: "kadaluarsa" | "rusak", jenisKompensasi: "nota_kredit" | "ganti_barang",
+   *   catatan, items: [{ batchId, qty }] or [{ produkId, qty }] for products without batches }
+   * @returns {Promise<object>}
+   */
+  create: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/retur-supplier', request);
+      return response.data;
+    } else {
+      const { CreateReturSupplier } = await import('../../wailsjs/go/main/App');
+      return await CreateReturSupplier(request);
+    }
+  },
+
+  /**
+   * Settle a return with what the supplier gave back
+   * @param {string} id
+   * @param {object} request - { jenisKompensasi: "nota_kredit" | "ganti_barang" | "ditolak", noNotaKredit, nilaiKredit, catatan }
+   * @returns {Promise<object>}
+   */
+  selesaikan: async (id, request) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/retur-supplier/${id}/selesai`, request);
+      return response.data;
+    } else {
+      const { SelesaikanReturSupplier } = await import('../../wailsjs/go/main/App');
+      return await SelesaikanReturSupplier(id, request);
+    }
+  },
+
+  /**
+   * Cancel a waiting return and put the goods back into their batches
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  batal: async (id) => {
+    if (isWebMode()) {
+      const response = await client.post(`/api/retur-supplier/${id}/batal`);
+      return response.data;
+    } else {
+      const { BatalkanReturSupplier } = await import('../../wailsjs/go/main/App');
+      return await BatalkanReturSupplier(id);
+    }
+  },
+};
@@ -0,0 +1,81 @@
+/**
+ * Role API Module
+ * Handles configurable roles and their permissions in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const roleAPI = {
+  /**
+   * Get all roles with their permissions
+   * @returns {Promise<Array>}
+   */
+  getAll: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/roles');
+      return response.data;
+    } else {
+      const { GetAllRole } = await import('../../wailsjs/go/main/App');
+      return await GetAllRole();
+    }
+  },
+
+  /**
+   * Get every permission a role can be granted
+   * @returns {Promise<Array>} - [{ kode, nama, grup }]
+   */
+  getDaftarPermission: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/roles/permissions');
+      return response.data;
+    } else {
+      const { GetDaftarPermission } = await import('../../wailsjs/go/main/App');
+      return await GetDaftarPermission();
+    }
+  },
+
+  /**
+   * Create a custom role
+   * @param {object} role - { nama, deskripsi, permissions }
+   * @returns {Promise<object>}
+   */
+  create: async (role) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/roles', role);
+      return response.data;
+    } else {
+      const { CreateRole } = await import('../../wailsjs/go/main/App');
+      return await CreateRole(role);
+    }
+  },
+
+  /**
+   * Update the description and permissions of a role
+   * @param {object} role - must include id
+   * @returns {Promise<object>}
+   */
+  update: async (role) => {
+    if (isWebMode()) {
+      const response = await client.put(`/api/roles/${role.id}`, role);
+      return response.data;
+    } else {
+      const { UpdateRole } = await import('../../wailsjs/go/main/App');
+      return await UpdateRole(role);
+    }
+  },
+
+  /**
+   * Delete a custom role
+   * @param {string} id
+   * @returns {Promise<void>}
+   */
+  delete: async (id) => {
+    if (isWebMode()) {
+      await client.delete(`/api/roles/${id}`);
+    } else {
+      const { DeleteRole } = await import('../../wailsjs/go/main/App');
+      await DeleteRole(id);
+    }
+  },
+};
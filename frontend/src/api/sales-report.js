@@ -24,4 +24,22 @@ export const salesReportAPI = {
       return await GetComprehensiveSalesReport(startDate, endDate);
     }
   },
+
+  /**
+   * Get output tax (PPN) report for monthly filing
+   * @param {string} startDate - Format: YYYY-MM-DD
+   * @param {string} endDate - Format: YYYY-MM-DD
+   * @returns {Promise<object>}
+   */
+  getLaporanPajak: async (startDate, endDate) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/sales-report/pajak', {
+        params: { start_date: startDate, end_date: endDate }
+      });
+      return response.data;
+    } else {
+      const { GetLaporanPajak } = await import('../../wailsjs/go/main/App');
+      return await GetLaporanPajak(startDate, endDate);
+    }
+  },
 };
@@ -0,0 +1,119 @@
+/**
+ * Sesi Kas API Module
+ * Handles cash drawer sessions (opening float, paid-in/out, closing count, X/Z reports) in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const sesiKasAPI = {
+  /**
+   * Get the open session of the logged in staff
+   * @param {string} staffId - used in desktop mode only
+   * @returns {Promise<object|null>}
+   */
+  getAktif: async (staffId) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/sesi-kas/aktif');
+      return response.data;
+    } else {
+      const { GetSesiKasAktif } = await import('../../wailsjs/go/main/App');
+      return await GetSesiKasAktif(staffId);
+    }
+  },
+
+  /**
+   * List sessions
+   * @param {string} staffId - empty for all staff (admin only in web mode)
+   * @param {string} status - "buka", "tutup" or empty
+   * @param {number} limit
+   * @returns {Promise<Array>}
+   */
+  getAll: async (staffId = '', status = '', limit = 100) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/sesi-kas', {
+        params: { staff_id: staffId || undefined, status: status || undefined, limit }
+      });
+      return response.data;
+    } else {
+      const { GetAllSesiKas } = await import('../../wailsjs/go/main/App');
+      return await GetAllSesiKas(staffId || 0, status, limit);
+    }
+  },
+
+  /**
+   * Open a session
+   * @param {object} request - { terminalId, shiftId (0 = by opening time), modalAwal, staffId, staffNama }
+   * @returns {Promise<object>}
+   */
+  buka: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/sesi-kas/buka', request);
+      return response.data;
+    } else {
+      const { BukaSesiKas } = await import('../../wailsjs/go/main/App');
+      return await BukaSesiKas(request);
+    }
+  },
+
+  /**
+   * Record a paid-in or paid-out entry
+   * @param {object} request - { sesiId, tipe: "masuk"|"keluar", jumlah, alasan, staffId, staffNama }
+   * @returns {Promise<object>}
+   */
+  catatMutasi: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/sesi-kas/mutasi', request);
+      return response.data;
+    } else {
+      const { CatatMutasiKas } = await import('../../wailsjs/go/main/App');
+      return await CatatMutasiKas(request);
+    }
+  },
+
+  /**
+   * Close a session with a denomination count
+   * @param {object} request - { sesiId, pecahan: [{ nilai, jumlah }], catatan, staffId, staffNama }
+   * @returns {Promise<object>} Z-report
+   */
+  tutup: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/sesi-kas/tutup', request);
+      return response.data;
+    } else {
+      const { TutupSesiKas } = await import('../../wailsjs/go/main/App');
+      return await TutupSesiKas(request);
+    }
+  },
+
+  /**
+   * Get the X-report (open session) or Z-report (closed session)
+   * @param {string} sesiId
+   * @param {string} jenis - "X" or "Z"
+   * @returns {Promise<object>}
+   */
+  getLaporan: async (sesiId, jenis = 'X') => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/sesi-kas/${sesiId}/laporan`, { params: { jenis } });
+      return response.data;
+    } else {
+      const { GetLaporanSesiKas } = await import('../../wailsjs/go/main/App');
+      return await GetLaporanSesiKas(sesiId, jenis);
+    }
+  },
+
+  /**
+   * Print the X/Z report
+   * @param {object} request - { sesiId, jenis, printerName }
+   * @returns {Promise<void>}
+   */
+  cetak: async (request) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/sesi-kas/cetak', request);
+      return response.data;
+    } else {
+      const { PrintLaporanSesiKas } = await import('../../wailsjs/go/main/App');
+      return await PrintLaporanSesiKas(request);
+    }
+  },
+};
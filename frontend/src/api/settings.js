@@ -35,4 +35,197 @@ export const settingsAPI = {
       return await UpdatePoinSettings(settings);
     }
   },
+
+  /**
+   * Get cash rounding settings
+   * @returns {Promise<object>}
+   */
+  getPembulatanSettings: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/pembulatan');
+      return response.data;
+    } else {
+      const { GetPembulatanSettings } = await import('../../wailsjs/go/main/App');
+      return await GetPembulatanSettings();
+    }
+  },
+
+  /**
+   * Preview the cash-rounded total collected at checkout
+   * @param {number} total - Total before rounding
+   * @param {number} nonTunai - Part of the total paid with non-cash methods
+   * @param {boolean} donasi - Customer rounds the cash part up and donates the difference
+   * @returns {Promise<object>} { total, nonTunai, pembulatan, donasi, donasiAktif, totalTagihan }
+   */
+  previewPembulatan: async (total, nonTunai = 0, donasi = false) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/pembulatan/preview', {
+        params: { total, non_tunai: nonTunai, donasi }
+      });
+      return response.data;
+    } else {
+      const { PreviewPembulatan } = await import('../../wailsjs/go/main/App');
+      return await PreviewPembulatan(total, nonTunai, donasi);
+    }
+  },
+
+  /**
+   * Update cash rounding settings
+   * @param {object} settings - { aktif, kelipatan, mode, donasiAktif }
+   * @returns {Promise<object>}
+   */
+  updatePembulatanSettings: async (settings) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/settings/pembulatan', settings);
+      return response.data;
+    } else {
+      const { UpdatePembulatanSettings } = await import('../../wailsjs/go/main/App');
+      return await UpdatePembulatanSettings(settings);
+    }
+  },
+
+  /**
+   * Get tax (PPN) settings
+   * @returns {Promise<object>}
+   */
+  getPajakSettings: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/pajak');
+      return response.data;
+    } else {
+      const { GetPajakSettings } = await import('../../wailsjs/go/main/App');
+      return await GetPajakSettings();
+    }
+  },
+
+  /**
+   * Update tax (PPN) settings
+   * @param {object} settings - { aktif, namaPajak, tarifDefault, mode }
+   * @returns {Promise<object>}
+   */
+  updatePajakSettings: async (settings) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/settings/pajak', settings);
+      return response.data;
+    } else {
+      const { UpdatePajakSettings } = await import('../../wailsjs/go/main/App');
+      return await UpdatePajakSettings(settings);
+    }
+  },
+
+  /**
+   * Get cash drawer settings of this terminal
+   * @returns {Promise<object>}
+   */
+  getLaciKasSettings: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/laci-kas');
+      return response.data;
+    } else {
+      const { GetLaciKasSettings } = await import('../../wailsjs/go/main/App');
+      return await GetLaciKasSettings();
+    }
+  },
+
+  /**
+   * Update cash drawer settings of this terminal
+   * @param {object} settings - { aktif, driver: "printer"|"serial", printerName, port, baudRate, pin }
+   * @returns {Promise<object>}
+   */
+  updateLaciKasSettings: async (settings) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/settings/laci-kas', settings);
+      return response.data;
+    } else {
+      const { UpdateLaciKasSettings } = await import('../../wailsjs/go/main/App');
+      return await UpdateLaciKasSettings(settings);
+    }
+  },
+
+  /**
+   * Get supervisor override thresholds
+   * @returns {Promise<object>}
+   */
+  getOtorisasiSettings: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/otorisasi');
+      return response.data;
+    } else {
+      const { GetOtorisasiSettings } = await import('../../wailsjs/go/main/App');
+      return await GetOtorisasiSettings();
+    }
+  },
+
+  /**
+   * Update supervisor override thresholds
+   * @param {object} settings - { batasDiskonManual, batasRefund, wajibNoSale, wajibPenyesuaianStok, masaBerlakuDetik }
+   * @returns {Promise<object>}
+   */
+  updateOtorisasiSettings: async (settings) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/settings/otorisasi', settings);
+      return response.data;
+    } else {
+      const { UpdateOtorisasiSettings } = await import('../../wailsjs/go/main/App');
+      return await UpdateOtorisasiSettings(settings);
+    }
+  },
+
+  /**
+   * Get auto-lock, login lockout and password policy settings
+   * @returns {Promise<object>}
+   */
+  getKeamananSettings: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/keamanan');
+      return response.data;
+    } else {
+      const { GetKeamananSettings } = await import('../../wailsjs/go/main/App');
+      return await GetKeamananSettings();
+    }
+  },
+
+  /**
+   * Update auto-lock, login lockout and password policy settings
+   * @param {object} settings - { autoLockMenit, maksGagalLogin, durasiKunciMenit, durasiKunciMaksMenit, panjangMinPassword, riwayatPassword }
+   * @returns {Promise<object>}
+   */
+  updateKeamananSettings: async (settings) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/settings/keamanan', settings);
+      return response.data;
+    } else {
+      const { UpdateKeamananSettings } = await import('../../wailsjs/go/main/App');
+      return await UpdateKeamananSettings(settings);
+    }
+  },
+
+  /**
+   * Get how product costs are updated when goods are received
+   * @returns {Promise<object>}
+   */
+  getPembelianSettings: async () => {
+    if (isWebMode()) {
+      const response = await client.get('/api/settings/pembelian');
+      return response.data;
+    } else {
+      const { GetPembelianSettings } = await import('../../wailsjs/go/main/App');
+      return await GetPembelianSettings();
+    }
+  },
+
+  /**
+   * Update how product costs are updated when goods are received
+   * @param {object} settings - { metodeHargaBeli: "tetap" | "terakhir" | "rata_rata" }
+   * @returns {Promise<object>}
+   */
+  updatePembelianSettings: async (settings) => {
+    if (isWebMode()) {
+      const response = await client.put('/api/settings/pembelian', settings);
+      return response.data;
+    } else {
+      const { UpdatePembelianSettings } = await import('../../wailsjs/go/main/App');
+      return await UpdatePembelianSettings(settings);
+    }
+  },
 };
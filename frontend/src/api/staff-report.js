@@ -270,4 +270,41 @@ export const staffReportAPI = {
         await UpdateShiftSettings(id, startTime, endTime, staffIDs);
     }
   },
+
+  /**
+   * Get the monthly attendance report of a staff member
+   * @param {string} staffId
+   * @param {number} tahun
+   * @param {number} bulan - 1-12
+   * @returns {Promise<object>}
+   */
+  getLaporanAbsensi: async (staffId, tahun, bulan) => {
+    if (isWebMode()) {
+        const response = await client.get(`/api/staff-report/${staffId}/absensi`, {
+            params: { tahun, bulan }
+        });
+        return response.data;
+    } else {
+        const { GetLaporanAbsensi } = await import('../../wailsjs/go/main/App');
+        return await GetLaporanAbsensi(String(staffId), tahun, bulan);
+    }
+  },
+
+  /**
+   * Get the monthly attendance report of all staff
+   * @param {number} tahun
+   * @param {number} bulan - 1-12
+   * @returns {Promise<Array>}
+   */
+  getAllLaporanAbsensi: async (tahun, bulan) => {
+    if (isWebMode()) {
+        const response = await client.get('/api/staff-report/absensi', {
+            params: { tahun, bulan }
+        });
+        return response.data;
+    } else {
+        const { GetAllLaporanAbsensi } = await import('../../wailsjs/go/main/App');
+        return await GetAllLaporanAbsensi(tahun, bulan);
+    }
+  },
 };
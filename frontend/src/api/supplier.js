@@ -0,0 +1,85 @@
+/**
+ * Supplier API Module
+ * Handles supplier master data in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const supplierAPI = {
+  /**
+   * Get suppliers
+   * @param {boolean} aktifSaja - only return active suppliers
+   * @returns {Promise<Array>}
+   */
+  getAll: async (aktifSaja = false) => {
+    if (isWebMode()) {
+      const response = await client.get('/api/supplier', {
+        params: { aktif: aktifSaja }
+      });
+      return response.data;
+    } else {
+      const { GetAllSupplier } = await import('../../wailsjs/go/main/App');
+      return await GetAllSupplier(aktifSaja);
+    }
+  },
+
+  /**
+   * Get supplier by ID
+   * @param {string} id
+   * @returns {Promise<object>}
+   */
+  getById: async (id) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/supplier/${id}`);
+      return response.data;
+    } else {
+      const { GetSupplierByID } = await import('../../wailsjs/go/main/App');
+      return await GetSupplierByID(id);
+    }
+  },
+
+  /**
+   * Create new supplier
+   * @param {object} supplier - { kode, nama, kontak, telepon, email, alamat, catatan, aktif }
+   * @returns {Promise<object>}
+   */
+  create: async (supplier) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/supplier', supplier);
+      return response.data;
+    } else {
+      const { CreateSupplier } = await import('../../wailsjs/go/main/App');
+      return await CreateSupplier(supplier);
+    }
+  },
+
+  /**
+   * Update supplier
+   * @param {object} supplier
+   * @returns {Promise<object>}
+   */
+  update: async (supplier) => {
+    if (isWebMode()) {
+      const response = await client.put(`/api/supplier/${supplier.id}`, supplier);
+      return response.data;
+    } else {
+      const { UpdateSupplier } = await import('../../wailsjs/go/main/App');
+      return await UpdateSupplier(supplier);
+    }
+  },
+
+  /**
+   * Delete supplier (refused once a purchase order uses it)
+   * @param {string} id
+   * @returns {Promise<void>}
+   */
+  delete: async (id) => {
+    if (isWebMode()) {
+      await client.delete(`/api/supplier/${id}`);
+    } else {
+      const { DeleteSupplier } = await import('../../wailsjs/go/main/App');
+      return await DeleteSupplier(id);
+    }
+  },
+};
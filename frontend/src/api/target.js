@@ -0,0 +1,68 @@
+/**
+ * Target API Module
+ * Handles daily, weekly and monthly sales targets per store, shift and staff in both desktop and web modes
+ */
+
+import client from './client';
+import { isWebMode } from '../utils/environment';
+
+export const targetAPI = {
+  /**
+   * Get sales targets
+   * @param {string} lingkup - optional scope filter: 'toko', 'shift' or 'staff'
+   * @returns {Promise<Array>}
+   */
+  getAll: async (lingkup = '') => {
+    if (isWebMode()) {
+      const response = await client.get('/api/target', { params: lingkup ? { lingkup } : {} });
+      return response.data;
+    } else {
+      const { GetAllTarget } = await import('../../wailsjs/go/main/App');
+      return await GetAllTarget(lingkup);
+    }
+  },
+
+  /**
+   * Create a sales target
+   * @param {object} target - { periode, metrik, lingkup, shiftId, staffId, nilai, aktif }
+   * @returns {Promise<object>}
+   */
+  create: async (target) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/target', target);
+      return response.data;
+    } else {
+      const { CreateTarget } = await import('../../wailsjs/go/main/App');
+      return await CreateTarget(target);
+    }
+  },
+
+  /**
+   * Update a sales target
+   * @param {object} target - must include id
+   * @returns {Promise<object>}
+   */
+  update: async (target) => {
+    if (isWebMode()) {
+      const response = await client.put(`/api/target/${target.id}`, target);
+      return response.data;
+    } else {
+      const { UpdateTarget } = await import('../../wailsjs/go/main/App');
+      return await UpdateTarget(target);
+    }
+  },
+
+  /**
+   * Delete a sales target
+   * @param {string} id
+   * @returns {Promise<void>}
+   */
+  delete: async (id) => {
+    if (isWebMode()) {
+      await client.delete(`/api/target/${id}`);
+    } else {
+      const { DeleteTarget } = await import('../../wailsjs/go/main/App');
+      await DeleteTarget(id);
+    }
+  },
+};
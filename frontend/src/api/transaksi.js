@@ -10,15 +10,32 @@ export const transaksiAPI = {
   /**
    * Create new transaction
    * @param {object} transaksi
+   * @param {string} [idempotencyKey] - Same key for retries of one checkout attempt
    * @returns {Promise<object>}
    */
-  create: async (transaksi) => {
+  create: async (transaksi, idempotencyKey = '') => {
     if (isWebMode()) {
-      const response = await client.post('/api/transaksi', transaksi);
+      const config = idempotencyKey ? { headers: { 'Idempotency-Key': idempotencyKey } } : undefined;
+      const response = await client.post('/api/transaksi', transaksi, config);
       return response.data;
     } else {
       const { CreateTransaksi } = await import('../../wailsjs/go/main/App');
-      return await CreateTransaksi(transaksi);
+      return await CreateTransaksi({ ...transaksi, idempotencyKey });
+    }
+  },
+
+  /**
+   * Calculate the server-side totals (discounts, PPN, total) of a cart without saving it
+   * @param {object} transaksi - Same shape as create
+   * @returns {Promise<object>} { subtotal, diskon, totalPajak, modePajak, total, ... }
+   */
+  preview: async (transaksi) => {
+    if (isWebMode()) {
+      const response = await client.post('/api/transaksi/preview', transaksi);
+      return response.data;
+    } else {
+      const { PreviewTransaksi } = await import('../../wailsjs/go/main/App');
+      return await PreviewTransaksi(transaksi);
     }
   },
 
@@ -50,6 +50,22 @@ export const userAPI = {
     }
   },
 
+  /**
+   * Get the latest login attempts of a user (admin only)
+   * @param {string} id
+   * @param {number} [limit=100]
+   * @returns {Promise<Array>}
+   */
+  getLoginHistory: async (id, limit = 100) => {
+    if (isWebMode()) {
+      const response = await client.get(`/api/users/${id}/login-history`, { params: { limit } });
+      return response.data;
+    } else {
+      const { GetLoginHistory } = await import('../../wailsjs/go/main/App');
+      return await GetLoginHistory(String(id), limit);
+    }
+  },
+
   /**
    * Create new user (admin only)
    * @param {object} user
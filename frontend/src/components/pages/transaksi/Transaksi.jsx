@@ -5,7 +5,8 @@ import {
     pelangganAPI,
     promoAPI,
     printerAPI,
-    settingsAPI
+    settingsAPI,
+    metodePembayaranAPI
 } from '../../../api';
 import { useToast } from '../../common/ToastContainer';
 import { useAuth } from '../../../contexts/AuthContext';
@@ -20,6 +21,7 @@ import {
     faWeightHanging
 } from '@fortawesome/free-solid-svg-icons';
 import CustomSelect from '../../common/CustomSelect';
+import { newIdempotencyKey } from '../../../utils/idempotency';
 
 export default function Transaksi() {
     const { addToast } = useToast();
@@ -49,14 +51,24 @@ export default function Transaksi() {
     const [eligiblePromos, setEligiblePromos] = useState([]);
     const [showPromoModal, setShowPromoModal] = useState(false);
 
-    // Options untuk CustomSelect metode pembayaran
-    const paymentMethodOptions = [
+    // Metode pembayaran dari pengaturan toko (fallback ke daftar standar)
+    const [paymentMethods, setPaymentMethods] = useState([]);
+    const paymentIcons = { tunai: faMoneyBill, dompet_digital: faQrcode };
+    const defaultPaymentMethodOptions = [
         { label: 'Tunai', value: 'tunai', icon: faMoneyBill, description: 'Pembayaran dengan uang tunai' },
         { label: 'QRIS', value: 'qris', icon: faQrcode, description: 'Pembayaran via QR Code' },
         { label: 'Transfer Bank', value: 'transfer', icon: faCreditCard, description: 'Pembayaran via transfer bank' },
         { label: 'Kartu Debit', value: 'debit', icon: faCreditCard, description: 'Pembayaran dengan kartu debit' },
         { label: 'Kartu Kredit', value: 'kredit', icon: faCreditCard, description: 'Pembayaran dengan kartu kredit' },
     ];
+    const paymentMethodOptions = paymentMethods.length > 0
+        ? paymentMethods.map(m => ({
+            label: m.nama,
+            value: m.kode,
+            icon: paymentIcons[m.tipe] || faCreditCard,
+            description: m.perluReferensi ? 'Nomor referensi wajib diisi' : ''
+        }))
+        : defaultPaymentMethodOptions;
 
     // Discount breakdown - HANYA POIN DAN PROMO (TIDAK ADA DISKON PELANGGAN)
     const [discount, setDiscount] = useState(0);
@@ -71,6 +83,12 @@ export default function Transaksi() {
     const [payments, setPayments] = useState([]);
     const [currentPayment, setCurrentPayment] = useState({ method: 'tunai', amount: 0, reference: '' });
     const [showPaymentModal, setShowPaymentModal] = useState(false);
+    // Cash-rounded total from the server, see settingsAPI.previewPembulatan
+    const [pembulatanPreview, setPembulatanPreview] = useState(null);
+    // Customer rounds the cash part up and donates the difference
+    const [donasiPembulatan, setDonasiPembulatan] = useState(false);
+    // Discounts, PPN and total calculated by the server, see transaksiAPI.preview
+    const [transaksiPreview, setTransaksiPreview] = useState(null);
 
     // Transaction state
     const [isProcessing, setIsProcessing] = useState(false);
@@ -79,6 +97,8 @@ export default function Transaksi() {
 
     const receiptRef = useRef(null);
     const receiptModalRef = useRef(null);
+    // One key per checkout attempt; kept across retries so the server never records it twice
+    const checkoutKeyRef = useRef(null);
     const [isScanning, setIsScanning] = useState(false);
 
     // Berat Modal state
@@ -160,6 +180,15 @@ export default function Transaksi() {
         } catch (error) {
             console.error('Error fetching settings:', error);
         }
+
+        try {
+            const metode = await metodePembayaranAPI.getAll(true);
+            if (Array.isArray(metode)) {
+                setPaymentMethods(metode);
+            }
+        } catch (error) {
+            console.error('Error fetching payment methods:', error);
+        }
     };
 
     const loadPromos = async () => {
@@ -769,22 +798,134 @@ export default function Transaksi() {
         // eslint-disable-next-line react-hooks/exhaustive-deps
     }, [cart]);
 
+    const isMetodeTunai = (kode) => {
+        const metode = paymentMethods.find(m => m.kode === kode);
+        return metode ? metode.tipe === 'tunai' : kode === 'tunai';
+    };
+
+    // Items in the shape the server expects: quantity for satuan, weight for curah
+    const buildItemsRequest = () => cart.map(item => (
+        item.jenisProduk === 'satuan'
+            ? {
+                produkId: item.id,
+                jumlah: item.quantity,          // Quantity untuk satuan tetap
+                hargaSatuan: item.pricePerKg,   // Harga per pcs
+                beratGram: 0                    // Tidak pakai berat
+            }
+            : {
+                produkId: item.id,
+                jumlah: 1,                      // Backward compatibility
+                hargaSatuan: item.pricePerKg,   // Harga per 1000g
+                beratGram: item.beratGram       // Berat dalam gram
+            }
+    ));
+
+    // Recalculate the cart on the server so PPN and the total match what checkout will charge
+    useEffect(() => {
+        if (cart.length === 0) {
+            setTransaksiPreview(null);
+            return;
+        }
+
+        let batal = false;
+        const fetchPreview = async () => {
+            try {
+                const preview = await transaksiAPI.preview({
+                    pelangganId: String(selectedCustomer?.id || 0),
+                    items: buildItemsRequest(),
+                    promoKode: appliedPromos.length > 0 ? appliedPromos.map(p => p.kode).join(',') : '',
+                    poinDitukar: pointsToRedeem
+                });
+                if (!batal) {
+                    setTransaksiPreview(preview || null);
+                }
+            } catch (error) {
+                console.error('Error previewing transaction:', error);
+                if (!batal) {
+                    setTransaksiPreview(null);
+                }
+            }
+        };
+        fetchPreview();
+
+        return () => {
+            batal = true;
+        };
+        // eslint-disable-next-line react-hooks/exhaustive-deps
+    }, [cart, discount, selectedCustomer, appliedPromos, pointsToRedeem]);
+
     const calculateTotals = () => {
         const subtotal = cart.reduce((sum, item) => sum + item.subtotal, 0);
-        const total = subtotal - discount;
+
+        // Use the server's total once it has caught up with the cart; exclusive PPN is added on top
+        const previewAktif = transaksiPreview && transaksiPreview.subtotal === subtotal && transaksiPreview.diskon === discount;
+        const pajak = previewAktif ? transaksiPreview.totalPajak : 0;
+        const modePajak = previewAktif ? transaksiPreview.modePajak : '';
+        const total = previewAktif ? transaksiPreview.total : subtotal - discount;
         const totalPaid = payments.reduce((sum, p) => sum + p.amount, 0);
-        const change = totalPaid - total;
 
-        return { subtotal, total, totalPaid, change };
+        // Only the cash part is rounded by the server; non-cash payments are collected exactly
+        const nonTunai = payments
+            .filter(p => !isMetodeTunai(p.method))
+            .reduce((sum, p) => sum + p.amount, 0);
+        const adaTunai = payments.some(p => isMetodeTunai(p.method)) || isMetodeTunai(currentPayment.method);
+        const previewCocok = adaTunai && pembulatanPreview
+            && pembulatanPreview.total === total
+            && pembulatanPreview.nonTunai === nonTunai
+            && pembulatanPreview.donasiDiminta === donasiPembulatan;
+        const pembulatan = previewCocok ? pembulatanPreview.pembulatan : 0;
+        const donasi = previewCocok ? pembulatanPreview.donasi : 0;
+        const tagihan = total + pembulatan + donasi;
+        const change = totalPaid - tagihan;
+
+        return { subtotal, pajak, modePajak, total, nonTunai, adaTunai, pembulatan, donasi, tagihan, totalPaid, change };
     };
 
+    // Fetch the server's cash-rounded total while the payment modal is open
+    useEffect(() => {
+        const { total, nonTunai, adaTunai } = calculateTotals();
+        if (!showPaymentModal || total <= 0 || !adaTunai) {
+            setPembulatanPreview(null);
+            return;
+        }
+
+        let batal = false;
+        const tagihanSebelumnya = pembulatanPreview?.totalTagihan;
+        const fetchPembulatan = async () => {
+            try {
+                const preview = await settingsAPI.previewPembulatan(total, nonTunai, donasiPembulatan);
+                if (batal || !preview) return;
+                setPembulatanPreview({ ...preview, donasiDiminta: donasiPembulatan });
+                // Prefill the rounded amount instead of the unrounded or previously rounded total
+                if (payments.length === 0) {
+                    setCurrentPayment(prev => (
+                        isMetodeTunai(prev.method) && (prev.amount === preview.total || prev.amount === tagihanSebelumnya)
+                            ? { ...prev, amount: preview.totalTagihan }
+                            : prev
+                    ));
+                }
+            } catch (error) {
+                console.error('Error previewing cash rounding:', error);
+            }
+        };
+        fetchPembulatan();
+
+        return () => {
+            batal = true;
+        };
+        // eslint-disable-next-line react-hooks/exhaustive-deps
+    }, [showPaymentModal, cart, discount, payments, currentPayment.method, paymentMethods, donasiPembulatan, transaksiPreview]);
+
     const addPayment = () => {
         if (currentPayment.amount <= 0) {
             addToast('Jumlah pembayaran harus lebih dari 0', 'error');
             return;
         }
 
-        if (currentPayment.method !== 'tunai' && !currentPayment.reference) {
+        const metode = paymentMethods.find(m => m.kode === currentPayment.method);
+        const isTunai = metode ? metode.tipe === 'tunai' : currentPayment.method === 'tunai';
+        const perluReferensi = metode ? metode.perluReferensi : !isTunai;
+        if (!isTunai && perluReferensi && !currentPayment.reference) {
             addToast('Nomor referensi diperlukan untuk pembayaran non-tunai', 'error');
             return;
         }
@@ -815,9 +956,9 @@ export default function Transaksi() {
             return;
         }
 
-        const { total, totalPaid } = calculateTotals();
+        const { total, tagihan, totalPaid } = calculateTotals();
 
-        if (totalPaid < total) {
+        if (totalPaid < tagihan) {
             addToast('Pembayaran belum mencukupi', 'error');
             return;
         }
@@ -851,33 +992,7 @@ export default function Transaksi() {
                 pelangganId: String(selectedCustomer?.id || 0),
                 pelangganNama: selectedCustomer?.nama || 'Umum',
                 pelangganTelp: selectedCustomer?.telepon || '',
-                items: cart.map(item => {
-                    console.log('[DEBUG FRONTEND] Cart Item:', {
-                        nama: item.name,
-                        jenisProduk: item.jenisProduk,
-                        beratGram: item.beratGram,
-                        quantity: item.quantity,
-                        pricePerKg: item.pricePerKg,
-                        subtotal: item.subtotal
-                    });
-
-                    // Conditional: jika satuan, kirim quantity. Jika curah, kirim beratGram
-                    if (item.jenisProduk === 'satuan') {
-                        return {
-                            produkId: item.id,
-                            jumlah: item.quantity,          // Quantity untuk satuan tetap
-                            hargaSatuan: item.pricePerKg,   // Harga per pcs
-                            beratGram: 0                    // Tidak pakai berat
-                        };
-                    } else {
-                        return {
-                            produkId: item.id,
-                            jumlah: 1,                      // Backward compatibility
-                            hargaSatuan: item.pricePerKg,   // Harga per 1000g
-                            beratGram: item.beratGram       // Berat dalam gram
-                        };
-                    }
-                }),
+                items: buildItemsRequest(),
                 pembayaran: payments.map(p => ({
                     metode: p.method,
                     jumlah: p.amount,
@@ -886,6 +1001,10 @@ export default function Transaksi() {
                 promoKode: appliedPromos.length > 0 ? appliedPromos.map(p => p.kode).join(',') : '',
                 poinDitukar: pointsToRedeem,
                 diskon: discount,
+                // The server rejects a donation without a cash payment
+                donasiPembulatan: donasiPembulatan && payments.some(p => isMetodeTunai(p.method)),
+                // Checked against the server's total so a stale cart or price is rejected
+                total,
                 catatan: '',
                 kasir: user.namaLengkap || 'Kasir',
                 staffId: String(user.id), // MUST be string to prevent JS precision loss!
@@ -898,13 +1017,21 @@ export default function Transaksi() {
             console.log('[DEBUG FRONTEND] Full request:', request);
 
 
-            const response = await transaksiAPI.create(request);
+            if (!checkoutKeyRef.current) {
+                checkoutKeyRef.current = newIdempotencyKey();
+            }
+            const response = await transaksiAPI.create(request, checkoutKeyRef.current);
+            // Definitive answer from the server; the next attempt gets a fresh key
+            checkoutKeyRef.current = null;
 
             if (response.success) {
                 // Fix: response.data contains the TransaksiResponse
                 const transaksiData = response.data || response;
                 setLastTransaction(transaksiData.transaksi);
                 setShowPaymentModal(false);
+                if (transaksiData.peringatanLaci) {
+                    addToast(`Laci kas tidak terbuka: ${transaksiData.peringatanLaci}`, 'warning');
+                }
 
                 try {
                     const printRequest = {
@@ -933,6 +1060,7 @@ export default function Transaksi() {
                 setPointsDiscount(0);
                 setDiscount(0);
                 setPayments([]);
+                setDonasiPembulatan(false);
                 setPromoAppliedProducts(new Set());
 
                 loadProducts();
@@ -948,6 +1076,7 @@ export default function Transaksi() {
     };
 
     const openPaymentModal = () => {
+        checkoutKeyRef.current = null;
         if (cart.length === 0) {
             addToast('Keranjang masih kosong', 'error');
             return;
@@ -1102,7 +1231,7 @@ export default function Transaksi() {
         }
     };
 
-    const { subtotal, total, totalPaid, change } = calculateTotals();
+    const { subtotal, pajak, modePajak, total, adaTunai, pembulatan, donasi, tagihan, totalPaid, change } = calculateTotals();
 
     return (
         <div className="page w-full max-w-full overflow-x-hidden min-h-screen p-8 border-1 border-gray-200">
@@ -1755,6 +1884,13 @@ export default function Transaksi() {
                                                 <span>Rp 0</span>
                                             </div>
                                         )}
+
+                                        {pajak > 0 && (
+                                            <div className="flex justify-between text-sm text-gray-600">
+                                                <span>{modePajak === 'exclusive' ? 'PPN' : 'PPN (termasuk dalam harga)'}</span>
+                                                <span>{modePajak === 'exclusive' ? '+ ' : ''}{formatRupiah(pajak)}</span>
+                                            </div>
+                                        )}
                                     </div>
                                 </div>
 
@@ -2282,8 +2418,8 @@ export default function Transaksi() {
                                                         <button
                                                             type="button"
                                                             onClick={() => {
-                                                                const { total } = calculateTotals();
-                                                                const remaining = total - payments.reduce((sum, p) => sum + p.amount, 0);
+                                                                const { tagihan } = calculateTotals();
+                                                                const remaining = tagihan - payments.reduce((sum, p) => sum + p.amount, 0);
                                                                 setCurrentPayment({ ...currentPayment, amount: Math.max(0, remaining) });
                                                             }}
                                                             className="text-xs text-green-600 hover:text-green-700 font-medium"
@@ -2414,9 +2550,46 @@ export default function Transaksi() {
 
                                 <div className="bg-gray-50 border border-gray-300 rounded-xl p-4">
                                     <div className="space-y-3">
+                                        {pajak > 0 && (
+                                            <div className="flex justify-between text-gray-700">
+                                                <span className="font-medium">{modePajak === 'exclusive' ? 'PPN' : 'PPN (termasuk)'}</span>
+                                                <span className="font-bold text-gray-900">{formatRupiah(pajak)}</span>
+                                            </div>
+                                        )}
+                                        {(pembulatan !== 0 || donasi > 0) && (
+                                            <div className="flex justify-between text-gray-700">
+                                                <span className="font-medium">Total Belanja</span>
+                                                <span className="font-bold text-gray-900">{formatRupiah(total)}</span>
+                                            </div>
+                                        )}
+                                        {pembulatan !== 0 && (
+                                            <div className="flex justify-between text-gray-700">
+                                                <span className="font-medium">Pembulatan Tunai</span>
+                                                <span className="font-bold text-gray-900">
+                                                    {pembulatan > 0 ? '+' : '-'}{formatRupiah(Math.abs(pembulatan))}
+                                                </span>
+                                            </div>
+                                        )}
+                                        {donasi > 0 && (
+                                            <div className="flex justify-between text-gray-700">
+                                                <span className="font-medium">Donasi Pembulatan</span>
+                                                <span className="font-bold text-gray-900">+{formatRupiah(donasi)}</span>
+                                            </div>
+                                        )}
+                                        {adaTunai && pembulatanPreview?.donasiAktif && (
+                                            <label className="flex items-center gap-2 text-sm text-gray-700 cursor-pointer">
+                                                <input
+                                                    type="checkbox"
+                                                    checked={donasiPembulatan}
+                                                    onChange={(e) => setDonasiPembulatan(e.target.checked)}
+                                                    className="rounded border-gray-300 text-green-600 focus:ring-green-500"
+                                                />
+                                                <span>Pelanggan mendonasikan pembulatan ke atas</span>
+                                            </label>
+                                        )}
                                         <div className="flex justify-between text-gray-700">
                                             <span className="font-medium">Total Tagihan</span>
-                                            <span className="font-bold text-gray-900">{formatRupiah(total)}</span>
+                                            <span className="font-bold text-gray-900">{formatRupiah(tagihan)}</span>
                                         </div>
                                         <div className="flex justify-between text-gray-700">
                                             <span className="font-medium">Total Dibayar</span>
@@ -2429,10 +2602,10 @@ export default function Transaksi() {
                                             </div>
                                         </div>
 
-                                        {totalPaid < total && (
+                                        {totalPaid < tagihan && (
                                             <div className="bg-red-50 border border-red-200 rounded-lg p-3 flex items-center gap-2 text-red-700 text-sm font-medium">
                                                 <FontAwesomeIcon icon={faExclamationTriangle} />
-                                                <span>Pembayaran kurang {formatRupiah(total - totalPaid)}</span>
+                                                <span>Pembayaran kurang {formatRupiah(tagihan - totalPaid)}</span>
                                             </div>
                                         )}
                                     </div>
@@ -2442,7 +2615,7 @@ export default function Transaksi() {
                             <div className="p-6 bg-gray-50 border-t border-gray-300">
                                 <button
                                     onClick={processTransaction}
-                                    disabled={totalPaid < total || isProcessing || payments.length === 0}
+                                    disabled={totalPaid < tagihan || isProcessing || payments.length === 0}
                                     className="w-full bg-green-600 text-white py-3 rounded-xl font-semibold hover:bg-green-700 disabled:bg-gray-300 disabled:cursor-not-allowed transition-all duration-300 shadow-lg hover:shadow-xl"
                                 >
                                     {isProcessing ? (
@@ -2566,10 +2739,28 @@ export default function Transaksi() {
                                             <span>-{formatRupiah(lastTransaction.transaksi.diskon)}</span>
                                         </div>
                                     )}
+                                    {lastTransaction.transaksi.totalPajak > 0 && (
+                                        <div className="flex justify-between">
+                                            <span>{lastTransaction.transaksi.modePajak === 'exclusive' ? 'PPN:' : 'PPN (termasuk):'}</span>
+                                            <span>{formatRupiah(lastTransaction.transaksi.totalPajak)}</span>
+                                        </div>
+                                    )}
                                     <div className="flex justify-between font-semibold text-base">
                                         <span>Total:</span>
                                         <span>{formatRupiah(lastTransaction.transaksi.total)}</span>
                                     </div>
+                                    {!!lastTransaction.transaksi.pembulatan && (
+                                        <div className="flex justify-between">
+                                            <span>Pembulatan:</span>
+                                            <span>{formatRupiah(lastTransaction.transaksi.pembulatan)}</span>
+                                        </div>
+                                    )}
+                                    {!!lastTransaction.transaksi.donasi && (
+                                        <div className="flex justify-between">
+                                            <span>Donasi:</span>
+                                            <span>{formatRupiah(lastTransaction.transaksi.donasi)}</span>
+                                        </div>
+                                    )}
                                     <div className="flex justify-between">
                                         <span>Bayar:</span>
                                         <span>{formatRupiah(lastTransaction.transaksi.totalBayar)}</span>
@@ -0,0 +1,21 @@
+/**
+ * Idempotency key utilities
+ * A key identifies one checkout attempt so a retried request is not processed twice
+ */
+
+/**
+ * Generate a new idempotency key (UUID v4)
+ * Falls back to Math.random when crypto.randomUUID is unavailable (non-secure web origins)
+ * @returns {string}
+ */
+export const newIdempotencyKey = () => {
+  if (typeof crypto !== 'undefined' && typeof crypto.randomUUID === 'function') {
+    return crypto.randomUUID();
+  }
+
+  return 'xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx'.replace(/[xy]/g, (c) => {
+    const r = (Math.random() * 16) | 0;
+    const v = c === 'x' ? r : (r & 0x3) | 0x8;
+    return v.toString(16);
+  });
+};
@@ -6,9 +6,11 @@ import (
 
 // Claims represents the custom JWT claims for authentication
 type Claims struct {
-	UserID      int64  `json:"user_id"`
-	Username    string `json:"username"`
-	NamaLengkap string `json:"nama_lengkap"`
-	Role        string `json:"role"`
+	UserID      int64    `json:"user_id"`
+	Username    string   `json:"username"`
+	NamaLengkap string   `json:"nama_lengkap"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`           // Untuk tampilan menu saja; hak akses dicek ulang ke role saat ini
+	TerminalID  string   `json:"terminal_id,omitempty"` // Terminal tempat token diterbitkan
 	jwt.RegisteredClaims
 }
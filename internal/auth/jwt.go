@@ -22,13 +22,15 @@ func NewJWTManager(secretKey []byte, expiry time.Duration) *JWTManager {
 	}
 }
 
-// GenerateToken creates a JWT token for a user
-func (m *JWTManager) GenerateToken(user *models.User) (string, error) {
+// GenerateToken creates a JWT token for a user signed in on a terminal (empty if unknown)
+func (m *JWTManager) GenerateToken(user *models.User, terminalID string) (string, error) {
 	claims := &Claims{
 		UserID:      user.ID,
 		Username:    user.Username,
 		NamaLengkap: user.NamaLengkap,
 		Role:        user.Role,
+		Permissions: user.Permissions,
+		TerminalID:  terminalID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -86,6 +88,8 @@ func (m *JWTManager) RefreshToken(tokenString string) (string, error) {
 		Username:    claims.Username,
 		NamaLengkap: claims.NamaLengkap,
 		Role:        claims.Role,
+		Permissions: claims.Permissions,
+		TerminalID:  claims.TerminalID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -9,21 +9,38 @@ import (
 // ServiceContainer holds all business logic services
 // This ensures both Wails and HTTP handlers use the SAME service instances
 type ServiceContainer struct {
-	ProdukService      *service.ProdukService
-	KategoriService    *service.KategoriService
-	TransaksiService   *service.TransaksiService
-	PelangganService   *service.PelangganService
-	PromoService       *service.PromoService
-	ReturnService      *service.ReturnService
-	PrinterService     *service.PrinterService
-	SettingsService    *service.SettingsService
-	HardwareService    *service.HardwareService
-	AnalyticsService   *service.AnalyticsService
-	BatchService       *service.BatchService
-	UserService        *service.UserService
-	StaffReportService *service.StaffReportService
-	SalesReportService *service.SalesReportService
-	DashboardService   *service.DashboardService
+	ProdukService           *service.ProdukService
+	KategoriService         *service.KategoriService
+	TransaksiService        *service.TransaksiService
+	PelangganService        *service.PelangganService
+	PromoService            *service.PromoService
+	ReturnService           *service.ReturnService
+	PrinterService          *service.PrinterService
+	SettingsService         *service.SettingsService
+	HardwareService         *service.HardwareService
+	AnalyticsService        *service.AnalyticsService
+	BatchService            *service.BatchService
+	UserService             *service.UserService
+	StaffReportService      *service.StaffReportService
+	SalesReportService      *service.SalesReportService
+	DashboardService        *service.DashboardService
+	HeldTransaksiService    *service.HeldTransaksiService
+	IdempotencyService      *service.IdempotencyService
+	MetodePembayaranService *service.MetodePembayaranService
+	PiutangService          *service.PiutangService
+	SesiKasService          *service.SesiKasService
+	LaciKasService          *service.LaciKasService
+	AbsensiService          *service.AbsensiService
+	KomisiService           *service.KomisiService
+	TargetService           *service.TargetService
+	RoleService             *service.RoleService
+	OtorisasiService        *service.OtorisasiService
+	TerminalService         *service.TerminalService
+	AuditService            *service.AuditService
+	SupplierService         *service.SupplierService
+	PembelianService        *service.PembelianService
+	OpnameService           *service.OpnameService
+	ReturSupplierService    *service.ReturSupplierService
 }
 
 // NewServiceContainer initializes all services
@@ -31,31 +48,72 @@ func NewServiceContainer() *ServiceContainer {
 	log.Println("[CONTAINER] Initializing service container...")
 
 	container := &ServiceContainer{
-		ProdukService:      service.NewProdukService(),
-		KategoriService:    service.NewKategoriService(),
-		TransaksiService:   service.NewTransaksiService(),
-		PelangganService:   service.NewPelangganService(),
-		PromoService:       service.NewPromoService(),
-		ReturnService:      service.NewReturnService(),
-		PrinterService:     service.NewPrinterService(),
-		SettingsService:    service.NewSettingsService(),
-		HardwareService:    service.NewHardwareService(),
-		AnalyticsService:   service.NewAnalyticsService(),
-		BatchService:       service.NewBatchService(),
-		UserService:        service.NewUserService(),
-		StaffReportService: service.NewStaffReportService(),
-		SalesReportService: service.NewSalesReportService(),
-		DashboardService:   service.NewDashboardService(),
+		ProdukService:           service.NewProdukService(),
+		KategoriService:         service.NewKategoriService(),
+		TransaksiService:        service.NewTransaksiService(),
+		PelangganService:        service.NewPelangganService(),
+		PromoService:            service.NewPromoService(),
+		ReturnService:           service.NewReturnService(),
+		PrinterService:          service.NewPrinterService(),
+		SettingsService:         service.NewSettingsService(),
+		HardwareService:         service.NewHardwareService(),
+		AnalyticsService:        service.NewAnalyticsService(),
+		BatchService:            service.NewBatchService(),
+		UserService:             service.NewUserService(),
+		StaffReportService:      service.NewStaffReportService(),
+		SalesReportService:      service.NewSalesReportService(),
+		DashboardService:        service.NewDashboardService(),
+		HeldTransaksiService:    service.NewHeldTransaksiService(),
+		IdempotencyService:      service.NewIdempotencyService(),
+		MetodePembayaranService: service.NewMetodePembayaranService(),
+		PiutangService:          service.NewPiutangService(),
+		SesiKasService:          service.NewSesiKasService(),
+		LaciKasService:          service.NewLaciKasService(),
+		AbsensiService:          service.NewAbsensiService(),
+		KomisiService:           service.NewKomisiService(),
+		TargetService:           service.NewTargetService(),
+		RoleService:             service.NewRoleService(),
+		OtorisasiService:        service.NewOtorisasiService(),
+		TerminalService:         service.NewTerminalService(),
+		AuditService:            service.NewAuditService(),
+		SupplierService:         service.NewSupplierService(),
+		PembelianService:        service.NewPembelianService(),
+		OpnameService:           service.NewOpnameService(),
+		ReturSupplierService:    service.NewReturSupplierService(),
 	}
 
-    // Ensure printer settings schema exists/updated
-    if err := container.PrinterService.EnsurePrintSettingsSchema(); err != nil {
-        log.Printf("[CONTAINER] Printer settings schema init error: %v", err)
-    }
+	// Ensure printer settings schema exists/updated
+	if err := container.PrinterService.EnsurePrintSettingsSchema(); err != nil {
+		log.Printf("[CONTAINER] Printer settings schema init error: %v", err)
+	}
+
+	// Ensure the built-in roles exist before any user logs in
+	if err := container.RoleService.EnsureDefaultRoles(); err != nil {
+		log.Printf("[CONTAINER] Role init error: %v", err)
+	}
 
 	// Ensure default admin exists
 	container.UserService.EnsureDefaultAdmin()
 
+	// Ensure the standard payment methods exist
+	if err := container.MetodePembayaranService.EnsureDefaultMetode(); err != nil {
+		log.Printf("[CONTAINER] Payment method init error: %v", err)
+	}
+
+	// Expire drafts held during a shift that has already ended
+	if expired, err := container.HeldTransaksiService.ExpireHeldTransaksi(); err != nil {
+		log.Printf("[CONTAINER] Held transaction expiry error: %v", err)
+	} else if expired > 0 {
+		log.Printf("[CONTAINER] Expired %d held transaction(s)", expired)
+	}
+
+	// Drop idempotency keys that can no longer be replayed
+	if removed, err := container.IdempotencyService.CleanupExpired(); err != nil {
+		log.Printf("[CONTAINER] Idempotency key cleanup error: %v", err)
+	} else if removed > 0 {
+		log.Printf("[CONTAINER] Removed %d expired idempotency key(s)", removed)
+	}
+
 	log.Println("[CONTAINER] All services initialized successfully")
 	return container
 }
@@ -793,6 +793,10 @@ func fixSchemaIssues() {
 	if err := FixTransaksiStaffColumnsSchema(); err != nil {
 		log.Printf("Warning: Failed to fix transaksi staff columns schema: %v", err)
 	}
+
+	if err := FixTransaksiItemPajakSchema(); err != nil {
+		log.Printf("Warning: Failed to fix transaksi_item pajak schema: %v", err)
+	}
 }
 
 // FixPostgresIDSchema updates PostgreSQL columns to BIGINT to support offline IDs
@@ -825,6 +829,7 @@ func FixPostgresIDSchema(db *sql.DB) error {
 
 		{"returns", "id"},
 		{"returns", "transaksi_id"},
+		{"returns", "staff_id"},
 
 		{"return_items", "id"},
 		{"return_items", "return_id"},
@@ -833,6 +838,110 @@ func FixPostgresIDSchema(db *sql.DB) error {
 		{"transaksi_batch", "id"},
 		{"transaksi_batch", "transaksi_item_id"},
 		{"transaksi_batch", "batch_id"},
+
+		{"transaksi_item_promo", "id"},
+		{"transaksi_item_promo", "transaksi_id"},
+		{"transaksi_item_promo", "transaksi_item_id"},
+
+		{"held_transaksi", "id"},
+		{"held_transaksi", "staff_id"},
+
+		{"transaksi", "shift_session_id"}, // FK to sesi_kas.id
+		{"transaksi_item_promo", "produk_id"},
+		{"transaksi_item_promo", "promo_id"},
+
+		{"metode_pembayaran", "id"},
+
+		{"saldo_pelanggan_history", "id"},
+		{"saldo_pelanggan_history", "pelanggan_id"},
+		{"saldo_pelanggan_history", "transaksi_id"},
+		{"saldo_pelanggan_history", "return_id"},
+		{"saldo_pelanggan_history", "staff_id"},
+
+		{"piutang", "id"},
+		{"piutang", "transaksi_id"},
+		{"piutang", "pelanggan_id"},
+
+		{"pembayaran_piutang", "id"},
+		{"pembayaran_piutang", "piutang_id"}, // FK to piutang.id
+		{"pembayaran_piutang", "pelanggan_id"},
+		{"pembayaran_piutang", "staff_id"},
+
+		{"sesi_kas", "id"},
+		{"sesi_kas", "staff_id"},
+
+		{"sesi_kas_mutasi", "id"},
+		{"sesi_kas_mutasi", "sesi_id"}, // FK to sesi_kas.id
+		{"sesi_kas_mutasi", "staff_id"},
+
+		{"laci_kas_log", "id"},
+		{"laci_kas_log", "transaksi_id"},
+		{"laci_kas_log", "sesi_id"},
+		{"laci_kas_log", "staff_id"},
+
+		{"absensi", "id"},
+		{"absensi", "staff_id"},
+
+		{"absensi_istirahat", "id"},
+		{"absensi_istirahat", "absensi_id"}, // FK to absensi.id
+
+		{"komisi_aturan", "id"},
+		{"komisi_periode", "id"},
+
+		{"komisi_staff", "id"},
+		{"komisi_staff", "periode_id"}, // FK to komisi_periode.id
+		{"komisi_staff", "staff_id"},
+
+		{"target_penjualan", "id"},
+		{"target_penjualan", "staff_id"},
+
+		{"role", "id"},
+
+		{"otorisasi", "id"},
+		{"otorisasi", "supervisor_id"},
+		{"otorisasi", "diminta_oleh_id"},
+
+		{"password_history", "id"},
+		{"password_history", "user_id"},
+
+		{"login_events", "id"},
+		{"login_events", "user_id"},
+
+		{"audit_log", "id"},
+		{"audit_log", "aktor_id"},
+
+		{"supplier", "id"},
+
+		{"pesanan_pembelian", "id"},
+		{"pesanan_pembelian", "supplier_id"}, // FK to supplier.id
+
+		{"pesanan_pembelian_item", "id"},
+		{"pesanan_pembelian_item", "pesanan_id"}, // FK to pesanan_pembelian.id
+		{"pesanan_pembelian_item", "produk_id"},
+
+		{"penerimaan_barang", "id"},
+		{"penerimaan_barang", "pesanan_id"},
+		{"penerimaan_barang", "supplier_id"},
+
+		{"penerimaan_barang_item", "id"},
+		{"penerimaan_barang_item", "penerimaan_id"}, // FK to penerimaan_barang.id
+		{"penerimaan_barang_item", "pesanan_item_id"},
+		{"penerimaan_barang_item", "produk_id"},
+
+		{"batch", "penerimaan_id"},
+
+		{"stok_opname", "id"},
+
+		{"stok_opname_item", "id"},
+		{"stok_opname_item", "opname_id"}, // FK to stok_opname.id
+		{"stok_opname_item", "produk_id"},
+
+		{"retur_supplier", "id"},
+		{"retur_supplier", "supplier_id"},
+
+		{"retur_supplier_item", "id"},
+		{"retur_supplier_item", "retur_id"}, // FK to retur_supplier.id
+		{"retur_supplier_item", "produk_id"},
 	}
 
 	for _, target := range targets {
@@ -926,6 +1035,41 @@ func FixTransaksiItemBeratGramSchema() error {
 	return nil
 }
 
+// FixTransaksiItemPajakSchema restores the PPN columns of transaksi_item. On a new SQLite
+// database the produk_id rebuild in FixTransaksiItemSchema runs after the migrations that add them.
+func FixTransaksiItemPajakSchema() error {
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"tarif_pajak", "REAL DEFAULT 0"},
+		{"dpp", "INTEGER DEFAULT 0"},
+		{"pajak", "INTEGER DEFAULT 0"},
+	}
+
+	for _, column := range columns {
+		var count int
+		query := `SELECT COUNT(*) FROM pragma_table_info('transaksi_item') WHERE name=?`
+		if IsPostgreSQL() {
+			query = `SELECT COUNT(*) FROM information_schema.columns WHERE table_name='transaksi_item' AND column_name=$1`
+		}
+		if err := DB.QueryRow(query, column.name).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check for %s column: %w", column.name, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("[SCHEMA FIX] Adding missing column '%s' to 'transaksi_item'...", column.name)
+		if _, err := DB.Exec("ALTER TABLE transaksi_item ADD COLUMN " + column.name + " " + column.definition); err != nil {
+			return fmt.Errorf("failed to add %s column: %w", column.name, err)
+		}
+		log.Printf("[SCHEMA FIX] ✓ Column '%s' added successfully", column.name)
+	}
+
+	return nil
+}
+
 // createFileBackup creates a full file-level backup of the database.
 // createFileBackup creates a full file-level backup of the database.
 // It checks if a backup for the current day already exists to avoid duplication.
@@ -1542,6 +1686,561 @@ func getTableCreationQueries() []string {
             updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
         )`,
 
+		// Penomoran transaksi (lokal per perangkat/terminal, tidak disinkronkan)
+		`CREATE TABLE IF NOT EXISTS penomoran_settings (
+            id INTEGER PRIMARY KEY,
+            kode_toko TEXT DEFAULT 'STORE1',
+            kode_terminal TEXT DEFAULT 'T01',
+            pemisah TEXT DEFAULT '-',
+            format_tanggal TEXT DEFAULT '20060102',
+            panjang_urutan INTEGER DEFAULT 6,
+            reset_harian INTEGER DEFAULT 1,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Pembulatan tunai (berlaku untuk seluruh toko)
+		`CREATE TABLE IF NOT EXISTS pembulatan_settings (
+            id INTEGER PRIMARY KEY,
+            aktif INTEGER DEFAULT 0,
+            kelipatan INTEGER DEFAULT 100,
+            mode TEXT DEFAULT 'nearest',
+            donasi_aktif INTEGER DEFAULT 0,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Pajak (PPN) toko; tarif per produk/kategori menimpa tarif default
+		`CREATE TABLE IF NOT EXISTS pajak_settings (
+            id INTEGER PRIMARY KEY,
+            aktif INTEGER DEFAULT 0,
+            nama_pajak TEXT DEFAULT 'PPN',
+            tarif_default REAL DEFAULT 11,
+            mode TEXT DEFAULT 'exclusive',
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Metode pembayaran yang dapat dipilih di kasir
+		`CREATE TABLE IF NOT EXISTS metode_pembayaran (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            kode TEXT UNIQUE NOT NULL,
+            nama TEXT NOT NULL,
+            tipe TEXT NOT NULL DEFAULT 'lainnya',
+            buka_laci INTEGER DEFAULT 0,
+            perlu_referensi INTEGER DEFAULT 0,
+            aktif INTEGER DEFAULT 1,
+            urutan INTEGER DEFAULT 0,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Saldo pelanggan (deposit / store credit) - setiap mutasi tercatat dengan referensinya
+		`CREATE TABLE IF NOT EXISTS saldo_pelanggan_history (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            pelanggan_id INTEGER NOT NULL,
+            tipe TEXT NOT NULL,
+            jumlah INTEGER NOT NULL,
+            saldo_sebelum INTEGER NOT NULL,
+            saldo_sesudah INTEGER NOT NULL,
+            metode TEXT,
+            transaksi_id INTEGER,
+            return_id INTEGER,
+            referensi TEXT,
+            keterangan TEXT,
+            staff_id INTEGER,
+            staff_nama TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (pelanggan_id) REFERENCES pelanggan(id)
+        )`,
+
+		// Piutang penjualan tempo per transaksi
+		`CREATE TABLE IF NOT EXISTS piutang (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            transaksi_id INTEGER NOT NULL,
+            nomor_transaksi TEXT NOT NULL,
+            pelanggan_id INTEGER NOT NULL,
+            jumlah INTEGER NOT NULL,
+            terbayar INTEGER DEFAULT 0,
+            jatuh_tempo DATETIME NOT NULL,
+            status TEXT DEFAULT 'belum_lunas',
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (transaksi_id) REFERENCES transaksi(id),
+            FOREIGN KEY (pelanggan_id) REFERENCES pelanggan(id)
+        )`,
+
+		// Pelunasan piutang (pergerakan kas tersendiri, dihitung di laporan shift)
+		`CREATE TABLE IF NOT EXISTS pembayaran_piutang (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            piutang_id INTEGER NOT NULL,
+            pelanggan_id INTEGER NOT NULL,
+            jumlah INTEGER NOT NULL,
+            metode TEXT NOT NULL,
+            referensi TEXT,
+            staff_id INTEGER,
+            staff_nama TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (piutang_id) REFERENCES piutang(id)
+        )`,
+
+		// Sesi laci kas: modal awal, kas masuk/keluar dan hitung tutup (laporan X/Z)
+		`CREATE TABLE IF NOT EXISTS sesi_kas (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            terminal_id TEXT,
+            staff_id INTEGER NOT NULL,
+            staff_nama TEXT,
+            status TEXT NOT NULL DEFAULT 'buka',
+            modal_awal INTEGER NOT NULL DEFAULT 0,
+            penjualan_tunai INTEGER DEFAULT 0,
+            pelunasan_tunai INTEGER DEFAULT 0,
+            topup_tunai INTEGER DEFAULT 0,
+            kas_masuk INTEGER DEFAULT 0,
+            kas_keluar INTEGER DEFAULT 0,
+            kas_diharapkan INTEGER DEFAULT 0,
+            kas_dihitung INTEGER DEFAULT 0,
+            selisih INTEGER DEFAULT 0,
+            pecahan TEXT,
+            catatan_tutup TEXT,
+            ditutup_oleh TEXT,
+            dibuka_at DATETIME NOT NULL,
+            ditutup_at DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Kas masuk / keluar selama sesi laci kas
+		`CREATE TABLE IF NOT EXISTS sesi_kas_mutasi (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            sesi_id INTEGER NOT NULL,
+            tipe TEXT NOT NULL,
+            jumlah INTEGER NOT NULL,
+            alasan TEXT NOT NULL,
+            staff_id INTEGER,
+            staff_nama TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (sesi_id) REFERENCES sesi_kas(id)
+        )`,
+
+		// Pengaturan laci kas per terminal (tidak disinkronkan)
+		`CREATE TABLE IF NOT EXISTS laci_kas_settings (
+            id INTEGER PRIMARY KEY,
+            aktif INTEGER DEFAULT 0,
+            driver TEXT DEFAULT 'printer',
+            printer_name TEXT,
+            port TEXT,
+            baud_rate INTEGER DEFAULT 9600,
+            pin INTEGER DEFAULT 0,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Log setiap pembukaan laci kas (transaksi dan no sale)
+		`CREATE TABLE IF NOT EXISTS laci_kas_log (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            tipe TEXT NOT NULL,
+            alasan TEXT,
+            transaksi_id INTEGER,
+            nomor_transaksi TEXT,
+            sesi_id INTEGER,
+            staff_id INTEGER,
+            staff_nama TEXT,
+            berhasil INTEGER DEFAULT 0,
+            pesan TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Absensi karyawan: jam masuk/pulang, keterlambatan dan lembur terhadap jadwal shift
+		`CREATE TABLE IF NOT EXISTS absensi (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            staff_id INTEGER NOT NULL,
+            staff_nama TEXT,
+            tanggal TEXT NOT NULL,
+            shift_id INTEGER,
+            shift_nama TEXT,
+            jadwal_masuk TEXT,
+            jadwal_pulang TEXT,
+            status TEXT NOT NULL DEFAULT 'masuk',
+            metode_masuk TEXT,
+            metode_pulang TEXT,
+            jam_masuk DATETIME NOT NULL,
+            jam_pulang DATETIME,
+            terlambat_menit INTEGER DEFAULT 0,
+            pulang_cepat_menit INTEGER DEFAULT 0,
+            lembur_menit INTEGER DEFAULT 0,
+            istirahat_menit INTEGER DEFAULT 0,
+            kerja_menit INTEGER DEFAULT 0,
+            catatan TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Istirahat selama satu absensi
+		`CREATE TABLE IF NOT EXISTS absensi_istirahat (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            absensi_id INTEGER NOT NULL,
+            mulai DATETIME NOT NULL,
+            selesai DATETIME,
+            durasi_menit INTEGER DEFAULT 0,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (absensi_id) REFERENCES absensi(id)
+        )`,
+
+		// Aturan komisi / insentif staff
+		`CREATE TABLE IF NOT EXISTS komisi_aturan (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            nama TEXT NOT NULL,
+            tipe TEXT NOT NULL,
+            persen REAL DEFAULT 0,
+            nominal_per_unit INTEGER DEFAULT 0,
+            produk_ids TEXT,
+            kategori TEXT,
+            staff_ids TEXT,
+            tingkat TEXT,
+            aktif INTEGER DEFAULT 1,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Periode pembayaran komisi (payroll)
+		`CREATE TABLE IF NOT EXISTS komisi_periode (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            periode_mulai TEXT NOT NULL,
+            periode_selesai TEXT NOT NULL,
+            status TEXT NOT NULL DEFAULT 'draft',
+            total_komisi INTEGER DEFAULT 0,
+            catatan TEXT,
+            dibuat_oleh TEXT,
+            dibayar_at DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Komisi per staff dalam satu periode, rincian per aturan disimpan sebagai JSON
+		`CREATE TABLE IF NOT EXISTS komisi_staff (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            periode_id INTEGER NOT NULL,
+            staff_id INTEGER NOT NULL,
+            staff_nama TEXT,
+            total_penjualan INTEGER DEFAULT 0,
+            total_profit INTEGER DEFAULT 0,
+            total_item INTEGER DEFAULT 0,
+            retur_penjualan INTEGER DEFAULT 0,
+            retur_profit INTEGER DEFAULT 0,
+            retur_item REAL DEFAULT 0,
+            total_komisi INTEGER DEFAULT 0,
+            rincian TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (periode_id) REFERENCES komisi_periode(id)
+        )`,
+
+		// Target penjualan harian/mingguan/bulanan untuk toko, shift dan staff
+		`CREATE TABLE IF NOT EXISTS target_penjualan (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            periode TEXT NOT NULL,
+            metrik TEXT NOT NULL,
+            lingkup TEXT NOT NULL,
+            shift_id INTEGER DEFAULT 0,
+            staff_id INTEGER DEFAULT 0,
+            nilai REAL NOT NULL,
+            aktif INTEGER DEFAULT 1,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Role dan hak akses; users.role menyimpan nama role
+		`CREATE TABLE IF NOT EXISTS role (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            nama TEXT UNIQUE NOT NULL,
+            deskripsi TEXT DEFAULT '',
+            permissions TEXT DEFAULT '[]',
+            sistem INTEGER DEFAULT 0,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Batas aksi kasir yang membutuhkan otorisasi supervisor
+		`CREATE TABLE IF NOT EXISTS otorisasi_settings (
+            id INTEGER PRIMARY KEY,
+            batas_diskon_manual INTEGER DEFAULT 0,
+            batas_refund INTEGER DEFAULT 100000,
+            wajib_no_sale INTEGER DEFAULT 1,
+            wajib_penyesuaian_stok INTEGER DEFAULT 0,
+            masa_berlaku_detik INTEGER DEFAULT 120,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Kebijakan keamanan login: kunci akun setelah gagal berulang dan kunci terminal saat tidak aktif
+		`CREATE TABLE IF NOT EXISTS keamanan_settings (
+            id INTEGER PRIMARY KEY,
+            auto_lock_menit INTEGER DEFAULT 5,
+            maks_gagal_login INTEGER DEFAULT 5,
+            durasi_kunci_menit INTEGER DEFAULT 15,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Token otorisasi supervisor: sekali pakai, berlaku singkat, terikat pada satu aksi.
+		// Hanya hash token yang disimpan.
+		`CREATE TABLE IF NOT EXISTS otorisasi (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            token_hash TEXT UNIQUE NOT NULL,
+            aksi TEXT NOT NULL,
+            referensi TEXT DEFAULT '',
+            supervisor_id INTEGER NOT NULL,
+            supervisor_username TEXT NOT NULL,
+            supervisor_nama TEXT,
+            diminta_oleh_id INTEGER,
+            diminta_oleh_nama TEXT,
+            expires_at DATETIME NOT NULL,
+            digunakan_at DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Riwayat hash password per user, agar password lama tidak dipakai ulang
+		`CREATE TABLE IF NOT EXISTS password_history (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER NOT NULL,
+            password_hash TEXT NOT NULL,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (user_id) REFERENCES users(id)
+        )`,
+
+		// Audit login: setiap percobaan login berhasil maupun gagal
+		`CREATE TABLE IF NOT EXISTS login_events (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            user_id INTEGER,
+            username TEXT NOT NULL,
+            metode TEXT NOT NULL,
+            berhasil INTEGER NOT NULL DEFAULT 0,
+            alasan TEXT,
+            ip_address TEXT,
+            terminal_id TEXT,
+            user_agent TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Audit trail perubahan data (append-only): siapa, entitas apa, dan nilai sebelum/sesudah dalam JSON
+		`CREATE TABLE IF NOT EXISTS audit_log (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            aktor_id INTEGER,
+            aktor_nama TEXT,
+            entitas TEXT NOT NULL,
+            entitas_id TEXT,
+            aksi TEXT NOT NULL,
+            perubahan TEXT,
+            keterangan TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Cara harga beli produk diperbarui saat barang diterima
+		`CREATE TABLE IF NOT EXISTS pembelian_settings (
+            id INTEGER PRIMARY KEY,
+            metode_harga_beli TEXT DEFAULT 'tetap',
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Supplier (pemasok barang)
+		`CREATE TABLE IF NOT EXISTS supplier (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            kode TEXT UNIQUE NOT NULL,
+            nama TEXT NOT NULL,
+            kontak TEXT,
+            telepon TEXT,
+            email TEXT,
+            alamat TEXT,
+            catatan TEXT,
+            aktif INTEGER DEFAULT 1,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Pesanan pembelian (purchase order) ke supplier
+		`CREATE TABLE IF NOT EXISTS pesanan_pembelian (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            nomor_po TEXT UNIQUE NOT NULL,
+            supplier_id INTEGER NOT NULL,
+            tanggal DATETIME DEFAULT CURRENT_TIMESTAMP,
+            tanggal_diharapkan TEXT,
+            status TEXT NOT NULL DEFAULT 'draft',
+            total INTEGER DEFAULT 0,
+            catatan TEXT,
+            dibuat_oleh TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (supplier_id) REFERENCES supplier(id)
+        )`,
+
+		// Item pesanan pembelian beserta jumlah yang sudah diterima
+		`CREATE TABLE IF NOT EXISTS pesanan_pembelian_item (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            pesanan_id INTEGER NOT NULL,
+            produk_id INTEGER NOT NULL,
+            produk_nama TEXT NOT NULL,
+            qty_pesan REAL NOT NULL,
+            qty_diterima REAL NOT NULL DEFAULT 0,
+            harga_beli INTEGER NOT NULL DEFAULT 0,
+            subtotal INTEGER NOT NULL DEFAULT 0,
+            FOREIGN KEY (pesanan_id) REFERENCES pesanan_pembelian(id) ON DELETE CASCADE,
+            FOREIGN KEY (produk_id) REFERENCES produk(id)
+        )`,
+
+		// Penerimaan barang: satu kali kedatangan barang dari supplier
+		`CREATE TABLE IF NOT EXISTS penerimaan_barang (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            nomor_penerimaan TEXT UNIQUE NOT NULL,
+            pesanan_id INTEGER,
+            supplier_id INTEGER,
+            tanggal DATETIME DEFAULT CURRENT_TIMESTAMP,
+            no_faktur TEXT,
+            total INTEGER DEFAULT 0,
+            catatan TEXT,
+            diterima_oleh TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (pesanan_id) REFERENCES pesanan_pembelian(id),
+            FOREIGN KEY (supplier_id) REFERENCES supplier(id)
+        )`,
+
+		// Item penerimaan barang dengan harga beli sebenarnya dan batch yang dibuat
+		`CREATE TABLE IF NOT EXISTS penerimaan_barang_item (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            penerimaan_id INTEGER NOT NULL,
+            pesanan_item_id INTEGER,
+            produk_id INTEGER NOT NULL,
+            produk_nama TEXT NOT NULL,
+            qty REAL NOT NULL,
+            harga_beli INTEGER NOT NULL DEFAULT 0,
+            subtotal INTEGER NOT NULL DEFAULT 0,
+            masa_simpan_hari INTEGER DEFAULT 0,
+            tanggal_kadaluarsa TEXT,
+            batch_id TEXT,
+            FOREIGN KEY (penerimaan_id) REFERENCES penerimaan_barang(id) ON DELETE CASCADE,
+            FOREIGN KEY (produk_id) REFERENCES produk(id)
+        )`,
+
+		// Stok opname: sesi hitung fisik stok, semua produk atau satu kategori
+		`CREATE TABLE IF NOT EXISTS stok_opname (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            nomor_opname TEXT UNIQUE NOT NULL,
+            kategori TEXT,
+            status TEXT NOT NULL DEFAULT 'dibuka',
+            catatan TEXT,
+            dibuka_oleh TEXT,
+            diposting_oleh TEXT,
+            tanggal_mulai DATETIME DEFAULT CURRENT_TIMESTAMP,
+            tanggal_posting DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Item stok opname dengan stok sistem yang dibekukan saat sesi dibuka
+		`CREATE TABLE IF NOT EXISTS stok_opname_item (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            opname_id INTEGER NOT NULL,
+            produk_id INTEGER NOT NULL,
+            produk_nama TEXT NOT NULL,
+            barcode TEXT,
+            kategori TEXT,
+            satuan TEXT,
+            qty_sistem REAL NOT NULL DEFAULT 0,
+            qty_hitung REAL,
+            harga_beli INTEGER NOT NULL DEFAULT 0,
+            dihitung_oleh TEXT,
+            perangkat TEXT,
+            dihitung_pada DATETIME,
+            FOREIGN KEY (opname_id) REFERENCES stok_opname(id) ON DELETE CASCADE,
+            FOREIGN KEY (produk_id) REFERENCES produk(id)
+        )`,
+
+		// Retur barang kedaluwarsa atau rusak ke supplier
+		`CREATE TABLE IF NOT EXISTS retur_supplier (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            nomor_retur TEXT UNIQUE NOT NULL,
+            supplier_id INTEGER NOT NULL,
+            tanggal DATETIME DEFAULT CURRENT_TIMESTAMP,
+            alasan TEXT NOT NULL,
+            jenis_kompensasi TEXT NOT NULL,
+            status TEXT NOT NULL DEFAULT 'menunggu',
+            total_nilai INTEGER DEFAULT 0,
+            nilai_kompensasi INTEGER DEFAULT 0,
+            no_nota_kredit TEXT,
+            catatan TEXT,
+            dibuat_oleh TEXT,
+            diselesaikan_oleh TEXT,
+            tanggal_selesai DATETIME,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (supplier_id) REFERENCES supplier(id)
+        )`,
+
+		// Item retur supplier beserta batch asal barang
+		`CREATE TABLE IF NOT EXISTS retur_supplier_item (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            retur_id INTEGER NOT NULL,
+            batch_id TEXT,
+            produk_id INTEGER NOT NULL,
+            produk_nama TEXT NOT NULL,
+            qty REAL NOT NULL,
+            harga_beli INTEGER NOT NULL DEFAULT 0,
+            subtotal INTEGER NOT NULL DEFAULT 0,
+            tanggal_kadaluarsa TEXT,
+            FOREIGN KEY (retur_id) REFERENCES retur_supplier(id) ON DELETE CASCADE,
+            FOREIGN KEY (produk_id) REFERENCES produk(id)
+        )`,
+
+		// Counter nomor transaksi per prefix (toko-terminal-tanggal), direservasi di dalam transaksi insert
+		`CREATE TABLE IF NOT EXISTS nomor_transaksi_counter (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            counter_key TEXT UNIQUE NOT NULL,
+            last_number INTEGER NOT NULL DEFAULT 0,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
+		// Idempotency keys (respons tersimpan untuk request yang di-retry, lokal per perangkat)
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            scope TEXT NOT NULL,
+            idem_key TEXT NOT NULL,
+            request_hash TEXT,
+            status TEXT NOT NULL DEFAULT 'processing',
+            status_code INTEGER DEFAULT 0,
+            response TEXT,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            UNIQUE (scope, idem_key)
+        )`,
+
+		// Rincian diskon promo per item transaksi
+		`CREATE TABLE IF NOT EXISTS transaksi_item_promo (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            transaksi_id INTEGER NOT NULL,
+            transaksi_item_id INTEGER NOT NULL,
+            produk_id INTEGER,
+            promo_id INTEGER NOT NULL,
+            promo_kode TEXT NOT NULL,
+            promo_nama TEXT,
+            tipe_promo TEXT,
+            diskon INTEGER NOT NULL DEFAULT 0,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            FOREIGN KEY (transaksi_id) REFERENCES transaksi(id) ON DELETE CASCADE
+        )`,
+
+		// Held transaksi table (Parked sales waiting to be resumed)
+		`CREATE TABLE IF NOT EXISTS held_transaksi (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            label TEXT,
+            terminal_id TEXT,
+            staff_id INTEGER,
+            staff_nama TEXT,
+            draft TEXT NOT NULL,
+            jumlah_item INTEGER DEFAULT 0,
+            subtotal INTEGER DEFAULT 0,
+            status TEXT DEFAULT 'held',
+            expires_at DATETIME NOT NULL,
+            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+
 		`CREATE TABLE IF NOT EXISTS sync_meta (
             key TEXT PRIMARY KEY,
             value TEXT NOT NULL
@@ -1570,6 +2269,7 @@ func getIndexCreationQueries() []string {
 		`CREATE INDEX IF NOT EXISTS idx_kategori_nama ON kategori(nama)`,
 		`CREATE INDEX IF NOT EXISTS idx_transaksi_nomor ON transaksi(nomor_transaksi)`,
 		`CREATE INDEX IF NOT EXISTS idx_transaksi_tanggal ON transaksi(tanggal)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_transaksi_idempotency_key ON transaksi(idempotency_key)`,
 		`CREATE INDEX IF NOT EXISTS idx_transaksi_item_transaksi ON transaksi_item(transaksi_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_pelanggan_telepon ON pelanggan(telepon)`,
 		`CREATE INDEX IF NOT EXISTS idx_pelanggan_tipe ON pelanggan(tipe)`,
@@ -1592,6 +2292,38 @@ func getIndexCreationQueries() []string {
 		`CREATE INDEX IF NOT EXISTS idx_produk_deleted_at ON produk(deleted_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_promo_deleted_at ON promo(deleted_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_kategori_deleted_at ON kategori(deleted_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaksi_item_promo_transaksi ON transaksi_item_promo(transaksi_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaksi_item_promo_promo ON transaksi_item_promo(promo_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_held_transaksi_status ON held_transaksi(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_held_transaksi_terminal ON held_transaksi(terminal_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_saldo_pelanggan_history_pelanggan ON saldo_pelanggan_history(pelanggan_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_piutang_pelanggan ON piutang(pelanggan_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_piutang_transaksi ON piutang(transaksi_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_pembayaran_piutang_piutang ON pembayaran_piutang(piutang_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_pembayaran_piutang_created ON pembayaran_piutang(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sesi_kas_staff ON sesi_kas(staff_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_sesi_kas_mutasi_sesi ON sesi_kas_mutasi(sesi_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaksi_shift_session ON transaksi(shift_session_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_laci_kas_log_staff ON laci_kas_log(staff_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_absensi_staff_tanggal ON absensi(staff_id, tanggal)`,
+		`CREATE INDEX IF NOT EXISTS idx_absensi_istirahat_absensi ON absensi_istirahat(absensi_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_komisi_staff_periode ON komisi_staff(periode_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_target_penjualan_lingkup ON target_penjualan(lingkup, aktif)`,
+		`CREATE INDEX IF NOT EXISTS idx_otorisasi_created ON otorisasi(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_password_history_user ON password_history(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_events_user ON login_events(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_entitas ON audit_log(entitas, entitas_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_pesanan_pembelian_supplier ON pesanan_pembelian(supplier_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_pesanan_pembelian_status ON pesanan_pembelian(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_pesanan_pembelian_item_pesanan ON pesanan_pembelian_item(pesanan_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_penerimaan_barang_pesanan ON penerimaan_barang(pesanan_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_penerimaan_barang_item_penerimaan ON penerimaan_barang_item(penerimaan_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_stok_opname_status ON stok_opname(status)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_stok_opname_item_produk ON stok_opname_item(opname_id, produk_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_retur_supplier_supplier ON retur_supplier(supplier_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_retur_supplier_tanggal ON retur_supplier(tanggal)`,
+		`CREATE INDEX IF NOT EXISTS idx_retur_supplier_item_retur ON retur_supplier_item(retur_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_sync_queue_status ON sync_queue(status)`,
 		`CREATE INDEX IF NOT EXISTS idx_sync_queue_created ON sync_queue(created_at)`,
 	}
@@ -1681,7 +2413,11 @@ func createSyncOutboxTriggers() error {
 		switch {
 		case name == "migrations" || name == "sync_queue" || name == "sync_meta" || name == "schema_migrations":
 			continue
-		case name == "shift_settings" || name == "shift_cashier":
+		case name == "shift_settings" || name == "shift_cashier" || name == "shift_staff":
+			continue
+		case name == "penomoran_settings" || name == "nomor_transaksi_counter" || name == "idempotency_keys" ||
+			name == "laci_kas_settings" || name == "otorisasi":
+			// Per-terminal numbering and hardware state must stay local to the device
 			continue
 		case strings.HasPrefix(name, "transaksi_item_backup_"):
 			continue
@@ -2162,6 +2898,154 @@ func getMigrationList() []struct {
 			name:  "add_promo_min_gramasi",
 			query: `ALTER TABLE promo ADD COLUMN min_gramasi INTEGER DEFAULT 0`,
 		},
+		{
+			name:  "add_transaksi_idempotency_key",
+			query: `ALTER TABLE transaksi ADD COLUMN idempotency_key TEXT`,
+		},
+		{
+			name:  "add_users_pin",
+			query: `ALTER TABLE users ADD COLUMN pin TEXT`,
+		},
+		{
+			name:  "add_transaksi_poin_didapat",
+			query: `ALTER TABLE transaksi ADD COLUMN poin_didapat INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_transaksi_void_alasan",
+			query: `ALTER TABLE transaksi ADD COLUMN void_alasan TEXT`,
+		},
+		{
+			name:  "add_transaksi_void_by",
+			query: `ALTER TABLE transaksi ADD COLUMN void_by TEXT`,
+		},
+		{
+			name:  "add_transaksi_void_approved_by",
+			query: `ALTER TABLE transaksi ADD COLUMN void_approved_by TEXT`,
+		},
+		{
+			name:  "add_transaksi_void_at",
+			query: `ALTER TABLE transaksi ADD COLUMN void_at DATETIME`,
+		},
+		{
+			name:  "add_transaksi_pembulatan",
+			query: `ALTER TABLE transaksi ADD COLUMN pembulatan INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_transaksi_donasi",
+			query: `ALTER TABLE transaksi ADD COLUMN donasi INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_produk_tarif_pajak",
+			query: `ALTER TABLE produk ADD COLUMN tarif_pajak REAL`,
+		},
+		{
+			name:  "add_kategori_tarif_pajak",
+			query: `ALTER TABLE kategori ADD COLUMN tarif_pajak REAL`,
+		},
+		{
+			name:  "add_transaksi_total_pajak",
+			query: `ALTER TABLE transaksi ADD COLUMN total_pajak INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_transaksi_mode_pajak",
+			query: `ALTER TABLE transaksi ADD COLUMN mode_pajak TEXT`,
+		},
+		{
+			name:  "add_transaksi_item_tarif_pajak",
+			query: `ALTER TABLE transaksi_item ADD COLUMN tarif_pajak REAL DEFAULT 0`,
+		},
+		{
+			name:  "add_transaksi_item_dpp",
+			query: `ALTER TABLE transaksi_item ADD COLUMN dpp INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_transaksi_item_pajak",
+			query: `ALTER TABLE transaksi_item ADD COLUMN pajak INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_returns_total_pajak",
+			query: `ALTER TABLE returns ADD COLUMN total_pajak INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_pelanggan_saldo",
+			query: `ALTER TABLE pelanggan ADD COLUMN saldo INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_pelanggan_limit_kredit",
+			query: `ALTER TABLE pelanggan ADD COLUMN limit_kredit INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_pelanggan_tempo_hari",
+			query: `ALTER TABLE pelanggan ADD COLUMN tempo_hari INTEGER DEFAULT 30`,
+		},
+		{
+			name:  "add_transaksi_shift_session_id",
+			query: `ALTER TABLE transaksi ADD COLUMN shift_session_id INTEGER`,
+		},
+		{
+			name:  "add_sesi_kas_shift_id",
+			query: `ALTER TABLE sesi_kas ADD COLUMN shift_id INTEGER`,
+		},
+		{
+			name:  "add_transaksi_diskon_manual",
+			query: `ALTER TABLE transaksi ADD COLUMN diskon_manual INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_transaksi_disetujui_oleh",
+			query: `ALTER TABLE transaksi ADD COLUMN disetujui_oleh TEXT`,
+		},
+		{
+			name:  "add_returns_disetujui_oleh",
+			query: `ALTER TABLE returns ADD COLUMN disetujui_oleh TEXT`,
+		},
+		{
+			name:  "add_stok_history_disetujui_oleh",
+			query: `ALTER TABLE stok_history ADD COLUMN disetujui_oleh TEXT`,
+		},
+		{
+			name:  "add_laci_kas_log_disetujui_oleh",
+			query: `ALTER TABLE laci_kas_log ADD COLUMN disetujui_oleh TEXT`,
+		},
+		{
+			name:  "add_users_gagal_login",
+			query: `ALTER TABLE users ADD COLUMN gagal_login INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_users_terkunci_sampai",
+			query: `ALTER TABLE users ADD COLUMN terkunci_sampai DATETIME`,
+		},
+		{
+			name:  "add_users_jumlah_kunci",
+			query: `ALTER TABLE users ADD COLUMN jumlah_kunci INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_keamanan_settings_durasi_kunci_maks_menit",
+			query: `ALTER TABLE keamanan_settings ADD COLUMN durasi_kunci_maks_menit INTEGER DEFAULT 1440`,
+		},
+		{
+			name:  "add_keamanan_settings_panjang_min_password",
+			query: `ALTER TABLE keamanan_settings ADD COLUMN panjang_min_password INTEGER DEFAULT 6`,
+		},
+		{
+			name:  "add_keamanan_settings_riwayat_password",
+			query: `ALTER TABLE keamanan_settings ADD COLUMN riwayat_password INTEGER DEFAULT 3`,
+		},
+		{
+			name:  "add_batch_harga_beli",
+			query: `ALTER TABLE batch ADD COLUMN harga_beli INTEGER DEFAULT 0`,
+		},
+		{
+			name:  "add_batch_penerimaan_id",
+			query: `ALTER TABLE batch ADD COLUMN penerimaan_id INTEGER`,
+		},
+		{
+			name:  "add_returns_staff_id",
+			query: `ALTER TABLE returns ADD COLUMN staff_id INTEGER`,
+		},
+		{
+			name:  "add_sesi_kas_refund_tunai",
+			query: `ALTER TABLE sesi_kas ADD COLUMN refund_tunai INTEGER DEFAULT 0`,
+		},
 	}
 }
 
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AbsensiHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewAbsensiHandler(services *container.ServiceContainer) *AbsensiHandler {
+	return &AbsensiHandler{services: services}
+}
+
+func (h *AbsensiHandler) GetAktif(c *gin.Context) {
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	absensi, err := h.services.AbsensiService.GetAbsensiAktif(claims.UserID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get attendance", err)
+		return
+	}
+	response.Success(c, absensi, "Attendance retrieved successfully")
+}
+
+func (h *AbsensiHandler) ClockIn(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	absensi, err := h.services.AbsensiService.ClockIn(req)
+	if err != nil {
+		response.BadRequest(c, "Failed to clock in", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, absensi, "Clocked in successfully")
+}
+
+func (h *AbsensiHandler) ClockOut(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	absensi, err := h.services.AbsensiService.ClockOut(req)
+	if err != nil {
+		response.BadRequest(c, "Failed to clock out", err)
+		return
+	}
+	response.Success(c, absensi, "Clocked out successfully")
+}
+
+func (h *AbsensiHandler) MulaiIstirahat(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	absensi, err := h.services.AbsensiService.MulaiIstirahat(req)
+	if err != nil {
+		response.BadRequest(c, "Failed to start break", err)
+		return
+	}
+	response.Success(c, absensi, "Break started successfully")
+}
+
+func (h *AbsensiHandler) SelesaiIstirahat(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	absensi, err := h.services.AbsensiService.SelesaiIstirahat(req)
+	if err != nil {
+		response.BadRequest(c, "Failed to end break", err)
+		return
+	}
+	response.Success(c, absensi, "Break ended successfully")
+}
+
+// bindRequest reads the request body; without a username and PIN the logged-in user clocks themselves
+func (h *AbsensiHandler) bindRequest(c *gin.Context) (*models.AbsensiRequest, bool) {
+	var req models.AbsensiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return nil, false
+	}
+	req.StaffID = 0
+	req.StaffNama = ""
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+	return &req, true
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewAuditHandler(services *container.ServiceContainer) *AuditHandler {
+	return &AuditHandler{services: services}
+}
+
+// GetAll returns audit entries, filtered by
+// ?entitas=&entitas_id=&aktor_id=&aksi=&start_date=&end_date=&limit=&offset=
+func (h *AuditHandler) GetAll(c *gin.Context) {
+	filter := &models.AuditFilter{
+		Entitas:   c.Query("entitas"),
+		EntitasID: c.Query("entitas_id"),
+		Aksi:      c.Query("aksi"),
+	}
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	if aktorID := c.Query("aktor_id"); aktorID != "" {
+		id, err := strconv.ParseInt(aktorID, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid actor ID", err)
+			return
+		}
+		filter.AktorID = id
+	}
+
+	if startDate := c.Query("start_date"); startDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+		if err != nil {
+			response.BadRequest(c, "Invalid start date format", err)
+			return
+		}
+		filter.StartDate = start
+	}
+
+	if endDate := c.Query("end_date"); endDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+		if err != nil {
+			response.BadRequest(c, "Invalid end date format", err)
+			return
+		}
+		filter.EndDate = end.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	entries, err := h.services.AuditService.GetAll(filter)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get audit log", err)
+		return
+	}
+	response.Success(c, entries, "Audit log retrieved successfully")
+}
+
+// aktorDari returns the logged-in user recorded as the actor of a change
+func aktorDari(c *gin.Context) models.Aktor {
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		return models.Aktor{}
+	}
+	return models.Aktor{ID: claims.UserID, Nama: claims.NamaLengkap}
+}
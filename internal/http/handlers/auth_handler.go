@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"strings"
+
 	"ritel-app/internal/auth"
 	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
 	"ritel-app/internal/http/response"
 	"ritel-app/internal/models"
 
@@ -32,6 +35,21 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// Browsers share this server, so its own terminal code is never assumed: a client
+	// that sends no terminal gets a token that is not bound to one
+	kodeTerminal := ""
+	if strings.TrimSpace(req.TerminalID) != "" {
+		var err error
+		kodeTerminal, err = h.services.TerminalService.KodeTerminal(req.TerminalID)
+		if err != nil {
+			response.BadRequest(c, "Invalid terminal", err)
+			return
+		}
+	}
+	req.TerminalID = kodeTerminal
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
 	// Call the same service method as Wails
 	loginResponse, err := h.services.UserService.Login(&req)
 	if err != nil {
@@ -39,24 +57,107 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	h.masukTerminal(c, loginResponse, kodeTerminal)
+}
+
+// PinLogin signs a user in on a shared terminal with their numeric PIN
+func (h *AuthHandler) PinLogin(c *gin.Context) {
+	var req models.PinLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// PIN login switches the user of one shared terminal, so the client must name it
+	if strings.TrimSpace(req.TerminalID) == "" {
+		response.BadRequest(c, "Terminal ID is required for PIN login", nil)
+		return
+	}
+	kodeTerminal, err := h.services.TerminalService.KodeTerminal(req.TerminalID)
+	if err != nil {
+		response.BadRequest(c, "Invalid terminal", err)
+		return
+	}
+	req.TerminalID = kodeTerminal
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	loginResponse, err := h.services.UserService.LoginPin(&req)
+	if err != nil {
+		response.InternalServerError(c, "Login failed", err)
+		return
+	}
+
+	h.masukTerminal(c, loginResponse, kodeTerminal)
+}
+
+// masukTerminal issues the JWT for a successful login and makes the user active on the terminal
+func (h *AuthHandler) masukTerminal(c *gin.Context, loginResponse *models.LoginResponse, kodeTerminal string) {
 	if !loginResponse.Success {
 		response.Unauthorized(c, loginResponse.Message)
 		return
 	}
 
 	// Generate JWT token
-	token, err := h.jwtManager.GenerateToken(loginResponse.User)
+	token, err := h.jwtManager.GenerateToken(loginResponse.User, kodeTerminal)
 	if err != nil {
 		response.InternalServerError(c, "Failed to generate token", err)
 		return
 	}
 
+	if kodeTerminal != "" {
+		h.services.TerminalService.Masuk(kodeTerminal, loginResponse.User)
+	}
+
 	// Add token to response
 	loginResponse.Token = token
+	loginResponse.TerminalID = kodeTerminal
 
 	response.Success(c, loginResponse, "Login successful")
 }
 
+// Lock locks the caller's terminal so the next cashier can sign in
+func (h *AuthHandler) Lock(c *gin.Context) {
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	if claims.TerminalID == "" {
+		response.BadRequest(c, "Token is not bound to a terminal", nil)
+		return
+	}
+
+	if err := h.services.TerminalService.Kunci(claims.TerminalID); err != nil {
+		response.BadRequest(c, "Failed to lock terminal", err)
+		return
+	}
+
+	response.Success(c, nil, "Terminal locked")
+}
+
+// TerminalStatus returns the lock screen state of the client's terminal (?kode=)
+func (h *AuthHandler) TerminalStatus(c *gin.Context) {
+	if strings.TrimSpace(c.Query("kode")) == "" {
+		response.BadRequest(c, "Terminal code is required", nil)
+		return
+	}
+	kodeTerminal, err := h.services.TerminalService.KodeTerminal(c.Query("kode"))
+	if err != nil {
+		response.BadRequest(c, "Invalid terminal", err)
+		return
+	}
+
+	status, err := h.services.TerminalService.Status(kodeTerminal)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get terminal status", err)
+		return
+	}
+
+	response.Success(c, status, "Terminal status retrieved")
+}
+
 // RefreshToken generates a new JWT token with extended expiry
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// Get current token from header
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KomisiHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewKomisiHandler(services *container.ServiceContainer) *KomisiHandler {
+	return &KomisiHandler{services: services}
+}
+
+func (h *KomisiHandler) GetAllAturan(c *gin.Context) {
+	aturan, err := h.services.KomisiService.GetAllAturan()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get commission rules", err)
+		return
+	}
+	response.Success(c, aturan, "Commission rules retrieved successfully")
+}
+
+func (h *KomisiHandler) CreateAturan(c *gin.Context) {
+	var aturan models.AturanKomisi
+	if err := c.ShouldBindJSON(&aturan); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if err := h.services.KomisiService.CreateAturan(&aturan); err != nil {
+		response.BadRequest(c, "Failed to create commission rule", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, aturan, "Commission rule created successfully")
+}
+
+func (h *KomisiHandler) UpdateAturan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid commission rule ID", err)
+		return
+	}
+
+	var aturan models.AturanKomisi
+	if err := c.ShouldBindJSON(&aturan); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	aturan.ID = id
+
+	if err := h.services.KomisiService.UpdateAturan(&aturan); err != nil {
+		response.BadRequest(c, "Failed to update commission rule", err)
+		return
+	}
+	response.Success(c, aturan, "Commission rule updated successfully")
+}
+
+func (h *KomisiHandler) DeleteAturan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid commission rule ID", err)
+		return
+	}
+	if err := h.services.KomisiService.DeleteAturan(id); err != nil {
+		response.BadRequest(c, "Failed to delete commission rule", err)
+		return
+	}
+	response.Success(c, nil, "Commission rule deleted successfully")
+}
+
+// Hitung previews the commission of all staff for a date range without saving it
+func (h *KomisiHandler) Hitung(c *gin.Context) {
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		response.BadRequest(c, "Invalid start date format", err)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		response.BadRequest(c, "Invalid end date format", err)
+		return
+	}
+
+	komisi, err := h.services.KomisiService.HitungKomisi(startDate, endDate.Add(24*time.Hour-time.Nanosecond))
+	if err != nil {
+		response.BadRequest(c, "Failed to calculate commissions", err)
+		return
+	}
+	response.Success(c, komisi, "Commissions calculated successfully")
+}
+
+func (h *KomisiHandler) GetAllPeriode(c *gin.Context) {
+	periode, err := h.services.KomisiService.GetAllPeriode()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get commission periods", err)
+		return
+	}
+	response.Success(c, periode, "Commission periods retrieved successfully")
+}
+
+func (h *KomisiHandler) GetPeriode(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid commission period ID", err)
+		return
+	}
+
+	periode, err := h.services.KomisiService.GetPeriode(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, periode, "Commission period retrieved successfully")
+}
+
+func (h *KomisiHandler) BuatPeriode(c *gin.Context) {
+	var req models.BuatPeriodeKomisiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.DibuatOleh = claims.NamaLengkap
+	}
+
+	periode, err := h.services.KomisiService.BuatPeriode(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to create commission period", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, periode, "Commission period created successfully")
+}
+
+func (h *KomisiHandler) TandaiDibayar(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid commission period ID", err)
+		return
+	}
+
+	periode, err := h.services.KomisiService.TandaiDibayar(id)
+	if err != nil {
+		response.BadRequest(c, "Failed to mark commission period as paid", err)
+		return
+	}
+	response.Success(c, periode, "Commission period marked as paid")
+}
+
+func (h *KomisiHandler) DeletePeriode(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid commission period ID", err)
+		return
+	}
+	if err := h.services.KomisiService.DeletePeriode(id); err != nil {
+		response.BadRequest(c, "Failed to delete commission period", err)
+		return
+	}
+	response.Success(c, nil, "Commission period deleted successfully")
+}
+
+// ExportPayroll downloads a saved payout as a CSV file
+func (h *KomisiHandler) ExportPayroll(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid commission period ID", err)
+		return
+	}
+
+	content, err := h.services.KomisiService.ExportPayroll(id)
+	if err != nil {
+		response.BadRequest(c, "Failed to export commission period", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="komisi_%d.csv"`, id))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(content))
+}
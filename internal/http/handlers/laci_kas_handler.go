@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LaciKasHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewLaciKasHandler(services *container.ServiceContainer) *LaciKasHandler {
+	return &LaciKasHandler{services: services}
+}
+
+func (h *LaciKasHandler) NoSale(c *gin.Context) {
+	var req models.NoSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	entry, err := h.services.LaciKasService.NoSale(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to open cash drawer", err)
+		return
+	}
+	response.Success(c, entry, "Cash drawer opened successfully")
+}
+
+func (h *LaciKasHandler) GetLog(c *gin.Context) {
+	startDate, err := time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid start date format", err)
+		return
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid end date format", err)
+		return
+	}
+	endDate = endDate.Add(24*time.Hour - time.Nanosecond)
+
+	var staffID int64
+	if raw := c.Query("staff_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid staff ID", err)
+			return
+		}
+		staffID = id
+	}
+
+	entries, err := h.services.LaciKasService.GetLog(staffID, c.Query("tipe"), startDate, endDate)
+	if err != nil {
+		response.BadRequest(c, "Failed to get cash drawer log", err)
+		return
+	}
+	response.Success(c, entries, "Cash drawer log retrieved successfully")
+}
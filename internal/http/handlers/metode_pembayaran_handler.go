@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetodePembayaranHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewMetodePembayaranHandler(services *container.ServiceContainer) *MetodePembayaranHandler {
+	return &MetodePembayaranHandler{services: services}
+}
+
+func (h *MetodePembayaranHandler) GetAll(c *gin.Context) {
+	aktifSaja := c.Query("aktif") == "true"
+	metode, err := h.services.MetodePembayaranService.GetAllMetode(aktifSaja)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get payment methods", err)
+		return
+	}
+	response.Success(c, metode, "Payment methods retrieved successfully")
+}
+
+func (h *MetodePembayaranHandler) Create(c *gin.Context) {
+	var metode models.MetodePembayaran
+	if err := c.ShouldBindJSON(&metode); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if err := h.services.MetodePembayaranService.CreateMetode(&metode); err != nil {
+		response.BadRequest(c, "Failed to create payment method", err)
+		return
+	}
+	response.Success(c, metode, "Payment method created successfully")
+}
+
+func (h *MetodePembayaranHandler) Update(c *gin.Context) {
+	var metode models.MetodePembayaran
+	if err := c.ShouldBindJSON(&metode); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if err := h.services.MetodePembayaranService.UpdateMetode(&metode); err != nil {
+		response.BadRequest(c, "Failed to update payment method", err)
+		return
+	}
+	response.Success(c, metode, "Payment method updated successfully")
+}
+
+func (h *MetodePembayaranHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid payment method ID", err)
+		return
+	}
+	if err := h.services.MetodePembayaranService.DeleteMetode(id); err != nil {
+		response.BadRequest(c, "Failed to delete payment method", err)
+		return
+	}
+	response.Success(c, nil, "Payment method deleted successfully")
+}
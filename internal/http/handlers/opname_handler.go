@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OpnameHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewOpnameHandler(services *container.ServiceContainer) *OpnameHandler {
+	return &OpnameHandler{services: services}
+}
+
+func (h *OpnameHandler) GetAll(c *gin.Context) {
+	opname, err := h.services.OpnameService.GetAllOpname(c.Query("status"))
+	if err != nil {
+		response.BadRequest(c, "Failed to get stock counts", err)
+		return
+	}
+	response.Success(c, opname, "Stock counts retrieved successfully")
+}
+
+func (h *OpnameHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid stock count ID", err)
+		return
+	}
+
+	opname, err := h.services.OpnameService.GetOpnameByID(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, opname, "Stock count retrieved successfully")
+}
+
+func (h *OpnameHandler) Buka(c *gin.Context) {
+	var req models.BukaOpnameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	opname, err := h.services.OpnameService.BukaOpname(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to open stock count", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, opname, "Stock count opened successfully")
+}
+
+func (h *OpnameHandler) Hitung(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid stock count ID", err)
+		return
+	}
+
+	var req models.HitungOpnameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	item, err := h.services.OpnameService.CatatHitungan(id, &req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to record count", err)
+		return
+	}
+	response.Success(c, item, "Count recorded successfully")
+}
+
+func (h *OpnameHandler) LaporanSelisih(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid stock count ID", err)
+		return
+	}
+
+	laporan, err := h.services.OpnameService.LaporanSelisih(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, laporan, "Variance report retrieved successfully")
+}
+
+func (h *OpnameHandler) Posting(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid stock count ID", err)
+		return
+	}
+
+	var req models.PostingOpnameRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	laporan, err := h.services.OpnameService.PostingOpname(id, &req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to post stock count", err)
+		return
+	}
+	response.Success(c, laporan, "Stock count posted successfully")
+}
+
+func (h *OpnameHandler) Batal(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid stock count ID", err)
+		return
+	}
+
+	opname, err := h.services.OpnameService.BatalkanOpname(id)
+	if err != nil {
+		response.BadRequest(c, "Failed to cancel stock count", err)
+		return
+	}
+	response.Success(c, opname, "Stock count cancelled successfully")
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OtorisasiHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewOtorisasiHandler(services *container.ServiceContainer) *OtorisasiHandler {
+	return &OtorisasiHandler{services: services}
+}
+
+func (h *OtorisasiHandler) Minta(c *gin.Context) {
+	var req models.MintaOtorisasiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	result, err := h.services.OtorisasiService.Minta(&req)
+	if err != nil {
+		response.BadRequest(c, "Override not approved", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, result, "Override approved successfully")
+}
+
+func (h *OtorisasiHandler) GetRiwayat(c *gin.Context) {
+	startDate, err := time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid start date format", err)
+		return
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid end date format", err)
+		return
+	}
+	endDate = endDate.Add(24*time.Hour - time.Nanosecond)
+
+	entries, err := h.services.OtorisasiService.GetRiwayat(startDate, endDate)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get override history", err)
+		return
+	}
+	response.Success(c, entries, "Override history retrieved successfully")
+}
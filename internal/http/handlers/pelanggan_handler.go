@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
 	"ritel-app/internal/http/response"
 	"ritel-app/internal/models"
 
@@ -129,3 +130,68 @@ func (h *PelangganHandler) GetWithStats(c *gin.Context) {
 	}
 	response.Success(c, stats, "Customer stats retrieved successfully")
 }
+
+func (h *PelangganHandler) GetSaldoHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid customer ID", err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	history, err := h.services.PelangganService.GetSaldoHistory(id, limit)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get balance history", err)
+		return
+	}
+	response.Success(c, history, "Balance history retrieved successfully")
+}
+
+func (h *PelangganHandler) TopUpSaldo(c *gin.Context) {
+	var req models.TopUpSaldoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+	pelanggan, err := h.services.PelangganService.TopUpSaldo(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to top up balance", err)
+		return
+	}
+	response.Success(c, pelanggan, "Balance topped up successfully")
+}
+
+func (h *PelangganHandler) PenyesuaianSaldo(c *gin.Context) {
+	var req models.PenyesuaianSaldoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+	pelanggan, err := h.services.PelangganService.PenyesuaianSaldo(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to adjust balance", err)
+		return
+	}
+	response.Success(c, pelanggan, "Balance adjusted successfully")
+}
+
+func (h *PelangganHandler) UpdateKredit(c *gin.Context) {
+	var req models.UpdateKreditPelangganRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	pelanggan, err := h.services.PelangganService.UpdateKredit(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to update credit terms", err)
+		return
+	}
+	response.Success(c, pelanggan, "Credit terms updated successfully")
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PembelianHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewPembelianHandler(services *container.ServiceContainer) *PembelianHandler {
+	return &PembelianHandler{services: services}
+}
+
+func (h *PembelianHandler) GetAll(c *gin.Context) {
+	pesanan, err := h.services.PembelianService.GetAllPesanan(c.Query("status"))
+	if err != nil {
+		response.BadRequest(c, "Failed to get purchase orders", err)
+		return
+	}
+	response.Success(c, pesanan, "Purchase orders retrieved successfully")
+}
+
+func (h *PembelianHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid purchase order ID", err)
+		return
+	}
+
+	pesanan, err := h.services.PembelianService.GetPesananByID(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, pesanan, "Purchase order retrieved successfully")
+}
+
+func (h *PembelianHandler) Create(c *gin.Context) {
+	var req models.PesananPembelianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.DibuatOleh = claims.NamaLengkap
+	}
+
+	pesanan, err := h.services.PembelianService.CreatePesanan(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to create purchase order", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, pesanan, "Purchase order created successfully")
+}
+
+func (h *PembelianHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid purchase order ID", err)
+		return
+	}
+
+	var req models.PesananPembelianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	pesanan, err := h.services.PembelianService.UpdatePesanan(id, &req)
+	if err != nil {
+		response.BadRequest(c, "Failed to update purchase order", err)
+		return
+	}
+	response.Success(c, pesanan, "Purchase order updated successfully")
+}
+
+func (h *PembelianHandler) UbahStatus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid purchase order ID", err)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	pesanan, err := h.services.PembelianService.UbahStatusPesanan(id, req.Status)
+	if err != nil {
+		response.BadRequest(c, "Failed to change purchase order status", err)
+		return
+	}
+	response.Success(c, pesanan, "Purchase order status updated successfully")
+}
+
+func (h *PembelianHandler) TerimaBarang(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid purchase order ID", err)
+		return
+	}
+
+	var req models.PenerimaanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	req.PesananID = id
+
+	penerimaan, err := h.services.PembelianService.TerimaBarang(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to receive goods", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, penerimaan, "Goods received successfully")
+}
+
+func (h *PembelianHandler) GetAllPenerimaan(c *gin.Context) {
+	var startDate, endDate time.Time
+	if start := c.Query("start_date"); start != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", start, time.Local)
+		if err != nil {
+			response.BadRequest(c, "Invalid start date format", err)
+			return
+		}
+		startDate = parsed
+	}
+	if end := c.Query("end_date"); end != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", end, time.Local)
+		if err != nil {
+			response.BadRequest(c, "Invalid end date format", err)
+			return
+		}
+		endDate = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	penerimaan, err := h.services.PembelianService.GetAllPenerimaan(startDate, endDate)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get goods receipts", err)
+		return
+	}
+	response.Success(c, penerimaan, "Goods receipts retrieved successfully")
+}
+
+func (h *PembelianHandler) GetPenerimaanByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid goods receipt ID", err)
+		return
+	}
+
+	penerimaan, err := h.services.PembelianService.GetPenerimaanByID(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, penerimaan, "Goods receipt retrieved successfully")
+}
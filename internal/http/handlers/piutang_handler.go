@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PiutangHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewPiutangHandler(services *container.ServiceContainer) *PiutangHandler {
+	return &PiutangHandler{services: services}
+}
+
+func (h *PiutangHandler) GetAll(c *gin.Context) {
+	var pelangganID int64
+	if raw := c.Query("pelanggan_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid customer ID", err)
+			return
+		}
+		pelangganID = id
+	}
+
+	piutang, err := h.services.PiutangService.GetPiutang(pelangganID, c.Query("status"))
+	if err != nil {
+		response.BadRequest(c, "Failed to get receivables", err)
+		return
+	}
+	response.Success(c, piutang, "Receivables retrieved successfully")
+}
+
+func (h *PiutangHandler) Bayar(c *gin.Context) {
+	var req models.BayarPiutangRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	pembayaran, err := h.services.PiutangService.BayarPiutang(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to record repayment", err)
+		return
+	}
+	response.Success(c, pembayaran, "Repayment recorded successfully")
+}
+
+func (h *PiutangHandler) GetPembayaran(c *gin.Context) {
+	startDate, err := time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid start date format", err)
+		return
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid end date format", err)
+		return
+	}
+	endDate = endDate.Add(24*time.Hour - time.Nanosecond)
+
+	pembayaran, err := h.services.PiutangService.GetPembayaranPiutang(startDate, endDate)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get repayments", err)
+		return
+	}
+	response.Success(c, pembayaran, "Repayments retrieved successfully")
+}
+
+func (h *PiutangHandler) GetLaporanUmur(c *gin.Context) {
+	tanggal := time.Now()
+	if raw := c.Query("tanggal"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+		if err != nil {
+			response.BadRequest(c, "Invalid date format", err)
+			return
+		}
+		tanggal = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	laporan, err := h.services.PiutangService.GetLaporanUmurPiutang(tanggal)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get receivable aging report", err)
+		return
+	}
+	response.Success(c, laporan, "Receivable aging report retrieved successfully")
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
 	"ritel-app/internal/http/response"
 	"ritel-app/internal/models"
 
@@ -39,7 +40,7 @@ func (h *ProdukHandler) Create(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.ProdukService.CreateProduk(&produk); err != nil {
+	if err := h.services.ProdukService.CreateProduk(&produk, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to create product", err)
 		return
 	}
@@ -55,7 +56,30 @@ func (h *ProdukHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.ProdukService.UpdateProduk(&produk); err != nil {
+	// Changing prices needs its own permission
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+	bolehUbahHarga, err := h.services.UserService.HasPermission(claims.UserID, models.PermPriceChange)
+	if err != nil {
+		response.InternalServerError(c, "Failed to check permissions", err)
+		return
+	}
+	if !bolehUbahHarga {
+		berubah, err := h.services.ProdukService.HargaBerubah(&produk)
+		if err != nil {
+			response.InternalServerError(c, "Failed to check product price", err)
+			return
+		}
+		if berubah {
+			response.Forbidden(c, "Insufficient permissions: "+models.PermPriceChange)
+			return
+		}
+	}
+
+	if err := h.services.ProdukService.UpdateProduk(&produk, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to update product", err)
 		return
 	}
@@ -71,7 +95,7 @@ func (h *ProdukHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.ProdukService.DeleteProduk(id); err != nil {
+	if err := h.services.ProdukService.DeleteProduk(id, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to delete product", err)
 		return
 	}
@@ -108,7 +132,7 @@ func (h *ProdukHandler) UpdateStok(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.ProdukService.UpdateStok(&req); err != nil {
+	if err := h.services.ProdukService.UpdateStok(&req, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to update stock", err)
 		return
 	}
@@ -124,7 +148,7 @@ func (h *ProdukHandler) UpdateStokIncrement(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.ProdukService.UpdateStokIncrement(&req); err != nil {
+	if err := h.services.ProdukService.UpdateStokIncrement(&req, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to update stock increment", err)
 		return
 	}
@@ -172,13 +196,21 @@ func (h *ProdukHandler) ClearKeranjang(c *gin.Context) {
 
 // ProcessKeranjang processes the cart (confirms purchase and updates stock)
 func (h *ProdukHandler) ProcessKeranjang(c *gin.Context) {
-	err := h.services.ProdukService.ProcessKeranjang()
+	var req models.ProsesKeranjangRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	penerimaan, err := h.services.ProdukService.ProcessKeranjang(&req, aktorDari(c))
 	if err != nil {
 		response.BadRequest(c, "Failed to process cart", err)
 		return
 	}
 
-	response.Success(c, nil, "Cart processed successfully")
+	response.Success(c, penerimaan, "Cart processed successfully")
 }
 
 // RemoveFromKeranjang removes an item from the cart
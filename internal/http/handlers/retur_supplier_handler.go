@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReturSupplierHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewReturSupplierHandler(services *container.ServiceContainer) *ReturSupplierHandler {
+	return &ReturSupplierHandler{services: services}
+}
+
+func (h *ReturSupplierHandler) GetAll(c *gin.Context) {
+	retur, err := h.services.ReturSupplierService.GetAllRetur(c.Query("status"))
+	if err != nil {
+		response.BadRequest(c, "Failed to get supplier returns", err)
+		return
+	}
+	response.Success(c, retur, "Supplier returns retrieved successfully")
+}
+
+func (h *ReturSupplierHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier return ID", err)
+		return
+	}
+
+	retur, err := h.services.ReturSupplierService.GetReturByID(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, retur, "Supplier return retrieved successfully")
+}
+
+func (h *ReturSupplierHandler) Create(c *gin.Context) {
+	var req models.ReturSupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	retur, err := h.services.ReturSupplierService.CreateRetur(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to create supplier return", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, retur, "Supplier return created successfully")
+}
+
+func (h *ReturSupplierHandler) Selesaikan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier return ID", err)
+		return
+	}
+
+	var req models.PenyelesaianReturSupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	retur, err := h.services.ReturSupplierService.SelesaikanRetur(id, &req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to settle supplier return", err)
+		return
+	}
+	response.Success(c, retur, "Supplier return settled successfully")
+}
+
+func (h *ReturSupplierHandler) Batal(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier return ID", err)
+		return
+	}
+
+	retur, err := h.services.ReturSupplierService.BatalkanRetur(id, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to cancel supplier return", err)
+		return
+	}
+	response.Success(c, retur, "Supplier return cancelled successfully")
+}
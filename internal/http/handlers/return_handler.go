@@ -47,6 +47,8 @@ func (h *ReturnHandler) Create(c *gin.Context) {
 		response.BadRequest(c, "Invalid request body", err)
 		return
 	}
+	aktor := aktorDari(c)
+	req.StaffID, req.StaffNama = aktor.ID, aktor.Nama
 	if err := h.services.ReturnService.CreateReturn(&req); err != nil {
 		response.BadRequest(c, "Failed to create return", err)
 		return
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewRoleHandler(services *container.ServiceContainer) *RoleHandler {
+	return &RoleHandler{services: services}
+}
+
+func (h *RoleHandler) GetAll(c *gin.Context) {
+	roles, err := h.services.RoleService.GetAllRole()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get roles", err)
+		return
+	}
+	response.Success(c, roles, "Roles retrieved successfully")
+}
+
+// GetDaftarPermission lists every permission a role can be granted
+func (h *RoleHandler) GetDaftarPermission(c *gin.Context) {
+	response.Success(c, h.services.RoleService.GetDaftarPermission(), "Permissions retrieved successfully")
+}
+
+func (h *RoleHandler) Create(c *gin.Context) {
+	var role models.Role
+	if err := c.ShouldBindJSON(&role); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if err := h.services.RoleService.CreateRole(&role, aktorDari(c)); err != nil {
+		response.BadRequest(c, "Failed to create role", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, role, "Role created successfully")
+}
+
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid role ID", err)
+		return
+	}
+
+	var role models.Role
+	if err := c.ShouldBindJSON(&role); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	role.ID = id
+
+	if err := h.services.RoleService.UpdateRole(&role, aktorDari(c)); err != nil {
+		response.BadRequest(c, "Failed to update role", err)
+		return
+	}
+	response.Success(c, role, "Role updated successfully")
+}
+
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid role ID", err)
+		return
+	}
+	if err := h.services.RoleService.DeleteRole(id, aktorDari(c)); err != nil {
+		response.BadRequest(c, "Failed to delete role", err)
+		return
+	}
+	response.Success(c, nil, "Role deleted successfully")
+}
@@ -43,3 +43,26 @@ func (h *SalesReportHandler) GetComprehensive(c *gin.Context) {
 	}
 	response.Success(c, report, "Comprehensive sales report retrieved successfully")
 }
+
+// GetLaporanPajak returns the output tax (PPN) report for a date range
+func (h *SalesReportHandler) GetLaporanPajak(c *gin.Context) {
+	startDate, err := time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid start date format", err)
+		return
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+	if err != nil {
+		response.BadRequest(c, "Invalid end date format", err)
+		return
+	}
+	endDate = endDate.Add(24*time.Hour - time.Nanosecond)
+
+	report, err := h.services.SalesReportService.GetLaporanPajak(startDate, endDate)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get tax report", err)
+		return
+	}
+	response.Success(c, report, "Tax report retrieved successfully")
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SesiKasHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewSesiKasHandler(services *container.ServiceContainer) *SesiKasHandler {
+	return &SesiKasHandler{services: services}
+}
+
+// GetAll lists sessions; non-admin staff only see their own sessions
+func (h *SesiKasHandler) GetAll(c *gin.Context) {
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	staffID := claims.UserID
+	if claims.Role == "admin" {
+		staffID = 0
+		if raw := c.Query("staff_id"); raw != "" {
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				response.BadRequest(c, "Invalid staff ID", err)
+				return
+			}
+			staffID = id
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	sesi, err := h.services.SesiKasService.GetAllSesi(staffID, c.Query("status"), limit)
+	if err != nil {
+		response.BadRequest(c, "Failed to get cash sessions", err)
+		return
+	}
+	response.Success(c, sesi, "Cash sessions retrieved successfully")
+}
+
+func (h *SesiKasHandler) GetAktif(c *gin.Context) {
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sesi, err := h.services.SesiKasService.GetSesiAktif(claims.UserID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get open cash session", err)
+		return
+	}
+	response.Success(c, sesi, "Open cash session retrieved successfully")
+}
+
+func (h *SesiKasHandler) Buka(c *gin.Context) {
+	var req models.BukaSesiKasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	sesi, err := h.services.SesiKasService.BukaSesi(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to open cash session", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, sesi, "Cash session opened successfully")
+}
+
+func (h *SesiKasHandler) CatatMutasi(c *gin.Context) {
+	var req models.MutasiKasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	mutasi, err := h.services.SesiKasService.CatatMutasi(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to record cash movement", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, mutasi, "Cash movement recorded successfully")
+}
+
+func (h *SesiKasHandler) Tutup(c *gin.Context) {
+	var req models.TutupSesiKasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	laporan, err := h.services.SesiKasService.TutupSesi(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to close cash session", err)
+		return
+	}
+	response.Success(c, laporan, "Cash session closed successfully")
+}
+
+func (h *SesiKasHandler) GetLaporan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID", err)
+		return
+	}
+
+	laporan, err := h.services.SesiKasService.GetLaporan(id, c.DefaultQuery("jenis", "X"))
+	if err != nil {
+		response.BadRequest(c, "Failed to get cash session report", err)
+		return
+	}
+	if !h.bolehLihat(c, laporan.Sesi) {
+		response.Forbidden(c, "Access denied")
+		return
+	}
+	response.Success(c, laporan, "Cash session report retrieved successfully")
+}
+
+func (h *SesiKasHandler) Cetak(c *gin.Context) {
+	var req models.PrintLaporanSesiKasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	laporan, err := h.services.SesiKasService.GetLaporan(req.SesiID, req.Jenis)
+	if err != nil {
+		response.BadRequest(c, "Failed to get cash session report", err)
+		return
+	}
+	if !h.bolehLihat(c, laporan.Sesi) {
+		response.Forbidden(c, "Access denied")
+		return
+	}
+
+	if err := h.services.PrinterService.PrintLaporanSesiKas(&req); err != nil {
+		response.InternalServerError(c, "Failed to print cash session report", err)
+		return
+	}
+	response.Success(c, nil, fmt.Sprintf("Report %s printed successfully", laporan.Jenis))
+}
+
+// bolehLihat allows admins to see every session and other staff only their own
+func (h *SesiKasHandler) bolehLihat(c *gin.Context, sesi *models.SesiKas) bool {
+	claims, err := middleware.GetUserClaims(c)
+	if err != nil {
+		return false
+	}
+	return claims.Role == "admin" || claims.UserID == sesi.StaffID
+}
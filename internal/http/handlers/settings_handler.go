@@ -5,6 +5,7 @@ import (
 	"ritel-app/internal/container"
 	"ritel-app/internal/http/response"
 	"ritel-app/internal/models"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,10 +36,195 @@ func (h *SettingsHandler) UpdatePoinSettings(c *gin.Context) {
 
 	fmt.Printf("[HTTP HANDLER] UpdatePoinSettings Request: %+v\n", req)
 
-	settings, err := h.services.SettingsService.UpdatePoinSettings(&req)
+	settings, err := h.services.SettingsService.UpdatePoinSettings(&req, aktorDari(c))
 	if err != nil {
 		response.BadRequest(c, "Failed to update point settings", err)
 		return
 	}
 	response.Success(c, settings, "Point settings updated successfully")
 }
+
+func (h *SettingsHandler) GetPenomoranSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetPenomoranSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get numbering settings", err)
+		return
+	}
+	response.Success(c, settings, "Numbering settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdatePenomoranSettings(c *gin.Context) {
+	var req models.PenomoranSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdatePenomoranSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update numbering settings", err)
+		return
+	}
+	response.Success(c, settings, "Numbering settings updated successfully")
+}
+
+func (h *SettingsHandler) GetPembulatanSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetPembulatanSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get rounding settings", err)
+		return
+	}
+	response.Success(c, settings, "Rounding settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdatePembulatanSettings(c *gin.Context) {
+	var req models.PembulatanSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdatePembulatanSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update rounding settings", err)
+		return
+	}
+	response.Success(c, settings, "Rounding settings updated successfully")
+}
+
+func (h *SettingsHandler) PreviewPembulatan(c *gin.Context) {
+	total, err := strconv.Atoi(c.Query("total"))
+	if err != nil {
+		response.BadRequest(c, "Invalid total", err)
+		return
+	}
+	nonTunai, _ := strconv.Atoi(c.DefaultQuery("non_tunai", "0"))
+	donasi := c.Query("donasi") == "true"
+
+	preview, err := h.services.SettingsService.PreviewPembulatan(total, nonTunai, donasi)
+	if err != nil {
+		response.BadRequest(c, "Failed to preview rounding", err)
+		return
+	}
+	response.Success(c, preview, "Rounding preview calculated successfully")
+}
+
+func (h *SettingsHandler) GetPajakSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetPajakSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get tax settings", err)
+		return
+	}
+	response.Success(c, settings, "Tax settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdatePajakSettings(c *gin.Context) {
+	var req models.PajakSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdatePajakSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update tax settings", err)
+		return
+	}
+	response.Success(c, settings, "Tax settings updated successfully")
+}
+
+func (h *SettingsHandler) GetLaciKasSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetLaciKasSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get cash drawer settings", err)
+		return
+	}
+	response.Success(c, settings, "Cash drawer settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdateLaciKasSettings(c *gin.Context) {
+	var req models.LaciKasSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdateLaciKasSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update cash drawer settings", err)
+		return
+	}
+	response.Success(c, settings, "Cash drawer settings updated successfully")
+}
+
+func (h *SettingsHandler) GetOtorisasiSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetOtorisasiSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get override settings", err)
+		return
+	}
+	response.Success(c, settings, "Override settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdateOtorisasiSettings(c *gin.Context) {
+	var req models.OtorisasiSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdateOtorisasiSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update override settings", err)
+		return
+	}
+	response.Success(c, settings, "Override settings updated successfully")
+}
+
+func (h *SettingsHandler) GetKeamananSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetKeamananSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get security settings", err)
+		return
+	}
+	response.Success(c, settings, "Security settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdateKeamananSettings(c *gin.Context) {
+	var req models.KeamananSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdateKeamananSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update security settings", err)
+		return
+	}
+	response.Success(c, settings, "Security settings updated successfully")
+}
+
+func (h *SettingsHandler) GetPembelianSettings(c *gin.Context) {
+	settings, err := h.services.SettingsService.GetPembelianSettings()
+	if err != nil {
+		response.InternalServerError(c, "Failed to get purchasing settings", err)
+		return
+	}
+	response.Success(c, settings, "Purchasing settings retrieved successfully")
+}
+
+func (h *SettingsHandler) UpdatePembelianSettings(c *gin.Context) {
+	var req models.PembelianSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	settings, err := h.services.SettingsService.UpdatePembelianSettings(&req, aktorDari(c))
+	if err != nil {
+		response.BadRequest(c, "Failed to update purchasing settings", err)
+		return
+	}
+	response.Success(c, settings, "Purchasing settings updated successfully")
+}
@@ -285,3 +285,60 @@ func (h *StaffReportHandler) UpdateShiftSettings(c *gin.Context) {
 
 	response.Success(c, nil, "Shift settings updated successfully")
 }
+
+// GetLaporanAbsensi returns the monthly attendance report of a staff member
+func (h *StaffReportHandler) GetLaporanAbsensi(c *gin.Context) {
+	staffID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid staff ID", err)
+		return
+	}
+
+	tahun, bulan, err := parsePeriodeBulan(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid period", err)
+		return
+	}
+
+	report, err := h.services.StaffReportService.GetLaporanAbsensi(staffID, tahun, bulan)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get attendance report", err)
+		return
+	}
+	response.Success(c, report, "Attendance report retrieved successfully")
+}
+
+// GetAllLaporanAbsensi returns the monthly attendance report of all staff
+func (h *StaffReportHandler) GetAllLaporanAbsensi(c *gin.Context) {
+	tahun, bulan, err := parsePeriodeBulan(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid period", err)
+		return
+	}
+
+	reports, err := h.services.StaffReportService.GetAllLaporanAbsensi(tahun, bulan)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get attendance reports", err)
+		return
+	}
+	response.Success(c, reports, "Attendance reports retrieved successfully")
+}
+
+// parsePeriodeBulan reads the tahun and bulan query parameters, defaulting to the current month
+func parsePeriodeBulan(c *gin.Context) (int, int, error) {
+	now := time.Now()
+	tahun, bulan := now.Year(), int(now.Month())
+
+	var err error
+	if raw := c.Query("tahun"); raw != "" {
+		if tahun, err = strconv.Atoi(raw); err != nil {
+			return 0, 0, err
+		}
+	}
+	if raw := c.Query("bulan"); raw != "" {
+		if bulan, err = strconv.Atoi(raw); err != nil {
+			return 0, 0, err
+		}
+	}
+	return tahun, bulan, nil
+}
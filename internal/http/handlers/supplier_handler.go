@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SupplierHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewSupplierHandler(services *container.ServiceContainer) *SupplierHandler {
+	return &SupplierHandler{services: services}
+}
+
+func (h *SupplierHandler) GetAll(c *gin.Context) {
+	aktifSaja := c.Query("aktif") == "true"
+	supplier, err := h.services.SupplierService.GetAllSupplier(aktifSaja)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get suppliers", err)
+		return
+	}
+	response.Success(c, supplier, "Suppliers retrieved successfully")
+}
+
+func (h *SupplierHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier ID", err)
+		return
+	}
+
+	supplier, err := h.services.SupplierService.GetSupplierByID(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, supplier, "Supplier retrieved successfully")
+}
+
+func (h *SupplierHandler) Create(c *gin.Context) {
+	var supplier models.Supplier
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if err := h.services.SupplierService.CreateSupplier(&supplier); err != nil {
+		response.BadRequest(c, "Failed to create supplier", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, supplier, "Supplier created successfully")
+}
+
+func (h *SupplierHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier ID", err)
+		return
+	}
+
+	var supplier models.Supplier
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	supplier.ID = id
+
+	if err := h.services.SupplierService.UpdateSupplier(&supplier); err != nil {
+		response.BadRequest(c, "Failed to update supplier", err)
+		return
+	}
+	response.Success(c, supplier, "Supplier updated successfully")
+}
+
+func (h *SupplierHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier ID", err)
+		return
+	}
+	if err := h.services.SupplierService.DeleteSupplier(id); err != nil {
+		response.BadRequest(c, "Failed to delete supplier", err)
+		return
+	}
+	response.Success(c, nil, "Supplier deleted successfully")
+}
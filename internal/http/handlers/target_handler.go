@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ritel-app/internal/container"
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TargetHandler struct {
+	services *container.ServiceContainer
+}
+
+func NewTargetHandler(services *container.ServiceContainer) *TargetHandler {
+	return &TargetHandler{services: services}
+}
+
+func (h *TargetHandler) GetAll(c *gin.Context) {
+	targets, err := h.services.TargetService.GetAllTarget(c.Query("lingkup"))
+	if err != nil {
+		response.InternalServerError(c, "Failed to get sales targets", err)
+		return
+	}
+	response.Success(c, targets, "Sales targets retrieved successfully")
+}
+
+func (h *TargetHandler) Create(c *gin.Context) {
+	var target models.TargetPenjualan
+	if err := c.ShouldBindJSON(&target); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	if err := h.services.TargetService.CreateTarget(&target); err != nil {
+		response.BadRequest(c, "Failed to create sales target", err)
+		return
+	}
+	response.SuccessWithStatus(c, http.StatusCreated, target, "Sales target created successfully")
+}
+
+func (h *TargetHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid sales target ID", err)
+		return
+	}
+
+	var target models.TargetPenjualan
+	if err := c.ShouldBindJSON(&target); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	target.ID = id
+
+	if err := h.services.TargetService.UpdateTarget(&target); err != nil {
+		response.BadRequest(c, "Failed to update sales target", err)
+		return
+	}
+	response.Success(c, target, "Sales target updated successfully")
+}
+
+func (h *TargetHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid sales target ID", err)
+		return
+	}
+	if err := h.services.TargetService.DeleteTarget(id); err != nil {
+		response.BadRequest(c, "Failed to delete sales target", err)
+		return
+	}
+	response.Success(c, nil, "Sales target deleted successfully")
+}
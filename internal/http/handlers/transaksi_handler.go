@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"net"
 	"strconv"
 	"time"
 
 	"ritel-app/internal/container"
+	"ritel-app/internal/http/middleware"
 	"ritel-app/internal/http/response"
 	"ritel-app/internal/models"
 
@@ -29,6 +31,16 @@ func (h *TransaksiHandler) Create(c *gin.Context) {
 		response.BadRequest(c, "Invalid request body", err)
 		return
 	}
+	// The header is the only key over HTTP, the same one the idempotency middleware reserved
+	req.IdempotencyKey = c.GetHeader(middleware.IdempotencyKeyHeader)
+	// Only a browser on the server machine shares its cash drawer; other terminals open their own
+	req.LaciLokal = net.ParseIP(c.RemoteIP()).IsLoopback()
+	// Number the sale with the terminal the cashier signed in on
+	if req.TerminalID == "" {
+		if claims, err := middleware.GetUserClaims(c); err == nil {
+			req.TerminalID = claims.TerminalID
+		}
+	}
 
 	result, err := h.services.TransaksiService.CreateTransaksi(&req)
 	if err != nil {
@@ -39,6 +51,23 @@ func (h *TransaksiHandler) Create(c *gin.Context) {
 	response.Success(c, result, "Transaction created successfully")
 }
 
+// Preview calculates the server-side totals of a cart without saving it
+func (h *TransaksiHandler) Preview(c *gin.Context) {
+	var req models.CreateTransaksiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	preview, err := h.services.TransaksiService.PreviewTransaksi(&req)
+	if err != nil {
+		response.BadRequest(c, "Failed to calculate transaction", err)
+		return
+	}
+
+	response.Success(c, preview, "Transaction calculated successfully")
+}
+
 // GetAll retrieves all transactions with pagination
 func (h *TransaksiHandler) GetAll(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
@@ -141,3 +170,107 @@ func (h *TransaksiHandler) GetByPelanggan(c *gin.Context) {
 
 	response.Success(c, transaksi, "Customer transactions retrieved successfully")
 }
+
+// Hold parks the current cart so it can be resumed later
+func (h *TransaksiHandler) Hold(c *gin.Context) {
+	var req models.HoldTransaksiRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	held, err := h.services.HeldTransaksiService.HoldTransaksi(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error(), err)
+		return
+	}
+
+	response.Success(c, held, "Transaction held successfully")
+}
+
+// GetHeld lists held transactions, optionally filtered by terminal_id and staff_id
+func (h *TransaksiHandler) GetHeld(c *gin.Context) {
+	terminalID := c.Query("terminal_id")
+
+	var staffID int64
+	if staffIDStr := c.Query("staff_id"); staffIDStr != "" {
+		parsed, err := strconv.ParseInt(staffIDStr, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid staff ID", err)
+			return
+		}
+		staffID = parsed
+	}
+
+	held, err := h.services.HeldTransaksiService.GetHeldTransaksi(terminalID, staffID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get held transactions", err)
+		return
+	}
+
+	response.Success(c, held, "Held transactions retrieved successfully")
+}
+
+// ResumeHeld takes a held transaction back into checkout
+func (h *TransaksiHandler) ResumeHeld(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid held transaction ID", err)
+		return
+	}
+
+	held, err := h.services.HeldTransaksiService.ResumeHeldTransaksi(id)
+	if err != nil {
+		response.BadRequest(c, err.Error(), err)
+		return
+	}
+
+	response.Success(c, held, "Held transaction resumed successfully")
+}
+
+// DiscardHeld drops a held transaction
+func (h *TransaksiHandler) DiscardHeld(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid held transaction ID", err)
+		return
+	}
+
+	if err := h.services.HeldTransaksiService.DiscardHeldTransaksi(id); err != nil {
+		response.BadRequest(c, err.Error(), err)
+		return
+	}
+
+	response.Success(c, nil, "Held transaction discarded successfully")
+}
+
+// Void cancels a completed transaction (requires admin credentials or PIN in the body)
+func (h *TransaksiHandler) Void(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid transaction ID", err)
+		return
+	}
+
+	var req models.VoidTransaksiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+	req.TransaksiID = id
+
+	// Record the logged-in user as the requester
+	if claims, err := middleware.GetUserClaims(c); err == nil {
+		req.StaffID = claims.UserID
+		req.StaffNama = claims.NamaLengkap
+	}
+
+	result, err := h.services.TransaksiService.VoidTransaksi(&req)
+	if err != nil {
+		response.InternalServerError(c, "Failed to void transaction", err)
+		return
+	}
+
+	response.Success(c, result, "Void request processed")
+}
@@ -50,13 +50,30 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 	response.Success(c, user, "User retrieved successfully")
 }
 
+// GetLoginHistory returns the latest login attempts of a user (?limit=, default 100)
+func (h *UserHandler) GetLoginHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	events, err := h.services.UserService.GetLoginHistory(id, limit)
+	if err != nil {
+		response.InternalServerError(c, "Failed to get login history", err)
+		return
+	}
+	response.Success(c, events, "Login history retrieved successfully")
+}
+
 func (h *UserHandler) Create(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequest(c, "Invalid request body", err)
 		return
 	}
-	if err := h.services.UserService.CreateUser(&req); err != nil {
+	if err := h.services.UserService.CreateUser(&req, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to create user", err)
 		return
 	}
@@ -69,7 +86,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 		response.BadRequest(c, "Invalid request body", err)
 		return
 	}
-	if err := h.services.UserService.UpdateUser(&req); err != nil {
+	if err := h.services.UserService.UpdateUser(&req, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to update user", err)
 		return
 	}
@@ -82,7 +99,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		response.BadRequest(c, "Invalid user ID", err)
 		return
 	}
-	if err := h.services.UserService.DeleteUser(id); err != nil {
+	if err := h.services.UserService.DeleteUser(id, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to delete user", err)
 		return
 	}
@@ -102,7 +119,7 @@ func (h *UserHandler) AdminChangePassword(c *gin.Context) {
 	}
 
 	// Update password directly
-	if err := h.services.UserService.AdminChangePassword(req.UserID, req.NewPassword); err != nil {
+	if err := h.services.UserService.AdminChangePassword(req.UserID, req.NewPassword, aktorDari(c)); err != nil {
 		response.BadRequest(c, "Failed to change password", err)
 		return
 	}
@@ -100,6 +100,64 @@ func RequireAdmin() gin.HandlerFunc {
 	return RequireRole("admin")
 }
 
+// PermissionChecker reports whether a user's current role grants at least one of perms
+type PermissionChecker interface {
+	HasPermission(userID int64, perms ...string) (bool, error)
+}
+
+// RequirePermission ensures the user's role grants at least one of the named permissions.
+// The role is read from the store on every request instead of the token claims, so a role
+// edit applies to tokens that were issued before it.
+func RequirePermission(checker PermissionChecker, perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := GetUserClaims(c)
+		if err != nil {
+			response.Unauthorized(c, "Authentication required")
+			c.Abort()
+			return
+		}
+
+		allowed, err := checker.HasPermission(claims.UserID, perms...)
+		if err != nil {
+			response.InternalServerError(c, "Failed to check permissions", err)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			response.Forbidden(c, "Insufficient permissions: "+strings.Join(perms, ", "))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TerminalChecker reports whether a user is still the active, unlocked user on a terminal
+type TerminalChecker interface {
+	Sentuh(terminalID string, userID int64) error
+}
+
+// TerminalAktif rejects tokens whose terminal has been locked or taken over by another user.
+// Tokens issued without a terminal ID are not checked.
+func TerminalAktif(checker TerminalChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := GetUserClaims(c)
+		if err != nil || claims.TerminalID == "" {
+			c.Next()
+			return
+		}
+
+		if err := checker.Sentuh(claims.TerminalID, claims.UserID); err != nil {
+			response.Unauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // GetUserClaims extracts claims from context
 func GetUserClaims(c *gin.Context) (*auth.Claims, error) {
 	claimsInterface, exists := c.Get(ClaimsKey)
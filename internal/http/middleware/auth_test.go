@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ritel-app/internal/auth"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryRoleStore is a minimal in-memory PermissionChecker for tests
+type memoryRoleStore struct {
+	roles       map[int64]string
+	permissions map[string][]string
+}
+
+func (s *memoryRoleStore) HasPermission(userID int64, perms ...string) (bool, error) {
+	role := s.roles[userID]
+	for _, perm := range perms {
+		if models.HasPermission(role, s.permissions[role], perm) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newPermissionTestRouter(store *memoryRoleStore, claims *auth.Claims) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/test", func(c *gin.Context) {
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}, RequirePermission(store, models.PermReturnApprove, models.PermTransactionView), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router
+}
+
+func permissionTestStatus(router *gin.Engine) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequirePermissionAcceptsAnyListedPermission(t *testing.T) {
+	store := &memoryRoleStore{
+		roles:       map[int64]string{1: "kasir"},
+		permissions: map[string][]string{"kasir": {models.PermTransactionView}},
+	}
+	router := newPermissionTestRouter(store, &auth.Claims{UserID: 1, Role: "kasir"})
+
+	assert.Equal(t, http.StatusOK, permissionTestStatus(router))
+}
+
+func TestRequirePermissionUsesCurrentRoleInsteadOfToken(t *testing.T) {
+	store := &memoryRoleStore{
+		roles:       map[int64]string{1: "kasir"},
+		permissions: map[string][]string{"kasir": {models.PermTransactionView}},
+	}
+	// The token was issued while the role still had the permission
+	claims := &auth.Claims{UserID: 1, Role: "kasir", Permissions: []string{models.PermTransactionView}}
+	router := newPermissionTestRouter(store, claims)
+
+	store.permissions["kasir"] = []string{models.PermTransactionCreate}
+	assert.Equal(t, http.StatusForbidden, permissionTestStatus(router))
+}
+
+func TestRequirePermissionRejectsMissingClaims(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &memoryRoleStore{roles: map[int64]string{}, permissions: map[string][]string{}}
+	router := gin.New()
+	router.GET("/test", RequirePermission(store, models.PermTransactionView), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, permissionTestStatus(router))
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"ritel-app/internal/http/response"
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyStore persists responses per idempotency key
+type IdempotencyStore interface {
+	Begin(scope, key string, request []byte) (*models.IdempotencyRecord, error)
+	Complete(scope, key string, statusCode int, response []byte) error
+	Release(scope, key string) error
+}
+
+// idempotencyWriter copies the response body so it can be stored
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Idempotency replays the stored response when a request is retried with the same
+// Idempotency-Key header. Requests without the header are passed through unchanged.
+func Idempotency(store IdempotencyStore, inProgressErr, mismatchErr error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			response.BadRequest(c, "Idempotency-Key terlalu panjang", nil)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, "Invalid request body", err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := c.Request.Method + " " + c.FullPath()
+		existing, err := store.Begin(scope, key, body)
+		switch {
+		case errors.Is(err, inProgressErr):
+			response.Error(c, http.StatusConflict, err.Error(), nil)
+			c.Abort()
+			return
+		case errors.Is(err, mismatchErr):
+			response.Error(c, http.StatusUnprocessableEntity, err.Error(), nil)
+			c.Abort()
+			return
+		case err != nil:
+			response.InternalServerError(c, "Failed to check idempotency key", err)
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(existing.StatusCode, "application/json; charset=utf-8", []byte(existing.Response))
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		// A panicking handler must not leave the key in processing; the Recovery middleware
+		// still handles the panic after the key is released
+		defer func() {
+			if r := recover(); r != nil {
+				_ = store.Release(scope, key)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		// Server errors are not stored so the client can retry with the same key
+		if writer.Status() >= http.StatusInternalServerError {
+			_ = store.Release(scope, key)
+			return
+		}
+		_ = store.Complete(scope, key, writer.Status(), writer.body.Bytes())
+	}
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ritel-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestInProgress = errors.New("in progress")
+	errTestMismatch   = errors.New("mismatch")
+)
+
+// memoryIdempotencyStore is a minimal in-memory IdempotencyStore for tests
+type memoryIdempotencyStore struct {
+	records map[string]*models.IdempotencyRecord
+}
+
+func (s *memoryIdempotencyStore) Begin(scope, key string, request []byte) (*models.IdempotencyRecord, error) {
+	id := scope + "|" + key
+	rec, ok := s.records[id]
+	if !ok {
+		s.records[id] = &models.IdempotencyRecord{Scope: scope, Key: key, RequestHash: string(request), Status: "processing"}
+		return nil, nil
+	}
+	if rec.RequestHash != string(request) {
+		return nil, errTestMismatch
+	}
+	if rec.Status != "completed" {
+		return nil, errTestInProgress
+	}
+	return rec, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(scope, key string, statusCode int, response []byte) error {
+	rec := s.records[scope+"|"+key]
+	rec.Status = "completed"
+	rec.StatusCode = statusCode
+	rec.Response = string(response)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(scope, key string) error {
+	delete(s.records, scope+"|"+key)
+	return nil
+}
+
+func newIdempotencyTestRouter(calls *int, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+	router := gin.New()
+	router.POST("/test", Idempotency(store, errTestInProgress, errTestMismatch), func(c *gin.Context) {
+		*calls++
+		c.JSON(status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func doIdempotentRequest(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/test", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls, 200)
+
+	first := doIdempotentRequest(router, "key-1", `{"a":1}`)
+	second := doIdempotentRequest(router, "key-1", `{"a":1}`)
+
+	assert.Equal(t, 1, calls, "Handler should run only once")
+	assert.Equal(t, 200, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String(), "Retry should return the original response")
+	assert.Equal(t, "true", second.Header().Get(IdempotencyReplayedHeader))
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls, 200)
+
+	doIdempotentRequest(router, "", `{"a":1}`)
+	doIdempotentRequest(router, "", `{"a":1}`)
+
+	assert.Equal(t, 2, calls, "Requests without a key are not deduplicated")
+}
+
+func TestIdempotency_KeyReusedForDifferentBody(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls, 200)
+
+	doIdempotentRequest(router, "key-1", `{"a":1}`)
+	w := doIdempotentRequest(router, "key-1", `{"a":2}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	calls := 0
+	router := newIdempotencyTestRouter(&calls, 500)
+
+	doIdempotentRequest(router, "key-1", `{"a":1}`)
+	doIdempotentRequest(router, "key-1", `{"a":1}`)
+
+	assert.Equal(t, 2, calls, "A failed request can be retried with the same key")
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls := 0
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyRecord{}}
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.POST("/test", Idempotency(store, errTestInProgress, errTestMismatch), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		c.JSON(200, gin.H{"call": calls})
+	})
+
+	first := doIdempotentRequest(router, "key-1", `{"a":1}`)
+	second := doIdempotentRequest(router, "key-1", `{"a":1}`)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, 200, second.Code, "A panicked request can be retried with the same key")
+	assert.Equal(t, 2, calls)
+}
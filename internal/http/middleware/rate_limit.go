@@ -208,8 +208,8 @@ func SmartRateLimit() gin.HandlerFunc {
 		var limit int
 		var window time.Duration
 
-		// Apply stricter limits for authentication endpoints
-		if path == "/api/auth/login" || path == "/api/auth/register" {
+		// Apply stricter limits for authentication endpoints, including supervisor override PINs
+		if path == "/api/auth/login" || path == "/api/auth/pin-login" || path == "/api/auth/register" || (path == "/api/otorisasi" && c.Request.Method == "POST") {
 			if loginLimiter == nil {
 				// Default: 5 requests per minute for login
 				loginLimiter = NewRateLimiter(5, time.Minute)
@@ -6,6 +6,8 @@ import (
 	"ritel-app/internal/container"
 	"ritel-app/internal/http/handlers"
 	"ritel-app/internal/http/middleware"
+	"ritel-app/internal/models"
+	"ritel-app/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -49,6 +51,20 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 	printerHandler := handlers.NewPrinterHandler(services)
 	hardwareHandler := handlers.NewHardwareHandler(services)
 	settingsHandler := handlers.NewSettingsHandler(services)
+	metodePembayaranHandler := handlers.NewMetodePembayaranHandler(services)
+	piutangHandler := handlers.NewPiutangHandler(services)
+	sesiKasHandler := handlers.NewSesiKasHandler(services)
+	laciKasHandler := handlers.NewLaciKasHandler(services)
+	absensiHandler := handlers.NewAbsensiHandler(services)
+	komisiHandler := handlers.NewKomisiHandler(services)
+	targetHandler := handlers.NewTargetHandler(services)
+	roleHandler := handlers.NewRoleHandler(services)
+	otorisasiHandler := handlers.NewOtorisasiHandler(services)
+	auditHandler := handlers.NewAuditHandler(services)
+	supplierHandler := handlers.NewSupplierHandler(services)
+	pembelianHandler := handlers.NewPembelianHandler(services)
+	opnameHandler := handlers.NewOpnameHandler(services)
+	returSupplierHandler := handlers.NewReturSupplierHandler(services)
 	syncHandler := handlers.NewSyncHandler()
 
 	// Health check endpoint (no auth required)
@@ -67,11 +83,29 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/pin-login", authHandler.PinLogin)
+			auth.GET("/terminal", authHandler.TerminalStatus)
 		}
 
+		// Locking must work even when the terminal check below would reject the token
+		api.POST("/auth/lock", middleware.JWTAuth(jwtManager), authHandler.Lock)
+
 		// ==================== PROTECTED ROUTES (JWT required) ====================
 		protected := api.Group("")
 		protected.Use(middleware.JWTAuth(jwtManager))
+		protected.Use(middleware.TerminalAktif(services.TerminalService))
+
+		// Replays the stored response when a write is retried with the same Idempotency-Key
+		idempotent := middleware.Idempotency(services.IdempotencyService, service.ErrIdempotencyInProgress, service.ErrIdempotencyMismatch)
+
+		// Checks a named permission against the user's current role, see models.DaftarPermission
+		perm := func(perms ...string) gin.HandlerFunc {
+			return middleware.RequirePermission(services.UserService, perms...)
+		}
+
+		// Catalogue reads are shared by the checkout, stock and purchasing screens
+		katalog := perm(models.PermTransactionCreate, models.PermProductEdit, models.PermStockAdjust,
+			models.PermStockReceive, models.PermStockCount, models.PermPurchaseManage)
 		{
 			// Auth (authenticated users only)
 			authProtected := protected.Group("/auth")
@@ -84,96 +118,205 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 			// ==================== PRODUCTS ====================
 			produk := protected.Group("/produk")
 			{
-				produk.GET("", produkHandler.GetAll)
-				produk.POST("", produkHandler.Create)
-				produk.PUT("", produkHandler.Update)
-				produk.DELETE("/:id", produkHandler.Delete)
-				produk.POST("/scan", produkHandler.ScanBarcode)
-				produk.PUT("/stok", produkHandler.UpdateStok)
-				produk.PUT("/stok/increment", produkHandler.UpdateStokIncrement)
-				produk.GET("/:id/stok-history", produkHandler.GetStokHistory)
+				produk.GET("", katalog, produkHandler.GetAll)
+				produk.POST("", perm(models.PermProductEdit), produkHandler.Create)
+				produk.PUT("", perm(models.PermProductEdit), produkHandler.Update)
+				produk.DELETE("/:id", perm(models.PermProductEdit), produkHandler.Delete)
+				produk.POST("/scan", perm(models.PermStockReceive), produkHandler.ScanBarcode)
+				produk.PUT("/stok", perm(models.PermStockAdjust), idempotent, produkHandler.UpdateStok)
+				produk.PUT("/stok/increment", perm(models.PermStockAdjust), idempotent, produkHandler.UpdateStokIncrement)
+				produk.GET("/:id/stok-history", perm(models.PermProductEdit, models.PermStockAdjust, models.PermStockReceive), produkHandler.GetStokHistory)
 
 				// Cart operations
-				produk.GET("/keranjang", produkHandler.GetKeranjang)
-				produk.DELETE("/keranjang", produkHandler.ClearKeranjang)
-				produk.POST("/keranjang/process", produkHandler.ProcessKeranjang)
-				produk.DELETE("/keranjang/:id", produkHandler.RemoveFromKeranjang)
-				produk.PUT("/keranjang/jumlah", produkHandler.UpdateKeranjangJumlah)
+				produk.GET("/keranjang", perm(models.PermStockReceive), produkHandler.GetKeranjang)
+				produk.DELETE("/keranjang", perm(models.PermStockReceive), produkHandler.ClearKeranjang)
+				produk.POST("/keranjang/process", perm(models.PermStockReceive), produkHandler.ProcessKeranjang)
+				produk.DELETE("/keranjang/:id", perm(models.PermStockReceive), produkHandler.RemoveFromKeranjang)
+				produk.PUT("/keranjang/jumlah", perm(models.PermStockReceive), produkHandler.UpdateKeranjangJumlah)
 			}
 
 			// ==================== CATEGORIES ====================
 			kategori := protected.Group("/kategori")
 			{
-				kategori.GET("", kategoriHandler.GetAll)
-				kategori.GET("/:id", kategoriHandler.GetByID)
-				kategori.POST("", kategoriHandler.Create)
-				kategori.PUT("", kategoriHandler.Update)
-				kategori.DELETE("/:id", kategoriHandler.Delete)
+				kategori.GET("", katalog, kategoriHandler.GetAll)
+				kategori.GET("/:id", katalog, kategoriHandler.GetByID)
+				kategori.POST("", perm(models.PermCategoryManage), kategoriHandler.Create)
+				kategori.PUT("", perm(models.PermCategoryManage), kategoriHandler.Update)
+				kategori.DELETE("/:id", perm(models.PermCategoryManage), kategoriHandler.Delete)
 			}
 
 			// ==================== TRANSACTIONS ====================
 			transaksi := protected.Group("/transaksi")
 			{
-				transaksi.GET("", transaksiHandler.GetAll)
-				transaksi.POST("", transaksiHandler.Create)
-				transaksi.GET("/:id", transaksiHandler.GetByID)
-				transaksi.GET("/nomor/:nomor", transaksiHandler.GetByNoTransaksi)
-				transaksi.GET("/date-range", transaksiHandler.GetByDateRange)
-				transaksi.GET("/today-stats", transaksiHandler.GetTodayStats)
-				transaksi.GET("/pelanggan/:id", transaksiHandler.GetByPelanggan)
+				transaksi.GET("", perm(models.PermTransactionView), transaksiHandler.GetAll)
+				transaksi.POST("", perm(models.PermTransactionCreate), idempotent, transaksiHandler.Create)
+				transaksi.POST("/preview", perm(models.PermTransactionCreate), transaksiHandler.Preview)
+				transaksi.GET("/:id", perm(models.PermTransactionView), transaksiHandler.GetByID)
+				transaksi.GET("/nomor/:nomor", perm(models.PermTransactionView), transaksiHandler.GetByNoTransaksi)
+				transaksi.GET("/date-range", perm(models.PermTransactionView), transaksiHandler.GetByDateRange)
+				transaksi.GET("/today-stats", perm(models.PermTransactionView), transaksiHandler.GetTodayStats)
+				transaksi.GET("/pelanggan/:id", perm(models.PermTransactionView), transaksiHandler.GetByPelanggan)
+				transaksi.POST("/:id/void", perm(models.PermTransactionVoid), transaksiHandler.Void)
+
+				// Held (parked) transactions
+				transaksi.POST("/hold", perm(models.PermTransactionCreate), transaksiHandler.Hold)
+				transaksi.GET("/hold", perm(models.PermTransactionCreate), transaksiHandler.GetHeld)
+				transaksi.POST("/hold/:id/resume", perm(models.PermTransactionCreate), transaksiHandler.ResumeHeld)
+				transaksi.DELETE("/hold/:id", perm(models.PermTransactionCreate), transaksiHandler.DiscardHeld)
 			}
 
 			// ==================== CUSTOMERS ====================
 			pelanggan := protected.Group("/pelanggan")
 			{
-				pelanggan.GET("", pelangganHandler.GetAll)
-				pelanggan.GET("/:id", pelangganHandler.GetByID)
-				pelanggan.GET("/telepon/:telepon", pelangganHandler.GetByTelepon)
-				pelanggan.GET("/tipe/:tipe", pelangganHandler.GetByTipe)
-				pelanggan.POST("", pelangganHandler.Create)
-				pelanggan.PUT("", pelangganHandler.Update)
-				pelanggan.DELETE("/:id", pelangganHandler.Delete)
-				pelanggan.POST("/poin", pelangganHandler.AddPoin)
-				pelanggan.GET("/:id/stats", pelangganHandler.GetWithStats)
+				pelanggan.GET("", perm(models.PermCustomerManage, models.PermTransactionCreate), pelangganHandler.GetAll)
+				pelanggan.GET("/:id", perm(models.PermCustomerManage, models.PermTransactionCreate), pelangganHandler.GetByID)
+				pelanggan.GET("/telepon/:telepon", perm(models.PermCustomerManage, models.PermTransactionCreate), pelangganHandler.GetByTelepon)
+				pelanggan.GET("/tipe/:tipe", perm(models.PermCustomerManage, models.PermTransactionCreate), pelangganHandler.GetByTipe)
+				pelanggan.POST("", perm(models.PermCustomerManage), pelangganHandler.Create)
+				pelanggan.PUT("", perm(models.PermCustomerManage), pelangganHandler.Update)
+				pelanggan.DELETE("/:id", perm(models.PermCustomerManage), pelangganHandler.Delete)
+				pelanggan.POST("/poin", perm(models.PermCustomerManage), pelangganHandler.AddPoin)
+				pelanggan.GET("/:id/stats", perm(models.PermCustomerManage, models.PermTransactionCreate), pelangganHandler.GetWithStats)
+				pelanggan.GET("/:id/saldo", perm(models.PermCustomerManage, models.PermCustomerBalance), pelangganHandler.GetSaldoHistory)
+				pelanggan.POST("/saldo/topup", perm(models.PermCustomerManage), idempotent, pelangganHandler.TopUpSaldo)
+				pelanggan.POST("/saldo/penyesuaian", perm(models.PermCustomerBalance), pelangganHandler.PenyesuaianSaldo)
+				pelanggan.PUT("/kredit", perm(models.PermCustomerCredit), pelangganHandler.UpdateKredit)
+			}
+
+			// ==================== RECEIVABLES (Penjualan Tempo) ====================
+			piutang := protected.Group("/piutang")
+			{
+				piutang.GET("", perm(models.PermReceivableCollect, models.PermCustomerCredit), piutangHandler.GetAll)
+				piutang.GET("/umur", perm(models.PermReceivableCollect, models.PermCustomerCredit), piutangHandler.GetLaporanUmur)
+				piutang.GET("/pembayaran", perm(models.PermReceivableCollect, models.PermCustomerCredit), piutangHandler.GetPembayaran)
+				piutang.POST("/bayar", perm(models.PermReceivableCollect), idempotent, piutangHandler.Bayar)
+			}
+
+			// ==================== CASH DRAWER SESSIONS (Sesi Kas) ====================
+			sesiKas := protected.Group("/sesi-kas")
+			sesiKas.Use(perm(models.PermCashSession))
+			{
+				sesiKas.GET("", sesiKasHandler.GetAll)
+				sesiKas.GET("/aktif", sesiKasHandler.GetAktif)
+				sesiKas.GET("/:id/laporan", sesiKasHandler.GetLaporan)
+				sesiKas.POST("/buka", idempotent, sesiKasHandler.Buka)
+				sesiKas.POST("/mutasi", idempotent, sesiKasHandler.CatatMutasi)
+				sesiKas.POST("/tutup", idempotent, sesiKasHandler.Tutup)
+				sesiKas.POST("/cetak", sesiKasHandler.Cetak)
+			}
+
+			// ==================== CASH DRAWER (Laci Kas) ====================
+			laciKas := protected.Group("/laci-kas")
+			{
+				laciKas.POST("/no-sale", perm(models.PermCashDrawerOpen), idempotent, laciKasHandler.NoSale)
+				laciKas.GET("/log", perm(models.PermCashDrawerLog), laciKasHandler.GetLog)
+			}
+
+			// ==================== SUPERVISOR OVERRIDE (Otorisasi) ====================
+			// Any signed-in cashier may ask; the supervisor's own credentials in the body are checked
+			otorisasi := protected.Group("/otorisasi")
+			{
+				otorisasi.POST("", otorisasiHandler.Minta)
+				otorisasi.GET("/riwayat", perm(models.PermOverrideApprove), otorisasiHandler.GetRiwayat)
+			}
+
+			// ==================== ABSENSI ====================
+			absensi := protected.Group("/absensi")
+			absensi.Use(perm(models.PermAttendanceClock))
+			{
+				absensi.GET("/aktif", absensiHandler.GetAktif)
+				absensi.POST("/masuk", idempotent, absensiHandler.ClockIn)
+				absensi.POST("/pulang", idempotent, absensiHandler.ClockOut)
+				absensi.POST("/istirahat/mulai", idempotent, absensiHandler.MulaiIstirahat)
+				absensi.POST("/istirahat/selesai", idempotent, absensiHandler.SelesaiIstirahat)
 			}
 
 			// ==================== PROMOTIONS ====================
 			promo := protected.Group("/promo")
 			{
-				promo.GET("", promoHandler.GetAll)
-				promo.GET("/active", promoHandler.GetActive)
-				promo.GET("/:id", promoHandler.GetByID)
-				promo.GET("/kode/:kode", promoHandler.GetByKode)
-				promo.POST("", promoHandler.Create)
-				promo.PUT("", promoHandler.Update)
-				promo.DELETE("/:id", promoHandler.Delete)
-				promo.POST("/apply", promoHandler.Apply)
-				promo.GET("/produk/:id", promoHandler.GetForProduct)
-				promo.GET("/:id/products", promoHandler.GetProducts)
+				promo.GET("", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.GetAll)
+				promo.GET("/active", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.GetActive)
+				promo.GET("/:id", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.GetByID)
+				promo.GET("/kode/:kode", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.GetByKode)
+				promo.POST("", perm(models.PermPromoManage), promoHandler.Create)
+				promo.PUT("", perm(models.PermPromoManage), promoHandler.Update)
+				promo.DELETE("/:id", perm(models.PermPromoManage), promoHandler.Delete)
+				promo.POST("/apply", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.Apply)
+				promo.GET("/produk/:id", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.GetForProduct)
+				promo.GET("/:id/products", perm(models.PermTransactionCreate, models.PermPromoManage), promoHandler.GetProducts)
 			}
 
 			// ==================== BATCHES ====================
 			batch := protected.Group("/batch")
 			{
-				batch.GET("", batchHandler.GetAll)
-				batch.GET("/:id", batchHandler.GetByID)
-				batch.GET("/produk/:id", batchHandler.GetByProduk)
-				batch.GET("/expiring/:days", batchHandler.GetExpiring)
-				batch.DELETE("/:id/expired", batchHandler.DeleteExpired)
-				batch.GET("/summary/:id", batchHandler.GetSummary)
-				batch.PUT("/update-status", batchHandler.UpdateStatuses)
+				batch.GET("", perm(models.PermBatchManage, models.PermStockAdjust, models.PermStockReceive, models.PermProductEdit), batchHandler.GetAll)
+				batch.GET("/:id", perm(models.PermBatchManage, models.PermStockAdjust, models.PermStockReceive, models.PermProductEdit), batchHandler.GetByID)
+				batch.GET("/produk/:id", perm(models.PermBatchManage, models.PermStockAdjust, models.PermStockReceive, models.PermProductEdit), batchHandler.GetByProduk)
+				batch.GET("/expiring/:days", perm(models.PermBatchManage, models.PermStockAdjust, models.PermStockReceive, models.PermProductEdit), batchHandler.GetExpiring)
+				batch.DELETE("/:id/expired", perm(models.PermBatchManage), batchHandler.DeleteExpired)
+				batch.GET("/summary/:id", perm(models.PermBatchManage, models.PermStockAdjust, models.PermStockReceive, models.PermProductEdit), batchHandler.GetSummary)
+				batch.PUT("/update-status", perm(models.PermBatchManage), batchHandler.UpdateStatuses)
+			}
+
+			// ==================== SUPPLIERS ====================
+			supplier := protected.Group("/supplier")
+			{
+				supplier.GET("", perm(models.PermPurchaseManage, models.PermStockReceive), supplierHandler.GetAll)
+				supplier.GET("/:id", perm(models.PermPurchaseManage, models.PermStockReceive), supplierHandler.GetByID)
+				supplier.POST("", perm(models.PermPurchaseManage), supplierHandler.Create)
+				supplier.PUT("/:id", perm(models.PermPurchaseManage), supplierHandler.Update)
+				supplier.DELETE("/:id", perm(models.PermPurchaseManage), supplierHandler.Delete)
+			}
+
+			// ==================== PURCHASE ORDERS & GOODS RECEIPTS ====================
+			pembelian := protected.Group("/pembelian")
+			{
+				pembelian.GET("", perm(models.PermPurchaseManage, models.PermStockReceive), pembelianHandler.GetAll)
+				pembelian.GET("/:id", perm(models.PermPurchaseManage, models.PermStockReceive), pembelianHandler.GetByID)
+				pembelian.POST("", perm(models.PermPurchaseManage), pembelianHandler.Create)
+				pembelian.PUT("/:id", perm(models.PermPurchaseManage), pembelianHandler.Update)
+				pembelian.POST("/:id/status", perm(models.PermPurchaseManage), pembelianHandler.UbahStatus)
+				pembelian.POST("/:id/terima", perm(models.PermStockReceive), idempotent, pembelianHandler.TerimaBarang)
+			}
+
+			penerimaan := protected.Group("/penerimaan")
+			{
+				penerimaan.GET("", perm(models.PermPurchaseManage, models.PermStockReceive), pembelianHandler.GetAllPenerimaan)
+				penerimaan.GET("/:id", perm(models.PermPurchaseManage, models.PermStockReceive), pembelianHandler.GetPenerimaanByID)
+			}
+
+			returSupplier := protected.Group("/retur-supplier")
+			{
+				returSupplier.GET("", perm(models.PermPurchaseManage), returSupplierHandler.GetAll)
+				returSupplier.GET("/:id", perm(models.PermPurchaseManage), returSupplierHandler.GetByID)
+				returSupplier.POST("", perm(models.PermPurchaseManage), idempotent, returSupplierHandler.Create)
+				returSupplier.POST("/:id/selesai", perm(models.PermPurchaseManage), idempotent, returSupplierHandler.Selesaikan)
+				returSupplier.POST("/:id/batal", perm(models.PermPurchaseManage), returSupplierHandler.Batal)
+			}
+
+			// ==================== STOCK COUNT (OPNAME) ====================
+			opname := protected.Group("/opname")
+			{
+				opname.GET("", perm(models.PermStockOpname, models.PermStockCount), opnameHandler.GetAll)
+				opname.GET("/:id", perm(models.PermStockOpname, models.PermStockCount), opnameHandler.GetByID)
+				opname.GET("/:id/selisih", perm(models.PermStockOpname, models.PermStockCount), opnameHandler.LaporanSelisih)
+				opname.POST("", perm(models.PermStockOpname), opnameHandler.Buka)
+				opname.POST("/:id/hitung", perm(models.PermStockCount), idempotent, opnameHandler.Hitung)
+				opname.POST("/:id/posting", perm(models.PermStockOpname), idempotent, opnameHandler.Posting)
+				opname.POST("/:id/batal", perm(models.PermStockOpname), opnameHandler.Batal)
 			}
 
 			// ==================== RETURNS ====================
 			returns := protected.Group("/return")
 			{
-				returns.GET("", returnHandler.GetAll)
-				returns.GET("/:id", returnHandler.GetByID)
-				returns.POST("", returnHandler.Create)
+				returns.GET("", perm(models.PermReturnApprove, models.PermTransactionView), returnHandler.GetAll)
+				returns.GET("/:id", perm(models.PermReturnApprove, models.PermTransactionView), returnHandler.GetByID)
+				returns.POST("", perm(models.PermReturnApprove), idempotent, returnHandler.Create)
 			}
 
 			// ==================== ANALYTICS ====================
 			analytics := protected.Group("/analytics")
+			analytics.Use(perm(models.PermReportSalesView))
 			{
 				analytics.GET("/top-products", analyticsHandler.GetTopProducts)
 				analytics.GET("/payment-breakdown", analyticsHandler.GetPaymentMethodBreakdown)
@@ -185,6 +328,7 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 
 			// ==================== DASHBOARD ====================
 			dashboard := protected.Group("/dashboard")
+			dashboard.Use(perm(models.PermReportSalesView))
 			{
 				dashboard.GET("", dashboardHandler.GetDashboardData)
 				dashboard.GET("/sales-chart", dashboardHandler.GetSalesChart)
@@ -195,6 +339,7 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 
 			// ==================== STAFF REPORTS ====================
 			staffReport := protected.Group("/staff-report")
+			staffReport.Use(perm(models.PermReportStaffView))
 			{
 				// Static routes first (before dynamic :id routes)
 				staffReport.GET("/all", staffReportHandler.GetAllStaffReports)
@@ -205,10 +350,11 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 				// START NEW ROUTE
 				staffReport.GET("/shift-reports", staffReportHandler.GetShiftReports)
 				staffReport.GET("/shift-settings", staffReportHandler.GetShiftSettings)
-				staffReport.PUT("/shift-settings/:id", staffReportHandler.UpdateShiftSettings)
+				staffReport.PUT("/shift-settings/:id", perm(models.PermShiftManage), staffReportHandler.UpdateShiftSettings)
 				staffReport.GET("/shift/:shift/cashiers", staffReportHandler.GetShiftCashiers)
 				staffReport.GET("/shift/:shift/detail", staffReportHandler.GetShiftDetail)
 				// END NEW ROUTE
+				staffReport.GET("/absensi", staffReportHandler.GetAllLaporanAbsensi)
 
 				// Dynamic :id routes
 				staffReport.GET("/:id", staffReportHandler.GetStaffReport)
@@ -217,29 +363,59 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 				staffReport.GET("/:id/historical", staffReportHandler.GetHistoricalData)
 				staffReport.GET("/:id/shift-data", staffReportHandler.GetStaffShiftData)
 				staffReport.GET("/:id/monthly-trend", staffReportHandler.GetWithMonthlyTrend)
+				staffReport.GET("/:id/absensi", staffReportHandler.GetLaporanAbsensi)
+			}
+
+			// ==================== KOMISI STAFF ====================
+			komisi := protected.Group("/komisi")
+			komisi.Use(perm(models.PermCommissionManage))
+			{
+				komisi.GET("/aturan", komisiHandler.GetAllAturan)
+				komisi.POST("/aturan", komisiHandler.CreateAturan)
+				komisi.PUT("/aturan/:id", komisiHandler.UpdateAturan)
+				komisi.DELETE("/aturan/:id", komisiHandler.DeleteAturan)
+				komisi.GET("/hitung", komisiHandler.Hitung)
+				komisi.GET("/periode", komisiHandler.GetAllPeriode)
+				komisi.POST("/periode", idempotent, komisiHandler.BuatPeriode)
+				komisi.GET("/periode/:id", komisiHandler.GetPeriode)
+				komisi.POST("/periode/:id/bayar", idempotent, komisiHandler.TandaiDibayar)
+				komisi.DELETE("/periode/:id", komisiHandler.DeletePeriode)
+				komisi.GET("/periode/:id/export", komisiHandler.ExportPayroll)
+			}
+
+			// ==================== TARGET PENJUALAN ====================
+			target := protected.Group("/target")
+			{
+				target.GET("", perm(models.PermTargetManage, models.PermReportStaffView), targetHandler.GetAll)
+				target.POST("", perm(models.PermTargetManage), targetHandler.Create)
+				target.PUT("/:id", perm(models.PermTargetManage), targetHandler.Update)
+				target.DELETE("/:id", perm(models.PermTargetManage), targetHandler.Delete)
 			}
 
 			// ==================== SALES REPORTS ====================
 			salesReport := protected.Group("/sales-report")
+			salesReport.Use(perm(models.PermReportProfitView))
 			{
 				salesReport.GET("/comprehensive", salesReportHandler.GetComprehensive)
+				salesReport.GET("/pajak", salesReportHandler.GetLaporanPajak)
 			}
 
 			// ==================== PRINTERS ====================
 			printer := protected.Group("/printer")
 			{
-				printer.GET("/list", printerHandler.GetInstalled)
+				printer.GET("/list", perm(models.PermHardwareManage), printerHandler.GetInstalled)
 				// Alias untuk kompatibilitas frontend lama
-				printer.GET("/available", printerHandler.GetInstalled)
-				printer.POST("/test", printerHandler.TestPrint)
-				printer.POST("/receipt", printerHandler.PrintReceipt)
-				printer.GET("/settings", printerHandler.GetSettings)
-				printer.POST("/settings", printerHandler.SaveSettings)
-				printer.POST("/default", printerHandler.SetDefault)
+				printer.GET("/available", perm(models.PermHardwareManage), printerHandler.GetInstalled)
+				printer.POST("/test", perm(models.PermHardwareManage), printerHandler.TestPrint)
+				printer.POST("/receipt", perm(models.PermTransactionCreate, models.PermTransactionView), printerHandler.PrintReceipt)
+				printer.GET("/settings", perm(models.PermHardwareManage), printerHandler.GetSettings)
+				printer.POST("/settings", perm(models.PermHardwareManage), printerHandler.SaveSettings)
+				printer.POST("/default", perm(models.PermHardwareManage), printerHandler.SetDefault)
 			}
 
 			// ==================== HARDWARE ====================
 			hardware := protected.Group("/hardware")
+			hardware.Use(perm(models.PermHardwareManage))
 			{
 				hardware.GET("/detect", hardwareHandler.DetectHardware)
 				hardware.POST("/test-scanner", hardwareHandler.TestScanner)
@@ -250,36 +426,71 @@ func SetupRouter(services *container.ServiceContainer, jwtManager *auth.JWTManag
 			// ==================== SETTINGS ====================
 			settings := protected.Group("/settings")
 			{
-				settings.GET("/poin", settingsHandler.GetPoinSettings)
-				settings.PUT("/poin", settingsHandler.UpdatePoinSettings)
+				settings.GET("/poin", perm(models.PermSettingsManage, models.PermTransactionCreate, models.PermCustomerManage), settingsHandler.GetPoinSettings)
+				settings.PUT("/poin", perm(models.PermSettingsManage), settingsHandler.UpdatePoinSettings)
+				settings.GET("/penomoran", perm(models.PermSettingsManage), settingsHandler.GetPenomoranSettings)
+				settings.PUT("/penomoran", perm(models.PermSettingsManage), settingsHandler.UpdatePenomoranSettings)
+				settings.GET("/pembulatan", perm(models.PermSettingsManage, models.PermTransactionCreate), settingsHandler.GetPembulatanSettings)
+				settings.GET("/pembulatan/preview", perm(models.PermSettingsManage, models.PermTransactionCreate), settingsHandler.PreviewPembulatan)
+				settings.PUT("/pembulatan", perm(models.PermSettingsManage), settingsHandler.UpdatePembulatanSettings)
+				settings.GET("/pajak", perm(models.PermSettingsManage, models.PermTransactionCreate, models.PermReportProfitView), settingsHandler.GetPajakSettings)
+				settings.PUT("/pajak", perm(models.PermSettingsManage), settingsHandler.UpdatePajakSettings)
+				settings.GET("/laci-kas", perm(models.PermSettingsManage, models.PermCashDrawerOpen, models.PermCashSession), settingsHandler.GetLaciKasSettings)
+				settings.PUT("/laci-kas", perm(models.PermSettingsManage), settingsHandler.UpdateLaciKasSettings)
+				settings.GET("/otorisasi", perm(models.PermSettingsManage, models.PermTransactionCreate), settingsHandler.GetOtorisasiSettings)
+				settings.PUT("/otorisasi", perm(models.PermSettingsManage), settingsHandler.UpdateOtorisasiSettings)
+				settings.GET("/keamanan", perm(models.PermSettingsManage), settingsHandler.GetKeamananSettings)
+				settings.PUT("/keamanan", perm(models.PermSettingsManage), settingsHandler.UpdateKeamananSettings)
+				settings.GET("/pembelian", perm(models.PermSettingsManage, models.PermPurchaseManage, models.PermStockReceive), settingsHandler.GetPembelianSettings)
+				settings.PUT("/pembelian", perm(models.PermSettingsManage), settingsHandler.UpdatePembelianSettings)
+			}
+
+			// ==================== PAYMENT METHODS ====================
+			metodePembayaran := protected.Group("/metode-pembayaran")
+			{
+				metodePembayaran.GET("", perm(models.PermPaymentMethodManage, models.PermTransactionCreate, models.PermReceivableCollect, models.PermCashSession), metodePembayaranHandler.GetAll)
+				metodePembayaran.POST("", perm(models.PermPaymentMethodManage), metodePembayaranHandler.Create)
+				metodePembayaran.PUT("", perm(models.PermPaymentMethodManage), metodePembayaranHandler.Update)
+				metodePembayaran.DELETE("/:id", perm(models.PermPaymentMethodManage), metodePembayaranHandler.Delete)
 			}
 
 			// ==================== SYNC (Offline-First Mode) ====================
 			// These endpoints are available when SYNC_MODE=enabled in .env
 			sync := protected.Group("/sync")
 			{
+				// Queue counts for the connection indicator every signed-in user sees
 				sync.GET("/status", syncHandler.GetSyncStatus)
-				sync.POST("/force", syncHandler.ForceSyncNow)
-				sync.GET("/pending", syncHandler.GetPendingSyncs)
-				sync.POST("/clear", syncHandler.ClearSyncedQueue)
+				sync.POST("/force", perm(models.PermSyncManage), syncHandler.ForceSyncNow)
+				sync.GET("/pending", perm(models.PermSyncManage), syncHandler.GetPendingSyncs)
+				sync.POST("/clear", perm(models.PermSyncManage), syncHandler.ClearSyncedQueue)
+			}
+
+			// ==================== USER MANAGEMENT ====================
+			users := protected.Group("/users")
+			users.Use(perm(models.PermUserManage))
+			{
+				users.GET("", userHandler.GetAll)
+				users.GET("/staff", userHandler.GetAllStaff)
+				users.GET("/:id", userHandler.GetByID)
+				users.GET("/:id/login-history", userHandler.GetLoginHistory)
+				users.POST("", userHandler.Create)
+				users.PUT("", userHandler.Update)
+				users.POST("/change-password", userHandler.AdminChangePassword)
+				users.DELETE("/:id", userHandler.Delete)
 			}
 
-			// ==================== ADMIN-ONLY ROUTES ====================
-			admin := protected.Group("")
-			admin.Use(middleware.RequireAdmin())
+			// ==================== ROLES & PERMISSIONS ====================
+			roles := protected.Group("/roles")
 			{
-				// User management
-				users := admin.Group("/users")
-				{
-					users.GET("", userHandler.GetAll)
-					users.GET("/staff", userHandler.GetAllStaff)
-					users.GET("/:id", userHandler.GetByID)
-					users.POST("", userHandler.Create)
-					users.PUT("", userHandler.Update)
-					users.POST("/change-password", userHandler.AdminChangePassword)
-					users.DELETE("/:id", userHandler.Delete)
-				}
+				roles.GET("", perm(models.PermRoleManage, models.PermUserManage), roleHandler.GetAll)
+				roles.GET("/permissions", perm(models.PermRoleManage), roleHandler.GetDaftarPermission)
+				roles.POST("", perm(models.PermRoleManage), roleHandler.Create)
+				roles.PUT("/:id", perm(models.PermRoleManage), roleHandler.Update)
+				roles.DELETE("/:id", perm(models.PermRoleManage), roleHandler.Delete)
 			}
+
+			// ==================== AUDIT LOG ====================
+			protected.GET("/audit", perm(models.PermAuditView), auditHandler.GetAll)
 		}
 	}
 
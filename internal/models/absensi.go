@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Absensi represents one attendance record: clock-in to clock-out of a staff member.
+// Lateness, early leave and overtime are measured against the shift schedule at clock-in.
+type Absensi struct {
+	ID               int64               `json:"id,string"`
+	StaffID          int64               `json:"staffId,string"`
+	StaffNama        string              `json:"staffNama"`
+	Tanggal          string              `json:"tanggal"` // Tanggal kerja (YYYY-MM-DD) sesuai jadwal shift
+	ShiftID          int                 `json:"shiftId"` // 0 = tidak ada jadwal shift
+	ShiftNama        string              `json:"shiftNama"`
+	JadwalMasuk      string              `json:"jadwalMasuk"`  // "HH:MM" dari shift_settings
+	JadwalPulang     string              `json:"jadwalPulang"` // "HH:MM" dari shift_settings
+	Status           string              `json:"status"`       // "masuk", "istirahat", "pulang"
+	MetodeMasuk      string              `json:"metodeMasuk"`  // "login", "pin"
+	MetodePulang     string              `json:"metodePulang"`
+	JamMasuk         time.Time           `json:"jamMasuk"`
+	JamPulang        *time.Time          `json:"jamPulang"`
+	TerlambatMenit   int                 `json:"terlambatMenit"`
+	PulangCepatMenit int                 `json:"pulangCepatMenit"`
+	LemburMenit      int                 `json:"lemburMenit"`
+	IstirahatMenit   int                 `json:"istirahatMenit"`
+	KerjaMenit       int                 `json:"kerjaMenit"` // Durasi kerja tanpa istirahat
+	Catatan          string              `json:"catatan"`
+	Istirahat        []*AbsensiIstirahat `json:"istirahat"`
+	CreatedAt        time.Time           `json:"createdAt"`
+	UpdatedAt        time.Time           `json:"updatedAt"`
+}
+
+// AbsensiIstirahat represents one break taken during an attendance record
+type AbsensiIstirahat struct {
+	ID          int64      `json:"id,string"`
+	AbsensiID   int64      `json:"absensiId,string"`
+	Mulai       time.Time  `json:"mulai"`
+	Selesai     *time.Time `json:"selesai"`
+	DurasiMenit int        `json:"durasiMenit"`
+}
+
+// AbsensiRequest represents a clock-in, clock-out or break request.
+// At a shared POS the staff member identifies with Username and PIN;
+// otherwise the logged-in user (StaffID) is used.
+type AbsensiRequest struct {
+	Username  string `json:"username"`
+	Pin       string `json:"pin"`
+	Catatan   string `json:"catatan"`
+	StaffID   int64  `json:"staffId,string"`
+	StaffNama string `json:"staffNama"`
+}
+
+// LaporanAbsensi is the monthly attendance summary of one staff member
+type LaporanAbsensi struct {
+	StaffID               int64      `json:"staffId,string"`
+	StaffNama             string     `json:"staffNama"`
+	Tahun                 int        `json:"tahun"`
+	Bulan                 int        `json:"bulan"`
+	HariHadir             int        `json:"hariHadir"`
+	JumlahTerlambat       int        `json:"jumlahTerlambat"`
+	TotalTerlambatMenit   int        `json:"totalTerlambatMenit"`
+	JumlahPulangCepat     int        `json:"jumlahPulangCepat"`
+	TotalPulangCepatMenit int        `json:"totalPulangCepatMenit"`
+	TotalLemburMenit      int        `json:"totalLemburMenit"`
+	TotalIstirahatMenit   int        `json:"totalIstirahatMenit"`
+	TotalKerjaMenit       int        `json:"totalKerjaMenit"`
+	Absensi               []*Absensi `json:"absensi"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Entitas yang dicatat di audit log
+const (
+	AuditEntitasProduk    = "produk"
+	AuditEntitasPelanggan = "pelanggan"
+	AuditEntitasUser      = "user"
+	AuditEntitasSettings  = "settings"
+	AuditEntitasRole      = "role"
+)
+
+// Aksi yang dicatat di audit log
+const (
+	AuditAksiCreate = "create"
+	AuditAksiUpdate = "update"
+	AuditAksiDelete = "delete"
+)
+
+// Aktor is the user who performed a change, taken from the JWT claims or the desktop session
+type Aktor struct {
+	ID   int64  `json:"id,string"`
+	Nama string `json:"nama"`
+}
+
+// AuditPerubahan holds the old and new value of one changed field
+type AuditPerubahan struct {
+	Sebelum interface{} `json:"sebelum"`
+	Sesudah interface{} `json:"sesudah"`
+}
+
+// AuditLog is one append-only entry of the audit trail
+type AuditLog struct {
+	ID         int64                     `json:"id,string"`
+	AktorID    int64                     `json:"aktorId,string"` // 0 = sistem
+	AktorNama  string                    `json:"aktorNama"`
+	Entitas    string                    `json:"entitas"`
+	EntitasID  string                    `json:"entitasId"`
+	Aksi       string                    `json:"aksi"`
+	Perubahan  map[string]AuditPerubahan `json:"perubahan"` // Per field JSON: nilai sebelum dan sesudah
+	Keterangan string                    `json:"keterangan"`
+	CreatedAt  time.Time                 `json:"createdAt"`
+}
+
+// AuditFilter narrows down the audit log; zero values are ignored
+type AuditFilter struct {
+	Entitas   string
+	EntitasID string
+	AktorID   int64
+	Aksi      string
+	StartDate time.Time
+	EndDate   time.Time
+	Limit     int
+	Offset    int
+}
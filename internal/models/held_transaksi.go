@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// HeldTransaksi represents a parked (suspended) sale that can be resumed later
+type HeldTransaksi struct {
+	ID         int64                  `json:"id,string"`
+	Label      string                 `json:"label"`
+	TerminalID string                 `json:"terminalId"`
+	StaffID    int64                  `json:"staffId,string"`
+	StaffNama  string                 `json:"staffNama"`
+	Draft      CreateTransaksiRequest `json:"draft"`
+	JumlahItem int                    `json:"jumlahItem"`
+	Subtotal   int                    `json:"subtotal"`
+	Status     string                 `json:"status"` // "held", "resumed", "expired", "discarded"
+	ExpiresAt  time.Time              `json:"expiresAt"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	UpdatedAt  time.Time              `json:"updatedAt"`
+}
+
+// HoldTransaksiRequest represents request to park the current cart
+type HoldTransaksiRequest struct {
+	Label      string                 `json:"label"`
+	TerminalID string                 `json:"terminalId"`
+	StaffID    int64                  `json:"staffId,string"`
+	StaffNama  string                 `json:"staffNama"`
+	Draft      CreateTransaksiRequest `json:"draft"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyRecord stores the response of a request made with an Idempotency-Key,
+// so a retried request returns the original response instead of being executed twice
+type IdempotencyRecord struct {
+	ID          int64     `json:"id,string"`
+	Scope       string    `json:"scope"` // Endpoint, e.g. "POST /api/transaksi"
+	Key         string    `json:"key"`
+	RequestHash string    `json:"requestHash"`
+	Status      string    `json:"status"` // "processing", "completed"
+	StatusCode  int       `json:"statusCode"`
+	Response    string    `json:"response"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
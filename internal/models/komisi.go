@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// AturanKomisi is one commission or incentive rule.
+// Rules can be limited to some products or a category (per-category bonus) and to some staff.
+type AturanKomisi struct {
+	ID             int64           `json:"id,string"`
+	Nama           string          `json:"nama"`
+	Tipe           string          `json:"tipe"`           // "persen_penjualan", "persen_profit", "per_unit", "target_bertingkat"
+	Persen         float64         `json:"persen"`         // persen_penjualan, persen_profit
+	NominalPerUnit int             `json:"nominalPerUnit"` // per_unit: per pcs, atau per kg untuk barang curah
+	ProdukIDs      []int           `json:"produkIds"`      // Kosong = semua produk
+	Kategori       string          `json:"kategori"`       // Kosong = semua kategori
+	StaffIDs       []int64         `json:"staffIds"`       // Kosong = semua staff
+	Tingkat        []TingkatKomisi `json:"tingkat"`        // target_bertingkat
+	Aktif          bool            `json:"aktif"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// TingkatKomisi is one tier of a target_bertingkat rule. The highest tier whose target
+// is reached by net sales pays Bonus plus Persen of net sales.
+type TingkatKomisi struct {
+	Target int     `json:"target"`
+	Bonus  int     `json:"bonus"`
+	Persen float64 `json:"persen"`
+}
+
+// PenjualanProdukKomisi is the sales of one product by a staff member within a period
+type PenjualanProdukKomisi struct {
+	ProdukID  int     `json:"produkId,string"`
+	Kategori  string  `json:"kategori"`
+	Jumlah    float64 `json:"jumlah"` // pcs, atau kg untuk barang curah
+	Penjualan int     `json:"penjualan"`
+	HPP       int     `json:"hpp"`
+}
+
+// KomisiStaff is the commission of one staff member for a period
+type KomisiStaff struct {
+	ID             int64                `json:"id,string"`
+	PeriodeID      int64                `json:"periodeId,string"`
+	StaffID        int64                `json:"staffId,string"`
+	StaffNama      string               `json:"staffNama"`
+	TotalPenjualan int                  `json:"totalPenjualan"`
+	TotalProfit    int                  `json:"totalProfit"`
+	TotalItem      int                  `json:"totalItem"`
+	ReturPenjualan int                  `json:"returPenjualan"` // Dari GetReturnImpactByStaffAndDateRange
+	ReturProfit    int                  `json:"returProfit"`
+	ReturItem      float64              `json:"returItem"`
+	TotalKomisi    int                  `json:"totalKomisi"`
+	Rincian        []RincianKomisiStaff `json:"rincian"`
+}
+
+// RincianKomisiStaff is the payout of one rule for a staff member
+type RincianKomisiStaff struct {
+	AturanID int64   `json:"aturanId,string"`
+	Nama     string  `json:"nama"`
+	Tipe     string  `json:"tipe"`
+	Basis    float64 `json:"basis"` // Penjualan, profit atau unit bersih setelah retur
+	Komisi   int     `json:"komisi"`
+}
+
+// PeriodeKomisi is a saved commission payout for a date range.
+// A draft can be deleted and recalculated; once paid it is locked.
+type PeriodeKomisi struct {
+	ID             int64          `json:"id,string"`
+	PeriodeMulai   string         `json:"periodeMulai"`   // YYYY-MM-DD
+	PeriodeSelesai string         `json:"periodeSelesai"` // YYYY-MM-DD
+	Status         string         `json:"status"`         // "draft", "dibayar"
+	TotalKomisi    int            `json:"totalKomisi"`
+	Catatan        string         `json:"catatan"`
+	DibuatOleh     string         `json:"dibuatOleh"`
+	DibayarAt      *time.Time     `json:"dibayarAt"`
+	Staff          []*KomisiStaff `json:"staff,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+// BuatPeriodeKomisiRequest represents request to calculate and save a commission payout
+type BuatPeriodeKomisiRequest struct {
+	PeriodeMulai   string `json:"periodeMulai"`
+	PeriodeSelesai string `json:"periodeSelesai"`
+	Catatan        string `json:"catatan"`
+	DibuatOleh     string `json:"dibuatOleh"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// LaciKasSettings configures how this terminal kicks its cash drawer
+type LaciKasSettings struct {
+	Aktif       bool   `json:"aktif"`
+	Driver      string `json:"driver"`      // "printer" (ESC p lewat printer struk) atau "serial"
+	PrinterName string `json:"printerName"` // Kosong = printer struk default
+	Port        string `json:"port"`        // Port serial, misalnya "COM3" atau "/dev/ttyUSB0"
+	BaudRate    int    `json:"baudRate"`
+	Pin         int    `json:"pin"` // 0 = pin 2, 1 = pin 5 konektor laci
+}
+
+// LogLaciKas records one drawer opening; unexplained openings are a shrinkage signal
+type LogLaciKas struct {
+	ID             int64               `json:"id,string"`
+	Tipe           string              `json:"tipe"` // "transaksi", "no_sale"
+	Alasan         string              `json:"alasan"`
+	TransaksiID    int64               `json:"transaksiId,string"`
+	NomorTransaksi string              `json:"nomorTransaksi"`
+	SesiID         int64               `json:"sesiId,string"`
+	StaffID        int64               `json:"staffId,string"`
+	StaffNama      string              `json:"staffNama"`
+	Berhasil       bool                `json:"berhasil"`
+	Pesan          string              `json:"pesan"`         // Pesan error driver jika gagal membuka
+	DisetujuiOleh  string              `json:"disetujuiOleh"` // Supervisor yang mengotorisasi no sale
+	CreatedAt      time.Time           `json:"createdAt"`
+	Otorisasi      *PemakaianOtorisasi `json:"-"` // Token otorisasi no sale yang dipakai saat log disimpan
+}
+
+// NoSaleRequest represents request to open the drawer without a sale
+type NoSaleRequest struct {
+	Alasan        string `json:"alasan"`
+	StaffID       int64  `json:"staffId,string"`
+	StaffNama     string `json:"staffNama"`
+	OverrideToken string `json:"overrideToken"` // Token otorisasi supervisor jika no sale wajib otorisasi
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Metode login yang dicatat di audit login
+const (
+	MetodeLoginPassword  = "password"
+	MetodeLoginPin       = "pin"
+	MetodeLoginOtorisasi = "otorisasi" // Verifikasi supervisor untuk token otorisasi
+	MetodeLoginAbsensi   = "absensi"   // PIN di mesin absensi
+)
+
+// LoginEvent records one sign-in attempt, successful or not
+type LoginEvent struct {
+	ID         int64     `json:"id,string"`
+	UserID     int64     `json:"userId,string"` // 0 jika username tidak dikenal
+	Username   string    `json:"username"`
+	Metode     string    `json:"metode"`
+	Berhasil   bool      `json:"berhasil"`
+	Alasan     string    `json:"alasan"` // Pesan kegagalan yang ditampilkan ke user
+	IPAddress  string    `json:"ipAddress"`
+	TerminalID string    `json:"terminalId"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// MetodeSaldo is the code of the system payment method that spends the customer's store credit
+const MetodeSaldo = "saldo"
+
+// MetodePembayaran represents a configured payment method
+type MetodePembayaran struct {
+	ID             int64     `json:"id,string"`
+	Kode           string    `json:"kode"`           // Disimpan di pembayaran.metode, misalnya "tunai", "qris"
+	Nama           string    `json:"nama"`           // Nama yang ditampilkan di kasir dan struk
+	Tipe           string    `json:"tipe"`           // "tunai", "kartu", "dompet_digital", "transfer", "saldo", "tempo", "lainnya"
+	BukaLaci       bool      `json:"bukaLaci"`       // Laci kas dibuka saat metode ini dipakai
+	PerluReferensi bool      `json:"perluReferensi"` // Nomor referensi wajib diisi
+	Aktif          bool      `json:"aktif"`
+	Urutan         int       `json:"urutan"` // Urutan tampil di layar pembayaran
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// IsTunai reports whether the method is cash, the only method allowed to produce change
+func (m *MetodePembayaran) IsTunai() bool {
+	return m.Tipe == "tunai"
+}
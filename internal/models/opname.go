@@ -0,0 +1,103 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Status sesi stok opname
+const (
+	StatusOpnameDibuka    = "dibuka"    // Sedang dihitung
+	StatusOpnameDiposting = "diposting" // Selisih sudah disetujui dan stok disesuaikan
+	StatusOpnameBatal     = "batal"
+)
+
+// Cara jumlah hitungan digabung ke baris opname
+const (
+	ModeHitungTambah = "tambah" // Ditambahkan, untuk produk yang dihitung di beberapa rak atau perangkat
+	ModeHitungGanti  = "ganti"  // Menggantikan hitungan sebelumnya (hitung ulang)
+)
+
+// StokOpname is a physical stock count session over all products or one category.
+// The system quantity of every product is frozen when the session is opened.
+type StokOpname struct {
+	ID             int64             `json:"id,string"`
+	NomorOpname    string            `json:"nomorOpname"`
+	Kategori       string            `json:"kategori"` // Kosong = semua produk
+	Status         string            `json:"status"`
+	Catatan        string            `json:"catatan"`
+	DibukaOleh     string            `json:"dibukaOleh"`
+	DipostingOleh  string            `json:"dipostingOleh"`
+	TanggalMulai   time.Time         `json:"tanggalMulai"`
+	TanggalPosting *time.Time        `json:"tanggalPosting,omitempty"`
+	JumlahItem     int               `json:"jumlahItem"`
+	JumlahDihitung int               `json:"jumlahDihitung"`
+	Items          []*StokOpnameItem `json:"items,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
+
+// StokOpnameItem is one product of a count session
+type StokOpnameItem struct {
+	ID           int64      `json:"id,string"`
+	OpnameID     int64      `json:"opnameId,string"`
+	ProdukID     int        `json:"produkId"`
+	ProdukNama   string     `json:"produkNama"`
+	Barcode      string     `json:"barcode"`
+	Kategori     string     `json:"kategori"`
+	Satuan       string     `json:"satuan"`
+	QtySistem    float64    `json:"qtySistem"` // Stok sistem saat sesi dibuka
+	QtyHitung    *float64   `json:"qtyHitung"` // nil = belum dihitung
+	Selisih      float64    `json:"selisih"`   // QtyHitung - QtySistem
+	HargaBeli    int        `json:"hargaBeli"` // Harga beli saat sesi dibuka
+	NilaiSelisih int        `json:"nilaiSelisih"`
+	DihitungOleh string     `json:"dihitungOleh"`
+	Perangkat    string     `json:"perangkat"`
+	DihitungPada *time.Time `json:"dihitungPada,omitempty"`
+}
+
+// HitungSelisih fills Selisih and NilaiSelisih from the counted quantity
+func (i *StokOpnameItem) HitungSelisih() {
+	i.Selisih, i.NilaiSelisih = 0, 0
+	if i.QtyHitung == nil {
+		return
+	}
+	i.Selisih = *i.QtyHitung - i.QtySistem
+	i.NilaiSelisih = int(math.Round(i.Selisih * float64(i.HargaBeli)))
+}
+
+// BukaOpnameRequest opens a count session
+type BukaOpnameRequest struct {
+	Kategori string `json:"kategori"` // Kosong = semua produk
+	Catatan  string `json:"catatan"`
+}
+
+// HitungOpnameRequest records a counted quantity from a scanner or manual entry
+type HitungOpnameRequest struct {
+	ProdukID  int     `json:"produkId"`
+	Barcode   string  `json:"barcode"` // Dipakai jika produkId kosong
+	Qty       float64 `json:"qty"`
+	Mode      string  `json:"mode"`      // "tambah" (default) atau "ganti"
+	Perangkat string  `json:"perangkat"` // Nama perangkat atau terminal yang menghitung
+}
+
+// PostingOpnameRequest approves the variances and adjusts stock
+type PostingOpnameRequest struct {
+	NolkanBelumDihitung bool `json:"nolkanBelumDihitung"` // Produk yang belum dihitung dianggap 0, jika tidak dilewati
+}
+
+// LaporanSelisihOpname summarises the variances of a count session valued at purchase cost
+type LaporanSelisihOpname struct {
+	Opname              *StokOpname       `json:"opname"`
+	JumlahItem          int               `json:"jumlahItem"`
+	JumlahDihitung      int               `json:"jumlahDihitung"`
+	JumlahBelumDihitung int               `json:"jumlahBelumDihitung"`
+	JumlahSelisih       int               `json:"jumlahSelisih"` // Produk yang hitungannya berbeda dari sistem
+	QtyLebih            float64           `json:"qtyLebih"`
+	QtyKurang           float64           `json:"qtyKurang"`
+	NilaiLebih          int               `json:"nilaiLebih"`
+	NilaiKurang         int               `json:"nilaiKurang"` // Positif
+	NilaiBersih         int               `json:"nilaiBersih"` // NilaiLebih - NilaiKurang
+	Selisih             []*StokOpnameItem `json:"selisih"`
+	BelumDihitung       []*StokOpnameItem `json:"belumDihitung"`
+}
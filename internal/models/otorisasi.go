@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Aksi kasir yang dapat diotorisasi supervisor
+const (
+	AksiDiskonManual    = "diskon_manual"
+	AksiUbahHarga       = "ubah_harga"
+	AksiVoid            = "void"
+	AksiRetur           = "retur"
+	AksiBukaLaci        = "buka_laci"
+	AksiPenyesuaianStok = "penyesuaian_stok"
+)
+
+// DaftarAksiOtorisasi lists every action an override token can be issued for
+var DaftarAksiOtorisasi = []string{
+	AksiDiskonManual,
+	AksiUbahHarga,
+	AksiVoid,
+	AksiRetur,
+	AksiBukaLaci,
+	AksiPenyesuaianStok,
+}
+
+// OtorisasiSettings mengatur kapan aksi kasir membutuhkan otorisasi supervisor.
+// Ubah harga dan void selalu membutuhkan otorisasi.
+type OtorisasiSettings struct {
+	BatasDiskonManual    int  `json:"batasDiskonManual"`    // Diskon manual di atas nilai ini butuh otorisasi (0 = setiap diskon manual)
+	BatasRefund          int  `json:"batasRefund"`          // Refund retur di atas nilai ini butuh otorisasi (0 = setiap retur)
+	WajibNoSale          bool `json:"wajibNoSale"`          // Buka laci tanpa penjualan butuh otorisasi
+	WajibPenyesuaianStok bool `json:"wajibPenyesuaianStok"` // Penyesuaian stok manual butuh otorisasi
+	MasaBerlakuDetik     int  `json:"masaBerlakuDetik"`     // Umur token otorisasi
+}
+
+// Otorisasi records one override token issued by a supervisor
+type Otorisasi struct {
+	ID                 int64      `json:"id,string"`
+	Aksi               string     `json:"aksi"`
+	Referensi          string     `json:"referensi"` // Nomor transaksi atau ID produk yang diotorisasi (kosong = bebas)
+	SupervisorID       int64      `json:"supervisorId,string"`
+	SupervisorUsername string     `json:"supervisorUsername"`
+	SupervisorNama     string     `json:"supervisorNama"`
+	DimintaOlehID      int64      `json:"dimintaOlehId,string"`
+	DimintaOlehNama    string     `json:"dimintaOlehNama"`
+	ExpiresAt          time.Time  `json:"expiresAt"`
+	DigunakanAt        *time.Time `json:"digunakanAt"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
+
+// MintaOtorisasiRequest is sent when a supervisor approves an action at the register
+type MintaOtorisasiRequest struct {
+	Username  string `json:"username"`
+	Pin       string `json:"pin"` // PIN atau password supervisor
+	Aksi      string `json:"aksi"`
+	Referensi string `json:"referensi"`
+	StaffID   int64  `json:"staffId,string"` // Kasir yang meminta otorisasi
+	StaffNama string `json:"staffNama"`
+}
+
+// OtorisasiResponse carries the single-use token back to the register
+type OtorisasiResponse struct {
+	Token          string    `json:"token"`
+	Aksi           string    `json:"aksi"`
+	SupervisorNama string    `json:"supervisorNama"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+// PemakaianOtorisasi is a checked override token that is spent inside the write it approves
+type PemakaianOtorisasi struct {
+	TokenHash string
+	Aksi      string
+	Referensi string
+}
@@ -15,6 +15,10 @@ type Pelanggan struct {
 	DiskonPersen   int       `json:"diskonPersen"` // Persentase diskon berdasarkan level
 	TotalTransaksi int       `json:"totalTransaksi"`
 	TotalBelanja   int       `json:"totalBelanja"`
+	Saldo          int       `json:"saldo"`       // Saldo deposit / store credit, diubah hanya lewat saldo_pelanggan_history
+	LimitKredit    int       `json:"limitKredit"` // Batas piutang tempo, 0 = tidak boleh tempo
+	TempoHari      int       `json:"tempoHari"`   // Lama tempo default dalam hari
+	Piutang        int       `json:"piutang"`     // Sisa piutang yang belum lunas
 	CreatedAt      time.Time `json:"createdAt"`
 	UpdatedAt      time.Time `json:"updatedAt"`
 }
@@ -70,3 +74,40 @@ type PelangganDetail struct {
 	Stats            *PelangganStats `json:"stats"`
 	TransaksiHistory []*Transaksi    `json:"transaksiHistory"`
 }
+
+// SaldoPelangganHistory is one movement in a customer's store credit ledger
+type SaldoPelangganHistory struct {
+	ID           int64     `json:"id,string"`
+	PelangganID  int64     `json:"pelangganId,string"`
+	Tipe         string    `json:"tipe"`   // "topup", "refund", "pembayaran", "void", "penyesuaian"
+	Jumlah       int       `json:"jumlah"` // Positif = saldo bertambah, negatif = saldo berkurang
+	SaldoSebelum int       `json:"saldoSebelum"`
+	SaldoSesudah int       `json:"saldoSesudah"`
+	Metode       string    `json:"metode,omitempty"`             // Metode pembayaran untuk top up
+	TransaksiID  int64     `json:"transaksiId,string,omitempty"` // Transaksi penyebab mutasi
+	ReturnID     int       `json:"returnId,omitempty"`           // Return penyebab mutasi
+	Referensi    string    `json:"referensi,omitempty"`          // Nomor transaksi/return atau referensi top up
+	Keterangan   string    `json:"keterangan"`
+	StaffID      int64     `json:"staffId,string,omitempty"`
+	StaffNama    string    `json:"staffNama,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// TopUpSaldoRequest represents a deposit paid in by a customer
+type TopUpSaldoRequest struct {
+	PelangganID int64  `json:"pelangganId,string"`
+	Jumlah      int    `json:"jumlah"`
+	Metode      string `json:"metode"` // Metode pembayaran yang dipakai untuk top up
+	Referensi   string `json:"referensi"`
+	StaffID     int64  `json:"staffId,string"`
+	StaffNama   string `json:"staffNama"`
+}
+
+// PenyesuaianSaldoRequest represents a manual correction of a customer's balance
+type PenyesuaianSaldoRequest struct {
+	PelangganID int64  `json:"pelangganId,string"`
+	Jumlah      int    `json:"jumlah"` // Positif menambah, negatif mengurangi
+	Alasan      string `json:"alasan"`
+	StaffID     int64  `json:"staffId,string"`
+	StaffNama   string `json:"staffNama"`
+}
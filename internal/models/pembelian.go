@@ -0,0 +1,158 @@
+package models
+
+import "time"
+
+// Status pesanan pembelian (purchase order)
+const (
+	StatusPODraft    = "draft"    // Masih dapat diubah
+	StatusPODipesan  = "dipesan"  // Sudah dikirim ke supplier, menunggu barang
+	StatusPOSebagian = "sebagian" // Sebagian barang sudah diterima
+	StatusPOSelesai  = "selesai"  // Semua barang diterima atau sisa pesanan ditutup
+	StatusPOBatal    = "batal"
+)
+
+// Metode pembaruan harga beli produk saat penerimaan barang
+const (
+	MetodeHargaBeliTetap    = "tetap"     // Harga beli produk tidak diubah
+	MetodeHargaBeliTerakhir = "terakhir"  // Harga beli produk = harga penerimaan terakhir
+	MetodeHargaBeliRataRata = "rata_rata" // Rata-rata tertimbang stok lama dan barang yang diterima
+)
+
+// Supplier is a vendor that products are purchased from
+type Supplier struct {
+	ID        int64     `json:"id,string"`
+	Kode      string    `json:"kode"`
+	Nama      string    `json:"nama"`
+	Kontak    string    `json:"kontak"` // Nama sales / contact person
+	Telepon   string    `json:"telepon"`
+	Email     string    `json:"email"`
+	Alamat    string    `json:"alamat"`
+	Catatan   string    `json:"catatan"`
+	Aktif     bool      `json:"aktif"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PesananPembelian is a purchase order sent to a supplier
+type PesananPembelian struct {
+	ID                int64                   `json:"id,string"`
+	NomorPO           string                  `json:"nomorPo"`
+	SupplierID        int64                   `json:"supplierId,string"`
+	SupplierNama      string                  `json:"supplierNama"`
+	Tanggal           time.Time               `json:"tanggal"`
+	TanggalDiharapkan string                  `json:"tanggalDiharapkan"` // YYYY-MM-DD, kosong jika tidak ditentukan
+	Status            string                  `json:"status"`
+	Total             int                     `json:"total"` // Total qty pesan x harga yang diharapkan
+	Catatan           string                  `json:"catatan"`
+	DibuatOleh        string                  `json:"dibuatOleh"`
+	Items             []*PesananPembelianItem `json:"items,omitempty"`
+	Penerimaan        []*PenerimaanBarang     `json:"penerimaan,omitempty"`
+	CreatedAt         time.Time               `json:"createdAt"`
+	UpdatedAt         time.Time               `json:"updatedAt"`
+}
+
+// PesananPembelianItem is one ordered product with its expected price
+type PesananPembelianItem struct {
+	ID          int64   `json:"id,string"`
+	PesananID   int64   `json:"pesananId,string"`
+	ProdukID    int     `json:"produkId"`
+	ProdukNama  string  `json:"produkNama"`
+	QtyPesan    float64 `json:"qtyPesan"`
+	QtyDiterima float64 `json:"qtyDiterima"`
+	HargaBeli   int     `json:"hargaBeli"` // Harga beli yang diharapkan per satuan
+	Subtotal    int     `json:"subtotal"`
+}
+
+// Sisa returns the quantity still to be delivered
+func (i *PesananPembelianItem) Sisa() float64 {
+	if i.QtyDiterima >= i.QtyPesan {
+		return 0
+	}
+	return i.QtyPesan - i.QtyDiterima
+}
+
+// PesananPembelianItemRequest is one line of a new or edited purchase order
+type PesananPembelianItemRequest struct {
+	ProdukID  int     `json:"produkId"`
+	Qty       float64 `json:"qty"`
+	HargaBeli int     `json:"hargaBeli"` // 0 = harga beli produk saat ini
+}
+
+// PesananPembelianRequest creates or edits a draft purchase order
+type PesananPembelianRequest struct {
+	SupplierID        int64                         `json:"supplierId,string"`
+	TanggalDiharapkan string                        `json:"tanggalDiharapkan"`
+	Catatan           string                        `json:"catatan"`
+	Items             []PesananPembelianItemRequest `json:"items"`
+	DibuatOleh        string                        `json:"-"` // Diisi dari user yang login
+}
+
+// PenerimaanBarang is a goods receipt: one delivery, received against a purchase order
+// or directly from the scan cart
+type PenerimaanBarang struct {
+	ID              int64                   `json:"id,string"`
+	NomorPenerimaan string                  `json:"nomorPenerimaan"`
+	PesananID       int64                   `json:"pesananId,string"`
+	NomorPO         string                  `json:"nomorPo"`
+	SupplierID      int64                   `json:"supplierId,string"`
+	SupplierNama    string                  `json:"supplierNama"`
+	Tanggal         time.Time               `json:"tanggal"`
+	NoFaktur        string                  `json:"noFaktur"` // Nomor faktur / surat jalan supplier
+	Total           int                     `json:"total"`
+	Catatan         string                  `json:"catatan"`
+	DiterimaOleh    string                  `json:"diterimaOleh"`
+	Items           []*PenerimaanBarangItem `json:"items,omitempty"`
+	PerubahanHarga  []*PerubahanHargaBeli   `json:"perubahanHarga,omitempty"` // Harga beli produk yang diperbarui oleh penerimaan ini
+	CreatedAt       time.Time               `json:"createdAt"`
+}
+
+// PerubahanHargaBeli records a product cost updated by a goods receipt
+type PerubahanHargaBeli struct {
+	ProdukID   int    `json:"produkId"`
+	ProdukNama string `json:"produkNama"`
+	Sebelum    int    `json:"sebelum"`
+	Sesudah    int    `json:"sesudah"`
+}
+
+// PenerimaanBarangItem is one received product with its actual cost and the batch it created
+type PenerimaanBarangItem struct {
+	ID                int64   `json:"id,string"`
+	PenerimaanID      int64   `json:"penerimaanId,string"`
+	PesananItemID     int64   `json:"pesananItemId,string"`
+	ProdukID          int     `json:"produkId"`
+	ProdukNama        string  `json:"produkNama"`
+	Qty               float64 `json:"qty"`
+	HargaBeli         int     `json:"hargaBeli"` // Harga beli sebenarnya per satuan
+	Subtotal          int     `json:"subtotal"`
+	MasaSimpanHari    int     `json:"masaSimpanHari"`    // 0 = tanpa batch kedaluwarsa
+	TanggalKadaluarsa string  `json:"tanggalKadaluarsa"` // YYYY-MM-DD, kosong jika produk tidak dilacak kedaluwarsanya
+	BatchID           string  `json:"batchId"`
+}
+
+// PenerimaanItemRequest is one received line. Expiry is taken from TanggalKadaluarsa,
+// else MasaSimpanHari, else the product's shelf life.
+type PenerimaanItemRequest struct {
+	ProdukID          int     `json:"produkId"`
+	Qty               float64 `json:"qty"`
+	HargaBeli         int     `json:"hargaBeli"`         // 0 = harga di pesanan pembelian
+	TanggalKadaluarsa string  `json:"tanggalKadaluarsa"` // YYYY-MM-DD
+	MasaSimpanHari    int     `json:"masaSimpanHari"`
+}
+
+// PenerimaanRequest receives a (partial) delivery against a purchase order
+type PenerimaanRequest struct {
+	PesananID int64                   `json:"pesananId,string"`
+	NoFaktur  string                  `json:"noFaktur"`
+	Catatan   string                  `json:"catatan"`
+	Items     []PenerimaanItemRequest `json:"items"`
+}
+
+// ProsesKeranjangRequest processes the scan cart into a goods receipt. With PesananID the scanned
+// items are received against that purchase order; Items may override cost and expiry per product.
+type ProsesKeranjangRequest struct {
+	PesananID  int64                   `json:"pesananId,string"`
+	SupplierID int64                   `json:"supplierId,string"` // Opsional jika tanpa pesanan pembelian
+	NoFaktur   string                  `json:"noFaktur"`
+	Catatan    string                  `json:"catatan"`
+	Items      []PenerimaanItemRequest `json:"items"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// MetodeTempo is the code of the system payment method that leaves the amount as customer debt
+const MetodeTempo = "tempo"
+
+// Piutang represents the outstanding balance of a credit ("tempo") sale
+type Piutang struct {
+	ID             int64     `json:"id,string"`
+	TransaksiID    int64     `json:"transaksiId,string"`
+	NomorTransaksi string    `json:"nomorTransaksi"`
+	PelangganID    int64     `json:"pelangganId,string"`
+	PelangganNama  string    `json:"pelangganNama"`
+	Jumlah         int       `json:"jumlah"`   // Nilai yang dibayar tempo saat transaksi
+	Terbayar       int       `json:"terbayar"` // Total pelunasan sejauh ini
+	Sisa           int       `json:"sisa"`
+	JatuhTempo     time.Time `json:"jatuhTempo"`
+	Status         string    `json:"status"` // "belum_lunas", "lunas", "void"
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// PembayaranPiutang represents a repayment of a credit sale; it is a cash movement of its own
+type PembayaranPiutang struct {
+	ID             int64     `json:"id,string"`
+	PiutangID      int64     `json:"piutangId,string"`
+	PelangganID    int64     `json:"pelangganId,string"`
+	NomorTransaksi string    `json:"nomorTransaksi"`
+	Jumlah         int       `json:"jumlah"`
+	Metode         string    `json:"metode"`
+	Referensi      string    `json:"referensi"`
+	StaffID        int64     `json:"staffId,string"`
+	StaffNama      string    `json:"staffNama"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// BayarPiutangRequest represents a repayment from a customer.
+// Without PiutangID the amount is applied to the oldest outstanding sales first.
+type BayarPiutangRequest struct {
+	PelangganID int64  `json:"pelangganId,string"`
+	PiutangID   int64  `json:"piutangId,string,omitempty"`
+	Jumlah      int    `json:"jumlah"`
+	Metode      string `json:"metode"`
+	Referensi   string `json:"referensi"`
+	StaffID     int64  `json:"staffId,string"`
+	StaffNama   string `json:"staffNama"`
+}
+
+// UpdateKreditPelangganRequest sets the credit terms of a customer
+type UpdateKreditPelangganRequest struct {
+	PelangganID int64 `json:"pelangganId,string"`
+	LimitKredit int   `json:"limitKredit"` // 0 = tidak boleh belanja tempo
+	TempoHari   int   `json:"tempoHari"`   // Lama tempo sejak tanggal transaksi
+}
+
+// UmurPiutang holds outstanding amounts grouped by age since the sale date
+type UmurPiutang struct {
+	Hari0Sampai30  int `json:"hari0Sampai30"`
+	Hari31Sampai60 int `json:"hari31Sampai60"`
+	Hari61Sampai90 int `json:"hari61Sampai90"`
+	HariLebih90    int `json:"hariLebih90"`
+	Total          int `json:"total"`
+	LewatTempo     int `json:"lewatTempo"` // Bagian dari total yang sudah melewati jatuh tempo
+}
+
+// UmurPiutangPelanggan is one customer row of the aging report
+type UmurPiutangPelanggan struct {
+	PelangganID   int64  `json:"pelangganId,string"`
+	PelangganNama string `json:"pelangganNama"`
+	Telepon       string `json:"telepon"`
+	LimitKredit   int    `json:"limitKredit"`
+	UmurPiutang
+}
+
+// LaporanUmurPiutang is the accounts receivable aging report
+type LaporanUmurPiutang struct {
+	Tanggal   time.Time               `json:"tanggal"`
+	Pelanggan []*UmurPiutangPelanggan `json:"pelanggan"`
+	Total     UmurPiutang             `json:"total"`
+}
@@ -21,6 +21,7 @@ type Produk struct {
 	Deskripsi                   string    `json:"deskripsi"`
 	Gambar                      string    `json:"gambar"`
 	HariPemberitahuanKadaluarsa int       `json:"hariPemberitahuanKadaluarsa"` // Existing field
+	TarifPajak                  *float64  `json:"tarifPajak"`                  // Tarif PPN dalam persen (nil = ikut kategori)
 	CreatedAt                   time.Time `json:"createdAt"`
 	UpdatedAt                   time.Time `json:"updatedAt"`
 }
@@ -37,6 +38,8 @@ type Batch struct {
 	Status            string    `json:"status"`            // fresh, hampir_expired, expired
 	Supplier          string    `json:"supplier"`          // Supplier name
 	Keterangan        string    `json:"keterangan"`        // Notes
+	HargaBeli         int       `json:"hargaBeli"`         // Purchase cost per unit when received (0 = unknown)
+	PenerimaanID      int64     `json:"penerimaanId,string"` // Goods receipt that created this batch
 	CreatedAt         time.Time `json:"createdAt"`
 	UpdatedAt         time.Time `json:"updatedAt"`
 }
@@ -84,6 +87,7 @@ type Kategori struct {
 	Nama         string    `json:"nama"`
 	Deskripsi    string    `json:"deskripsi"`
 	Icon         string    `json:"icon"`
+	TarifPajak   *float64  `json:"tarifPajak"` // Tarif PPN dalam persen (nil = ikut tarif toko)
 	JumlahProduk int       `json:"jumlahProduk"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
@@ -101,5 +105,6 @@ type UpdateStokRequest struct {
 	NilaiKerugian  int     `json:"nilaiKerugian"`  // Loss value in rupiah (calculated from qty * harga_beli)
 	MasaSimpanHari int     `json:"masaSimpanHari"` // For batch creation during restock
 	Supplier       string  `json:"supplier"`       // Supplier for this batch
+	OverrideToken  string  `json:"overrideToken"`  // Token otorisasi supervisor untuk penyesuaian stok
 }
  
\ No newline at end of file
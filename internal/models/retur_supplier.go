@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Status retur ke supplier
+const (
+	StatusReturSupplierMenunggu = "menunggu" // Barang sudah dikirim, menunggu nota kredit atau penggantian
+	StatusReturSupplierSelesai  = "selesai"
+	StatusReturSupplierBatal    = "batal"
+)
+
+// Kompensasi yang diberikan supplier atas barang yang diretur
+const (
+	KompensasiNotaKredit  = "nota_kredit"  // Potongan tagihan senilai barang
+	KompensasiGantiBarang = "ganti_barang" // Barang diganti baru, stok kembali bertambah
+	KompensasiDitolak     = "ditolak"      // Supplier tidak memberi kompensasi
+)
+
+// ReturSupplier is a document returning expired or damaged goods to a supplier. Stock is
+// taken out when it is created; the credit note or replacement settles it later.
+type ReturSupplier struct {
+	ID               int64                `json:"id,string"`
+	NomorRetur       string               `json:"nomorRetur"`
+	SupplierID       int64                `json:"supplierId,string"`
+	SupplierNama     string               `json:"supplierNama"`
+	Tanggal          time.Time            `json:"tanggal"`
+	Alasan           string               `json:"alasan"`          // "kadaluarsa" atau "rusak", sama dengan tipe kerugian
+	JenisKompensasi  string               `json:"jenisKompensasi"` // Yang diharapkan, lalu yang diterima saat selesai
+	Status           string               `json:"status"`
+	TotalNilai       int                  `json:"totalNilai"`      // Nilai barang pada harga beli
+	NilaiKompensasi  int                  `json:"nilaiKompensasi"` // Nilai nota kredit atau barang pengganti
+	NoNotaKredit     string               `json:"noNotaKredit"`
+	Catatan          string               `json:"catatan"`
+	DibuatOleh       string               `json:"dibuatOleh"`
+	DiselesaikanOleh string               `json:"diselesaikanOleh"`
+	TanggalSelesai   *time.Time           `json:"tanggalSelesai,omitempty"`
+	Items            []*ReturSupplierItem `json:"items,omitempty"`
+	CreatedAt        time.Time            `json:"createdAt"`
+	UpdatedAt        time.Time            `json:"updatedAt"`
+}
+
+// ReturSupplierItem is one returned line, taken from a specific batch when the product has one
+type ReturSupplierItem struct {
+	ID                int64   `json:"id,string"`
+	ReturID           int64   `json:"returId,string"`
+	BatchID           string  `json:"batchId"`
+	ProdukID          int     `json:"produkId"`
+	ProdukNama        string  `json:"produkNama"`
+	Qty               float64 `json:"qty"`
+	HargaBeli         int     `json:"hargaBeli"` // Harga beli batch, atau harga beli produk jika tanpa batch
+	Subtotal          int     `json:"subtotal"`
+	TanggalKadaluarsa string  `json:"tanggalKadaluarsa"`
+}
+
+// ReturSupplierItemRequest picks a batch (or a product without batches) and the quantity to return
+type ReturSupplierItemRequest struct {
+	BatchID  string  `json:"batchId"`
+	ProdukID int     `json:"produkId"` // Untuk produk tanpa batch
+	Qty      float64 `json:"qty"`
+}
+
+// ReturSupplierRequest creates a supplier return
+type ReturSupplierRequest struct {
+	SupplierID      int64                      `json:"supplierId,string"`
+	Alasan          string                     `json:"alasan"`
+	JenisKompensasi string                     `json:"jenisKompensasi"`
+	Catatan         string                     `json:"catatan"`
+	Items           []ReturSupplierItemRequest `json:"items"`
+}
+
+// PenyelesaianReturSupplierRequest settles a supplier return with what the supplier gave back
+type PenyelesaianReturSupplierRequest struct {
+	JenisKompensasi string `json:"jenisKompensasi"`
+	NoNotaKredit    string `json:"noNotaKredit"`
+	NilaiKredit     int    `json:"nilaiKredit"` // Untuk nota kredit; 0 = senilai barang
+	Catatan         string `json:"catatan"`
+}
+
+// KerugianReturSupplier is the loss of supplier returns of one reason after compensation
+type KerugianReturSupplier struct {
+	Alasan string `json:"alasan"`
+	Nilai  int    `json:"nilai"`
+	Jumlah int    `json:"jumlah"`
+}
@@ -4,19 +4,23 @@ import "time"
 
 // Return represents a product return/exchange transaction
 type Return struct {
-	ID                   int       `json:"id"`
-	TransaksiID          int       `json:"transaksi_id"`
-	NoTransaksi          string    `json:"no_transaksi"`
-	ReturnDate           time.Time `json:"return_date"`
-	Reason               string    `json:"reason"` // "damaged", "wrong_item", "expired", "other"
-	Type                 string    `json:"type"`   // "refund" or "exchange"
-	ReplacementProductID int       `json:"replacement_product_id,omitempty"`
-	RefundAmount         int       `json:"refund_amount"`
-	RefundMethod         string    `json:"refund_method,omitempty"` // "tunai", "transfer"
-	RefundStatus         string    `json:"refund_status"`           // "pending", "completed", "cancelled"
-	Notes                string    `json:"notes,omitempty"`
-	CreatedAt            time.Time `json:"createdAt"`
-	UpdatedAt            time.Time `json:"updatedAt"`
+	ID                   int                 `json:"id"`
+	TransaksiID          int                 `json:"transaksi_id"`
+	NoTransaksi          string              `json:"no_transaksi"`
+	ReturnDate           time.Time           `json:"return_date"`
+	Reason               string              `json:"reason"` // "damaged", "wrong_item", "expired", "other"
+	Type                 string              `json:"type"`   // "refund" or "exchange"
+	ReplacementProductID int                 `json:"replacement_product_id,omitempty"`
+	RefundAmount         int                 `json:"refund_amount"`
+	TotalPajak           int                 `json:"total_pajak"`             // PPN yang dibalik oleh return ini
+	RefundMethod         string              `json:"refund_method,omitempty"` // "tunai", "transfer", "saldo"
+	RefundStatus         string              `json:"refund_status"`           // "pending", "completed", "cancelled"
+	Notes                string              `json:"notes,omitempty"`
+	DisetujuiOleh        string              `json:"disetujui_oleh,omitempty"`  // Supervisor yang mengotorisasi refund di atas batas
+	StaffID              int64               `json:"staff_id,string,omitempty"` // Kasir yang memproses return dan membayar refund
+	Otorisasi            *PemakaianOtorisasi `json:"-"`                         // Token otorisasi refund yang dipakai saat return disimpan
+	CreatedAt            time.Time           `json:"createdAt"`
+	UpdatedAt            time.Time           `json:"updatedAt"`
 }
 
 // ReturnItem represents a product item in a return
@@ -51,8 +55,11 @@ type CreateReturnRequest struct {
 	Type                 string                 `json:"type"`
 	ReplacementProductID int                    `json:"replacement_product_id,omitempty"`
 	ReturnDate           string                 `json:"return_date"`
-	RefundMethod         string                 `json:"refund_method,omitempty"` // "tunai", "transfer"
+	RefundMethod         string                 `json:"refund_method,omitempty"` // "tunai", "transfer", "saldo"
 	Notes                string                 `json:"notes,omitempty"`
+	OverrideToken        string                 `json:"override_token,omitempty"` // Token otorisasi supervisor untuk refund di atas batas
+	StaffID              int64                  `json:"-"`                        // Diisi dari user yang login
+	StaffNama            string                 `json:"-"`
 }
 
 // ReturnProductRequest represents product in create return request
@@ -0,0 +1,140 @@
+package models
+
+import "time"
+
+// Named permissions checked by the HTTP routes and the desktop bindings
+const (
+	PermProductEdit         = "product.edit"
+	PermPriceChange         = "price.change"
+	PermStockAdjust         = "stock.adjust"
+	PermStockReceive        = "stock.receive"
+	PermPurchaseManage      = "purchase.manage"
+	PermStockCount          = "stock.count"
+	PermStockOpname         = "stock.opname"
+	PermCategoryManage      = "category.manage"
+	PermTransactionCreate   = "transaction.create"
+	PermTransactionView     = "transaction.view"
+	PermTransactionVoid     = "transaction.void"
+	PermCustomerManage      = "customer.manage"
+	PermCustomerBalance     = "customer.balance.adjust"
+	PermCustomerCredit      = "customer.credit.manage"
+	PermReceivableCollect   = "receivable.collect"
+	PermCashSession         = "cash.session"
+	PermCashDrawerOpen      = "cash.drawer.open"
+	PermCashDrawerLog       = "cash.drawer.log.view"
+	PermAttendanceClock     = "attendance.clock"
+	PermPromoManage         = "promo.manage"
+	PermBatchManage         = "batch.manage"
+	PermReturnApprove       = "return.approve"
+	PermOverrideApprove     = "override.approve"
+	PermReportSalesView     = "report.sales.view"
+	PermReportProfitView    = "report.profit.view"
+	PermReportStaffView     = "report.staff.view"
+	PermCommissionManage    = "commission.manage"
+	PermTargetManage        = "target.manage"
+	PermShiftManage         = "shift.manage"
+	PermSettingsManage      = "settings.manage"
+	PermPaymentMethodManage = "payment_method.manage"
+	PermHardwareManage      = "hardware.manage"
+	PermSyncManage          = "sync.manage"
+	PermUserManage          = "user.manage"
+	PermRoleManage          = "role.manage"
+	PermAuditView           = "audit.view"
+)
+
+// Permission describes one named permission
+type Permission struct {
+	Kode string `json:"kode"`
+	Nama string `json:"nama"`
+	Grup string `json:"grup"`
+}
+
+// DaftarPermission lists every permission a role can be granted
+var DaftarPermission = []Permission{
+	{Kode: PermProductEdit, Nama: "Tambah, ubah dan hapus produk", Grup: "Produk"},
+	{Kode: PermPriceChange, Nama: "Ubah harga jual dan harga beli", Grup: "Produk"},
+	{Kode: PermStockAdjust, Nama: "Penyesuaian stok", Grup: "Produk"},
+	{Kode: PermStockReceive, Nama: "Terima barang (keranjang restok)", Grup: "Produk"},
+	{Kode: PermPurchaseManage, Nama: "Kelola supplier dan pesanan pembelian", Grup: "Produk"},
+	{Kode: PermStockCount, Nama: "Hitung stok opname", Grup: "Produk"},
+	{Kode: PermStockOpname, Nama: "Buka, batalkan dan posting stok opname", Grup: "Produk"},
+	{Kode: PermCategoryManage, Nama: "Kelola kategori", Grup: "Produk"},
+	{Kode: PermBatchManage, Nama: "Kelola batch kedaluwarsa", Grup: "Produk"},
+	{Kode: PermTransactionCreate, Nama: "Buat transaksi penjualan", Grup: "Transaksi"},
+	{Kode: PermTransactionView, Nama: "Lihat riwayat transaksi", Grup: "Transaksi"},
+	{Kode: PermTransactionVoid, Nama: "Void transaksi", Grup: "Transaksi"},
+	{Kode: PermReturnApprove, Nama: "Proses retur dan refund", Grup: "Transaksi"},
+	{Kode: PermPromoManage, Nama: "Kelola promo", Grup: "Transaksi"},
+	{Kode: PermOverrideApprove, Nama: "Memberi otorisasi supervisor (diskon, ubah harga, void, retur, no sale)", Grup: "Transaksi"},
+	{Kode: PermCustomerManage, Nama: "Kelola pelanggan dan poin", Grup: "Pelanggan"},
+	{Kode: PermCustomerBalance, Nama: "Penyesuaian saldo pelanggan", Grup: "Pelanggan"},
+	{Kode: PermCustomerCredit, Nama: "Atur limit kredit pelanggan", Grup: "Pelanggan"},
+	{Kode: PermReceivableCollect, Nama: "Terima pembayaran piutang", Grup: "Pelanggan"},
+	{Kode: PermCashSession, Nama: "Buka, catat dan tutup sesi kas", Grup: "Kas"},
+	{Kode: PermCashDrawerOpen, Nama: "Buka laci tanpa penjualan", Grup: "Kas"},
+	{Kode: PermCashDrawerLog, Nama: "Lihat log laci kas", Grup: "Kas"},
+	{Kode: PermAttendanceClock, Nama: "Absen masuk dan pulang", Grup: "Staff"},
+	{Kode: PermShiftManage, Nama: "Atur jadwal shift", Grup: "Staff"},
+	{Kode: PermCommissionManage, Nama: "Kelola komisi staff", Grup: "Staff"},
+	{Kode: PermTargetManage, Nama: "Kelola target penjualan", Grup: "Staff"},
+	{Kode: PermReportSalesView, Nama: "Lihat dashboard dan analitik penjualan", Grup: "Laporan"},
+	{Kode: PermReportProfitView, Nama: "Lihat laporan laba dan pajak", Grup: "Laporan"},
+	{Kode: PermReportStaffView, Nama: "Lihat laporan staff dan shift", Grup: "Laporan"},
+	{Kode: PermSettingsManage, Nama: "Ubah pengaturan toko", Grup: "Pengaturan"},
+	{Kode: PermPaymentMethodManage, Nama: "Kelola metode pembayaran", Grup: "Pengaturan"},
+	{Kode: PermHardwareManage, Nama: "Atur printer dan perangkat keras", Grup: "Pengaturan"},
+	{Kode: PermSyncManage, Nama: "Kelola sinkronisasi", Grup: "Pengaturan"},
+	{Kode: PermUserManage, Nama: "Kelola user", Grup: "Pengaturan"},
+	{Kode: PermRoleManage, Nama: "Kelola role dan hak akses", Grup: "Pengaturan"},
+	{Kode: PermAuditView, Nama: "Lihat audit log perubahan data", Grup: "Pengaturan"},
+}
+
+// PermissionStaffDefault is granted to the built-in staff role on first start.
+// Void still needs a supervisor override on top of the permission.
+var PermissionStaffDefault = []string{
+	PermTransactionCreate,
+	PermTransactionView,
+	PermTransactionVoid,
+	PermCustomerManage,
+	PermReceivableCollect,
+	PermCashSession,
+	PermCashDrawerOpen,
+	PermAttendanceClock,
+	PermReturnApprove,
+	PermStockAdjust,
+	PermStockReceive,
+	PermStockCount,
+	PermBatchManage,
+	PermReportSalesView,
+	PermReportStaffView,
+}
+
+// Role groups permissions under a name that is stored in User.Role.
+// The built-in admin role always has every permission and cannot be changed.
+type Role struct {
+	ID          int64     `json:"id,string"`
+	Nama        string    `json:"nama"`
+	Deskripsi   string    `json:"deskripsi"`
+	Permissions []string  `json:"permissions"`
+	Sistem      bool      `json:"sistem"` // Role bawaan (admin, staff), tidak dapat dihapus
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// HasPermission reports whether a role name with the given permissions grants perm
+func HasPermission(role string, permissions []string, perm string) bool {
+	if role == "admin" {
+		return true
+	}
+	for _, p := range permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the user's role grants perm
+func (u *User) HasPermission(perm string) bool {
+	return HasPermission(u.Role, u.Permissions, perm)
+}
@@ -91,6 +91,19 @@ type LossAnalysisData struct {
 	Colors        []string             `json:"colors"`        // For chart colors
 }
 
+// VoidSummary represents voided transactions, reported separately from sales
+type VoidSummary struct {
+	TotalVoid  int `json:"totalVoid"`  // Total nilai transaksi yang di-void
+	JumlahVoid int `json:"jumlahVoid"` // Jumlah transaksi yang di-void
+}
+
+// PembulatanSummary represents cash rounding and rounding donations, kept out of omset
+type PembulatanSummary struct {
+	TotalPembulatan int `json:"totalPembulatan"` // Total selisih pembulatan (bisa negatif)
+	TotalDonasi     int `json:"totalDonasi"`     // Total donasi dari pembulatan
+	JumlahTransaksi int `json:"jumlahTransaksi"` // Jumlah transaksi yang dibulatkan atau berdonasi
+}
+
 // ComprehensiveSalesReport represents complete sales report
 type ComprehensiveSalesReport struct {
 	Summary                *SalesSummaryResponse                    `json:"summary"`
@@ -104,6 +117,8 @@ type ComprehensiveSalesReport struct {
 	DiscountTypeBreakdown  []*DiscountTypeBreakdown                 `json:"discountTypeBreakdown"`
 	PaymentMethodBreakdown []*PaymentMethodBreakdown                `json:"paymentMethodBreakdown"`
 	LossAnalysis           *LossAnalysisData                        `json:"lossAnalysis"`
+	VoidSummary            *VoidSummary                             `json:"voidSummary"`
+	PembulatanSummary      *PembulatanSummary                       `json:"pembulatanSummary"`
 	StartDate              time.Time                                `json:"startDate"`
 	EndDate                time.Time                                `json:"endDate"`
 	GeneratedAt            time.Time                                `json:"generatedAt"`
@@ -129,3 +144,25 @@ type SalesReportFilter struct {
 	Year      int    `json:"year"`      // Optional: for yearly reports
 	Month     int    `json:"month"`     // Optional: for monthly reports (1-12)
 }
+
+// LaporanPajak represents output tax (PPN) for a period, used for monthly filing
+type LaporanPajak struct {
+	NamaPajak       string               `json:"namaPajak"`
+	StartDate       time.Time            `json:"startDate"`
+	EndDate         time.Time            `json:"endDate"`
+	PerTarif        []*LaporanPajakTarif `json:"perTarif"`
+	TotalDPP        int                  `json:"totalDpp"`
+	TotalPajak      int                  `json:"totalPajak"`
+	JumlahTransaksi int                  `json:"jumlahTransaksi"`
+	PajakRetur      int                  `json:"pajakRetur"`  // PPN yang dibalik oleh return
+	PajakBersih     int                  `json:"pajakBersih"` // TotalPajak - PajakRetur
+	GeneratedAt     time.Time            `json:"generatedAt"`
+}
+
+// LaporanPajakTarif represents tax totals for a single tax rate
+type LaporanPajakTarif struct {
+	TarifPajak float64 `json:"tarifPajak"`
+	DPP        int     `json:"dpp"`
+	Pajak      int     `json:"pajak"`
+	JumlahItem int     `json:"jumlahItem"`
+}
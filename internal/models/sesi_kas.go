@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// SesiKas represents one cash drawer session, from opening float to closing count.
+// Once closed, the totals are frozen and form the Z-report.
+type SesiKas struct {
+	ID             int64        `json:"id,string"`
+	TerminalID     string       `json:"terminalId"`
+	StaffID        int64        `json:"staffId,string"`
+	StaffNama      string       `json:"staffNama"`
+	ShiftID        int          `json:"shiftId"` // Shift (shift_settings) yang dilayani sesi ini; 0 = tidak terikat shift
+	Status         string       `json:"status"`  // "buka", "tutup"
+	ModalAwal      int          `json:"modalAwal"`
+	PenjualanTunai int          `json:"penjualanTunai"` // Pembayaran tunai dikurangi kembalian
+	PelunasanTunai int          `json:"pelunasanTunai"` // Pelunasan piutang yang diterima tunai
+	TopUpTunai     int          `json:"topUpTunai"`     // Top up saldo pelanggan yang diterima tunai
+	RefundTunai    int          `json:"refundTunai"`    // Refund retur yang dibayarkan tunai
+	KasMasuk       int          `json:"kasMasuk"`
+	KasKeluar      int          `json:"kasKeluar"`
+	KasDiharapkan  int          `json:"kasDiharapkan"`
+	KasDihitung    int          `json:"kasDihitung"`
+	Selisih        int          `json:"selisih"` // Dihitung - diharapkan; negatif = kurang
+	Pecahan        []PecahanKas `json:"pecahan"`
+	CatatanTutup   string       `json:"catatanTutup"`
+	DitutupOleh    string       `json:"ditutupOleh"`
+	DibukaAt       time.Time    `json:"dibukaAt"`
+	DitutupAt      *time.Time   `json:"ditutupAt"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+// PecahanKas is one denomination line of the closing count
+type PecahanKas struct {
+	Nilai  int `json:"nilai"`  // Nilai pecahan, misalnya 50000
+	Jumlah int `json:"jumlah"` // Jumlah lembar/keping
+}
+
+// MutasiKas represents a paid-in or paid-out entry of a drawer session
+type MutasiKas struct {
+	ID        int64     `json:"id,string"`
+	SesiID    int64     `json:"sesiId,string"`
+	Tipe      string    `json:"tipe"` // "masuk", "keluar"
+	Jumlah    int       `json:"jumlah"`
+	Alasan    string    `json:"alasan"`
+	StaffID   int64     `json:"staffId,string"`
+	StaffNama string    `json:"staffNama"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BukaSesiKasRequest represents request to open a drawer session
+type BukaSesiKasRequest struct {
+	TerminalID string `json:"terminalId"`
+	ShiftID    int    `json:"shiftId"` // 0 = ditentukan dari jam buka
+	ModalAwal  int    `json:"modalAwal"`
+	StaffID    int64  `json:"staffId,string"`
+	StaffNama  string `json:"staffNama"`
+}
+
+// MutasiKasRequest represents request to record a paid-in or paid-out entry
+type MutasiKasRequest struct {
+	SesiID    int64  `json:"sesiId,string"`
+	Tipe      string `json:"tipe"`
+	Jumlah    int    `json:"jumlah"`
+	Alasan    string `json:"alasan"`
+	StaffID   int64  `json:"staffId,string"`
+	StaffNama string `json:"staffNama"`
+}
+
+// TutupSesiKasRequest represents request to close a drawer session with a denomination count
+type TutupSesiKasRequest struct {
+	SesiID    int64        `json:"sesiId,string"`
+	Pecahan   []PecahanKas `json:"pecahan"`
+	Catatan   string       `json:"catatan"`
+	StaffID   int64        `json:"staffId,string"`
+	StaffNama string       `json:"staffNama"`
+}
+
+// LaporanSesiKas is an X-report (open session, running totals) or Z-report (closed session, final)
+type LaporanSesiKas struct {
+	Jenis    string       `json:"jenis"` // "X", "Z"
+	Sesi     *SesiKas     `json:"sesi"`
+	Mutasi   []*MutasiKas `json:"mutasi"`
+	DibuatAt time.Time    `json:"dibuatAt"`
+}
+
+// PrintLaporanSesiKasRequest represents request to print an X/Z report
+type PrintLaporanSesiKasRequest struct {
+	SesiID      int64  `json:"sesiId,string"`
+	Jenis       string `json:"jenis"`
+	PrinterName string `json:"printerName"`
+}
@@ -20,4 +20,56 @@ type UpdatePoinSettingsRequest struct {
 	Level3MinPoints         int `json:"level3MinPoints"`
 	Level2MinSpending       int `json:"level2MinSpending"` // Legacy
 	Level3MinSpending       int `json:"level3MinSpending"` // Legacy
-}
\ No newline at end of file
+}
+// PenomoranSettings mengatur format nomor transaksi per terminal.
+// Contoh hasil: STORE1-T02-20261016-000123
+type PenomoranSettings struct {
+	KodeToko      string `json:"kodeToko"`
+	KodeTerminal  string `json:"kodeTerminal"`  // Harus unik per perangkat agar nomor offline tidak bentrok
+	Pemisah       string `json:"pemisah"`       // Pemisah antar bagian, default "-"
+	FormatTanggal string `json:"formatTanggal"` // "20060102", "060102", atau "" (tanpa tanggal)
+	PanjangUrutan int    `json:"panjangUrutan"` // Jumlah digit nomor urut (zero-padded)
+	ResetHarian   bool   `json:"resetHarian"`   // Nomor urut mulai dari 1 setiap hari
+}
+
+// PembulatanSettings mengatur pembulatan total untuk bagian yang dibayar tunai.
+type PembulatanSettings struct {
+	Aktif       bool   `json:"aktif"`
+	Kelipatan   int    `json:"kelipatan"`   // Kelipatan pembulatan, misalnya 100 atau 500
+	Mode        string `json:"mode"`        // "nearest", "down", atau "up"
+	DonasiAktif bool   `json:"donasiAktif"` // Pelanggan boleh mendonasikan selisih pembulatan ke atas
+}
+
+// PembulatanPreview adalah total yang ditagih di kasir setelah pembulatan tunai.
+type PembulatanPreview struct {
+	Total        int `json:"total"`        // Total sebelum pembulatan
+	NonTunai     int `json:"nonTunai"`     // Bagian yang dibayar non-tunai, tidak dibulatkan
+	Pembulatan   int  `json:"pembulatan"`   // Selisih pembulatan (negatif = dibulatkan ke bawah)
+	Donasi       int  `json:"donasi"`       // Selisih pembulatan ke atas yang didonasikan pelanggan
+	DonasiAktif  bool `json:"donasiAktif"`  // Pelanggan boleh memilih donasi pembulatan
+	TotalTagihan int  `json:"totalTagihan"` // Total + pembulatan + donasi
+}
+
+// PajakSettings mengatur PPN untuk seluruh toko.
+// Tarif per produk atau per kategori menimpa TarifDefault.
+type PajakSettings struct {
+	Aktif        bool    `json:"aktif"`
+	NamaPajak    string  `json:"namaPajak"`    // Label di struk dan laporan, default "PPN"
+	TarifDefault float64 `json:"tarifDefault"` // Tarif dalam persen, misalnya 11
+	Mode         string  `json:"mode"`         // "exclusive" (pajak ditambahkan) atau "inclusive" (harga sudah termasuk pajak)
+}
+
+// KeamananSettings mengatur penguncian akun, kebijakan password dan kunci terminal untuk seluruh toko.
+type KeamananSettings struct {
+	AutoLockMenit        int `json:"autoLockMenit"`        // Terminal terkunci setelah tidak ada aktivitas (0 = nonaktif)
+	MaksGagalLogin       int `json:"maksGagalLogin"`       // Percobaan password/PIN gagal sebelum akun dikunci
+	DurasiKunciMenit     int `json:"durasiKunciMenit"`     // Lama kunci pertama, berlipat dua setiap kali akun terkunci lagi
+	DurasiKunciMaksMenit int `json:"durasiKunciMaksMenit"` // Batas atas lama kunci
+	PanjangMinPassword   int `json:"panjangMinPassword"`
+	RiwayatPassword      int `json:"riwayatPassword"` // Jumlah password terakhir yang tidak boleh dipakai ulang (0 = bebas)
+}
+
+// PembelianSettings mengatur bagaimana harga beli produk diperbarui saat barang diterima.
+type PembelianSettings struct {
+	MetodeHargaBeli string `json:"metodeHargaBeli"` // "tetap", "terakhir" (harga terakhir) atau "rata_rata" (rata-rata tertimbang)
+}
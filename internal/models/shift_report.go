@@ -10,6 +10,8 @@ type ShiftStats struct {
 	TotalItemTerjual int     `json:"totalItemTerjual"`
 	TotalRefund      float64 `json:"totalRefund"`
 	TotalDiskon      float64 `json:"totalDiskon"`
+	TotalVoid        float64 `json:"totalVoid"`
+	TotalVoidCount   int     `json:"totalVoidCount"`
 	StaffCount       int     `json:"staffCount"`
 
 	// Trends (percentage change from previous day)
@@ -19,6 +21,9 @@ type ShiftStats struct {
 	TrendProduk    float64 `json:"trendProduk"`
 	TrendRefund    float64 `json:"trendRefund"`
 	TrendDiskon    float64 `json:"trendDiskon"`
+
+	// Attainment against the shift targets
+	Target []*PencapaianTarget `json:"target"`
 }
 
 // ShiftReportsResponse holds stats for both shifts
@@ -39,6 +44,11 @@ type ShiftDetailResponse struct {
 	TopProducts      []ShiftProduct     `json:"topProducts"`
 	HourlyData       []ShiftHourlyData  `json:"hourlyData"`
 	StaffPerformance []ShiftStaffPerf   `json:"staffPerformance"`
+
+	// Reconciliation: credit sales bring in no cash, repayments bring in cash without a sale
+	CreditSales            float64                  `json:"creditSales"`
+	ReceivablePayments     []ShiftReceivablePayment `json:"receivablePayments"`
+	ReceivablePaymentTotal float64                  `json:"receivablePaymentTotal"`
 }
 
 // ShiftTransaction represents a simplified transaction view for the report
@@ -52,6 +62,15 @@ type ShiftTransaction struct {
 	ItemCount      int       `json:"itemCount"`
 }
 
+// ShiftReceivablePayment represents a credit sale repayment received during the shift
+type ShiftReceivablePayment struct {
+	NomorTransaksi string    `json:"nomorTransaksi"`
+	Time           time.Time `json:"time"`
+	Cashier        string    `json:"cashier"`
+	Method         string    `json:"method"`
+	Amount         float64   `json:"amount"`
+}
+
 // ShiftProduct represents a top-selling product in the shift
 type ShiftProduct struct {
 	Name     string  `json:"name"`
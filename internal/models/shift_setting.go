@@ -5,5 +5,16 @@ type ShiftSetting struct {
 	Name      string `json:"name"`      // "Shift 1", "Shift 2"
 	StartTime string `json:"startTime"` // "06:00"
 	EndTime   string `json:"endTime"`   // "14:00"
-	StaffIDs  string `json:"staffIds"`  // Comma-separated staff IDs (e.g., "1,2,5")
+	StaffIDs  string `json:"staffIds"`  // Comma-separated staff IDs (e.g., "1,2,5"), stored in shift_staff
+}
+
+// Key returns the report key of the shift ("shift1", "shift2"), or "" for other shifts
+func (s ShiftSetting) Key() string {
+	switch s.Name {
+	case "Shift 1":
+		return "shift1"
+	case "Shift 2":
+		return "shift2"
+	}
+	return ""
 }
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// TargetPenjualan is a sales target for the whole store, a shift or a staff member.
+// ShiftID / StaffID 0 on a shift or staff target applies to every shift or staff member
+// that has no target of its own.
+type TargetPenjualan struct {
+	ID        int64     `json:"id,string"`
+	Periode   string    `json:"periode"` // "harian", "mingguan", "bulanan"
+	Metrik    string    `json:"metrik"`  // "omzet", "transaksi", "item", "rata_rata_belanja"
+	Lingkup   string    `json:"lingkup"` // "toko", "shift", "staff"
+	ShiftID   int       `json:"shiftId"`
+	StaffID   int64     `json:"staffId,string"`
+	Nilai     float64   `json:"nilai"`
+	Aktif     bool      `json:"aktif"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PencapaianTarget is the attainment of one metric against its target over a report range.
+// Daily, weekly and monthly targets are pro-rated to the number of days in the range;
+// the average basket target is not pro-rated.
+type PencapaianTarget struct {
+	TargetID  int64   `json:"targetId,string"`
+	Metrik    string  `json:"metrik"`
+	Periode   string  `json:"periode"`
+	Target    float64 `json:"target"`
+	Realisasi float64 `json:"realisasi"`
+	Persen    float64 `json:"persen"`
+}
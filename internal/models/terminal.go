@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// StatusTerminal is the lock state of a shared terminal and the user active on it
+type StatusTerminal struct {
+	TerminalID        string    `json:"terminalId"`
+	Terkunci          bool      `json:"terkunci"`
+	UserID            int64     `json:"userId,string"`
+	Username          string    `json:"username"`
+	NamaLengkap       string    `json:"namaLengkap"`
+	AktivitasTerakhir time.Time `json:"aktivitasTerakhir"`
+	AutoLockMenit     int       `json:"autoLockMenit"`
+}
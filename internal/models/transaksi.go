@@ -19,12 +19,19 @@ type Transaksi struct {
 	Total           int       `json:"total"`
 	TotalBayar      int       `json:"totalBayar"`
 	Kembalian       int       `json:"kembalian"`
+	Pembulatan      int       `json:"pembulatan"` // Selisih pembulatan tunai (negatif = dibulatkan ke bawah)
+	Donasi          int       `json:"donasi"`     // Selisih pembulatan yang didonasikan pelanggan
+	TotalPajak      int       `json:"totalPajak"` // Total PPN transaksi
+	ModePajak       string    `json:"modePajak"`  // "exclusive", "inclusive", atau "" jika tanpa pajak
+	DiskonManual    int       `json:"diskonManual"`
+	DisetujuiOleh   string    `json:"disetujuiOleh"` // Supervisor yang mengotorisasi diskon manual atau ubah harga
 	Status          string    `json:"status"`
 	Catatan         string    `json:"catatan"`
-	Kasir           string    `json:"kasir"`          // Legacy field - nama kasir
-	StaffID         *int64    `json:"staffId,string"` // ID staff yang melakukan transaksi (nullable untuk backward compatibility)
-	StaffNama       string    `json:"staffNama"`      // Nama staff (denormalized untuk performa)
-	Profit          int       `json:"profit"`         // Total Profit (Calculated)
+	Kasir           string    `json:"kasir"`                 // Legacy field - nama kasir
+	StaffID         *int64    `json:"staffId,string"`        // ID staff yang melakukan transaksi (nullable untuk backward compatibility)
+	StaffNama       string    `json:"staffNama"`             // Nama staff (denormalized untuk performa)
+	ShiftSessionID  *int64    `json:"shiftSessionId,string"` // Sesi kas kasir saat checkout (NULL untuk data lama)
+	Profit          int       `json:"profit"`                // Total Profit (Calculated)
 	CreatedAt       time.Time `json:"createdAt"`
 }
 
@@ -40,6 +47,9 @@ type TransaksiItem struct {
 	Jumlah         int       `json:"jumlah"`      // Quantity (untuk backward compatibility)
 	BeratGram      float64   `json:"beratGram"`   // Berat dalam gram (0 jika dijual per quantity)
 	Subtotal       int       `json:"subtotal"`
+	TarifPajak     float64   `json:"tarifPajak"` // Tarif PPN dalam persen saat transaksi
+	DPP            int       `json:"dpp"`        // Dasar pengenaan pajak setelah diskon
+	Pajak          int       `json:"pajak"`      // Nilai PPN item
 	CreatedAt      time.Time `json:"createdAt"`
 }
 
@@ -47,7 +57,7 @@ type TransaksiItem struct {
 type Pembayaran struct {
 	ID          int       `json:"id"`
 	TransaksiID int       `json:"transaksiId"`
-	Metode      string    `json:"metode"` // "tunai", "qris", "transfer", "debit", "kredit"
+	Metode      string    `json:"metode"` // Kode metode dari tabel metode_pembayaran
 	Jumlah      int       `json:"jumlah"`
 	Referensi   string    `json:"referensi"` // Reference number for non-cash payments
 	CreatedAt   time.Time `json:"createdAt"`
@@ -55,36 +65,99 @@ type Pembayaran struct {
 
 // TransaksiDetail represents complete transaction with items and payments
 type TransaksiDetail struct {
-	Transaksi  *Transaksi       `json:"transaksi"`
-	Items      []*TransaksiItem `json:"items"`
-	Pembayaran []*Pembayaran    `json:"pembayaran"`
+	Transaksi  *Transaksi            `json:"transaksi"`
+	Items      []*TransaksiItem      `json:"items"`
+	Pembayaran []*Pembayaran         `json:"pembayaran"`
+	Promos     []*TransaksiItemPromo `json:"promos,omitempty"` // Rincian diskon promo per item
+}
+
+// TransaksiItemPromo records how much of a promo discount was applied to a line item
+type TransaksiItemPromo struct {
+	ID              int64     `json:"id,string"`
+	TransaksiID     int64     `json:"transaksiId,string"`
+	TransaksiItemID int64     `json:"transaksiItemId,string"`
+	ProdukID        int       `json:"produkId"`
+	PromoID         int       `json:"promoId"`
+	PromoKode       string    `json:"promoKode"`
+	PromoNama       string    `json:"promoNama"`
+	TipePromo       string    `json:"tipePromo"`
+	Diskon          int       `json:"diskon"` // Porsi diskon promo untuk item ini
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ItemPromoAllocation is a server-computed promo discount share for one request item
+type ItemPromoAllocation struct {
+	ItemIndex int // Posisi item di CreateTransaksiRequest.Items
+	ProdukID  int
+	PromoID   int
+	PromoKode string
+	PromoNama string
+	TipePromo string
+	Diskon    int
+}
+
+// TransaksiPreview is the server-side calculation of a cart before checkout
+type TransaksiPreview struct {
+	Subtotal    int    `json:"subtotal"`
+	DiskonPromo int    `json:"diskonPromo"`
+	DiskonPoin  int    `json:"diskonPoin"`
+	PoinDipakai int    `json:"poinDipakai"`
+	Diskon      int    `json:"diskon"`     // Total diskon (promo + poin + manual)
+	TotalPajak  int    `json:"totalPajak"` // Total PPN
+	ModePajak   string `json:"modePajak"`  // "exclusive", "inclusive", atau "" jika tanpa pajak
+	Total       int    `json:"total"`      // Total tagihan sebelum pembulatan tunai
 }
 
 // CreateTransaksiRequest represents request to create a new transaction
 type CreateTransaksiRequest struct {
-	PelangganID     int64                  `json:"pelangganId,string"`
-	PelangganNama   string                 `json:"pelangganNama"`
-	PelangganTelp   string                 `json:"pelangganTelp"`
-	Items           []TransaksiItemRequest `json:"items"`
-	Pembayaran      []PembayaranRequest    `json:"pembayaran"`
-	PromoKode       string                 `json:"promoKode"`
-	PoinDitukar     int                    `json:"poinDitukar"`     // Jumlah poin yang ingin ditukar
-	Diskon          int                    `json:"diskon"`          // Total diskon
-	DiskonPromo     int                    `json:"diskonPromo"`     // Diskon dari promo
-	DiskonPelanggan int                    `json:"diskonPelanggan"` // Diskon dari level pelanggan
-	Catatan         string                 `json:"catatan"`
-	Kasir           string                 `json:"kasir"`
-	StaffID         int64                  `json:"staffId,string"` // Use string to prevent JS precision loss!
-	StaffNama       string                 `json:"staffNama"`      // Nama staff
-	CreatedAt       time.Time              `json:"createdAt"`
+	PelangganID      int64                  `json:"pelangganId,string"`
+	PelangganNama    string                 `json:"pelangganNama"`
+	PelangganTelp    string                 `json:"pelangganTelp"`
+	Items            []TransaksiItemRequest `json:"items"`
+	Pembayaran       []PembayaranRequest    `json:"pembayaran"`
+	PromoKode        string                 `json:"promoKode"`
+	PoinDitukar      int                    `json:"poinDitukar"`     // Jumlah poin yang ingin ditukar
+	Diskon           int                    `json:"diskon"`          // Total diskon
+	DiskonPromo      int                    `json:"diskonPromo"`     // Diskon dari promo
+	DiskonPelanggan  int                    `json:"diskonPelanggan"` // Diskon dari level pelanggan
+	Total            int                    `json:"total"`           // Total dihitung client sebelum pembulatan tunai, harus sama dengan server
+	Catatan          string                 `json:"catatan"`
+	Kasir            string                 `json:"kasir"`
+	StaffID          int64                  `json:"staffId,string"`   // Use string to prevent JS precision loss!
+	StaffNama        string                 `json:"staffNama"`        // Nama staff
+	TerminalID       string                 `json:"terminalId"`       // Kode terminal untuk penomoran (kosong = pengaturan lokal)
+	IdempotencyKey   string                 `json:"idempotencyKey"`   // UUID dari client agar retry tidak membuat transaksi ganda
+	DonasiPembulatan bool                   `json:"donasiPembulatan"` // Pelanggan mendonasikan selisih pembulatan
+	DiskonManual     int                    `json:"diskonManual"`     // Diskon manual kasir, termasuk dalam Diskon
+	OverrideToken    string                 `json:"overrideToken"`    // Token otorisasi supervisor untuk diskon manual atau ubah harga
+	CreatedAt        time.Time              `json:"createdAt"`
+
+	// Diisi oleh service layer, tidak pernah dibaca dari client
+	DiskonPoin     int                   `json:"-"`
+	PromoBreakdown []ItemPromoAllocation `json:"-"`
+	Pembulatan     int                   `json:"-"`
+	Donasi         int                   `json:"-"`
+	TotalPajak     int                   `json:"-"`
+	ModePajak      string                `json:"-"`
+	JatuhTempo     time.Time             `json:"-"`
+	ShiftSessionID int64                 `json:"-"`
+	DisetujuiOleh  string                `json:"-"`
+	Otorisasi      []PemakaianOtorisasi  `json:"-"` // Token otorisasi yang dipakai saat transaksi disimpan
+	LaciLokal      bool                  `json:"-"` // Penjualan dari perangkat ini, laci kasnya dibuka otomatis
 }
 
 // TransaksiItemRequest represents item in create transaction request
 type TransaksiItemRequest struct {
-	ProdukID    int     `json:"produkId"`
-	Jumlah      int     `json:"jumlah"`      // Untuk backward compatibility (default 1)
-	HargaSatuan int     `json:"hargaSatuan"` // Harga per 1000 gram
-	BeratGram   float64 `json:"beratGram"`   // Berat yang dibeli dalam gram
+	ProdukID      int     `json:"produkId"`
+	Jumlah        int     `json:"jumlah"`        // Untuk backward compatibility (default 1)
+	HargaSatuan   int     `json:"hargaSatuan"`   // Harga per 1000 gram
+	BeratGram     float64 `json:"beratGram"`     // Berat yang dibeli dalam gram
+	OverrideToken string  `json:"overrideToken"` // Token otorisasi supervisor jika harga berbeda dari harga jual
+
+	// Diisi oleh service layer, tidak pernah dibaca dari client
+	TarifPajak float64 `json:"-"`
+	DPP        int     `json:"-"`
+	Pajak      int     `json:"-"`
 }
 
 // PembayaranRequest represents payment in create transaction request
@@ -94,11 +167,24 @@ type PembayaranRequest struct {
 	Referensi string `json:"referensi"`
 }
 
+// VoidTransaksiRequest represents request to void a completed transaction.
+// ApproverUsername/ApproverSecret are the admin credentials (password or PIN) used as a second factor.
+type VoidTransaksiRequest struct {
+	TransaksiID      int64  `json:"transaksiId,string"`
+	Alasan           string `json:"alasan"`
+	ApproverUsername string `json:"approverUsername"`
+	ApproverSecret   string `json:"approverSecret"` // Password atau PIN admin
+	StaffID          int64  `json:"staffId,string"` // Staff yang mengajukan void
+	StaffNama        string `json:"staffNama"`
+	OverrideToken    string `json:"overrideToken"` // Token otorisasi supervisor, pengganti username dan PIN
+}
+
 // TransaksiResponse represents response after creating transaction
 type TransaksiResponse struct {
-	Success   bool             `json:"success"`
-	Message   string           `json:"message"`
-	Transaksi *TransaksiDetail `json:"transaksi,omitempty"`
+	Success        bool             `json:"success"`
+	Message        string           `json:"message"`
+	Transaksi      *TransaksiDetail `json:"transaksi,omitempty"`
+	PeringatanLaci string           `json:"peringatanLaci,omitempty"` // Laci kas gagal dibuka otomatis
 }
 
 type StokHistory struct {
@@ -107,10 +193,11 @@ type StokHistory struct {
 	StokSebelum    float64   `json:"stokSebelum"`
 	StokSesudah    float64   `json:"stokSesudah"`
 	Perubahan      float64   `json:"perubahan"`
-	JenisPerubahan string    `json:"jenisPerubahan"` // "manual", "penjualan", "pembelian", "adjustment"
+	JenisPerubahan string    `json:"jenisPerubahan"` // "manual", "penjualan", "pembelian", "adjustment", "return", "void", "opname"
 	Keterangan     string    `json:"keterangan"`
-	TipeKerugian   string    `json:"tipeKerugian"`  // "kadaluarsa", "rusak", "hilang", "other" (only for pengurangan)
-	NilaiKerugian  int       `json:"nilaiKerugian"` // Loss value in rupiah
+	TipeKerugian   string    `json:"tipeKerugian"`            // "kadaluarsa", "rusak", "hilang", "other" (only for pengurangan)
+	NilaiKerugian  int       `json:"nilaiKerugian"`           // Loss value in rupiah
+	DisetujuiOleh  string    `json:"disetujuiOleh,omitempty"` // Supervisor yang mengotorisasi penyesuaian stok
 	CreatedAt      time.Time `json:"createdAt"`
 }
 
@@ -8,8 +8,9 @@ type User struct {
 	Username    string     `json:"username"`
 	Password    string     `json:"-"` // Never include in JSON responses
 	NamaLengkap string     `json:"namaLengkap"`
-	Role        string     `json:"role"`   // "admin" or "staff"
-	Status      string     `json:"status"` // "active" or "inactive"
+	Role        string     `json:"role"`                  // "admin", "staff" atau role buatan
+	Permissions []string   `json:"permissions,omitempty"` // Hak akses role, diisi saat login
+	Status      string     `json:"status"`                // "active" or "inactive"
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   time.Time  `json:"updatedAt"`
 	DeletedAt   *time.Time `json:"deletedAt,omitempty"` // Soft delete
@@ -17,16 +18,29 @@ type User struct {
 
 // LoginRequest represents login credentials
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	TerminalID string `json:"terminalId"` // Kode terminal klien; lewat HTTP kosong = token tanpa terminal
+	IPAddress  string `json:"-"`          // Diisi handler HTTP untuk audit login
+	UserAgent  string `json:"-"`
+}
+
+// PinLoginRequest represents a fast login with a numeric PIN on a shared terminal
+type PinLoginRequest struct {
+	Username   string `json:"username"`
+	Pin        string `json:"pin"`
+	TerminalID string `json:"terminalId"`
+	IPAddress  string `json:"-"`
+	UserAgent  string `json:"-"`
 }
 
 // LoginResponse represents login result
 type LoginResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	User    *User  `json:"user,omitempty"`
-	Token   string `json:"token,omitempty"` // For session management
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	User       *User  `json:"user,omitempty"`
+	Token      string `json:"token,omitempty"`      // For session management
+	TerminalID string `json:"terminalId,omitempty"` // Terminal tempat user aktif
 }
 
 // CreateUserRequest represents request to create new user
@@ -34,7 +48,8 @@ type CreateUserRequest struct {
 	Username    string `json:"username"`
 	Password    string `json:"password"`
 	NamaLengkap string `json:"namaLengkap"`
-	Role        string `json:"role"` // "admin" or "staff"
+	Role        string `json:"role"`          // "admin", "staff" atau role buatan
+	Pin         string `json:"pin,omitempty"` // Optional - PIN numerik untuk login cepat
 }
 
 // UpdateUserRequest represents request to update user
@@ -42,6 +57,7 @@ type UpdateUserRequest struct {
 	ID          int64  `json:"id"`
 	Username    string `json:"username"`
 	Password    string `json:"password,omitempty"` // Optional - only if changing password
+	Pin         string `json:"pin,omitempty"`      // Optional - PIN numerik untuk login cepat dan persetujuan supervisor
 	NamaLengkap string `json:"namaLengkap"`
 	Role        string `json:"role"`
 	Status      string `json:"status"`
@@ -65,6 +81,8 @@ type StaffReport struct {
 	TotalItemTerjual int       `json:"totalItemTerjual"` // Total qty produk
 	TotalRefund      int       `json:"totalRefund"`      // Total refund/retur (menggunakan harga beli)
 	TotalReturnCount int       `json:"totalReturnCount"` // Total jumlah transaksi retur
+	TotalVoid        int       `json:"totalVoid"`        // Total nilai transaksi yang di-void
+	TotalVoidCount   int       `json:"totalVoidCount"`   // Total jumlah transaksi yang di-void
 	PeriodeMulai     time.Time `json:"periodeMulai"`
 	PeriodeSelesai   time.Time `json:"periodeSelesai"`
 }
@@ -94,11 +112,12 @@ type StaffDailyReport struct {
 
 // StaffReportWithTrend represents report with trend comparison
 type StaffReportWithTrend struct {
-	Current        *StaffReport `json:"current"`
-	Previous       *StaffReport `json:"previous"`
-	TrendPenjualan string       `json:"trendPenjualan"` // "naik", "turun", "tetap"
-	TrendTransaksi string       `json:"trendTransaksi"`
-	PercentChange  float64      `json:"percentChange"` // Percentage change in revenue
+	Current        *StaffReport        `json:"current"`
+	Previous       *StaffReport        `json:"previous"`
+	TrendPenjualan string              `json:"trendPenjualan"` // "naik", "turun", "tetap"
+	TrendTransaksi string              `json:"trendTransaksi"`
+	PercentChange  float64             `json:"percentChange"` // Percentage change in revenue
+	Target         []*PencapaianTarget `json:"target"`        // Attainment against the staff targets
 }
 
 // StaffHistoricalData represents historical data for charts
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// AbsensiRepository handles database operations for staff attendance
+type AbsensiRepository struct{}
+
+// NewAbsensiRepository creates a new repository instance
+func NewAbsensiRepository() *AbsensiRepository {
+	return &AbsensiRepository{}
+}
+
+const absensiColumns = `
+	id, staff_id, COALESCE(staff_nama, ''), tanggal, COALESCE(shift_id, 0), COALESCE(shift_nama, ''),
+	COALESCE(jadwal_masuk, ''), COALESCE(jadwal_pulang, ''), status,
+	COALESCE(metode_masuk, ''), COALESCE(metode_pulang, ''), jam_masuk, jam_pulang,
+	COALESCE(terlambat_menit, 0), COALESCE(pulang_cepat_menit, 0), COALESCE(lembur_menit, 0),
+	COALESCE(istirahat_menit, 0), COALESCE(kerja_menit, 0), COALESCE(catatan, ''), created_at, updated_at
+`
+
+// Create records a clock-in
+func (r *AbsensiRepository) Create(a *models.Absensi) error {
+	now := time.Now().UTC()
+	a.Status = "masuk"
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	var shiftID interface{}
+	if a.ShiftID != 0 {
+		shiftID = a.ShiftID
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		a.ID = database.GenerateOfflineID()
+		query := `
+			INSERT INTO absensi (id, staff_id, staff_nama, tanggal, shift_id, shift_nama, jadwal_masuk, jadwal_pulang,
+				status, metode_masuk, jam_masuk, terlambat_menit, catatan, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, a.ID, a.StaffID, a.StaffNama, a.Tanggal, shiftID, a.ShiftNama,
+			a.JadwalMasuk, a.JadwalPulang, a.Status, a.MetodeMasuk, a.JamMasuk.UTC(), a.TerlambatMenit,
+			a.Catatan, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to clock in: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO absensi (staff_id, staff_nama, tanggal, shift_id, shift_nama, jadwal_masuk, jadwal_pulang,
+			status, metode_masuk, jam_masuk, terlambat_menit, catatan, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err := database.QueryRow(query, a.StaffID, a.StaffNama, a.Tanggal, shiftID, a.ShiftNama,
+		a.JadwalMasuk, a.JadwalPulang, a.Status, a.MetodeMasuk, a.JamMasuk.UTC(), a.TerlambatMenit,
+		a.Catatan, now, now).Scan(&a.ID)
+	if err != nil {
+		return fmt.Errorf("failed to clock in: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an attendance record by ID
+func (r *AbsensiRepository) GetByID(id int64) (*models.Absensi, error) {
+	row := database.QueryRow(`SELECT `+absensiColumns+` FROM absensi WHERE id = ?`, id)
+	a, err := scanAbsensi(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+	return a, nil
+}
+
+// GetAktifByStaff retrieves the attendance record a staff member has not clocked out of yet
+func (r *AbsensiRepository) GetAktifByStaff(staffID int64) (*models.Absensi, error) {
+	row := database.QueryRow(`
+		SELECT `+absensiColumns+` FROM absensi
+		WHERE staff_id = ? AND status != 'pulang'
+		ORDER BY jam_masuk DESC LIMIT 1
+	`, staffID)
+	a, err := scanAbsensi(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open attendance: %w", err)
+	}
+	return a, nil
+}
+
+// GetByStaffAndTanggal retrieves the attendance of a staff member for work dates within [dari, sampai] (YYYY-MM-DD)
+func (r *AbsensiRepository) GetByStaffAndTanggal(staffID int64, dari, sampai string) ([]*models.Absensi, error) {
+	rows, err := database.Query(`
+		SELECT `+absensiColumns+` FROM absensi
+		WHERE staff_id = ? AND tanggal >= ? AND tanggal <= ?
+		ORDER BY jam_masuk ASC
+	`, staffID, dari, sampai)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.Absensi
+	for rows.Next() {
+		a, err := scanAbsensi(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attendance: %w", err)
+		}
+		list = append(list, a)
+	}
+
+	return list, nil
+}
+
+// MulaiIstirahat starts a break; the staff member must be clocked in and not already on a break
+func (r *AbsensiRepository) MulaiIstirahat(a *models.Absensi, mulai time.Time) (*models.AbsensiIstirahat, error) {
+	tx, err := r.beginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	result, err := tx.Exec(database.TranslateQuery(`
+		UPDATE absensi SET status = 'istirahat', updated_at = ? WHERE id = ? AND status = 'masuk'
+	`), now, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start break: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("staff tidak sedang bekerja")
+	}
+
+	ist := &models.AbsensiIstirahat{AbsensiID: a.ID, Mulai: mulai.UTC()}
+	if database.UseDualMode && database.IsSQLite() {
+		ist.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO absensi_istirahat (id, absensi_id, mulai, created_at) VALUES (?, ?, ?, ?)
+		`), ist.ID, ist.AbsensiID, ist.Mulai, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO absensi_istirahat (absensi_id, mulai, created_at) VALUES (?, ?, ?) RETURNING id
+		`), ist.AbsensiID, ist.Mulai, now).Scan(&ist.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start break: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	a.Status = "istirahat"
+	a.UpdatedAt = now
+	return ist, nil
+}
+
+// SelesaiIstirahat ends the open break and adds its duration to the attendance record
+func (r *AbsensiRepository) SelesaiIstirahat(a *models.Absensi, selesai time.Time) (*models.AbsensiIstirahat, error) {
+	tx, err := r.beginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ist := &models.AbsensiIstirahat{AbsensiID: a.ID}
+	err = tx.QueryRow(database.TranslateQuery(`
+		SELECT id, mulai FROM absensi_istirahat
+		WHERE absensi_id = ? AND selesai IS NULL
+		ORDER BY mulai DESC LIMIT 1
+	`), a.ID).Scan(&ist.ID, &ist.Mulai)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("staff tidak sedang istirahat")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get break: %w", err)
+	}
+
+	selesai = selesai.UTC()
+	ist.Selesai = &selesai
+	ist.DurasiMenit = menitAntara(ist.Mulai, selesai)
+
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE absensi_istirahat SET selesai = ?, durasi_menit = ? WHERE id = ?
+	`), selesai, ist.DurasiMenit, ist.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end break: %w", err)
+	}
+
+	now := time.Now().UTC()
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE absensi SET status = 'masuk', istirahat_menit = COALESCE(istirahat_menit, 0) + ?, updated_at = ?
+		WHERE id = ?
+	`), ist.DurasiMenit, now, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end break: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	a.Status = "masuk"
+	a.IstirahatMenit += ist.DurasiMenit
+	a.UpdatedAt = now
+	return ist, nil
+}
+
+// Pulang records the clock-out with its final totals. A finished record is never updated again.
+func (r *AbsensiRepository) Pulang(a *models.Absensi) error {
+	now := time.Now().UTC()
+	query := `
+		UPDATE absensi
+		SET status = 'pulang', metode_pulang = ?, jam_pulang = ?, pulang_cepat_menit = ?, lembur_menit = ?,
+			kerja_menit = ?, catatan = ?, updated_at = ?
+		WHERE id = ? AND status = 'masuk'
+	`
+	result, err := database.Exec(query, a.MetodePulang, a.JamPulang.UTC(), a.PulangCepatMenit, a.LemburMenit,
+		a.KerjaMenit, a.Catatan, now, a.ID)
+	if err != nil {
+		return fmt.Errorf("failed to clock out: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("absensi sudah ditutup")
+	}
+
+	a.Status = "pulang"
+	a.UpdatedAt = now
+	return nil
+}
+
+// GetIstirahat retrieves the breaks of an attendance record
+func (r *AbsensiRepository) GetIstirahat(absensiID int64) ([]*models.AbsensiIstirahat, error) {
+	rows, err := database.Query(`
+		SELECT id, absensi_id, mulai, selesai, COALESCE(durasi_menit, 0)
+		FROM absensi_istirahat
+		WHERE absensi_id = ?
+		ORDER BY mulai ASC
+	`, absensiID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get breaks: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.AbsensiIstirahat
+	for rows.Next() {
+		ist := &models.AbsensiIstirahat{}
+		var selesai sql.NullTime
+		if err := rows.Scan(&ist.ID, &ist.AbsensiID, &ist.Mulai, &selesai, &ist.DurasiMenit); err != nil {
+			return nil, fmt.Errorf("failed to scan break: %w", err)
+		}
+		if selesai.Valid {
+			ist.Selesai = &selesai.Time
+		}
+		list = append(list, ist)
+	}
+
+	return list, nil
+}
+
+func (r *AbsensiRepository) beginTx() (*sql.Tx, error) {
+	db := database.DB
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// menitAntara returns the whole minutes from a to b, never negative
+func menitAntara(a, b time.Time) int {
+	if !b.After(a) {
+		return 0
+	}
+	return int(b.Sub(a) / time.Minute)
+}
+
+func scanAbsensi(row rowScanner) (*models.Absensi, error) {
+	a := &models.Absensi{}
+	var jamPulang sql.NullTime
+	err := row.Scan(
+		&a.ID, &a.StaffID, &a.StaffNama, &a.Tanggal, &a.ShiftID, &a.ShiftNama,
+		&a.JadwalMasuk, &a.JadwalPulang, &a.Status,
+		&a.MetodeMasuk, &a.MetodePulang, &a.JamMasuk, &jamPulang,
+		&a.TerlambatMenit, &a.PulangCepatMenit, &a.LemburMenit,
+		&a.IstirahatMenit, &a.KerjaMenit, &a.Catatan, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if jamPulang.Valid {
+		a.JamPulang = &jamPulang.Time
+	}
+	return a, nil
+}
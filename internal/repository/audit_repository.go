@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// AuditRepository stores the audit trail. Entries are only ever inserted, never updated or deleted.
+type AuditRepository struct{}
+
+// NewAuditRepository creates a new repository instance
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// Create appends an entry to the audit log
+func (r *AuditRepository) Create(a *models.AuditLog) error {
+	a.CreatedAt = time.Now().UTC()
+
+	perubahan, err := json.Marshal(a.Perubahan)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	var aktorID interface{}
+	if a.AktorID != 0 {
+		aktorID = a.AktorID
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		a.ID = database.GenerateOfflineID()
+		_, err = database.Exec(`
+			INSERT INTO audit_log (id, aktor_id, aktor_nama, entitas, entitas_id, aksi, perubahan, keterangan, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, a.ID, aktorID, a.AktorNama, a.Entitas, a.EntitasID, a.Aksi, string(perubahan), a.Keterangan, a.CreatedAt)
+	} else {
+		err = database.QueryRow(`
+			INSERT INTO audit_log (aktor_id, aktor_nama, entitas, entitas_id, aksi, perubahan, keterangan, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`, aktorID, a.AktorNama, a.Entitas, a.EntitasID, a.Aksi, string(perubahan), a.Keterangan, a.CreatedAt).Scan(&a.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+
+	return nil
+}
+
+// GetAll retrieves audit entries matching the filter, newest first
+func (r *AuditRepository) GetAll(filter *models.AuditFilter) ([]*models.AuditLog, error) {
+	var kondisi []string
+	var args []interface{}
+
+	if filter.Entitas != "" {
+		kondisi = append(kondisi, "entitas = ?")
+		args = append(args, filter.Entitas)
+	}
+	if filter.EntitasID != "" {
+		kondisi = append(kondisi, "entitas_id = ?")
+		args = append(args, filter.EntitasID)
+	}
+	if filter.AktorID != 0 {
+		kondisi = append(kondisi, "aktor_id = ?")
+		args = append(args, filter.AktorID)
+	}
+	if filter.Aksi != "" {
+		kondisi = append(kondisi, "aksi = ?")
+		args = append(args, filter.Aksi)
+	}
+	if !filter.StartDate.IsZero() {
+		kondisi = append(kondisi, "created_at >= ?")
+		args = append(args, filter.StartDate.UTC())
+	}
+	if !filter.EndDate.IsZero() {
+		kondisi = append(kondisi, "created_at <= ?")
+		args = append(args, filter.EndDate.UTC())
+	}
+
+	query := `SELECT id, COALESCE(aktor_id, 0), COALESCE(aktor_nama, ''), entitas, COALESCE(entitas_id, ''), aksi,
+		COALESCE(perubahan, ''), COALESCE(keterangan, ''), created_at
+		FROM audit_log`
+	if len(kondisi) > 0 {
+		query += " WHERE " + strings.Join(kondisi, " AND ")
+	}
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*models.AuditLog, 0)
+	for rows.Next() {
+		a := &models.AuditLog{}
+		var perubahan string
+		if err := rows.Scan(&a.ID, &a.AktorID, &a.AktorNama, &a.Entitas, &a.EntitasID, &a.Aksi,
+			&perubahan, &a.Keterangan, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		if err := decodeJSONKolom(perubahan, &a.Perubahan); err != nil {
+			return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+		}
+		list = append(list, a)
+	}
+
+	return list, nil
+}
@@ -26,6 +26,121 @@ func NewBatchRepository() *BatchRepository {
 
 // CreateBatch creates a new batch
 func (r *BatchRepository) CreateBatch(batch *models.Batch) error {
+	query, args := r.prepareBatchInsert(batch)
+
+	// use database.Exec for dialect translation
+	_, err := database.Exec(query, args...)
+
+	if err != nil {
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBatchTx creates a new batch within a transaction
+func (r *BatchRepository) CreateBatchTx(tx *sql.Tx, batch *models.Batch) error {
+	query, args := r.prepareBatchInsert(batch)
+
+	if _, err := tx.Exec(database.TranslateQuery(query), args...); err != nil {
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	return nil
+}
+
+// RestokBatchTx adds restocked quantity inside a transaction. Stock restocked on the same day
+// with the same shelf life and purchase cost is merged into one batch; otherwise a new batch is created.
+// It returns the batch that holds the quantity.
+func (r *BatchRepository) RestokBatchTx(tx *sql.Tx, batch *models.Batch) (*models.Batch, error) {
+	var query string
+	if database.IsPostgreSQL() {
+		query = `
+			SELECT id FROM batch
+			WHERE produk_id = ?
+			  AND tanggal_restok::date = ?::date
+			  AND masa_simpan_hari = ?
+			  AND COALESCE(harga_beli, 0) = ?
+			  AND qty_tersisa > 0
+			ORDER BY created_at DESC
+			LIMIT 1
+		`
+	} else {
+		query = `
+			SELECT id FROM batch
+			WHERE produk_id = ?
+			  AND DATE(tanggal_restok) = DATE(?)
+			  AND masa_simpan_hari = ?
+			  AND COALESCE(harga_beli, 0) = ?
+			  AND qty_tersisa > 0
+			ORDER BY created_at DESC
+			LIMIT 1
+		`
+	}
+
+	var existingID string
+	err := tx.QueryRow(database.TranslateQuery(query), batch.ProdukID, batch.TanggalRestok.Format("2006-01-02"),
+		batch.MasaSimpanHari, batch.HargaBeli).Scan(&existingID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to find batch: %w", err)
+	}
+
+	if existingID == "" {
+		if err := r.CreateBatchTx(tx, batch); err != nil {
+			return nil, err
+		}
+		return batch, nil
+	}
+
+	// Merge into existing batch
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE batch
+		SET qty = qty + ?, qty_tersisa = qty_tersisa + ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`), batch.Qty, batch.Qty, existingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update existing batch: %w", err)
+	}
+
+	merged := &models.Batch{}
+	err = tx.QueryRow(database.TranslateQuery(`
+		SELECT id, produk_id, qty, qty_tersisa, tanggal_restok,
+		       masa_simpan_hari, tanggal_kadaluarsa, status,
+		       supplier, keterangan, COALESCE(harga_beli, 0), COALESCE(penerimaan_id, 0), created_at, updated_at
+		FROM batch
+		WHERE id = ?
+	`), existingID).Scan(
+		&merged.ID,
+		&merged.ProdukID,
+		&merged.Qty,
+		&merged.QtyTersisa,
+		&merged.TanggalRestok,
+		&merged.MasaSimpanHari,
+		&merged.TanggalKadaluarsa,
+		&merged.Status,
+		&merged.Supplier,
+		&merged.Keterangan,
+		&merged.HargaBeli,
+		&merged.PenerimaanID,
+		&merged.CreatedAt,
+		&merged.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+
+	return merged, nil
+}
+
+// TanggalRestokHariIni returns today's restock date: midnight WIB (UTC+7),
+// so expiry dates computed from it fall on whole days
+func TanggalRestokHariIni() time.Time {
+	now := time.Now().UTC().Add(7 * time.Hour)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// prepareBatchInsert fills in the derived batch fields and builds the insert statement
+func (r *BatchRepository) prepareBatchInsert(batch *models.Batch) (string, []interface{}) {
 	// Generate UUID for batch ID
 	if batch.ID == "" {
 		batch.ID = uuid.New().String()
@@ -41,17 +156,20 @@ func (r *BatchRepository) CreateBatch(batch *models.Batch) error {
 	// Initialize qty_tersisa same as qty
 	batch.QtyTersisa = batch.Qty
 
+	var penerimaanID interface{}
+	if batch.PenerimaanID != 0 {
+		penerimaanID = batch.PenerimaanID
+	}
+
 	query := `
 		INSERT INTO batch (
 			id, produk_id, qty, qty_tersisa, tanggal_restok,
 			masa_simpan_hari, tanggal_kadaluarsa, status,
-			supplier, keterangan
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			supplier, keterangan, harga_beli, penerimaan_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := database.Exec(
-		// use database.Exec for dialect translation
-		query,
+	return query, []interface{}{
 		batch.ID,
 		batch.ProdukID,
 		batch.Qty,
@@ -62,13 +180,9 @@ func (r *BatchRepository) CreateBatch(batch *models.Batch) error {
 		batch.Status,
 		batch.Supplier,
 		batch.Keterangan,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to create batch: %w", err)
+		batch.HargaBeli,
+		penerimaanID,
 	}
-
-	return nil
 }
 
 // GetBatchByID retrieves a batch by ID
@@ -76,7 +190,7 @@ func (r *BatchRepository) GetBatchByID(id string) (*models.Batch, error) {
 	query := `
 		SELECT id, produk_id, qty, qty_tersisa, tanggal_restok,
 		       masa_simpan_hari, tanggal_kadaluarsa, status,
-		       supplier, keterangan, created_at, updated_at
+		       supplier, keterangan, COALESCE(harga_beli, 0), COALESCE(penerimaan_id, 0), created_at, updated_at
 		FROM batch
 		WHERE id = ?
 	`
@@ -93,6 +207,8 @@ func (r *BatchRepository) GetBatchByID(id string) (*models.Batch, error) {
 		&batch.Status,
 		&batch.Supplier,
 		&batch.Keterangan,
+		&batch.HargaBeli,
+		&batch.PenerimaanID,
 		&batch.CreatedAt,
 		&batch.UpdatedAt,
 	)
@@ -118,7 +234,7 @@ func (r *BatchRepository) GetBatchesByProdukID(produkID int) ([]*models.Batch, e
 	query := `
 		SELECT id, produk_id, qty, qty_tersisa, tanggal_restok,
 		       masa_simpan_hari, tanggal_kadaluarsa, status,
-		       supplier, keterangan, created_at, updated_at
+		       supplier, keterangan, COALESCE(harga_beli, 0), COALESCE(penerimaan_id, 0), created_at, updated_at
 		FROM batch
 		WHERE produk_id = ? AND qty_tersisa > 0
 		ORDER BY tanggal_restok ASC, created_at ASC
@@ -145,6 +261,8 @@ func (r *BatchRepository) GetBatchesByProdukID(produkID int) ([]*models.Batch, e
 			&batch.Status,
 			&batch.Supplier,
 			&batch.Keterangan,
+			&batch.HargaBeli,
+			&batch.PenerimaanID,
 			&batch.CreatedAt,
 			&batch.UpdatedAt,
 		)
@@ -169,7 +287,7 @@ func (r *BatchRepository) GetAllBatches() ([]*models.Batch, error) {
 	query := `
 		SELECT id, produk_id, qty, qty_tersisa, tanggal_restok,
 		       masa_simpan_hari, tanggal_kadaluarsa, status,
-		       supplier, keterangan, created_at, updated_at
+		       supplier, keterangan, COALESCE(harga_beli, 0), COALESCE(penerimaan_id, 0), created_at, updated_at
 		FROM batch
 		ORDER BY tanggal_restok DESC, created_at DESC
 	`
@@ -198,6 +316,8 @@ func (r *BatchRepository) GetAllBatches() ([]*models.Batch, error) {
 			&batch.Status,
 			&batch.Supplier,
 			&batch.Keterangan,
+			&batch.HargaBeli,
+			&batch.PenerimaanID,
 			&batch.CreatedAt,
 			&batch.UpdatedAt,
 		)
@@ -240,7 +360,7 @@ func (r *BatchRepository) GetExpiringBatches(daysThreshold int) ([]*models.Batch
 			SELECT
 				b.id, b.produk_id, b.qty, b.qty_tersisa, b.tanggal_restok,
 				b.masa_simpan_hari, b.tanggal_kadaluarsa, b.status,
-				b.supplier, b.keterangan, b.created_at, b.updated_at,
+				b.supplier, b.keterangan, COALESCE(b.harga_beli, 0), COALESCE(b.penerimaan_id, 0), b.created_at, b.updated_at,
 				p.hari_pemberitahuan_kadaluarsa,
 				p.nama as produk_nama,
 				(b.tanggal_kadaluarsa::date - $1::date) as days_diff
@@ -256,7 +376,7 @@ func (r *BatchRepository) GetExpiringBatches(daysThreshold int) ([]*models.Batch
 			SELECT
 				b.id, b.produk_id, b.qty, b.qty_tersisa, b.tanggal_restok,
 				b.masa_simpan_hari, b.tanggal_kadaluarsa, b.status,
-				b.supplier, b.keterangan, b.created_at, b.updated_at,
+				b.supplier, b.keterangan, COALESCE(b.harga_beli, 0), COALESCE(b.penerimaan_id, 0), b.created_at, b.updated_at,
 				p.hari_pemberitahuan_kadaluarsa,
 				p.nama as produk_nama,
 				CAST(julianday(DATE(b.tanggal_kadaluarsa)) - julianday(DATE(?)) AS INTEGER) as days_diff
@@ -306,6 +426,8 @@ func (r *BatchRepository) GetExpiringBatches(daysThreshold int) ([]*models.Batch
 			&batch.Status,
 			&batch.Supplier,
 			&batch.Keterangan,
+			&batch.HargaBeli,
+			&batch.PenerimaanID,
 			&batch.CreatedAt,
 			&batch.UpdatedAt,
 			&hariPemberitahuan,
@@ -378,6 +500,50 @@ func (r *BatchRepository) UpdateBatchQtyTx(tx *sql.Tx, batchID string, qtyReduct
 	return nil
 }
 
+// KurangiBatchTx takes qty from the product's batches oldest first within a transaction.
+// Stock that is not tracked in any batch is not an error; what the batches hold is taken.
+func (r *BatchRepository) KurangiBatchTx(tx *sql.Tx, produkID int, qty float64) error {
+	rows, err := tx.Query(database.TranslateQuery(`
+		SELECT id, qty_tersisa FROM batch
+		WHERE produk_id = ? AND qty_tersisa > 0
+		ORDER BY tanggal_restok ASC, created_at ASC
+	`), produkID)
+	if err != nil {
+		return fmt.Errorf("failed to query batches: %w", err)
+	}
+
+	type sisaBatch struct {
+		id         string
+		qtyTersisa float64
+	}
+	var batches []sisaBatch
+	for rows.Next() {
+		var b sisaBatch
+		if err := rows.Scan(&b.id, &b.qtyTersisa); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan batch: %w", err)
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+
+	for _, b := range batches {
+		if qty <= 0 {
+			break
+		}
+		ambil := qty
+		if ambil > b.qtyTersisa {
+			ambil = b.qtyTersisa
+		}
+		if err := r.UpdateBatchQtyTx(tx, b.id, ambil); err != nil {
+			return fmt.Errorf("failed to update batch %s: %w", b.id, err)
+		}
+		qty -= ambil
+	}
+
+	return nil
+}
+
 // RestoreQty restores quantity to a batch (used for returns)
 func (r *BatchRepository) RestoreQty(batchID string, qty float64) error {
 	query := `
@@ -397,6 +563,23 @@ func (r *BatchRepository) RestoreQty(batchID string, qty float64) error {
 	return nil
 }
 
+// RestoreQtyTx adds quantity back to a batch within a transaction
+func (r *BatchRepository) RestoreQtyTx(tx *sql.Tx, batchID string, qty float64) error {
+	query := `
+		UPDATE batch
+		SET qty_tersisa = qty_tersisa + ?,
+		    updated_at = ?
+		WHERE id = ?`
+
+	query = database.TranslateQuery(query)
+
+	if _, err := tx.Exec(query, qty, time.Now(), batchID); err != nil {
+		return fmt.Errorf("failed to restore qty to batch in transaction: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateBatchStatus updates the status of a batch
 func (r *BatchRepository) UpdateBatchStatus(batchID string, status string) error {
 	query := `UPDATE batch SET status = ? WHERE id = ?`
@@ -463,7 +646,7 @@ func (r *BatchRepository) FindBatchByDateAndShelfLife(produkID int, date string,
 		query = `
 			SELECT id, produk_id, qty, qty_tersisa, tanggal_restok,
 			       masa_simpan_hari, tanggal_kadaluarsa, status,
-			       supplier, keterangan, created_at, updated_at
+			       supplier, keterangan, COALESCE(harga_beli, 0), COALESCE(penerimaan_id, 0), created_at, updated_at
 			FROM batch
 			WHERE produk_id = $1
 			  AND tanggal_restok::date = $2::date
@@ -476,7 +659,7 @@ func (r *BatchRepository) FindBatchByDateAndShelfLife(produkID int, date string,
 		query = `
 			SELECT id, produk_id, qty, qty_tersisa, tanggal_restok,
 			       masa_simpan_hari, tanggal_kadaluarsa, status,
-			       supplier, keterangan, created_at, updated_at
+			       supplier, keterangan, COALESCE(harga_beli, 0), COALESCE(penerimaan_id, 0), created_at, updated_at
 			FROM batch
 			WHERE produk_id = ?
 			  AND DATE(tanggal_restok) = DATE(?)
@@ -499,6 +682,8 @@ func (r *BatchRepository) FindBatchByDateAndShelfLife(produkID int, date string,
 		&batch.Status,
 		&batch.Supplier,
 		&batch.Keterangan,
+		&batch.HargaBeli,
+		&batch.PenerimaanID,
 		&batch.CreatedAt,
 		&batch.UpdatedAt,
 	)
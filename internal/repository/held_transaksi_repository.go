@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// HeldTransaksiRepository handles database operations for parked transactions
+type HeldTransaksiRepository struct{}
+
+// NewHeldTransaksiRepository creates a new repository instance
+func NewHeldTransaksiRepository() *HeldTransaksiRepository {
+	return &HeldTransaksiRepository{}
+}
+
+// Create saves a new held transaction draft
+func (r *HeldTransaksiRepository) Create(h *models.HeldTransaksi) error {
+	draft, err := json.Marshal(h.Draft)
+	if err != nil {
+		return fmt.Errorf("failed to encode draft: %w", err)
+	}
+
+	now := time.Now().UTC()
+	h.CreatedAt = now
+	h.UpdatedAt = now
+	if h.Status == "" {
+		h.Status = "held"
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		id := database.GenerateOfflineID()
+		query := `
+			INSERT INTO held_transaksi (id, label, terminal_id, staff_id, staff_nama, draft, jumlah_item, subtotal, status, expires_at, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, id, h.Label, h.TerminalID, h.StaffID, h.StaffNama, string(draft),
+			h.JumlahItem, h.Subtotal, h.Status, h.ExpiresAt.UTC(), now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create held transaction: %w", err)
+		}
+		h.ID = id
+		return nil
+	}
+
+	query := `
+		INSERT INTO held_transaksi (label, terminal_id, staff_id, staff_nama, draft, jumlah_item, subtotal, status, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err = database.QueryRow(query, h.Label, h.TerminalID, h.StaffID, h.StaffNama, string(draft),
+		h.JumlahItem, h.Subtotal, h.Status, h.ExpiresAt.UTC(), now, now).Scan(&h.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create held transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a held transaction by ID
+func (r *HeldTransaksiRepository) GetByID(id int64) (*models.HeldTransaksi, error) {
+	query := `
+		SELECT id, label, terminal_id, staff_id, staff_nama, draft, jumlah_item, subtotal, status, expires_at, created_at, updated_at
+		FROM held_transaksi WHERE id = ?
+	`
+
+	h, err := scanHeldTransaksi(database.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held transaction: %w", err)
+	}
+
+	return h, nil
+}
+
+// GetActive retrieves drafts that are still held, optionally filtered by terminal and/or staff
+func (r *HeldTransaksiRepository) GetActive(terminalID string, staffID int64) ([]*models.HeldTransaksi, error) {
+	query := `
+		SELECT id, label, terminal_id, staff_id, staff_nama, draft, jumlah_item, subtotal, status, expires_at, created_at, updated_at
+		FROM held_transaksi WHERE status = 'held'
+	`
+	var args []interface{}
+	if terminalID != "" {
+		query += ` AND terminal_id = ?`
+		args = append(args, terminalID)
+	}
+	if staffID != 0 {
+		query += ` AND staff_id = ?`
+		args = append(args, staffID)
+	}
+	query += ` ORDER BY created_at ASC`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.HeldTransaksi
+	for rows.Next() {
+		h, err := scanHeldTransaksi(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan held transaction: %w", err)
+		}
+		result = append(result, h)
+	}
+
+	return result, nil
+}
+
+// UpdateStatus changes the status of a held transaction.
+// Only drafts that are still "held" can move to another status.
+func (r *HeldTransaksiRepository) UpdateStatus(id int64, status string) error {
+	query := `UPDATE held_transaksi SET status = ?, updated_at = ? WHERE id = ? AND status = 'held'`
+	result, err := database.Exec(query, status, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update held transaction: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("held transaction not found or no longer held")
+	}
+
+	return nil
+}
+
+// ExpireOverdue marks every held draft whose expiry time has passed as expired
+func (r *HeldTransaksiRepository) ExpireOverdue(now time.Time) (int64, error) {
+	query := `UPDATE held_transaksi SET status = 'expired', updated_at = ? WHERE status = 'held' AND expires_at <= ?`
+	result, err := database.Exec(query, now.UTC(), now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire held transactions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+type heldTransaksiScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanHeldTransaksi(row heldTransaksiScanner) (*models.HeldTransaksi, error) {
+	h := &models.HeldTransaksi{}
+	var label, terminalID, staffNama sql.NullString
+	var staffID sql.NullInt64
+	var draft string
+
+	err := row.Scan(
+		&h.ID, &label, &terminalID, &staffID, &staffNama, &draft,
+		&h.JumlahItem, &h.Subtotal, &h.Status, &h.ExpiresAt, &h.CreatedAt, &h.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Label = label.String
+	h.TerminalID = terminalID.String
+	h.StaffID = staffID.Int64
+	h.StaffNama = staffNama.String
+
+	if err := json.Unmarshal([]byte(draft), &h.Draft); err != nil {
+		return nil, fmt.Errorf("failed to decode draft: %w", err)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// IdempotencyRepository handles database operations for idempotency keys
+type IdempotencyRepository struct{}
+
+// NewIdempotencyRepository creates a new repository instance
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{}
+}
+
+// Reserve claims a key for a scope. It returns reserved=false together with the
+// existing record when the key has been used before.
+func (r *IdempotencyRepository) Reserve(scope, key, requestHash string) (*models.IdempotencyRecord, bool, error) {
+	now := time.Now().UTC()
+	query := `
+		INSERT INTO idempotency_keys (scope, idem_key, request_hash, status, created_at, updated_at)
+		VALUES (?, ?, ?, 'processing', ?, ?)
+		ON CONFLICT(scope, idem_key) DO NOTHING
+	`
+	result, err := database.Exec(query, scope, key, requestHash, now, now)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if rows > 0 {
+		return nil, true, nil
+	}
+
+	existing, err := r.Get(scope, key)
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+// Get retrieves the record of a key (nil if not found)
+func (r *IdempotencyRepository) Get(scope, key string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT id, scope, idem_key, request_hash, status, status_code, response, created_at, updated_at
+		FROM idempotency_keys WHERE scope = ? AND idem_key = ?
+	`
+
+	rec := &models.IdempotencyRecord{}
+	var requestHash, response sql.NullString
+	var statusCode sql.NullInt64
+	err := database.QueryRow(query, scope, key).Scan(
+		&rec.ID, &rec.Scope, &rec.Key, &requestHash, &rec.Status, &statusCode, &response,
+		&rec.CreatedAt, &rec.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	rec.RequestHash = requestHash.String
+	rec.StatusCode = int(statusCode.Int64)
+	rec.Response = response.String
+
+	return rec, nil
+}
+
+// Complete stores the response of a reserved key
+func (r *IdempotencyRepository) Complete(scope, key string, statusCode int, response string) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status = 'completed', status_code = ?, response = ?, updated_at = ?
+		WHERE scope = ? AND idem_key = ?
+	`
+	if _, err := database.Exec(query, statusCode, response, time.Now().UTC(), scope, key); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release removes a reserved key that did not complete, so the request can be retried
+func (r *IdempotencyRepository) Release(scope, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE scope = ? AND idem_key = ? AND status = 'processing'`
+	if _, err := database.Exec(query, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteOlderThan removes keys created before the given time
+func (r *IdempotencyRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE created_at < ?`
+	result, err := database.Exec(query, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
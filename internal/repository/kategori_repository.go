@@ -20,10 +20,10 @@ func (r *KategoriRepository) Create(kategori *models.Kategori) error {
 	if database.UseDualMode && database.IsSQLite() {
 		id := database.GenerateOfflineID()
 		query := `
-			INSERT INTO kategori (id, nama, deskripsi, icon, created_at, updated_at)
-			VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			INSERT INTO kategori (id, nama, deskripsi, icon, tarif_pajak, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		`
-		_, err := database.Exec(query, id, kategori.Nama, kategori.Deskripsi, kategori.Icon)
+		_, err := database.Exec(query, id, kategori.Nama, kategori.Deskripsi, kategori.Icon, kategori.TarifPajak)
 		if err != nil {
 			return fmt.Errorf("failed to create kategori: %w", err)
 		}
@@ -32,8 +32,8 @@ func (r *KategoriRepository) Create(kategori *models.Kategori) error {
 	}
 
 	query := `
-		INSERT INTO kategori (nama, deskripsi, icon, created_at, updated_at)
-		VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id
+		INSERT INTO kategori (nama, deskripsi, icon, tarif_pajak, created_at, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id
 	`
 
 	var id int64
@@ -41,6 +41,7 @@ func (r *KategoriRepository) Create(kategori *models.Kategori) error {
 		kategori.Nama,
 		kategori.Deskripsi,
 		kategori.Icon,
+		kategori.TarifPajak,
 	).Scan(&id)
 	if err != nil {
 		return fmt.Errorf("failed to create kategori: %w", err)
@@ -58,6 +59,7 @@ func (r *KategoriRepository) GetAll() ([]*models.Kategori, error) {
 			k.nama,
 			k.deskripsi,
 			k.icon,
+			k.tarif_pajak,
 			COUNT(p.id) as jumlah_produk,
 			k.created_at,
 			k.updated_at
@@ -76,11 +78,13 @@ func (r *KategoriRepository) GetAll() ([]*models.Kategori, error) {
 	var kategoris []*models.Kategori
 	for rows.Next() {
 		var k models.Kategori
+		var tarifPajak sql.NullFloat64
 		err := rows.Scan(
 			&k.ID,
 			&k.Nama,
 			&k.Deskripsi,
 			&k.Icon,
+			&tarifPajak,
 			&k.JumlahProduk,
 			&k.CreatedAt,
 			&k.UpdatedAt,
@@ -88,6 +92,9 @@ func (r *KategoriRepository) GetAll() ([]*models.Kategori, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan kategori: %w", err)
 		}
+		if tarifPajak.Valid {
+			k.TarifPajak = &tarifPajak.Float64
+		}
 		kategoris = append(kategoris, &k)
 	}
 
@@ -102,6 +109,7 @@ func (r *KategoriRepository) GetByID(id int) (*models.Kategori, error) {
 			k.nama,
 			k.deskripsi,
 			k.icon,
+			k.tarif_pajak,
 			COUNT(p.id) as jumlah_produk,
 			k.created_at,
 			k.updated_at
@@ -112,11 +120,13 @@ func (r *KategoriRepository) GetByID(id int) (*models.Kategori, error) {
 	`
 
 	var k models.Kategori
+	var tarifPajak sql.NullFloat64
 	err := database.QueryRow(query, id).Scan(
 		&k.ID,
 		&k.Nama,
 		&k.Deskripsi,
 		&k.Icon,
+		&tarifPajak,
 		&k.JumlahProduk,
 		&k.CreatedAt,
 		&k.UpdatedAt,
@@ -128,6 +138,9 @@ func (r *KategoriRepository) GetByID(id int) (*models.Kategori, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kategori: %w", err)
 	}
+	if tarifPajak.Valid {
+		k.TarifPajak = &tarifPajak.Float64
+	}
 
 	return &k, nil
 }
@@ -140,6 +153,7 @@ func (r *KategoriRepository) GetByNama(nama string) (*models.Kategori, error) {
 			k.nama,
 			k.deskripsi,
 			k.icon,
+			k.tarif_pajak,
 			COUNT(p.id) as jumlah_produk,
 			k.created_at,
 			k.updated_at
@@ -150,11 +164,13 @@ func (r *KategoriRepository) GetByNama(nama string) (*models.Kategori, error) {
 	`
 
 	var k models.Kategori
+	var tarifPajak sql.NullFloat64
 	err := database.QueryRow(query, nama).Scan(
 		&k.ID,
 		&k.Nama,
 		&k.Deskripsi,
 		&k.Icon,
+		&tarifPajak,
 		&k.JumlahProduk,
 		&k.CreatedAt,
 		&k.UpdatedAt,
@@ -166,6 +182,9 @@ func (r *KategoriRepository) GetByNama(nama string) (*models.Kategori, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kategori: %w", err)
 	}
+	if tarifPajak.Valid {
+		k.TarifPajak = &tarifPajak.Float64
+	}
 
 	return &k, nil
 }
@@ -174,7 +193,7 @@ func (r *KategoriRepository) GetByNama(nama string) (*models.Kategori, error) {
 func (r *KategoriRepository) Update(kategori *models.Kategori) error {
 	query := `
 		UPDATE kategori
-		SET nama = ?, deskripsi = ?, icon = ?
+		SET nama = ?, deskripsi = ?, icon = ?, tarif_pajak = ?
 		WHERE id = ?
 	`
 
@@ -182,6 +201,7 @@ func (r *KategoriRepository) Update(kategori *models.Kategori) error {
 		kategori.Nama,
 		kategori.Deskripsi,
 		kategori.Icon,
+		kategori.TarifPajak,
 		kategori.ID,
 	)
 	if err != nil {
@@ -0,0 +1,468 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// KomisiRepository handles database operations for commission rules and payouts
+type KomisiRepository struct{}
+
+// NewKomisiRepository creates a new repository instance
+func NewKomisiRepository() *KomisiRepository {
+	return &KomisiRepository{}
+}
+
+const aturanKomisiColumns = `
+	id, nama, tipe, COALESCE(persen, 0), COALESCE(nominal_per_unit, 0), COALESCE(produk_ids, ''),
+	COALESCE(kategori, ''), COALESCE(staff_ids, ''), COALESCE(tingkat, ''), aktif, created_at, updated_at
+`
+
+// CreateAturan saves a new commission rule
+func (r *KomisiRepository) CreateAturan(a *models.AturanKomisi) error {
+	produkIDs, staffIDs, tingkat, err := encodeAturanKomisi(a)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	if database.UseDualMode && database.IsSQLite() {
+		a.ID = database.GenerateOfflineID()
+		query := `
+			INSERT INTO komisi_aturan (id, nama, tipe, persen, nominal_per_unit, produk_ids, kategori, staff_ids, tingkat, aktif, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, a.ID, a.Nama, a.Tipe, a.Persen, a.NominalPerUnit, produkIDs, a.Kategori,
+			staffIDs, tingkat, boolToInt(a.Aktif), now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create commission rule: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO komisi_aturan (nama, tipe, persen, nominal_per_unit, produk_ids, kategori, staff_ids, tingkat, aktif, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err = database.QueryRow(query, a.Nama, a.Tipe, a.Persen, a.NominalPerUnit, produkIDs, a.Kategori,
+		staffIDs, tingkat, boolToInt(a.Aktif), now, now).Scan(&a.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create commission rule: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAturan saves changes to a commission rule
+func (r *KomisiRepository) UpdateAturan(a *models.AturanKomisi) error {
+	produkIDs, staffIDs, tingkat, err := encodeAturanKomisi(a)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE komisi_aturan
+		SET nama = ?, tipe = ?, persen = ?, nominal_per_unit = ?, produk_ids = ?, kategori = ?, staff_ids = ?,
+			tingkat = ?, aktif = ?, updated_at = ?
+		WHERE id = ?
+	`
+	a.UpdatedAt = time.Now().UTC()
+	result, err := database.Exec(query, a.Nama, a.Tipe, a.Persen, a.NominalPerUnit, produkIDs, a.Kategori,
+		staffIDs, tingkat, boolToInt(a.Aktif), a.UpdatedAt, a.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update commission rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("commission rule not found")
+	}
+
+	return nil
+}
+
+// DeleteAturan removes a commission rule; saved payouts keep their own breakdown
+func (r *KomisiRepository) DeleteAturan(id int64) error {
+	result, err := database.Exec(`DELETE FROM komisi_aturan WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete commission rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("commission rule not found")
+	}
+
+	return nil
+}
+
+// GetAllAturan retrieves all commission rules
+func (r *KomisiRepository) GetAllAturan() ([]*models.AturanKomisi, error) {
+	rows, err := database.Query(`SELECT ` + aturanKomisiColumns + ` FROM komisi_aturan ORDER BY nama ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commission rules: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.AturanKomisi{}
+	for rows.Next() {
+		a, err := scanAturanKomisi(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan commission rule: %w", err)
+		}
+		list = append(list, a)
+	}
+
+	return list, nil
+}
+
+// GetAturanByID retrieves a commission rule by ID
+func (r *KomisiRepository) GetAturanByID(id int64) (*models.AturanKomisi, error) {
+	row := database.QueryRow(`SELECT `+aturanKomisiColumns+` FROM komisi_aturan WHERE id = ?`, id)
+	a, err := scanAturanKomisi(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission rule: %w", err)
+	}
+	return a, nil
+}
+
+// GetPenjualanProduk aggregates the completed sales of a staff member per product.
+// HPP uses the current purchase price, like the staff performance report.
+func (r *KomisiRepository) GetPenjualanProduk(staffID int64, startDate, endDate time.Time) ([]*models.PenjualanProdukKomisi, error) {
+	// Match the kasir name as well for transactions recorded before staff_id existed
+	var staffName string
+	if err := database.QueryRow("SELECT nama_lengkap FROM users WHERE id = ?", staffID).Scan(&staffName); err != nil {
+		staffName = ""
+	}
+
+	query := `
+		SELECT
+			COALESCE(ti.produk_id, 0), COALESCE(ti.produk_kategori, ''),
+			COALESCE(SUM(CASE WHEN ti.beratgram > 0 THEN ti.beratgram / 1000.0 ELSE ti.jumlah END), 0),
+			COALESCE(SUM(ti.subtotal), 0),
+			COALESCE(SUM(CASE
+				WHEN ti.beratgram > 0 THEN ti.beratgram / 1000.0 * COALESCE(p.harga_beli, 0)
+				ELSE ti.jumlah * COALESCE(p.harga_beli, 0)
+			END), 0)
+		FROM transaksi_item ti
+		JOIN transaksi t ON t.id = ti.transaksi_id
+		LEFT JOIN produk p ON p.id = ti.produk_id
+		WHERE (t.staff_id = ? OR LOWER(t.kasir) = LOWER(?))
+		  AND t.status = 'selesai'
+		  AND DATE(t.tanggal) >= ?
+		  AND DATE(t.tanggal) <= ?
+		GROUP BY ti.produk_id, ti.produk_kategori
+	`
+
+	rows, err := database.Query(query, staffID, staffName, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product sales: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.PenjualanProdukKomisi
+	for rows.Next() {
+		p := &models.PenjualanProdukKomisi{}
+		var hpp float64
+		if err := rows.Scan(&p.ProdukID, &p.Kategori, &p.Jumlah, &p.Penjualan, &hpp); err != nil {
+			return nil, fmt.Errorf("failed to scan product sales: %w", err)
+		}
+		p.HPP = int(hpp)
+		list = append(list, p)
+	}
+
+	return list, nil
+}
+
+// CountPeriodeOverlap counts saved payouts whose date range overlaps [mulai, selesai]
+func (r *KomisiRepository) CountPeriodeOverlap(mulai, selesai string) (int, error) {
+	var count int
+	err := database.QueryRow(`
+		SELECT COUNT(*) FROM komisi_periode WHERE periode_mulai <= ? AND periode_selesai >= ?
+	`, selesai, mulai).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check commission periods: %w", err)
+	}
+	return count, nil
+}
+
+// CreatePeriode saves a payout with the commission of every staff member in one transaction
+func (r *KomisiRepository) CreatePeriode(p *models.PeriodeKomisi) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	p.Status = "draft"
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	dualMode := database.UseDualMode && database.IsSQLite()
+
+	if dualMode {
+		p.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO komisi_periode (id, periode_mulai, periode_selesai, status, total_komisi, catatan, dibuat_oleh, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), p.ID, p.PeriodeMulai, p.PeriodeSelesai, p.Status, p.TotalKomisi, p.Catatan, p.DibuatOleh, now, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO komisi_periode (periode_mulai, periode_selesai, status, total_komisi, catatan, dibuat_oleh, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), p.PeriodeMulai, p.PeriodeSelesai, p.Status, p.TotalKomisi, p.Catatan, p.DibuatOleh, now, now).Scan(&p.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create commission period: %w", err)
+	}
+
+	for _, k := range p.Staff {
+		k.PeriodeID = p.ID
+		rincian, err := json.Marshal(k.Rincian)
+		if err != nil {
+			return fmt.Errorf("failed to encode commission breakdown: %w", err)
+		}
+
+		if dualMode {
+			k.ID = database.GenerateOfflineID()
+			_, err = tx.Exec(database.TranslateQuery(`
+				INSERT INTO komisi_staff (id, periode_id, staff_id, staff_nama, total_penjualan, total_profit, total_item,
+					retur_penjualan, retur_profit, retur_item, total_komisi, rincian, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`), k.ID, k.PeriodeID, k.StaffID, k.StaffNama, k.TotalPenjualan, k.TotalProfit, k.TotalItem,
+				k.ReturPenjualan, k.ReturProfit, k.ReturItem, k.TotalKomisi, string(rincian), now)
+		} else {
+			err = tx.QueryRow(database.TranslateQuery(`
+				INSERT INTO komisi_staff (periode_id, staff_id, staff_nama, total_penjualan, total_profit, total_item,
+					retur_penjualan, retur_profit, retur_item, total_komisi, rincian, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+			`), k.PeriodeID, k.StaffID, k.StaffNama, k.TotalPenjualan, k.TotalProfit, k.TotalItem,
+				k.ReturPenjualan, k.ReturProfit, k.ReturItem, k.TotalKomisi, string(rincian), now).Scan(&k.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save staff commission: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetAllPeriode retrieves saved payouts, newest first, without staff lines
+func (r *KomisiRepository) GetAllPeriode() ([]*models.PeriodeKomisi, error) {
+	rows, err := database.Query(`
+		SELECT id, periode_mulai, periode_selesai, status, COALESCE(total_komisi, 0), COALESCE(catatan, ''),
+			COALESCE(dibuat_oleh, ''), dibayar_at, created_at, updated_at
+		FROM komisi_periode
+		ORDER BY periode_mulai DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commission periods: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.PeriodeKomisi{}
+	for rows.Next() {
+		p, err := scanPeriodeKomisi(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan commission period: %w", err)
+		}
+		list = append(list, p)
+	}
+
+	return list, nil
+}
+
+// GetPeriodeByID retrieves a saved payout with its staff lines
+func (r *KomisiRepository) GetPeriodeByID(id int64) (*models.PeriodeKomisi, error) {
+	row := database.QueryRow(`
+		SELECT id, periode_mulai, periode_selesai, status, COALESCE(total_komisi, 0), COALESCE(catatan, ''),
+			COALESCE(dibuat_oleh, ''), dibayar_at, created_at, updated_at
+		FROM komisi_periode
+		WHERE id = ?
+	`, id)
+	p, err := scanPeriodeKomisi(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission period: %w", err)
+	}
+
+	rows, err := database.Query(`
+		SELECT id, periode_id, staff_id, COALESCE(staff_nama, ''), COALESCE(total_penjualan, 0),
+			COALESCE(total_profit, 0), COALESCE(total_item, 0), COALESCE(retur_penjualan, 0),
+			COALESCE(retur_profit, 0), COALESCE(retur_item, 0), COALESCE(total_komisi, 0), COALESCE(rincian, '')
+		FROM komisi_staff
+		WHERE periode_id = ?
+		ORDER BY staff_nama ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staff commissions: %w", err)
+	}
+	defer rows.Close()
+
+	p.Staff = []*models.KomisiStaff{}
+	for rows.Next() {
+		k := &models.KomisiStaff{}
+		var rincian string
+		err := rows.Scan(&k.ID, &k.PeriodeID, &k.StaffID, &k.StaffNama, &k.TotalPenjualan, &k.TotalProfit,
+			&k.TotalItem, &k.ReturPenjualan, &k.ReturProfit, &k.ReturItem, &k.TotalKomisi, &rincian)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan staff commission: %w", err)
+		}
+		k.Rincian = []models.RincianKomisiStaff{}
+		if rincian != "" {
+			if err := json.Unmarshal([]byte(rincian), &k.Rincian); err != nil {
+				return nil, fmt.Errorf("failed to decode commission breakdown: %w", err)
+			}
+		}
+		p.Staff = append(p.Staff, k)
+	}
+
+	return p, nil
+}
+
+// TandaiDibayar locks a draft payout as paid
+func (r *KomisiRepository) TandaiDibayar(id int64) error {
+	now := time.Now().UTC()
+	result, err := database.Exec(`
+		UPDATE komisi_periode SET status = 'dibayar', dibayar_at = ?, updated_at = ? WHERE id = ? AND status = 'draft'
+	`, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark commission period as paid: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("periode komisi tidak ditemukan atau sudah dibayar")
+	}
+	return nil
+}
+
+// DeletePeriode removes a draft payout and its staff lines
+func (r *KomisiRepository) DeletePeriode(id int64) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(database.TranslateQuery(`DELETE FROM komisi_periode WHERE id = ? AND status = 'draft'`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete commission period: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("periode komisi tidak ditemukan atau sudah dibayar")
+	}
+
+	if _, err := tx.Exec(database.TranslateQuery(`DELETE FROM komisi_staff WHERE periode_id = ?`), id); err != nil {
+		return fmt.Errorf("failed to delete staff commissions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func encodeAturanKomisi(a *models.AturanKomisi) (string, string, string, error) {
+	produkIDs, err := json.Marshal(a.ProdukIDs)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode products: %w", err)
+	}
+	staffIDs, err := json.Marshal(a.StaffIDs)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode staff: %w", err)
+	}
+	tingkat, err := json.Marshal(a.Tingkat)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode tiers: %w", err)
+	}
+	return string(produkIDs), string(staffIDs), string(tingkat), nil
+}
+
+func scanAturanKomisi(row rowScanner) (*models.AturanKomisi, error) {
+	a := &models.AturanKomisi{}
+	var produkIDs, staffIDs, tingkat string
+	var aktif int
+	err := row.Scan(&a.ID, &a.Nama, &a.Tipe, &a.Persen, &a.NominalPerUnit, &produkIDs, &a.Kategori,
+		&staffIDs, &tingkat, &aktif, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.Aktif = aktif == 1
+
+	a.ProdukIDs = []int{}
+	a.StaffIDs = []int64{}
+	a.Tingkat = []models.TingkatKomisi{}
+	if err := decodeJSONKolom(produkIDs, &a.ProdukIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+	if err := decodeJSONKolom(staffIDs, &a.StaffIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode staff: %w", err)
+	}
+	if err := decodeJSONKolom(tingkat, &a.Tingkat); err != nil {
+		return nil, fmt.Errorf("failed to decode tiers: %w", err)
+	}
+	return a, nil
+}
+
+// decodeJSONKolom decodes a JSON text column, leaving dest untouched when the column is empty
+func decodeJSONKolom(raw string, dest interface{}) error {
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), dest)
+}
+
+func scanPeriodeKomisi(row rowScanner) (*models.PeriodeKomisi, error) {
+	p := &models.PeriodeKomisi{}
+	var dibayarAt sql.NullTime
+	err := row.Scan(&p.ID, &p.PeriodeMulai, &p.PeriodeSelesai, &p.Status, &p.TotalKomisi, &p.Catatan,
+		&p.DibuatOleh, &dibayarAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if dibayarAt.Valid {
+		p.DibayarAt = &dibayarAt.Time
+	}
+	return p, nil
+}
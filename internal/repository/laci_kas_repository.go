@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// LaciKasRepository handles the cash drawer opening log
+type LaciKasRepository struct {
+	otorisasiRepo *OtorisasiRepository
+}
+
+// NewLaciKasRepository creates a new repository instance
+func NewLaciKasRepository() *LaciKasRepository {
+	return &LaciKasRepository{
+		otorisasiRepo: NewOtorisasiRepository(),
+	}
+}
+
+// CreateLog records a drawer opening. The no-sale override token, if any, is spent in the
+// same database transaction as the log entry.
+func (r *LaciKasRepository) CreateLog(l *models.LogLaciKas) error {
+	l.CreatedAt = time.Now().UTC()
+
+	// Zero IDs are stored as NULL
+	var transaksiID, sesiID, staffID interface{}
+	if l.TransaksiID != 0 {
+		transaksiID = l.TransaksiID
+	}
+	if l.SesiID != 0 {
+		sesiID = l.SesiID
+	}
+	if l.StaffID != 0 {
+		staffID = l.StaffID
+	}
+
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if l.Otorisasi != nil {
+		if err := r.otorisasiRepo.PakaiTx(tx, *l.Otorisasi, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		l.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO laci_kas_log (id, tipe, alasan, transaksi_id, nomor_transaksi, sesi_id, staff_id, staff_nama, berhasil, pesan, disetujui_oleh, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), l.ID, l.Tipe, l.Alasan, transaksiID, l.NomorTransaksi, sesiID, staffID, l.StaffNama, boolToInt(l.Berhasil), l.Pesan, l.DisetujuiOleh, l.CreatedAt)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO laci_kas_log (tipe, alasan, transaksi_id, nomor_transaksi, sesi_id, staff_id, staff_nama, berhasil, pesan, disetujui_oleh, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), l.Tipe, l.Alasan, transaksiID, l.NomorTransaksi, sesiID, staffID, l.StaffNama, boolToInt(l.Berhasil), l.Pesan, l.DisetujuiOleh, l.CreatedAt).Scan(&l.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to log drawer opening: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit drawer log: %w", err)
+	}
+	return nil
+}
+
+// GetLog retrieves drawer openings within a time range, optionally filtered by staff and type
+func (r *LaciKasRepository) GetLog(staffID int64, tipe string, startDate, endDate time.Time) ([]*models.LogLaciKas, error) {
+	query := `
+		SELECT id, tipe, COALESCE(alasan, ''), COALESCE(transaksi_id, 0), COALESCE(nomor_transaksi, ''),
+			COALESCE(sesi_id, 0), COALESCE(staff_id, 0), COALESCE(staff_nama, ''), berhasil, COALESCE(pesan, ''),
+			COALESCE(disetujui_oleh, ''), created_at
+		FROM laci_kas_log
+		WHERE created_at >= ? AND created_at <= ?
+	`
+	args := []interface{}{startDate.UTC(), endDate.UTC()}
+	if staffID != 0 {
+		query += ` AND staff_id = ?`
+		args = append(args, staffID)
+	}
+	if tipe != "" {
+		query += ` AND tipe = ?`
+		args = append(args, tipe)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drawer log: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*models.LogLaciKas, 0)
+	for rows.Next() {
+		var l models.LogLaciKas
+		var berhasil int
+		err := rows.Scan(&l.ID, &l.Tipe, &l.Alasan, &l.TransaksiID, &l.NomorTransaksi,
+			&l.SesiID, &l.StaffID, &l.StaffNama, &berhasil, &l.Pesan, &l.DisetujuiOleh, &l.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan drawer log: %w", err)
+		}
+		l.Berhasil = berhasil == 1
+		list = append(list, &l)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// LoginEventRepository stores the login audit trail
+type LoginEventRepository struct{}
+
+// NewLoginEventRepository creates a new repository instance
+func NewLoginEventRepository() *LoginEventRepository {
+	return &LoginEventRepository{}
+}
+
+// Create records one login attempt
+func (r *LoginEventRepository) Create(e *models.LoginEvent) error {
+	e.CreatedAt = time.Now().UTC()
+
+	var userID interface{}
+	if e.UserID != 0 {
+		userID = e.UserID
+	}
+
+	var err error
+	if database.UseDualMode && database.IsSQLite() {
+		e.ID = database.GenerateOfflineID()
+		_, err = database.Exec(`
+			INSERT INTO login_events (id, user_id, username, metode, berhasil, alasan, ip_address, terminal_id, user_agent, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, e.ID, userID, e.Username, e.Metode, boolToInt(e.Berhasil), e.Alasan, e.IPAddress, e.TerminalID, e.UserAgent, e.CreatedAt)
+	} else {
+		err = database.QueryRow(`
+			INSERT INTO login_events (user_id, username, metode, berhasil, alasan, ip_address, terminal_id, user_agent, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`, userID, e.Username, e.Metode, boolToInt(e.Berhasil), e.Alasan, e.IPAddress, e.TerminalID, e.UserAgent, e.CreatedAt).Scan(&e.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create login event: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUser retrieves the latest login attempts of a user, newest first
+func (r *LoginEventRepository) GetByUser(userID int64, limit int) ([]*models.LoginEvent, error) {
+	rows, err := database.Query(`
+		SELECT id, COALESCE(user_id, 0), username, metode, berhasil, COALESCE(alasan, ''),
+			COALESCE(ip_address, ''), COALESCE(terminal_id, ''), COALESCE(user_agent, ''), created_at
+		FROM login_events
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query login events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.LoginEvent, 0)
+	for rows.Next() {
+		e := &models.LoginEvent{}
+		var berhasil int
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Username, &e.Metode, &berhasil, &e.Alasan,
+			&e.IPAddress, &e.TerminalID, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan login event: %w", err)
+		}
+		e.Berhasil = berhasil == 1
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// MetodePembayaranRepository handles database operations for payment methods
+type MetodePembayaranRepository struct{}
+
+// NewMetodePembayaranRepository creates a new repository instance
+func NewMetodePembayaranRepository() *MetodePembayaranRepository {
+	return &MetodePembayaranRepository{}
+}
+
+// Create saves a new payment method
+func (r *MetodePembayaranRepository) Create(m *models.MetodePembayaran) error {
+	now := time.Now().UTC()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+
+	if database.UseDualMode && database.IsSQLite() {
+		id := database.GenerateOfflineID()
+		query := `
+			INSERT INTO metode_pembayaran (id, kode, nama, tipe, buka_laci, perlu_referensi, aktif, urutan, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, id, m.Kode, m.Nama, m.Tipe, boolToInt(m.BukaLaci), boolToInt(m.PerluReferensi),
+			boolToInt(m.Aktif), m.Urutan, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create payment method: %w", err)
+		}
+		m.ID = id
+		return nil
+	}
+
+	query := `
+		INSERT INTO metode_pembayaran (kode, nama, tipe, buka_laci, perlu_referensi, aktif, urutan, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err := database.QueryRow(query, m.Kode, m.Nama, m.Tipe, boolToInt(m.BukaLaci), boolToInt(m.PerluReferensi),
+		boolToInt(m.Aktif), m.Urutan, now, now).Scan(&m.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create payment method: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves changes to a payment method
+func (r *MetodePembayaranRepository) Update(m *models.MetodePembayaran) error {
+	query := `
+		UPDATE metode_pembayaran
+		SET kode = ?, nama = ?, tipe = ?, buka_laci = ?, perlu_referensi = ?, aktif = ?, urutan = ?, updated_at = ?
+		WHERE id = ?
+	`
+	m.UpdatedAt = time.Now().UTC()
+	result, err := database.Exec(query, m.Kode, m.Nama, m.Tipe, boolToInt(m.BukaLaci), boolToInt(m.PerluReferensi),
+		boolToInt(m.Aktif), m.Urutan, m.UpdatedAt, m.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update payment method: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment method not found")
+	}
+
+	return nil
+}
+
+// Delete removes a payment method
+func (r *MetodePembayaranRepository) Delete(id int64) error {
+	result, err := database.Exec(`DELETE FROM metode_pembayaran WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete payment method: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment method not found")
+	}
+
+	return nil
+}
+
+// GetAll retrieves all payment methods in display order
+func (r *MetodePembayaranRepository) GetAll() ([]*models.MetodePembayaran, error) {
+	query := `
+		SELECT id, kode, nama, tipe, buka_laci, perlu_referensi, aktif, urutan, created_at, updated_at
+		FROM metode_pembayaran
+		ORDER BY urutan ASC, nama ASC
+	`
+
+	rows, err := database.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment methods: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.MetodePembayaran{}
+	for rows.Next() {
+		m, err := scanMetodePembayaran(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment method: %w", err)
+		}
+		list = append(list, m)
+	}
+
+	return list, nil
+}
+
+// GetByID retrieves a payment method by ID
+func (r *MetodePembayaranRepository) GetByID(id int64) (*models.MetodePembayaran, error) {
+	query := `
+		SELECT id, kode, nama, tipe, buka_laci, perlu_referensi, aktif, urutan, created_at, updated_at
+		FROM metode_pembayaran WHERE id = ?
+	`
+
+	m, err := scanMetodePembayaran(database.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment method: %w", err)
+	}
+
+	return m, nil
+}
+
+// GetByKode retrieves a payment method by its code
+func (r *MetodePembayaranRepository) GetByKode(kode string) (*models.MetodePembayaran, error) {
+	query := `
+		SELECT id, kode, nama, tipe, buka_laci, perlu_referensi, aktif, urutan, created_at, updated_at
+		FROM metode_pembayaran WHERE kode = ?
+	`
+
+	m, err := scanMetodePembayaran(database.QueryRow(query, kode))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment method: %w", err)
+	}
+
+	return m, nil
+}
+
+// Count returns the number of configured payment methods
+func (r *MetodePembayaranRepository) Count() (int, error) {
+	var count int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM metode_pembayaran`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count payment methods: %w", err)
+	}
+	return count, nil
+}
+
+// CountUsage returns how many payments were recorded with the given method code
+func (r *MetodePembayaranRepository) CountUsage(kode string) (int, error) {
+	var count int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM pembayaran WHERE metode = ?`, kode).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count payment method usage: %w", err)
+	}
+	return count, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMetodePembayaran(row rowScanner) (*models.MetodePembayaran, error) {
+	m := &models.MetodePembayaran{}
+	var bukaLaci, perluReferensi, aktif int
+	err := row.Scan(&m.ID, &m.Kode, &m.Nama, &m.Tipe, &bukaLaci, &perluReferensi, &aktif, &m.Urutan,
+		&m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.BukaLaci = bukaLaci == 1
+	m.PerluReferensi = perluReferensi == 1
+	m.Aktif = aktif == 1
+	return m, nil
+}
+
+// boolToInt stores booleans as 0/1 so the same query works on SQLite and PostgreSQL INTEGER columns
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
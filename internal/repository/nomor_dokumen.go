@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ritel-app/internal/database"
+)
+
+// Jenis dokumen stok yang dinomori dengan reserveNomorDokumen
+const (
+	JenisDokumenPO         = "PO"
+	JenisDokumenPenerimaan = "TB"
+)
+
+// reserveNomorDokumen reserves the next number of a stock document (e.g. "PO-T01-20261016-0001")
+// inside the insert transaction. The terminal code keeps numbers from offline devices apart;
+// the sequence restarts every day. tabel and kolom name where issued numbers are stored.
+func reserveNomorDokumen(tx *sql.Tx, jenis, tabel, kolom string) (string, error) {
+	penomoran, err := NewSettingsRepository().GetPenomoranSettings()
+	if err != nil {
+		return "", err
+	}
+
+	wib := time.FixedZone("WIB", 7*3600)
+	parts := []string{jenis}
+	if penomoran.KodeTerminal != "" {
+		parts = append(parts, penomoran.KodeTerminal)
+	}
+	parts = append(parts, time.Now().In(wib).Format("20060102"))
+	prefix := strings.Join(parts, "-") + "-"
+
+	selectQuery := `SELECT last_number FROM nomor_transaksi_counter WHERE counter_key = ?`
+	if database.IsPostgreSQL() {
+		selectQuery += ` FOR UPDATE`
+	}
+	var lastNumber int64
+	err = tx.QueryRow(database.TranslateQuery(selectQuery), prefix).Scan(&lastNumber)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to read document counter: %w", err)
+	}
+
+	// Jangan pernah di bawah nomor yang sudah ada (mis. hasil sync)
+	var highest string
+	err = tx.QueryRow(database.TranslateQuery(fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE %s LIKE ?
+		ORDER BY LENGTH(%s) DESC, %s DESC
+		LIMIT 1
+	`, kolom, tabel, kolom, kolom, kolom)), prefix+"%").Scan(&highest)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to read last document number: %w", err)
+	}
+	if highest != "" {
+		if existing, convErr := strconv.ParseInt(strings.TrimPrefix(highest, prefix), 10, 64); convErr == nil && existing > lastNumber {
+			lastNumber = existing
+		}
+	}
+
+	nextNumber := lastNumber + 1
+	_, err = tx.Exec(database.TranslateQuery(`
+		INSERT INTO nomor_transaksi_counter (counter_key, last_number, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(counter_key) DO UPDATE SET
+			last_number = excluded.last_number,
+			updated_at = excluded.updated_at
+	`), prefix, nextNumber, time.Now().UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to reserve document number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, nextNumber), nil
+}
@@ -0,0 +1,431 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// JenisDokumenOpname numbers stock count sessions
+const JenisDokumenOpname = "SO"
+
+// OpnameRepository handles stock count sessions
+type OpnameRepository struct {
+	batchRepo *BatchRepository
+}
+
+// NewOpnameRepository creates a new repository instance
+func NewOpnameRepository() *OpnameRepository {
+	return &OpnameRepository{
+		batchRepo: NewBatchRepository(),
+	}
+}
+
+// CreateOpname opens a count session and freezes the stock and cost of every product in
+// scope (all products, or one category) as its lines
+func (r *OpnameRepository) CreateOpname(o *models.StokOpname) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	nomor, err := reserveNomorDokumen(tx, JenisDokumenOpname, "stok_opname", "nomor_opname")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	o.NomorOpname = nomor
+	o.Status = models.StatusOpnameDibuka
+	o.TanggalMulai = now
+	o.CreatedAt = now
+	o.UpdatedAt = now
+	dualMode := database.UseDualMode && database.IsSQLite()
+
+	if dualMode {
+		o.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO stok_opname (id, nomor_opname, kategori, status, catatan, dibuka_oleh, tanggal_mulai, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), o.ID, o.NomorOpname, o.Kategori, o.Status, o.Catatan, o.DibukaOleh, now, now, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO stok_opname (nomor_opname, kategori, status, catatan, dibuka_oleh, tanggal_mulai, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), o.NomorOpname, o.Kategori, o.Status, o.Catatan, o.DibukaOleh, now, now, now).Scan(&o.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create stock count: %w", err)
+	}
+
+	// Bekukan stok sistem dan harga beli saat sesi dibuka
+	query := `
+		SELECT id, nama, COALESCE(barcode, ''), COALESCE(kategori, ''), COALESCE(satuan, ''), stok, harga_beli
+		FROM produk
+		WHERE deleted_at IS NULL
+	`
+	args := []interface{}{}
+	if o.Kategori != "" {
+		query += ` AND kategori = ?`
+		args = append(args, o.Kategori)
+	}
+	query += ` ORDER BY nama ASC`
+
+	rows, err := tx.Query(database.TranslateQuery(query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to query products: %w", err)
+	}
+	var items []*models.StokOpnameItem
+	for rows.Next() {
+		item := &models.StokOpnameItem{OpnameID: o.ID}
+		if err := rows.Scan(&item.ProdukID, &item.ProdukNama, &item.Barcode, &item.Kategori, &item.Satuan,
+			&item.QtySistem, &item.HargaBeli); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan product: %w", err)
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if len(items) == 0 {
+		return fmt.Errorf("tidak ada produk untuk dihitung")
+	}
+
+	for _, item := range items {
+		if dualMode {
+			item.ID = database.GenerateOfflineID()
+			_, err = tx.Exec(database.TranslateQuery(`
+				INSERT INTO stok_opname_item (id, opname_id, produk_id, produk_nama, barcode, kategori, satuan, qty_sistem, harga_beli)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`), item.ID, item.OpnameID, item.ProdukID, item.ProdukNama, item.Barcode, item.Kategori, item.Satuan, item.QtySistem, item.HargaBeli)
+		} else {
+			err = tx.QueryRow(database.TranslateQuery(`
+				INSERT INTO stok_opname_item (opname_id, produk_id, produk_nama, barcode, kategori, satuan, qty_sistem, harga_beli)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+			`), item.OpnameID, item.ProdukID, item.ProdukNama, item.Barcode, item.Kategori, item.Satuan, item.QtySistem, item.HargaBeli).Scan(&item.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save stock count item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	o.JumlahItem = len(items)
+	return nil
+}
+
+const opnameSelect = `
+	SELECT o.id, o.nomor_opname, COALESCE(o.kategori, ''), o.status, COALESCE(o.catatan, ''),
+	       COALESCE(o.dibuka_oleh, ''), COALESCE(o.diposting_oleh, ''), o.tanggal_mulai, o.tanggal_posting,
+	       (SELECT COUNT(*) FROM stok_opname_item i WHERE i.opname_id = o.id),
+	       (SELECT COUNT(i.qty_hitung) FROM stok_opname_item i WHERE i.opname_id = o.id),
+	       o.created_at, o.updated_at
+	FROM stok_opname o
+`
+
+func scanStokOpname(row rowScanner) (*models.StokOpname, error) {
+	o := &models.StokOpname{}
+	var tanggalPosting sql.NullTime
+	err := row.Scan(&o.ID, &o.NomorOpname, &o.Kategori, &o.Status, &o.Catatan, &o.DibukaOleh, &o.DipostingOleh,
+		&o.TanggalMulai, &tanggalPosting, &o.JumlahItem, &o.JumlahDihitung, &o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tanggalPosting.Valid {
+		o.TanggalPosting = &tanggalPosting.Time
+	}
+	return o, nil
+}
+
+// GetAllOpname retrieves count sessions newest first, optionally filtered by status
+func (r *OpnameRepository) GetAllOpname(status string) ([]*models.StokOpname, error) {
+	query := opnameSelect
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE o.status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY o.tanggal_mulai DESC, o.id DESC`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock counts: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.StokOpname{}
+	for rows.Next() {
+		o, err := scanStokOpname(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock count: %w", err)
+		}
+		list = append(list, o)
+	}
+
+	return list, nil
+}
+
+// GetOpnameByID retrieves a count session without its lines
+func (r *OpnameRepository) GetOpnameByID(id int64) (*models.StokOpname, error) {
+	o, err := scanStokOpname(database.QueryRow(opnameSelect+` WHERE o.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock count: %w", err)
+	}
+	return o, nil
+}
+
+const opnameItemSelect = `
+	SELECT id, opname_id, produk_id, produk_nama, COALESCE(barcode, ''), COALESCE(kategori, ''), COALESCE(satuan, ''),
+	       qty_sistem, qty_hitung, harga_beli, COALESCE(dihitung_oleh, ''), COALESCE(perangkat, ''), dihitung_pada
+	FROM stok_opname_item
+`
+
+func scanStokOpnameItem(row rowScanner) (*models.StokOpnameItem, error) {
+	item := &models.StokOpnameItem{}
+	var qtyHitung sql.NullFloat64
+	var dihitungPada sql.NullTime
+	err := row.Scan(&item.ID, &item.OpnameID, &item.ProdukID, &item.ProdukNama, &item.Barcode, &item.Kategori, &item.Satuan,
+		&item.QtySistem, &qtyHitung, &item.HargaBeli, &item.DihitungOleh, &item.Perangkat, &dihitungPada)
+	if err != nil {
+		return nil, err
+	}
+	if qtyHitung.Valid {
+		item.QtyHitung = &qtyHitung.Float64
+	}
+	if dihitungPada.Valid {
+		item.DihitungPada = &dihitungPada.Time
+	}
+	item.HitungSelisih()
+	return item, nil
+}
+
+// GetOpnameItems retrieves the lines of a count session by product name
+func (r *OpnameRepository) GetOpnameItems(opnameID int64) ([]*models.StokOpnameItem, error) {
+	rows, err := database.Query(opnameItemSelect+` WHERE opname_id = ? ORDER BY produk_nama ASC, id ASC`, opnameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock count items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*models.StokOpnameItem{}
+	for rows.Next() {
+		item, err := scanStokOpnameItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock count item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetOpnameItem retrieves the line of one product in a count session
+func (r *OpnameRepository) GetOpnameItem(opnameID int64, produkID int) (*models.StokOpnameItem, error) {
+	item, err := scanStokOpnameItem(database.QueryRow(opnameItemSelect+` WHERE opname_id = ? AND produk_id = ?`, opnameID, produkID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock count item: %w", err)
+	}
+	return item, nil
+}
+
+// CatatHitungan records a counted quantity on an open session. The update is a single
+// statement so counts sent at the same time from several devices are all kept.
+func (r *OpnameRepository) CatatHitungan(opnameID int64, produkID int, qty float64, mode, dihitungOleh, perangkat string) error {
+	setQty := `qty_hitung = COALESCE(qty_hitung, 0) + ?`
+	if mode == models.ModeHitungGanti {
+		setQty = `qty_hitung = ?`
+	}
+
+	result, err := database.Exec(`
+		UPDATE stok_opname_item
+		SET `+setQty+`, dihitung_oleh = ?, perangkat = ?, dihitung_pada = ?
+		WHERE opname_id = ? AND produk_id = ?
+		  AND EXISTS (SELECT 1 FROM stok_opname WHERE id = ? AND status = ?)
+	`, qty, dihitungOleh, perangkat, time.Now().UTC(), opnameID, produkID, opnameID, models.StatusOpnameDibuka)
+	if err != nil {
+		return fmt.Errorf("failed to record count: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("produk tidak termasuk sesi opname ini atau sesi sudah ditutup")
+	}
+	return nil
+}
+
+// BatalkanOpname cancels an open count session without touching stock
+func (r *OpnameRepository) BatalkanOpname(id int64) error {
+	result, err := database.Exec(`
+		UPDATE stok_opname SET status = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`, models.StatusOpnameBatal, time.Now().UTC(), id, models.StatusOpnameDibuka)
+	if err != nil {
+		return fmt.Errorf("failed to cancel stock count: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("sesi opname tidak ditemukan atau sudah ditutup")
+	}
+	return nil
+}
+
+// PostingOpname applies the variances of an open count session in one transaction. Sales
+// go on while counting, so each variance is added to the current stock rather than
+// overwriting it with the count. Shortages are taken from the oldest batches, surpluses of
+// products with a shelf life go into today's restock batch, and every adjustment is written
+// to the stock history as "opname", shortages valued at the frozen purchase cost.
+func (r *OpnameRepository) PostingOpname(id int64, dipostingOleh string, nolkanBelumDihitung bool) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statusQuery := `SELECT nomor_opname, status FROM stok_opname WHERE id = ?`
+	if database.IsPostgreSQL() {
+		statusQuery += ` FOR UPDATE`
+	}
+	var nomor, status string
+	err = tx.QueryRow(database.TranslateQuery(statusQuery), id).Scan(&nomor, &status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("sesi opname tidak ditemukan")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read stock count status: %w", err)
+	}
+	if status != models.StatusOpnameDibuka {
+		return fmt.Errorf("sesi opname berstatus %s tidak dapat diposting", status)
+	}
+
+	now := time.Now().UTC()
+	if nolkanBelumDihitung {
+		if _, err := tx.Exec(database.TranslateQuery(`
+			UPDATE stok_opname_item SET qty_hitung = 0, dihitung_oleh = ?, dihitung_pada = ?
+			WHERE opname_id = ? AND qty_hitung IS NULL
+		`), dipostingOleh, now, id); err != nil {
+			return fmt.Errorf("failed to zero uncounted items: %w", err)
+		}
+	}
+
+	rows, err := tx.Query(database.TranslateQuery(`
+		SELECT produk_id, produk_nama, qty_sistem, qty_hitung, harga_beli
+		FROM stok_opname_item
+		WHERE opname_id = ? AND qty_hitung IS NOT NULL
+	`), id)
+	if err != nil {
+		return fmt.Errorf("failed to query stock count items: %w", err)
+	}
+	var items []*models.StokOpnameItem
+	for rows.Next() {
+		item := &models.StokOpnameItem{}
+		var qtyHitung float64
+		if err := rows.Scan(&item.ProdukID, &item.ProdukNama, &item.QtySistem, &qtyHitung, &item.HargaBeli); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan stock count item: %w", err)
+		}
+		item.QtyHitung = &qtyHitung
+		item.HitungSelisih()
+		if math.Abs(item.Selisih) >= 0.0001 {
+			items = append(items, item)
+		}
+	}
+	rows.Close()
+
+	keterangan := "Stok opname " + nomor
+	restockDate := TanggalRestokHariIni()
+
+	for _, item := range items {
+		// Baris produk dikunci agar penjualan bersamaan tidak tertimpa oleh stok absolut di bawah
+		stokQuery := `SELECT stok, COALESCE(masa_simpan_hari, 0) FROM produk WHERE id = ?`
+		if database.IsPostgreSQL() {
+			stokQuery += ` FOR UPDATE`
+		}
+		var stokSebelum float64
+		var masaSimpanHari int
+		err = tx.QueryRow(database.TranslateQuery(stokQuery), item.ProdukID).
+			Scan(&stokSebelum, &masaSimpanHari)
+		if err == sql.ErrNoRows {
+			continue // Produk dihapus permanen setelah sesi dibuka
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read product stock: %w", err)
+		}
+		stokSesudah := stokSebelum + item.Selisih
+
+		if _, err := tx.Exec(database.TranslateQuery(`UPDATE produk SET stok = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`),
+			stokSesudah, item.ProdukID); err != nil {
+			return fmt.Errorf("failed to update stock for product %s: %w", item.ProdukNama, err)
+		}
+
+		tipeKerugian, nilaiKerugian := "", 0
+		if item.Selisih < 0 {
+			if err := r.batchRepo.KurangiBatchTx(tx, item.ProdukID, -item.Selisih); err != nil {
+				return err
+			}
+			tipeKerugian, nilaiKerugian = "hilang", -item.NilaiSelisih
+		} else if masaSimpanHari > 0 {
+			if _, err := r.batchRepo.RestokBatchTx(tx, &models.Batch{
+				ProdukID:       item.ProdukID,
+				Qty:            item.Selisih,
+				TanggalRestok:  restockDate,
+				MasaSimpanHari: masaSimpanHari,
+				Keterangan:     keterangan,
+				HargaBeli:      item.HargaBeli,
+			}); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO stok_history (
+				produk_id, stok_sebelum, stok_sesudah, perubahan,
+				jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian, disetujui_oleh
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), item.ProdukID, stokSebelum, stokSesudah, item.Selisih, "opname", keterangan, tipeKerugian, nilaiKerugian, dipostingOleh)
+		if err != nil {
+			return fmt.Errorf("failed to create stock history: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(database.TranslateQuery(`
+		UPDATE stok_opname SET status = ?, diposting_oleh = ?, tanggal_posting = ?, updated_at = ?
+		WHERE id = ?
+	`), models.StatusOpnameDiposting, dipostingOleh, now, now, id); err != nil {
+		return fmt.Errorf("failed to post stock count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// OtorisasiRepository stores supervisor override tokens
+type OtorisasiRepository struct{}
+
+// NewOtorisasiRepository creates a new repository instance
+func NewOtorisasiRepository() *OtorisasiRepository {
+	return &OtorisasiRepository{}
+}
+
+const otorisasiColumns = `id, aksi, COALESCE(referensi, ''), supervisor_id, supervisor_username, COALESCE(supervisor_nama, ''),
+	COALESCE(diminta_oleh_id, 0), COALESCE(diminta_oleh_nama, ''), expires_at, digunakan_at, created_at`
+
+// Create saves a newly issued token; only its hash is stored
+func (r *OtorisasiRepository) Create(o *models.Otorisasi, tokenHash string) error {
+	o.CreatedAt = time.Now().UTC()
+
+	var dimintaOlehID interface{}
+	if o.DimintaOlehID != 0 {
+		dimintaOlehID = o.DimintaOlehID
+	}
+
+	var err error
+	if database.UseDualMode && database.IsSQLite() {
+		o.ID = database.GenerateOfflineID()
+		_, err = database.Exec(`
+			INSERT INTO otorisasi (id, token_hash, aksi, referensi, supervisor_id, supervisor_username, supervisor_nama,
+				diminta_oleh_id, diminta_oleh_nama, expires_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, o.ID, tokenHash, o.Aksi, o.Referensi, o.SupervisorID, o.SupervisorUsername, o.SupervisorNama,
+			dimintaOlehID, o.DimintaOlehNama, o.ExpiresAt, o.CreatedAt)
+	} else {
+		err = database.QueryRow(`
+			INSERT INTO otorisasi (token_hash, aksi, referensi, supervisor_id, supervisor_username, supervisor_nama,
+				diminta_oleh_id, diminta_oleh_nama, expires_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`, tokenHash, o.Aksi, o.Referensi, o.SupervisorID, o.SupervisorUsername, o.SupervisorNama,
+			dimintaOlehID, o.DimintaOlehNama, o.ExpiresAt, o.CreatedAt).Scan(&o.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create otorisasi: %w", err)
+	}
+
+	return nil
+}
+
+// Pakai marks a token as used. The update only matches an unused, unexpired token for the
+// same action (and reference, when the token was issued for one), so a token cannot be spent twice.
+// Returns nil when no token matched.
+func (r *OtorisasiRepository) Pakai(tokenHash, aksi, referensi string, now time.Time) (*models.Otorisasi, error) {
+	result, err := database.Exec(`
+		UPDATE otorisasi SET digunakan_at = ?
+		WHERE token_hash = ? AND aksi = ? AND digunakan_at IS NULL AND expires_at > ?
+		AND (referensi = '' OR referensi = ?)
+	`, now.UTC(), tokenHash, aksi, now.UTC(), referensi)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use otorisasi: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected != 1 {
+		return nil, nil
+	}
+
+	o, err := scanOtorisasi(database.QueryRow(`SELECT `+otorisasiColumns+` FROM otorisasi WHERE token_hash = ?`, tokenHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otorisasi: %w", err)
+	}
+	return o, nil
+}
+
+// Cek returns the token when it can still be spent for the action, without spending it.
+// Returns nil when no token matched.
+func (r *OtorisasiRepository) Cek(tokenHash, aksi, referensi string, now time.Time) (*models.Otorisasi, error) {
+	o, err := scanOtorisasi(database.QueryRow(`SELECT `+otorisasiColumns+` FROM otorisasi
+		WHERE token_hash = ? AND aksi = ? AND digunakan_at IS NULL AND expires_at > ?
+		AND (referensi = '' OR referensi = ?)
+	`, tokenHash, aksi, now.UTC(), referensi))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otorisasi: %w", err)
+	}
+	return o, nil
+}
+
+// PakaiTx marks a checked token as used inside the transaction that writes the approved action,
+// so the token stays unused when that write is rolled back.
+func (r *OtorisasiRepository) PakaiTx(tx *sql.Tx, p models.PemakaianOtorisasi, now time.Time) error {
+	result, err := tx.Exec(database.TranslateQuery(`
+		UPDATE otorisasi SET digunakan_at = ?
+		WHERE token_hash = ? AND aksi = ? AND digunakan_at IS NULL AND expires_at > ?
+		AND (referensi = '' OR referensi = ?)
+	`), now.UTC(), p.TokenHash, p.Aksi, now.UTC(), p.Referensi)
+	if err != nil {
+		return fmt.Errorf("failed to use otorisasi: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected != 1 {
+		return fmt.Errorf("token otorisasi tidak valid, kedaluwarsa, atau sudah dipakai")
+	}
+	return nil
+}
+
+// GetRiwayat retrieves issued tokens within a time range, newest first
+func (r *OtorisasiRepository) GetRiwayat(startDate, endDate time.Time) ([]*models.Otorisasi, error) {
+	rows, err := database.Query(`SELECT `+otorisasiColumns+` FROM otorisasi
+		WHERE created_at >= ? AND created_at <= ?
+		ORDER BY created_at DESC`, startDate.UTC(), endDate.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query otorisasi: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*models.Otorisasi, 0)
+	for rows.Next() {
+		o, err := scanOtorisasi(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan otorisasi: %w", err)
+		}
+		list = append(list, o)
+	}
+
+	return list, nil
+}
+
+func scanOtorisasi(row rowScanner) (*models.Otorisasi, error) {
+	o := &models.Otorisasi{}
+	var digunakanAt sql.NullTime
+	err := row.Scan(&o.ID, &o.Aksi, &o.Referensi, &o.SupervisorID, &o.SupervisorUsername, &o.SupervisorNama,
+		&o.DimintaOlehID, &o.DimintaOlehNama, &o.ExpiresAt, &digunakanAt, &o.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if digunakanAt.Valid {
+		o.DigunakanAt = &digunakanAt.Time
+	}
+	return o, nil
+}
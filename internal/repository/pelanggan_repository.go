@@ -86,7 +86,9 @@ func (r *PelangganRepository) GetAll() ([]*models.Pelanggan, error) {
 	query := `
 		SELECT
 			id, nama, telepon, email, alamat, level, tipe, poin, diskon_persen,
-			total_transaksi, total_belanja, created_at, updated_at
+			total_transaksi, total_belanja, COALESCE(saldo, 0),
+			COALESCE(limit_kredit, 0), COALESCE(tempo_hari, 30),
+			COALESCE((SELECT SUM(pt.jumlah - pt.terbayar) FROM piutang pt WHERE pt.pelanggan_id = pelanggan.id AND pt.status = 'belum_lunas'), 0), created_at, updated_at
 		FROM pelanggan
 		WHERE deleted_at IS NULL
 		ORDER BY nama ASC
@@ -116,6 +118,10 @@ func (r *PelangganRepository) GetAll() ([]*models.Pelanggan, error) {
 			&diskonPersen,
 			&p.TotalTransaksi,
 			&p.TotalBelanja,
+			&p.Saldo,
+			&p.LimitKredit,
+			&p.TempoHari,
+			&p.Piutang,
 			&p.CreatedAt,
 			&p.UpdatedAt,
 		)
@@ -149,7 +155,9 @@ func (r *PelangganRepository) GetByID(id int64) (*models.Pelanggan, error) {
 	query := `
 		SELECT
 			id, nama, telepon, email, alamat, level, tipe, poin, diskon_persen,
-			total_transaksi, total_belanja, created_at, updated_at
+			total_transaksi, total_belanja, COALESCE(saldo, 0),
+			COALESCE(limit_kredit, 0), COALESCE(tempo_hari, 30),
+			COALESCE((SELECT SUM(pt.jumlah - pt.terbayar) FROM piutang pt WHERE pt.pelanggan_id = pelanggan.id AND pt.status = 'belum_lunas'), 0), created_at, updated_at
 		FROM pelanggan
 		WHERE id = ? AND deleted_at IS NULL
 	`
@@ -170,6 +178,10 @@ func (r *PelangganRepository) GetByID(id int64) (*models.Pelanggan, error) {
 		&diskonPersen,
 		&p.TotalTransaksi,
 		&p.TotalBelanja,
+		&p.Saldo,
+		&p.LimitKredit,
+		&p.TempoHari,
+		&p.Piutang,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	)
@@ -204,7 +216,9 @@ func (r *PelangganRepository) GetByTelepon(telepon string) (*models.Pelanggan, e
 	query := `
 		SELECT
 			id, nama, telepon, email, alamat, level, tipe, poin, diskon_persen,
-			total_transaksi, total_belanja, created_at, updated_at
+			total_transaksi, total_belanja, COALESCE(saldo, 0),
+			COALESCE(limit_kredit, 0), COALESCE(tempo_hari, 30),
+			COALESCE((SELECT SUM(pt.jumlah - pt.terbayar) FROM piutang pt WHERE pt.pelanggan_id = pelanggan.id AND pt.status = 'belum_lunas'), 0), created_at, updated_at
 		FROM pelanggan
 		WHERE telepon = ? AND deleted_at IS NULL
 	`
@@ -225,6 +239,10 @@ func (r *PelangganRepository) GetByTelepon(telepon string) (*models.Pelanggan, e
 		&diskonPersen,
 		&p.TotalTransaksi,
 		&p.TotalBelanja,
+		&p.Saldo,
+		&p.LimitKredit,
+		&p.TempoHari,
+		&p.Piutang,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	)
@@ -397,6 +415,56 @@ func (r *PelangganRepository) IncrementStats(id int64, totalBelanja int) error {
 	return nil
 }
 
+// DecrementStats reverses one transaction from pelanggan statistics (used when a sale is voided)
+func (r *PelangganRepository) DecrementStats(id int64, totalBelanja int) error {
+	query := `
+		UPDATE pelanggan
+		SET total_transaksi = CASE WHEN total_transaksi > 0 THEN total_transaksi - 1 ELSE 0 END,
+		    total_belanja = CASE WHEN total_belanja > ? THEN total_belanja - ? ELSE 0 END
+		WHERE id = ?
+	`
+
+	result, err := database.Exec(query, totalBelanja, totalBelanja, id)
+	if err != nil {
+		return fmt.Errorf("failed to decrement stats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("pelanggan not found")
+	}
+
+	return nil
+}
+
+// UpdateKredit sets the credit limit and default credit term of a customer
+func (r *PelangganRepository) UpdateKredit(id int64, limitKredit int, tempoHari int) error {
+	query := `
+		UPDATE pelanggan
+		SET limit_kredit = ?, tempo_hari = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := database.Exec(query, limitKredit, tempoHari, id)
+	if err != nil {
+		return fmt.Errorf("failed to update credit terms: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pelanggan not found")
+	}
+
+	return nil
+}
+
 // Delete soft-deletes a pelanggan (sets deleted_at timestamp)
 func (r *PelangganRepository) Delete(id int64) error {
 	query := `
@@ -452,7 +520,9 @@ func (r *PelangganRepository) GetDeleted() ([]*models.Pelanggan, error) {
 	query := `
 		SELECT
 			id, nama, telepon, email, alamat, level, tipe, poin,
-			total_transaksi, total_belanja, created_at, updated_at
+			total_transaksi, total_belanja, COALESCE(saldo, 0),
+			COALESCE(limit_kredit, 0), COALESCE(tempo_hari, 30),
+			COALESCE((SELECT SUM(pt.jumlah - pt.terbayar) FROM piutang pt WHERE pt.pelanggan_id = pelanggan.id AND pt.status = 'belum_lunas'), 0), created_at, updated_at
 		FROM pelanggan
 		WHERE deleted_at IS NOT NULL
 		ORDER BY deleted_at DESC
@@ -481,6 +551,10 @@ func (r *PelangganRepository) GetDeleted() ([]*models.Pelanggan, error) {
 			&p.Poin,
 			&p.TotalTransaksi,
 			&p.TotalBelanja,
+			&p.Saldo,
+			&p.LimitKredit,
+			&p.TempoHari,
+			&p.Piutang,
 			&p.CreatedAt,
 			&p.UpdatedAt,
 		)
@@ -511,7 +585,9 @@ func (r *PelangganRepository) GetByTipe(tipe string) ([]*models.Pelanggan, error
 	query := `
 		SELECT
 			id, nama, telepon, email, alamat, level, tipe, poin,
-			total_transaksi, total_belanja, created_at, updated_at
+			total_transaksi, total_belanja, COALESCE(saldo, 0),
+			COALESCE(limit_kredit, 0), COALESCE(tempo_hari, 30),
+			COALESCE((SELECT SUM(pt.jumlah - pt.terbayar) FROM piutang pt WHERE pt.pelanggan_id = pelanggan.id AND pt.status = 'belum_lunas'), 0), created_at, updated_at
 		FROM pelanggan
 		WHERE tipe = ? AND deleted_at IS NULL
 		ORDER BY nama ASC
@@ -540,6 +616,10 @@ func (r *PelangganRepository) GetByTipe(tipe string) ([]*models.Pelanggan, error
 			&p.Poin,
 			&p.TotalTransaksi,
 			&p.TotalBelanja,
+			&p.Saldo,
+			&p.LimitKredit,
+			&p.TempoHari,
+			&p.Piutang,
 			&p.CreatedAt,
 			&p.UpdatedAt,
 		)
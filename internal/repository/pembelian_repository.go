@@ -0,0 +1,604 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// PembelianRepository handles purchase orders and goods receipts
+type PembelianRepository struct {
+	batchRepo *BatchRepository
+}
+
+// NewPembelianRepository creates a new repository instance
+func NewPembelianRepository() *PembelianRepository {
+	return &PembelianRepository{
+		batchRepo: NewBatchRepository(),
+	}
+}
+
+// CreatePesanan saves a new draft purchase order with its lines and assigns its number
+func (r *PembelianRepository) CreatePesanan(po *models.PesananPembelian) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	nomor, err := reserveNomorDokumen(tx, JenisDokumenPO, "pesanan_pembelian", "nomor_po")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	po.NomorPO = nomor
+	po.Status = models.StatusPODraft
+	po.Tanggal = now
+	po.CreatedAt = now
+	po.UpdatedAt = now
+	dualMode := database.UseDualMode && database.IsSQLite()
+
+	if dualMode {
+		po.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO pesanan_pembelian (id, nomor_po, supplier_id, tanggal, tanggal_diharapkan, status, total, catatan, dibuat_oleh, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), po.ID, po.NomorPO, po.SupplierID, po.Tanggal, po.TanggalDiharapkan, po.Status, po.Total, po.Catatan, po.DibuatOleh, now, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO pesanan_pembelian (nomor_po, supplier_id, tanggal, tanggal_diharapkan, status, total, catatan, dibuat_oleh, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), po.NomorPO, po.SupplierID, po.Tanggal, po.TanggalDiharapkan, po.Status, po.Total, po.Catatan, po.DibuatOleh, now, now).Scan(&po.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create purchase order: %w", err)
+	}
+
+	if err := r.insertPesananItems(tx, po); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdatePesanan replaces the header and lines of a purchase order that is still a draft
+func (r *PembelianRepository) UpdatePesanan(po *models.PesananPembelian) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	po.UpdatedAt = time.Now().UTC()
+	result, err := tx.Exec(database.TranslateQuery(`
+		UPDATE pesanan_pembelian
+		SET supplier_id = ?, tanggal_diharapkan = ?, total = ?, catatan = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`), po.SupplierID, po.TanggalDiharapkan, po.Total, po.Catatan, po.UpdatedAt, po.ID, models.StatusPODraft)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pesanan pembelian tidak ditemukan atau sudah bukan draft")
+	}
+
+	if _, err := tx.Exec(database.TranslateQuery(`DELETE FROM pesanan_pembelian_item WHERE pesanan_id = ?`), po.ID); err != nil {
+		return fmt.Errorf("failed to delete purchase order items: %w", err)
+	}
+	if err := r.insertPesananItems(tx, po); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *PembelianRepository) insertPesananItems(tx *sql.Tx, po *models.PesananPembelian) error {
+	dualMode := database.UseDualMode && database.IsSQLite()
+
+	for _, item := range po.Items {
+		item.PesananID = po.ID
+
+		var err error
+		if dualMode {
+			item.ID = database.GenerateOfflineID()
+			_, err = tx.Exec(database.TranslateQuery(`
+				INSERT INTO pesanan_pembelian_item (id, pesanan_id, produk_id, produk_nama, qty_pesan, qty_diterima, harga_beli, subtotal)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			`), item.ID, item.PesananID, item.ProdukID, item.ProdukNama, item.QtyPesan, item.QtyDiterima, item.HargaBeli, item.Subtotal)
+		} else {
+			err = tx.QueryRow(database.TranslateQuery(`
+				INSERT INTO pesanan_pembelian_item (pesanan_id, produk_id, produk_nama, qty_pesan, qty_diterima, harga_beli, subtotal)
+				VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id
+			`), item.PesananID, item.ProdukID, item.ProdukNama, item.QtyPesan, item.QtyDiterima, item.HargaBeli, item.Subtotal).Scan(&item.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save purchase order item: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateStatusPesanan moves a purchase order to status, but only from one of the allowed statuses
+func (r *PembelianRepository) UpdateStatusPesanan(id int64, status string, dari []string) error {
+	placeholders := make([]string, len(dari))
+	args := []interface{}{status, time.Now().UTC(), id}
+	for i, s := range dari {
+		placeholders[i] = "?"
+		args = append(args, s)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE pesanan_pembelian SET status = ?, updated_at = ?
+		WHERE id = ? AND status IN (%s)
+	`, strings.Join(placeholders, ", "))
+	result, err := database.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("status pesanan pembelian tidak dapat diubah menjadi %s", status)
+	}
+	return nil
+}
+
+// GetAllPesanan retrieves purchase orders newest first, optionally filtered by status
+func (r *PembelianRepository) GetAllPesanan(status string) ([]*models.PesananPembelian, error) {
+	query := `
+		SELECT po.id, po.nomor_po, po.supplier_id, COALESCE(s.nama, ''), po.tanggal, po.tanggal_diharapkan,
+		       po.status, po.total, po.catatan, po.dibuat_oleh, po.created_at, po.updated_at
+		FROM pesanan_pembelian po
+		LEFT JOIN supplier s ON s.id = po.supplier_id
+	`
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE po.status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY po.tanggal DESC, po.id DESC`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase orders: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.PesananPembelian{}
+	for rows.Next() {
+		po, err := scanPesananPembelian(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order: %w", err)
+		}
+		list = append(list, po)
+	}
+
+	return list, nil
+}
+
+// GetPesananByID retrieves a purchase order with its lines
+func (r *PembelianRepository) GetPesananByID(id int64) (*models.PesananPembelian, error) {
+	query := `
+		SELECT po.id, po.nomor_po, po.supplier_id, COALESCE(s.nama, ''), po.tanggal, po.tanggal_diharapkan,
+		       po.status, po.total, po.catatan, po.dibuat_oleh, po.created_at, po.updated_at
+		FROM pesanan_pembelian po
+		LEFT JOIN supplier s ON s.id = po.supplier_id
+		WHERE po.id = ?
+	`
+
+	po, err := scanPesananPembelian(database.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+
+	rows, err := database.Query(`
+		SELECT id, pesanan_id, produk_id, produk_nama, qty_pesan, qty_diterima, harga_beli, subtotal
+		FROM pesanan_pembelian_item
+		WHERE pesanan_id = ?
+		ORDER BY id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase order items: %w", err)
+	}
+	defer rows.Close()
+
+	po.Items = []*models.PesananPembelianItem{}
+	for rows.Next() {
+		item := &models.PesananPembelianItem{}
+		if err := rows.Scan(&item.ID, &item.PesananID, &item.ProdukID, &item.ProdukNama, &item.QtyPesan,
+			&item.QtyDiterima, &item.HargaBeli, &item.Subtotal); err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order item: %w", err)
+		}
+		po.Items = append(po.Items, item)
+	}
+
+	return po, nil
+}
+
+func scanPesananPembelian(row rowScanner) (*models.PesananPembelian, error) {
+	po := &models.PesananPembelian{}
+	var tanggalDiharapkan, catatan, dibuatOleh sql.NullString
+	err := row.Scan(&po.ID, &po.NomorPO, &po.SupplierID, &po.SupplierNama, &po.Tanggal, &tanggalDiharapkan,
+		&po.Status, &po.Total, &catatan, &dibuatOleh, &po.CreatedAt, &po.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	po.TanggalDiharapkan = tanggalDiharapkan.String
+	po.Catatan = catatan.String
+	po.DibuatOleh = dibuatOleh.String
+	return po, nil
+}
+
+// OpsiPenerimaan controls the side effects of CreatePenerimaan besides stock, batches and history
+type OpsiPenerimaan struct {
+	MetodeHargaBeli    string // models.MetodeHargaBeli*: how produk.harga_beli follows the received cost
+	KosongkanKeranjang bool   // Clear the scan cart in the same transaction
+}
+
+// CreatePenerimaan records a goods receipt in one transaction: it numbers the receipt, adds
+// stock, puts each line into an expiry batch carrying the real cost, writes stock history,
+// optionally updates product costs and, for a purchase order, marks the received quantities
+// so the order becomes sebagian or selesai.
+func (r *PembelianRepository) CreatePenerimaan(p *models.PenerimaanBarang, opsi OpsiPenerimaan) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if p.PesananID != 0 {
+		// Status pesanan dicek ulang di dalam transaksi agar dua penerimaan bersamaan tidak lolos
+		statusQuery := `SELECT status FROM pesanan_pembelian WHERE id = ?`
+		if database.IsPostgreSQL() {
+			statusQuery += ` FOR UPDATE`
+		}
+		var status string
+		err = tx.QueryRow(database.TranslateQuery(statusQuery), p.PesananID).Scan(&status)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("pesanan pembelian tidak ditemukan")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read purchase order status: %w", err)
+		}
+		if status != models.StatusPODipesan && status != models.StatusPOSebagian {
+			return fmt.Errorf("pesanan pembelian berstatus %s tidak dapat menerima barang", status)
+		}
+	}
+
+	nomor, err := reserveNomorDokumen(tx, JenisDokumenPenerimaan, "penerimaan_barang", "nomor_penerimaan")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	p.NomorPenerimaan = nomor
+	p.Tanggal = now
+	p.CreatedAt = now
+	dualMode := database.UseDualMode && database.IsSQLite()
+
+	var pesananID, supplierID interface{}
+	if p.PesananID != 0 {
+		pesananID = p.PesananID
+	}
+	if p.SupplierID != 0 {
+		supplierID = p.SupplierID
+	}
+
+	if dualMode {
+		p.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO penerimaan_barang (id, nomor_penerimaan, pesanan_id, supplier_id, tanggal, no_faktur, total, catatan, diterima_oleh, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), p.ID, p.NomorPenerimaan, pesananID, supplierID, p.Tanggal, p.NoFaktur, p.Total, p.Catatan, p.DiterimaOleh, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO penerimaan_barang (nomor_penerimaan, pesanan_id, supplier_id, tanggal, no_faktur, total, catatan, diterima_oleh, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), p.NomorPenerimaan, pesananID, supplierID, p.Tanggal, p.NoFaktur, p.Total, p.Catatan, p.DiterimaOleh, now).Scan(&p.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create goods receipt: %w", err)
+	}
+
+	restockDate := TanggalRestokHariIni()
+
+	keterangan := "Penerimaan " + p.NomorPenerimaan
+	if p.NomorPO != "" {
+		keterangan += fmt.Sprintf(" (PO %s)", p.NomorPO)
+	}
+	if p.NoFaktur != "" {
+		keterangan += ", faktur " + p.NoFaktur
+	}
+
+	for _, item := range p.Items {
+		item.PenerimaanID = p.ID
+
+		// 1. Tandai jumlah diterima pada pesanan, tidak boleh melebihi sisa pesanan
+		var pesananItemID interface{}
+		if item.PesananItemID != 0 {
+			pesananItemID = item.PesananItemID
+			result, err := tx.Exec(database.TranslateQuery(`
+				UPDATE pesanan_pembelian_item SET qty_diterima = qty_diterima + ?
+				WHERE id = ? AND pesanan_id = ? AND qty_diterima + ? <= qty_pesan + 0.0001
+			`), item.Qty, item.PesananItemID, p.PesananID, item.Qty)
+			if err != nil {
+				return fmt.Errorf("failed to update received quantity: %w", err)
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if rowsAffected == 0 {
+				return fmt.Errorf("jumlah diterima untuk %s melebihi sisa pesanan", item.ProdukNama)
+			}
+		}
+
+		// 2. Tambah stok produk dan perbarui harga beli sesuai pengaturan
+		// Baris produk dikunci agar penjualan bersamaan tidak tertimpa oleh stok absolut di bawah
+		stokQuery := `SELECT stok, harga_beli FROM produk WHERE id = ? AND deleted_at IS NULL`
+		if database.IsPostgreSQL() {
+			stokQuery += ` FOR UPDATE`
+		}
+		var stokSebelum float64
+		var hargaBeliLama int
+		err = tx.QueryRow(database.TranslateQuery(stokQuery), item.ProdukID).
+			Scan(&stokSebelum, &hargaBeliLama)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("produk %s tidak ditemukan", item.ProdukNama)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read product stock: %w", err)
+		}
+		stokSesudah := stokSebelum + item.Qty
+		hargaBeliBaru := hargaBeliSetelahPenerimaan(opsi.MetodeHargaBeli, hargaBeliLama, stokSebelum, item.HargaBeli, item.Qty)
+
+		if _, err := tx.Exec(database.TranslateQuery(`UPDATE produk SET stok = ?, harga_beli = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`),
+			stokSesudah, hargaBeliBaru, item.ProdukID); err != nil {
+			return fmt.Errorf("failed to update stock for product %s: %w", item.ProdukNama, err)
+		}
+		if hargaBeliBaru != hargaBeliLama {
+			p.PerubahanHarga = append(p.PerubahanHarga, &models.PerubahanHargaBeli{
+				ProdukID:   item.ProdukID,
+				ProdukNama: item.ProdukNama,
+				Sebelum:    hargaBeliLama,
+				Sesudah:    hargaBeliBaru,
+			})
+		}
+
+		// 3. Batch kedaluwarsa dengan harga beli sebenarnya, digabung dengan restok hari ini yang sama
+		if item.MasaSimpanHari > 0 {
+			batch, err := r.batchRepo.RestokBatchTx(tx, &models.Batch{
+				ProdukID:       item.ProdukID,
+				Qty:            item.Qty,
+				TanggalRestok:  restockDate,
+				MasaSimpanHari: item.MasaSimpanHari,
+				Supplier:       p.SupplierNama,
+				Keterangan:     keterangan,
+				HargaBeli:      item.HargaBeli,
+				PenerimaanID:   p.ID,
+			})
+			if err != nil {
+				return err
+			}
+			item.BatchID = batch.ID
+			item.TanggalKadaluarsa = batch.TanggalKadaluarsa.Format("2006-01-02")
+		}
+
+		// 4. Simpan baris penerimaan
+		if dualMode {
+			item.ID = database.GenerateOfflineID()
+			_, err = tx.Exec(database.TranslateQuery(`
+				INSERT INTO penerimaan_barang_item (id, penerimaan_id, pesanan_item_id, produk_id, produk_nama, qty, harga_beli, subtotal,
+					masa_simpan_hari, tanggal_kadaluarsa, batch_id)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`), item.ID, item.PenerimaanID, pesananItemID, item.ProdukID, item.ProdukNama, item.Qty, item.HargaBeli, item.Subtotal,
+				item.MasaSimpanHari, item.TanggalKadaluarsa, item.BatchID)
+		} else {
+			err = tx.QueryRow(database.TranslateQuery(`
+				INSERT INTO penerimaan_barang_item (penerimaan_id, pesanan_item_id, produk_id, produk_nama, qty, harga_beli, subtotal,
+					masa_simpan_hari, tanggal_kadaluarsa, batch_id)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+			`), item.PenerimaanID, pesananItemID, item.ProdukID, item.ProdukNama, item.Qty, item.HargaBeli, item.Subtotal,
+				item.MasaSimpanHari, item.TanggalKadaluarsa, item.BatchID).Scan(&item.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save goods receipt item: %w", err)
+		}
+
+		// 5. Riwayat stok
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO stok_history (
+				produk_id, stok_sebelum, stok_sesudah, perubahan,
+				jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`), item.ProdukID, stokSebelum, stokSesudah, item.Qty, "pembelian", keterangan, "", 0)
+		if err != nil {
+			return fmt.Errorf("failed to create stock history: %w", err)
+		}
+	}
+
+	if p.PesananID != 0 {
+		// Status pesanan: selesai jika semua baris sudah diterima penuh
+		var sisa int
+		err = tx.QueryRow(database.TranslateQuery(`
+			SELECT COUNT(*) FROM pesanan_pembelian_item
+			WHERE pesanan_id = ? AND qty_diterima < qty_pesan - 0.0001
+		`), p.PesananID).Scan(&sisa)
+		if err != nil {
+			return fmt.Errorf("failed to check remaining order quantity: %w", err)
+		}
+		statusBaru := models.StatusPOSebagian
+		if sisa == 0 {
+			statusBaru = models.StatusPOSelesai
+		}
+		if _, err := tx.Exec(database.TranslateQuery(`UPDATE pesanan_pembelian SET status = ?, updated_at = ? WHERE id = ?`),
+			statusBaru, now, p.PesananID); err != nil {
+			return fmt.Errorf("failed to update purchase order status: %w", err)
+		}
+	}
+
+	if opsi.KosongkanKeranjang {
+		if _, err := tx.Exec(database.TranslateQuery(`DELETE FROM keranjang`)); err != nil {
+			return fmt.Errorf("failed to clear cart: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// hargaBeliSetelahPenerimaan returns the product cost after receiving qty at hargaBeli.
+// The weighted average ignores negative stock so an oversold product takes the new cost.
+func hargaBeliSetelahPenerimaan(metode string, hargaLama int, stokLama float64, hargaBeli int, qty float64) int {
+	switch metode {
+	case models.MetodeHargaBeliTerakhir:
+		return hargaBeli
+	case models.MetodeHargaBeliRataRata:
+		if stokLama < 0 {
+			stokLama = 0
+		}
+		if stokLama+qty <= 0 {
+			return hargaLama
+		}
+		return int(math.Round((stokLama*float64(hargaLama) + qty*float64(hargaBeli)) / (stokLama + qty)))
+	}
+	return hargaLama
+}
+
+// GetAllPenerimaan retrieves goods receipts within a date range, newest first, without lines
+func (r *PembelianRepository) GetAllPenerimaan(startDate, endDate time.Time) ([]*models.PenerimaanBarang, error) {
+	return r.queryPenerimaan(`WHERE pb.tanggal >= ? AND pb.tanggal <= ? ORDER BY pb.tanggal DESC, pb.id DESC`,
+		startDate.UTC(), endDate.UTC())
+}
+
+// GetPenerimaanByID retrieves a goods receipt with its lines
+func (r *PembelianRepository) GetPenerimaanByID(id int64) (*models.PenerimaanBarang, error) {
+	list, err := r.queryPenerimaan(`WHERE pb.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	if list[0].Items, err = r.getPenerimaanItems(id); err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// GetPenerimaanByPesanan retrieves the goods receipts of a purchase order with their lines
+func (r *PembelianRepository) GetPenerimaanByPesanan(pesananID int64) ([]*models.PenerimaanBarang, error) {
+	list, err := r.queryPenerimaan(`WHERE pb.pesanan_id = ? ORDER BY pb.tanggal ASC, pb.id ASC`, pesananID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range list {
+		if p.Items, err = r.getPenerimaanItems(p.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (r *PembelianRepository) queryPenerimaan(kondisi string, args ...interface{}) ([]*models.PenerimaanBarang, error) {
+	rows, err := database.Query(`
+		SELECT pb.id, pb.nomor_penerimaan, COALESCE(pb.pesanan_id, 0), COALESCE(po.nomor_po, ''),
+		       COALESCE(pb.supplier_id, 0), COALESCE(s.nama, ''), pb.tanggal, pb.no_faktur, pb.total,
+		       pb.catatan, pb.diterima_oleh, pb.created_at
+		FROM penerimaan_barang pb
+		LEFT JOIN pesanan_pembelian po ON po.id = pb.pesanan_id
+		LEFT JOIN supplier s ON s.id = pb.supplier_id
+		`+kondisi, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goods receipts: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.PenerimaanBarang{}
+	for rows.Next() {
+		p := &models.PenerimaanBarang{}
+		var noFaktur, catatan, diterimaOleh sql.NullString
+		if err := rows.Scan(&p.ID, &p.NomorPenerimaan, &p.PesananID, &p.NomorPO, &p.SupplierID, &p.SupplierNama,
+			&p.Tanggal, &noFaktur, &p.Total, &catatan, &diterimaOleh, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan goods receipt: %w", err)
+		}
+		p.NoFaktur = noFaktur.String
+		p.Catatan = catatan.String
+		p.DiterimaOleh = diterimaOleh.String
+		list = append(list, p)
+	}
+
+	return list, nil
+}
+
+func (r *PembelianRepository) getPenerimaanItems(penerimaanID int64) ([]*models.PenerimaanBarangItem, error) {
+	rows, err := database.Query(`
+		SELECT id, penerimaan_id, COALESCE(pesanan_item_id, 0), produk_id, produk_nama, qty, harga_beli, subtotal,
+		       COALESCE(masa_simpan_hari, 0), tanggal_kadaluarsa, batch_id
+		FROM penerimaan_barang_item
+		WHERE penerimaan_id = ?
+		ORDER BY id ASC
+	`, penerimaanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goods receipt items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*models.PenerimaanBarangItem{}
+	for rows.Next() {
+		item := &models.PenerimaanBarangItem{}
+		var tanggalKadaluarsa, batchID sql.NullString
+		if err := rows.Scan(&item.ID, &item.PenerimaanID, &item.PesananItemID, &item.ProdukID, &item.ProdukNama,
+			&item.Qty, &item.HargaBeli, &item.Subtotal, &item.MasaSimpanHari, &tanggalKadaluarsa, &batchID); err != nil {
+			return nil, fmt.Errorf("failed to scan goods receipt item: %w", err)
+		}
+		item.TanggalKadaluarsa = tanggalKadaluarsa.String
+		item.BatchID = batchID.String
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// PiutangRepository handles database operations for credit sales and their repayments
+type PiutangRepository struct{}
+
+// NewPiutangRepository creates a new repository instance
+func NewPiutangRepository() *PiutangRepository {
+	return &PiutangRepository{}
+}
+
+// CreateTx records the credit part of a sale inside the transaction insert.
+// The customer's credit limit is checked against the outstanding balance within the same transaction.
+func (r *PiutangRepository) CreateTx(tx *sql.Tx, p *models.Piutang) error {
+	var limitKredit, outstanding int
+	err := tx.QueryRow(database.TranslateQuery(`
+		SELECT COALESCE(limit_kredit, 0),
+			COALESCE((SELECT SUM(jumlah - terbayar) FROM piutang WHERE pelanggan_id = ? AND status = 'belum_lunas'), 0)
+		FROM pelanggan WHERE id = ? AND deleted_at IS NULL
+	`), p.PelangganID, p.PelangganID).Scan(&limitKredit, &outstanding)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("pelanggan tidak ditemukan")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get customer credit: %w", err)
+	}
+	if outstanding+p.Jumlah > limitKredit {
+		return fmt.Errorf("limit kredit pelanggan terlampaui (limit: Rp %d, piutang: Rp %d)", limitKredit, outstanding)
+	}
+
+	now := time.Now().UTC()
+	p.Status = "belum_lunas"
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	if database.UseDualMode && database.IsSQLite() {
+		p.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO piutang (id, transaksi_id, nomor_transaksi, pelanggan_id, jumlah, terbayar, jatuh_tempo, status, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?, ?)
+		`), p.ID, p.TransaksiID, p.NomorTransaksi, p.PelangganID, p.Jumlah, p.JatuhTempo, p.Status, now, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO piutang (transaksi_id, nomor_transaksi, pelanggan_id, jumlah, terbayar, jatuh_tempo, status, created_at, updated_at)
+			VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?) RETURNING id
+		`), p.TransaksiID, p.NomorTransaksi, p.PelangganID, p.Jumlah, p.JatuhTempo, p.Status, now, now).Scan(&p.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create receivable: %w", err)
+	}
+
+	p.Sisa = p.Jumlah
+	return nil
+}
+
+// VoidTx cancels the receivable of a voided sale and returns how much had already been repaid
+func (r *PiutangRepository) VoidTx(tx *sql.Tx, transaksiID int64) (int, error) {
+	var terbayar int
+	err := tx.QueryRow(database.TranslateQuery(`
+		SELECT COALESCE(SUM(terbayar), 0) FROM piutang WHERE transaksi_id = ? AND status != 'void'
+	`), transaksiID).Scan(&terbayar)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get receivable: %w", err)
+	}
+
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE piutang SET status = 'void', updated_at = ? WHERE transaksi_id = ? AND status != 'void'
+	`), time.Now().UTC(), transaksiID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to void receivable: %w", err)
+	}
+
+	return terbayar, nil
+}
+
+// Bayar applies a repayment to one receivable, or to the oldest outstanding receivables of the customer.
+// Every allocation is stored as its own repayment row.
+func (r *PiutangRepository) Bayar(req *models.BayarPiutangRequest) ([]*models.PembayaranPiutang, error) {
+	db := database.DB
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, nomor_transaksi, jumlah - terbayar
+		FROM piutang
+		WHERE pelanggan_id = ? AND status = 'belum_lunas'
+	`
+	args := []interface{}{req.PelangganID}
+	if req.PiutangID != 0 {
+		query += ` AND id = ?`
+		args = append(args, req.PiutangID)
+	}
+	query += ` ORDER BY jatuh_tempo ASC, created_at ASC`
+
+	rows, err := tx.Query(database.TranslateQuery(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outstanding receivables: %w", err)
+	}
+	type outstanding struct {
+		ID             int64
+		NomorTransaksi string
+		Sisa           int
+	}
+	var list []outstanding
+	totalSisa := 0
+	for rows.Next() {
+		var o outstanding
+		if err := rows.Scan(&o.ID, &o.NomorTransaksi, &o.Sisa); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan receivable: %w", err)
+		}
+		list = append(list, o)
+		totalSisa += o.Sisa
+	}
+	rows.Close()
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf("tidak ada piutang yang belum lunas")
+	}
+	if req.Jumlah > totalSisa {
+		return nil, fmt.Errorf("pembayaran melebihi sisa piutang (sisa: Rp %d)", totalSisa)
+	}
+
+	now := time.Now().UTC()
+	var staffID interface{}
+	if req.StaffID != 0 {
+		staffID = req.StaffID
+	}
+
+	var hasil []*models.PembayaranPiutang
+	sisaBayar := req.Jumlah
+	for _, o := range list {
+		if sisaBayar == 0 {
+			break
+		}
+		bagian := o.Sisa
+		if bagian > sisaBayar {
+			bagian = sisaBayar
+		}
+
+		result, err := tx.Exec(database.TranslateQuery(`
+			UPDATE piutang
+			SET terbayar = terbayar + ?,
+				status = CASE WHEN terbayar + ? >= jumlah THEN 'lunas' ELSE status END,
+				updated_at = ?
+			WHERE id = ? AND status = 'belum_lunas' AND jumlah - terbayar >= ?
+		`), bagian, bagian, now, o.ID, bagian)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update receivable: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return nil, fmt.Errorf("piutang %s berubah saat diproses, silakan ulangi", o.NomorTransaksi)
+		}
+
+		p := &models.PembayaranPiutang{
+			PiutangID:      o.ID,
+			PelangganID:    req.PelangganID,
+			NomorTransaksi: o.NomorTransaksi,
+			Jumlah:         bagian,
+			Metode:         req.Metode,
+			Referensi:      req.Referensi,
+			StaffID:        req.StaffID,
+			StaffNama:      req.StaffNama,
+			CreatedAt:      now,
+		}
+		if database.UseDualMode && database.IsSQLite() {
+			p.ID = database.GenerateOfflineID()
+			_, err = tx.Exec(database.TranslateQuery(`
+				INSERT INTO pembayaran_piutang (id, piutang_id, pelanggan_id, jumlah, metode, referensi, staff_id, staff_nama, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`), p.ID, p.PiutangID, p.PelangganID, p.Jumlah, p.Metode, p.Referensi, staffID, p.StaffNama, now)
+		} else {
+			err = tx.QueryRow(database.TranslateQuery(`
+				INSERT INTO pembayaran_piutang (piutang_id, pelanggan_id, jumlah, metode, referensi, staff_id, staff_nama, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+			`), p.PiutangID, p.PelangganID, p.Jumlah, p.Metode, p.Referensi, staffID, p.StaffNama, now).Scan(&p.ID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to record repayment: %w", err)
+		}
+
+		hasil = append(hasil, p)
+		sisaBayar -= bagian
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return hasil, nil
+}
+
+// GetAll retrieves receivables, optionally filtered by customer and status
+func (r *PiutangRepository) GetAll(pelangganID int64, status string) ([]*models.Piutang, error) {
+	query := `
+		SELECT pt.id, pt.transaksi_id, pt.nomor_transaksi, pt.pelanggan_id, COALESCE(p.nama, ''),
+			pt.jumlah, pt.terbayar, pt.jatuh_tempo, pt.status, pt.created_at, pt.updated_at
+		FROM piutang pt
+		LEFT JOIN pelanggan p ON p.id = pt.pelanggan_id
+		WHERE 1 = 1
+	`
+	var args []interface{}
+	if pelangganID != 0 {
+		query += ` AND pt.pelanggan_id = ?`
+		args = append(args, pelangganID)
+	}
+	if status != "" {
+		query += ` AND pt.status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY pt.jatuh_tempo ASC, pt.created_at ASC`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receivables: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.Piutang
+	for rows.Next() {
+		var p models.Piutang
+		err := rows.Scan(
+			&p.ID, &p.TransaksiID, &p.NomorTransaksi, &p.PelangganID, &p.PelangganNama,
+			&p.Jumlah, &p.Terbayar, &p.JatuhTempo, &p.Status, &p.CreatedAt, &p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan receivable: %w", err)
+		}
+		p.Sisa = p.Jumlah - p.Terbayar
+		list = append(list, &p)
+	}
+
+	return list, nil
+}
+
+// GetPembayaranByDateRange retrieves repayments received within a date range
+func (r *PiutangRepository) GetPembayaranByDateRange(startDate, endDate time.Time) ([]*models.PembayaranPiutang, error) {
+	query := `
+		SELECT pp.id, pp.piutang_id, pp.pelanggan_id, COALESCE(pt.nomor_transaksi, ''), pp.jumlah, pp.metode,
+			COALESCE(pp.referensi, ''), COALESCE(pp.staff_id, 0), COALESCE(pp.staff_nama, ''), pp.created_at
+		FROM pembayaran_piutang pp
+		LEFT JOIN piutang pt ON pt.id = pp.piutang_id
+		WHERE pp.created_at >= ? AND pp.created_at <= ?
+		ORDER BY pp.created_at ASC
+	`
+
+	rows, err := database.Query(query, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repayments: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.PembayaranPiutang
+	for rows.Next() {
+		var p models.PembayaranPiutang
+		err := rows.Scan(
+			&p.ID, &p.PiutangID, &p.PelangganID, &p.NomorTransaksi, &p.Jumlah, &p.Metode,
+			&p.Referensi, &p.StaffID, &p.StaffNama, &p.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan repayment: %w", err)
+		}
+		list = append(list, &p)
+	}
+
+	return list, nil
+}
@@ -26,8 +26,8 @@ func (r *ProdukRepository) Create(produk *models.Produk) error {
 		INSERT INTO produk (
 			sku, barcode, nama, kategori, berat, harga_beli, harga_jual,
 			stok, satuan, jenis_produk, kadaluarsa, tanggal_masuk, deskripsi, gambar,
-			hari_pemberitahuan_kadaluarsa, masa_simpan_hari
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			hari_pemberitahuan_kadaluarsa, masa_simpan_hari, tarif_pajak
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var id int64
@@ -53,6 +53,7 @@ func (r *ProdukRepository) Create(produk *models.Produk) error {
 			produk.Gambar,
 			produk.HariPemberitahuanKadaluarsa,
 			produk.MasaSimpanHari,
+			produk.TarifPajak,
 		)
 		if execErr != nil {
 			return fmt.Errorf("failed to insert product: %w", execErr)
@@ -83,6 +84,7 @@ func (r *ProdukRepository) Create(produk *models.Produk) error {
 			produk.Gambar,
 			produk.HariPemberitahuanKadaluarsa,
 			produk.MasaSimpanHari,
+			produk.TarifPajak,
 		).Scan(&id)
 
 		if err != nil {
@@ -102,7 +104,7 @@ func (r *ProdukRepository) GetByBarcode(barcode string) (*models.Produk, error)
 	query := `
 		SELECT id, sku, barcode, nama, kategori, berat, harga_beli, harga_jual,
 		       stok, satuan, jenis_produk, kadaluarsa, tanggal_masuk, deskripsi, gambar,
-		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari,
+		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari, tarif_pajak,
 		       created_at, updated_at
 		FROM produk
 		WHERE barcode = ? AND deleted_at IS NULL
@@ -110,6 +112,7 @@ func (r *ProdukRepository) GetByBarcode(barcode string) (*models.Produk, error)
 
 	produk := &models.Produk{}
 	var barcodeNull, kadaluarsa, tanggalMasuk, gambar, jenisProduk sql.NullString
+	var tarifPajak sql.NullFloat64
 
 	err := database.QueryRow(query, barcode).Scan(
 		&produk.ID,
@@ -129,6 +132,7 @@ func (r *ProdukRepository) GetByBarcode(barcode string) (*models.Produk, error)
 		&gambar,
 		&produk.HariPemberitahuanKadaluarsa,
 		&produk.MasaSimpanHari,
+		&tarifPajak,
 		&produk.CreatedAt,
 		&produk.UpdatedAt,
 	)
@@ -157,6 +161,9 @@ func (r *ProdukRepository) GetByBarcode(barcode string) (*models.Produk, error)
 	if gambar.Valid {
 		produk.Gambar = gambar.String
 	}
+	if tarifPajak.Valid {
+		produk.TarifPajak = &tarifPajak.Float64
+	}
 
 	return produk, nil
 }
@@ -166,7 +173,7 @@ func (r *ProdukRepository) GetBySKU(sku string) (*models.Produk, error) {
 	query := `
 		SELECT id, sku, barcode, nama, kategori, berat, harga_beli, harga_jual,
 		       stok, satuan, jenis_produk, kadaluarsa, tanggal_masuk, deskripsi, gambar,
-		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari,
+		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari, tarif_pajak,
 		       created_at, updated_at
 		FROM produk
 		WHERE sku = ? AND deleted_at IS NULL
@@ -174,6 +181,7 @@ func (r *ProdukRepository) GetBySKU(sku string) (*models.Produk, error) {
 
 	produk := &models.Produk{}
 	var barcodeNull, kadaluarsa, tanggalMasuk, gambar, jenisProduk sql.NullString
+	var tarifPajak sql.NullFloat64
 
 	err := database.QueryRow(query, sku).Scan(
 		&produk.ID,
@@ -193,6 +201,7 @@ func (r *ProdukRepository) GetBySKU(sku string) (*models.Produk, error) {
 		&gambar,
 		&produk.HariPemberitahuanKadaluarsa,
 		&produk.MasaSimpanHari,
+		&tarifPajak,
 		&produk.CreatedAt,
 		&produk.UpdatedAt,
 	)
@@ -221,6 +230,9 @@ func (r *ProdukRepository) GetBySKU(sku string) (*models.Produk, error) {
 	if gambar.Valid {
 		produk.Gambar = gambar.String
 	}
+	if tarifPajak.Valid {
+		produk.TarifPajak = &tarifPajak.Float64
+	}
 
 	return produk, nil
 }
@@ -230,7 +242,7 @@ func (r *ProdukRepository) GetAll() ([]*models.Produk, error) {
 	query := `
 		SELECT id, sku, barcode, nama, kategori, berat, harga_beli, harga_jual,
 		       stok, satuan, jenis_produk, kadaluarsa, tanggal_masuk, deskripsi, gambar,
-		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari,
+		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari, tarif_pajak,
 		       created_at, updated_at
 		FROM produk
 		WHERE deleted_at IS NULL
@@ -248,6 +260,7 @@ func (r *ProdukRepository) GetAll() ([]*models.Produk, error) {
 	for rows.Next() {
 		produk := &models.Produk{}
 		var barcodeNull, kadaluarsa, tanggalMasuk, gambar, jenisProduk sql.NullString
+		var tarifPajak sql.NullFloat64
 
 		err := rows.Scan(
 			&produk.ID,
@@ -267,6 +280,7 @@ func (r *ProdukRepository) GetAll() ([]*models.Produk, error) {
 			&gambar,
 			&produk.HariPemberitahuanKadaluarsa,
 			&produk.MasaSimpanHari,
+			&tarifPajak,
 			&produk.CreatedAt,
 			&produk.UpdatedAt,
 		)
@@ -292,6 +306,9 @@ func (r *ProdukRepository) GetAll() ([]*models.Produk, error) {
 		if gambar.Valid {
 			produk.Gambar = gambar.String
 		}
+		if tarifPajak.Valid {
+			produk.TarifPajak = &tarifPajak.Float64
+		}
 
 		products = append(products, produk)
 	}
@@ -304,8 +321,8 @@ func (r *ProdukRepository) CreateStokHistory(history *models.StokHistory) error
 	query := `
         INSERT INTO stok_history (
             produk_id, stok_sebelum, stok_sesudah, perubahan,
-            jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian
-        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+            jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian, disetujui_oleh
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
 
 	result, err := database.Exec(
@@ -318,6 +335,7 @@ func (r *ProdukRepository) CreateStokHistory(history *models.StokHistory) error
 		history.Keterangan,
 		history.TipeKerugian,
 		history.NilaiKerugian,
+		history.DisetujuiOleh,
 	)
 
 	if err != nil {
@@ -340,7 +358,7 @@ func (r *ProdukRepository) GetByID(id int) (*models.Produk, error) {
 	query := `
         SELECT id, sku, barcode, nama, kategori, berat, harga_beli, harga_jual,
                stok, satuan, jenis_produk, kadaluarsa, tanggal_masuk, deskripsi, gambar,
-               hari_pemberitahuan_kadaluarsa, masa_simpan_hari,
+               hari_pemberitahuan_kadaluarsa, masa_simpan_hari, tarif_pajak,
                created_at, updated_at
         FROM produk
         WHERE id = ? AND deleted_at IS NULL
@@ -348,6 +366,7 @@ func (r *ProdukRepository) GetByID(id int) (*models.Produk, error) {
 
 	produk := &models.Produk{}
 	var barcodeNull, kadaluarsa, tanggalMasuk, gambar, jenisProduk sql.NullString
+	var tarifPajak sql.NullFloat64
 
 	err := database.QueryRow(query, id).Scan(
 		&produk.ID,
@@ -367,6 +386,7 @@ func (r *ProdukRepository) GetByID(id int) (*models.Produk, error) {
 		&gambar,
 		&produk.HariPemberitahuanKadaluarsa,
 		&produk.MasaSimpanHari,
+		&tarifPajak,
 		&produk.CreatedAt,
 		&produk.UpdatedAt,
 	)
@@ -395,6 +415,9 @@ func (r *ProdukRepository) GetByID(id int) (*models.Produk, error) {
 	if gambar.Valid {
 		produk.Gambar = gambar.String
 	}
+	if tarifPajak.Valid {
+		produk.TarifPajak = &tarifPajak.Float64
+	}
 
 	return produk, nil
 }
@@ -404,7 +427,8 @@ func (r *ProdukRepository) GetStokHistory(produkID int) ([]*models.StokHistory,
 
 	query := `
         SELECT id, produk_id, stok_sebelum, stok_sesudah, perubahan,
-               jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian, created_at
+               jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian,
+               COALESCE(disetujui_oleh, ''), created_at
         FROM stok_history
         WHERE produk_id = ?
         ORDER BY created_at DESC
@@ -433,6 +457,7 @@ func (r *ProdukRepository) GetStokHistory(produkID int) ([]*models.StokHistory,
 			&h.Keterangan,
 			&tipeKerugian,
 			&nilaiKerugianRaw,
+			&h.DisetujuiOleh,
 			&h.CreatedAt,
 		)
 		if err != nil {
@@ -476,7 +501,7 @@ func (r *ProdukRepository) Update(produk *models.Produk) error {
 			berat = ?, harga_beli = ?, harga_jual = ?,
 			stok = ?, satuan = ?, jenis_produk = ?, kadaluarsa = ?,
 			tanggal_masuk = ?, deskripsi = ?, gambar = ?,
-			hari_pemberitahuan_kadaluarsa = ?, masa_simpan_hari = ?,
+			hari_pemberitahuan_kadaluarsa = ?, masa_simpan_hari = ?, tarif_pajak = ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
@@ -499,6 +524,7 @@ func (r *ProdukRepository) Update(produk *models.Produk) error {
 		produk.Gambar,
 		produk.HariPemberitahuanKadaluarsa,
 		produk.MasaSimpanHari,
+		produk.TarifPajak,
 		produk.ID,
 	)
 
@@ -584,7 +610,7 @@ func (r *ProdukRepository) GetDeleted() ([]*models.Produk, error) {
 	query := `
 		SELECT id, sku, barcode, nama, kategori, berat, harga_beli, harga_jual,
 		       stok, satuan, jenis_produk, kadaluarsa, tanggal_masuk, deskripsi, gambar,
-		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari,
+		       hari_pemberitahuan_kadaluarsa, masa_simpan_hari, tarif_pajak,
 		       created_at, updated_at
 		FROM produk
 		WHERE deleted_at IS NOT NULL
@@ -601,6 +627,7 @@ func (r *ProdukRepository) GetDeleted() ([]*models.Produk, error) {
 	for rows.Next() {
 		produk := &models.Produk{}
 		var barcodeNull, kadaluarsa, tanggalMasuk, gambar, jenisProduk sql.NullString
+		var tarifPajak sql.NullFloat64
 
 		err := rows.Scan(
 			&produk.ID,
@@ -620,6 +647,7 @@ func (r *ProdukRepository) GetDeleted() ([]*models.Produk, error) {
 			&gambar,
 			&produk.HariPemberitahuanKadaluarsa,
 			&produk.MasaSimpanHari,
+			&tarifPajak,
 			&produk.CreatedAt,
 			&produk.UpdatedAt,
 		)
@@ -640,6 +668,9 @@ func (r *ProdukRepository) GetDeleted() ([]*models.Produk, error) {
 		if gambar.Valid {
 			produk.Gambar = gambar.String
 		}
+		if tarifPajak.Valid {
+			produk.TarifPajak = &tarifPajak.Float64
+		}
 		if jenisProduk.Valid {
 			produk.JenisProduk = jenisProduk.String
 		}
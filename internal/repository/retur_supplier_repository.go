@@ -0,0 +1,409 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// JenisDokumenReturSupplier numbers supplier returns
+const JenisDokumenReturSupplier = "RS"
+
+// ReturSupplierRepository handles returns of goods to suppliers
+type ReturSupplierRepository struct {
+	batchRepo *BatchRepository
+}
+
+// NewReturSupplierRepository creates a new repository instance
+func NewReturSupplierRepository() *ReturSupplierRepository {
+	return &ReturSupplierRepository{
+		batchRepo: NewBatchRepository(),
+	}
+}
+
+// CreateRetur saves a supplier return in one transaction: each line is taken out of its
+// batch and out of stock, and written to the stock history as "retur_supplier".
+func (r *ReturSupplierRepository) CreateRetur(retur *models.ReturSupplier) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	nomor, err := reserveNomorDokumen(tx, JenisDokumenReturSupplier, "retur_supplier", "nomor_retur")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	retur.NomorRetur = nomor
+	retur.Status = models.StatusReturSupplierMenunggu
+	retur.Tanggal = now
+	retur.CreatedAt = now
+	retur.UpdatedAt = now
+	dualMode := database.UseDualMode && database.IsSQLite()
+
+	if dualMode {
+		retur.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO retur_supplier (id, nomor_retur, supplier_id, tanggal, alasan, jenis_kompensasi, status, total_nilai, catatan, dibuat_oleh, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), retur.ID, retur.NomorRetur, retur.SupplierID, retur.Tanggal, retur.Alasan, retur.JenisKompensasi, retur.Status,
+			retur.TotalNilai, retur.Catatan, retur.DibuatOleh, now, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO retur_supplier (nomor_retur, supplier_id, tanggal, alasan, jenis_kompensasi, status, total_nilai, catatan, dibuat_oleh, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), retur.NomorRetur, retur.SupplierID, retur.Tanggal, retur.Alasan, retur.JenisKompensasi, retur.Status,
+			retur.TotalNilai, retur.Catatan, retur.DibuatOleh, now, now).Scan(&retur.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create supplier return: %w", err)
+	}
+
+	keterangan := fmt.Sprintf("Retur ke supplier %s (%s)", retur.NomorRetur, retur.SupplierNama)
+
+	for _, item := range retur.Items {
+		item.ReturID = retur.ID
+
+		// 1. Ambil dari batch yang dipilih, tidak boleh melebihi sisa batch
+		if item.BatchID != "" {
+			if err := r.batchRepo.UpdateBatchQtyTx(tx, item.BatchID, item.Qty); err != nil {
+				return fmt.Errorf("sisa batch %s tidak mencukupi: %w", item.ProdukNama, err)
+			}
+		}
+
+		// 2. Kurangi stok produk
+		// Baris produk dikunci agar penjualan bersamaan tidak tertimpa oleh stok absolut di bawah
+		stokQuery := `SELECT stok FROM produk WHERE id = ?`
+		if database.IsPostgreSQL() {
+			stokQuery += ` FOR UPDATE`
+		}
+		var stokSebelum float64
+		err = tx.QueryRow(database.TranslateQuery(stokQuery), item.ProdukID).Scan(&stokSebelum)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("produk %s tidak ditemukan", item.ProdukNama)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read product stock: %w", err)
+		}
+		if stokSebelum < item.Qty-0.0001 {
+			return fmt.Errorf("stok %s (%g) tidak mencukupi untuk retur %g", item.ProdukNama, stokSebelum, item.Qty)
+		}
+		stokSesudah := stokSebelum - item.Qty
+
+		if _, err := tx.Exec(database.TranslateQuery(`UPDATE produk SET stok = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`),
+			stokSesudah, item.ProdukID); err != nil {
+			return fmt.Errorf("failed to update stock for product %s: %w", item.ProdukNama, err)
+		}
+
+		// 3. Simpan baris retur
+		var batchID interface{}
+		if item.BatchID != "" {
+			batchID = item.BatchID
+		}
+		if dualMode {
+			item.ID = database.GenerateOfflineID()
+			_, err = tx.Exec(database.TranslateQuery(`
+				INSERT INTO retur_supplier_item (id, retur_id, batch_id, produk_id, produk_nama, qty, harga_beli, subtotal, tanggal_kadaluarsa)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`), item.ID, item.ReturID, batchID, item.ProdukID, item.ProdukNama, item.Qty, item.HargaBeli, item.Subtotal, item.TanggalKadaluarsa)
+		} else {
+			err = tx.QueryRow(database.TranslateQuery(`
+				INSERT INTO retur_supplier_item (retur_id, batch_id, produk_id, produk_nama, qty, harga_beli, subtotal, tanggal_kadaluarsa)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+			`), item.ReturID, batchID, item.ProdukID, item.ProdukNama, item.Qty, item.HargaBeli, item.Subtotal, item.TanggalKadaluarsa).Scan(&item.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save supplier return item: %w", err)
+		}
+
+		// 4. Riwayat stok; nilai kerugian dihitung dari dokumen retur di laporan
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO stok_history (
+				produk_id, stok_sebelum, stok_sesudah, perubahan,
+				jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`), item.ProdukID, stokSebelum, stokSesudah, -item.Qty, "retur_supplier", keterangan, retur.Alasan, item.Subtotal)
+		if err != nil {
+			return fmt.Errorf("failed to create stock history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SelesaikanRetur settles a waiting return with the supplier's compensation. A replacement
+// puts the returned quantities back into stock as fresh batches.
+func (r *ReturSupplierRepository) SelesaikanRetur(retur *models.ReturSupplier) error {
+	tx, err := r.beginReturMenunggu(retur.ID)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if retur.JenisKompensasi == models.KompensasiGantiBarang {
+		keterangan := fmt.Sprintf("Penggantian retur %s (%s)", retur.NomorRetur, retur.SupplierNama)
+		if err := r.kembalikanStok(tx, retur, keterangan, false); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE retur_supplier
+		SET status = ?, jenis_kompensasi = ?, nilai_kompensasi = ?, no_nota_kredit = ?, catatan = ?,
+		    diselesaikan_oleh = ?, tanggal_selesai = ?, updated_at = ?
+		WHERE id = ?
+	`), models.StatusReturSupplierSelesai, retur.JenisKompensasi, retur.NilaiKompensasi, retur.NoNotaKredit, retur.Catatan,
+		retur.DiselesaikanOleh, now, now, retur.ID)
+	if err != nil {
+		return fmt.Errorf("failed to settle supplier return: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// BatalkanRetur cancels a waiting return, e.g. when the goods never left the store, and
+// puts the quantities back into the batches they were taken from
+func (r *ReturSupplierRepository) BatalkanRetur(retur *models.ReturSupplier, dibatalkanOleh string) error {
+	tx, err := r.beginReturMenunggu(retur.ID)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	keterangan := fmt.Sprintf("Pembatalan retur %s", retur.NomorRetur)
+	if err := r.kembalikanStok(tx, retur, keterangan, true); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE retur_supplier SET status = ?, diselesaikan_oleh = ?, tanggal_selesai = ?, updated_at = ?
+		WHERE id = ?
+	`), models.StatusReturSupplierBatal, dibatalkanOleh, now, now, retur.ID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel supplier return: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// beginReturMenunggu starts a transaction on a return that is still waiting for settlement
+func (r *ReturSupplierRepository) beginReturMenunggu(id int64) (*sql.Tx, error) {
+	db := database.DB
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	statusQuery := `SELECT status FROM retur_supplier WHERE id = ?`
+	if database.IsPostgreSQL() {
+		statusQuery += ` FOR UPDATE`
+	}
+	var status string
+	err = tx.QueryRow(database.TranslateQuery(statusQuery), id).Scan(&status)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("retur supplier tidak ditemukan")
+	} else if err != nil {
+		err = fmt.Errorf("failed to read supplier return status: %w", err)
+	} else if status != models.StatusReturSupplierMenunggu {
+		err = fmt.Errorf("retur supplier berstatus %s tidak dapat diubah", status)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// kembalikanStok adds the returned lines back to stock, into their original batch when
+// keBatchAsal is set, otherwise into today's restock batch of products with a shelf life
+func (r *ReturSupplierRepository) kembalikanStok(tx *sql.Tx, retur *models.ReturSupplier, keterangan string, keBatchAsal bool) error {
+	restockDate := TanggalRestokHariIni()
+
+	for _, item := range retur.Items {
+		stokQuery := `SELECT stok, COALESCE(masa_simpan_hari, 0) FROM produk WHERE id = ?`
+		if database.IsPostgreSQL() {
+			stokQuery += ` FOR UPDATE`
+		}
+		var stokSebelum float64
+		var masaSimpanHari int
+		err := tx.QueryRow(database.TranslateQuery(stokQuery), item.ProdukID).
+			Scan(&stokSebelum, &masaSimpanHari)
+		if err == sql.ErrNoRows {
+			continue // Produk dihapus permanen
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read product stock: %w", err)
+		}
+		stokSesudah := stokSebelum + item.Qty
+
+		if _, err := tx.Exec(database.TranslateQuery(`UPDATE produk SET stok = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`),
+			stokSesudah, item.ProdukID); err != nil {
+			return fmt.Errorf("failed to update stock for product %s: %w", item.ProdukNama, err)
+		}
+
+		if keBatchAsal && item.BatchID != "" {
+			if err := r.batchRepo.RestoreQtyTx(tx, item.BatchID, item.Qty); err != nil {
+				return err
+			}
+		} else if !keBatchAsal && masaSimpanHari > 0 {
+			if _, err := r.batchRepo.RestokBatchTx(tx, &models.Batch{
+				ProdukID:       item.ProdukID,
+				Qty:            item.Qty,
+				TanggalRestok:  restockDate,
+				MasaSimpanHari: masaSimpanHari,
+				Supplier:       retur.SupplierNama,
+				Keterangan:     keterangan,
+				HargaBeli:      item.HargaBeli,
+			}); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO stok_history (
+				produk_id, stok_sebelum, stok_sesudah, perubahan,
+				jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`), item.ProdukID, stokSebelum, stokSesudah, item.Qty, "retur_supplier", keterangan, "", 0)
+		if err != nil {
+			return fmt.Errorf("failed to create stock history: %w", err)
+		}
+	}
+	return nil
+}
+
+const returSupplierSelect = `
+	SELECT rs.id, rs.nomor_retur, rs.supplier_id, COALESCE(s.nama, ''), rs.tanggal, rs.alasan, rs.jenis_kompensasi,
+	       rs.status, rs.total_nilai, rs.nilai_kompensasi, COALESCE(rs.no_nota_kredit, ''), COALESCE(rs.catatan, ''),
+	       COALESCE(rs.dibuat_oleh, ''), COALESCE(rs.diselesaikan_oleh, ''), rs.tanggal_selesai, rs.created_at, rs.updated_at
+	FROM retur_supplier rs
+	LEFT JOIN supplier s ON s.id = rs.supplier_id
+`
+
+func scanReturSupplier(row rowScanner) (*models.ReturSupplier, error) {
+	retur := &models.ReturSupplier{}
+	var tanggalSelesai sql.NullTime
+	err := row.Scan(&retur.ID, &retur.NomorRetur, &retur.SupplierID, &retur.SupplierNama, &retur.Tanggal, &retur.Alasan,
+		&retur.JenisKompensasi, &retur.Status, &retur.TotalNilai, &retur.NilaiKompensasi, &retur.NoNotaKredit, &retur.Catatan,
+		&retur.DibuatOleh, &retur.DiselesaikanOleh, &tanggalSelesai, &retur.CreatedAt, &retur.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tanggalSelesai.Valid {
+		retur.TanggalSelesai = &tanggalSelesai.Time
+	}
+	return retur, nil
+}
+
+// GetAllRetur retrieves supplier returns newest first, optionally filtered by status
+func (r *ReturSupplierRepository) GetAllRetur(status string) ([]*models.ReturSupplier, error) {
+	query := returSupplierSelect
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE rs.status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY rs.tanggal DESC, rs.id DESC`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier returns: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.ReturSupplier{}
+	for rows.Next() {
+		retur, err := scanReturSupplier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier return: %w", err)
+		}
+		list = append(list, retur)
+	}
+
+	return list, nil
+}
+
+// GetReturByID retrieves a supplier return with its lines
+func (r *ReturSupplierRepository) GetReturByID(id int64) (*models.ReturSupplier, error) {
+	retur, err := scanReturSupplier(database.QueryRow(returSupplierSelect+` WHERE rs.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier return: %w", err)
+	}
+
+	rows, err := database.Query(`
+		SELECT id, retur_id, COALESCE(batch_id, ''), produk_id, produk_nama, qty, harga_beli, subtotal, COALESCE(tanggal_kadaluarsa, '')
+		FROM retur_supplier_item
+		WHERE retur_id = ?
+		ORDER BY id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier return items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &models.ReturSupplierItem{}
+		if err := rows.Scan(&item.ID, &item.ReturID, &item.BatchID, &item.ProdukID, &item.ProdukNama, &item.Qty,
+			&item.HargaBeli, &item.Subtotal, &item.TanggalKadaluarsa); err != nil {
+			return nil, fmt.Errorf("failed to scan supplier return item: %w", err)
+		}
+		retur.Items = append(retur.Items, item)
+	}
+
+	return retur, nil
+}
+
+// GetKerugian sums, per reason, the value of returns made in a date range that the supplier
+// has not compensated. Waiting returns count in full; cancelled returns not at all.
+func (r *ReturSupplierRepository) GetKerugian(startDate, endDate time.Time) ([]*models.KerugianReturSupplier, error) {
+	rows, err := database.Query(`
+		SELECT alasan, COALESCE(SUM(total_nilai - nilai_kompensasi), 0), COUNT(*)
+		FROM retur_supplier
+		WHERE status <> ? AND tanggal BETWEEN ? AND ?
+		GROUP BY alasan
+	`, models.StatusReturSupplierBatal, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier return losses: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.KerugianReturSupplier
+	for rows.Next() {
+		k := &models.KerugianReturSupplier{}
+		if err := rows.Scan(&k.Alasan, &k.Nilai, &k.Jumlah); err != nil {
+			return nil, fmt.Errorf("failed to scan supplier return loss: %w", err)
+		}
+		list = append(list, k)
+	}
+
+	return list, nil
+}
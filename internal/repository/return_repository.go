@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"ritel-app/internal/database"
@@ -9,34 +10,61 @@ import (
 )
 
 // ReturnRepository handles database operations for returns
-type ReturnRepository struct{}
+type ReturnRepository struct {
+	otorisasiRepo *OtorisasiRepository
+}
 
 // NewReturnRepository creates a new repository instance
 func NewReturnRepository() *ReturnRepository {
-	return &ReturnRepository{}
+	return &ReturnRepository{
+		otorisasiRepo: NewOtorisasiRepository(),
+	}
 }
 
-// Create creates a new return transaction
+// Create creates a new return transaction. The refund override token, if any, is spent
+// in the same database transaction so it stays unused when the insert fails.
 func (r *ReturnRepository) Create(returnData *models.Return) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if returnData.Otorisasi != nil {
+		if err := r.otorisasiRepo.PakaiTx(tx, *returnData.Otorisasi, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	var staffID interface{}
+	if returnData.StaffID != 0 {
+		staffID = returnData.StaffID
+	}
+
+	var replacementProductID interface{}
+	if returnData.ReplacementProductID > 0 {
+		replacementProductID = returnData.ReplacementProductID
+	}
+
 	if database.UseDualMode && database.IsSQLite() {
 		id := database.GenerateOfflineID()
 		query := `
 		INSERT INTO returns (
 			id, transaksi_id, no_transaksi, return_date, reason, type,
-			replacement_product_id, refund_amount, refund_method, refund_status, notes,
-			created_at, updated_at
+			replacement_product_id, refund_amount, refund_method, refund_status, notes, total_pajak, disetujui_oleh,
+			staff_id, created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
 
-		var replacementProductID interface{}
-		if returnData.ReplacementProductID > 0 {
-			replacementProductID = returnData.ReplacementProductID
-		} else {
-			replacementProductID = nil
-		}
-
-		_, err := database.Exec(query,
+		_, err := tx.Exec(database.TranslateQuery(query),
 			id,
 			returnData.TransaksiID,
 			returnData.NoTransaksi,
@@ -48,11 +76,17 @@ func (r *ReturnRepository) Create(returnData *models.Return) error {
 			returnData.RefundMethod,
 			returnData.RefundStatus,
 			returnData.Notes,
+			returnData.TotalPajak,
+			returnData.DisetujuiOleh,
+			staffID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create return: %w", err)
 		}
 
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit return: %w", err)
+		}
 		returnData.ID = int(id)
 		return nil
 	}
@@ -60,21 +94,14 @@ func (r *ReturnRepository) Create(returnData *models.Return) error {
 	query := `
 		INSERT INTO returns (
 			transaksi_id, no_transaksi, return_date, reason, type,
-			replacement_product_id, refund_amount, refund_method, refund_status, notes,
-			created_at, updated_at
+			replacement_product_id, refund_amount, refund_method, refund_status, notes, total_pajak, disetujui_oleh,
+			staff_id, created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id
 	`
 
-	var replacementProductID interface{}
-	if returnData.ReplacementProductID > 0 {
-		replacementProductID = returnData.ReplacementProductID
-	} else {
-		replacementProductID = nil
-	}
-
 	var id int64
-	err := database.QueryRow(query,
+	err = tx.QueryRow(database.TranslateQuery(query),
 		returnData.TransaksiID,
 		returnData.NoTransaksi,
 		returnData.ReturnDate,
@@ -85,11 +112,17 @@ func (r *ReturnRepository) Create(returnData *models.Return) error {
 		returnData.RefundMethod,
 		returnData.RefundStatus,
 		returnData.Notes,
+		returnData.TotalPajak,
+		returnData.DisetujuiOleh,
+		staffID,
 	).Scan(&id)
 	if err != nil {
 		return fmt.Errorf("failed to create return: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit return: %w", err)
+	}
 	returnData.ID = int(id)
 	return nil
 }
@@ -156,7 +189,8 @@ func (r *ReturnRepository) GetReturnedQuantity(transaksiID int, productID int) (
 
 // UpdateTransactionStatus updates the status of a transaction
 func (r *ReturnRepository) UpdateTransactionStatus(transaksiID int, status string) error {
-	query := `UPDATE transaksi SET status = ? WHERE id = ?`
+	// A sale voided while the return was processed keeps its void status
+	query := `UPDATE transaksi SET status = ? WHERE id = ? AND status != 'void'`
 	_, err := database.Exec(query, status, transaksiID)
 	if err != nil {
 		return fmt.Errorf("failed to update transaction status: %w", err)
@@ -221,7 +255,7 @@ func (r *ReturnRepository) GetAll() ([]*models.Return, error) {
 		SELECT
 			id, transaksi_id, no_transaksi, return_date, reason, type,
 			COALESCE(replacement_product_id, 0),
-			COALESCE(refund_amount, 0), COALESCE(refund_method, ''),
+			COALESCE(refund_amount, 0), COALESCE(refund_method, ''), COALESCE(total_pajak, 0), COALESCE(disetujui_oleh, ''),
 			COALESCE(refund_status, 'pending'), COALESCE(notes, ''),
 			created_at, updated_at
 		FROM returns
@@ -250,6 +284,8 @@ func (r *ReturnRepository) GetAll() ([]*models.Return, error) {
 			&ret.ReplacementProductID,
 			&ret.RefundAmount,
 			&ret.RefundMethod,
+			&ret.TotalPajak,
+			&ret.DisetujuiOleh,
 			&ret.RefundStatus,
 			&ret.Notes,
 			&createdAtStr,
@@ -282,7 +318,7 @@ func (r *ReturnRepository) GetByID(id int) (*models.Return, error) {
 		SELECT
 			id, transaksi_id, no_transaksi, return_date, reason, type,
 			COALESCE(replacement_product_id, 0),
-			COALESCE(refund_amount, 0), COALESCE(refund_method, ''),
+			COALESCE(refund_amount, 0), COALESCE(refund_method, ''), COALESCE(total_pajak, 0), COALESCE(disetujui_oleh, ''),
 			COALESCE(refund_status, 'pending'), COALESCE(notes, ''),
 			created_at, updated_at
 		FROM returns
@@ -303,6 +339,8 @@ func (r *ReturnRepository) GetByID(id int) (*models.Return, error) {
 		&ret.ReplacementProductID,
 		&ret.RefundAmount,
 		&ret.RefundMethod,
+		&ret.TotalPajak,
+		&ret.DisetujuiOleh,
 		&ret.RefundStatus,
 		&ret.Notes,
 		&createdAtStr,
@@ -384,13 +422,30 @@ func (r *ReturnRepository) GetTotalRefundByDateRange(startDate, endDate time.Tim
 	return totalRefund, nil
 }
 
+// GetTotalPajakByDateRange calculates the tax (PPN) reversed by returns in a date range
+func (r *ReturnRepository) GetTotalPajakByDateRange(startDate, endDate time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(total_pajak), 0)
+		FROM returns
+		WHERE return_date >= ? AND return_date <= ?
+	`
+
+	var totalPajak int
+	err := database.QueryRow(query, startDate, endDate).Scan(&totalPajak)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total return tax: %w", err)
+	}
+
+	return totalPajak, nil
+}
+
 // GetReturnsByDateRange retrieves all returns in a date range
 func (r *ReturnRepository) GetReturnsByDateRange(startDate, endDate time.Time) ([]*models.Return, error) {
 	query := `
 		SELECT
 			id, transaksi_id, no_transaksi, return_date, reason, type,
 			COALESCE(replacement_product_id, 0),
-			COALESCE(refund_amount, 0), COALESCE(refund_method, ''),
+			COALESCE(refund_amount, 0), COALESCE(refund_method, ''), COALESCE(total_pajak, 0), COALESCE(disetujui_oleh, ''),
 			COALESCE(refund_status, 'pending'), COALESCE(notes, ''),
 			created_at, updated_at
 		FROM returns
@@ -420,6 +475,8 @@ func (r *ReturnRepository) GetReturnsByDateRange(startDate, endDate time.Time) (
 			&ret.ReplacementProductID,
 			&ret.RefundAmount,
 			&ret.RefundMethod,
+			&ret.TotalPajak,
+			&ret.DisetujuiOleh,
 			&ret.RefundStatus,
 			&ret.Notes,
 			&createdAtStr,
@@ -666,7 +723,7 @@ func (r *ReturnRepository) GetReturnsByStaffAndDateRange(staffID int64, startDat
 		SELECT
 			r.id, r.transaksi_id, r.no_transaksi, r.return_date, r.reason, r.type,
 			COALESCE(r.replacement_product_id, 0),
-			COALESCE(r.refund_amount, 0), COALESCE(r.refund_method, ''),
+			COALESCE(r.refund_amount, 0), COALESCE(r.refund_method, ''), COALESCE(r.total_pajak, 0), COALESCE(r.disetujui_oleh, ''),
 			COALESCE(r.refund_status, 'pending'), COALESCE(r.notes, ''),
 			r.created_at, r.updated_at
 		FROM returns r
@@ -699,6 +756,8 @@ func (r *ReturnRepository) GetReturnsByStaffAndDateRange(staffID int64, startDat
 			&ret.ReplacementProductID,
 			&ret.RefundAmount,
 			&ret.RefundMethod,
+			&ret.TotalPajak,
+			&ret.DisetujuiOleh,
 			&ret.RefundStatus,
 			&ret.Notes,
 			&createdAtStr,
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// RoleRepository handles database operations for roles
+type RoleRepository struct{}
+
+// NewRoleRepository creates a new repository instance
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{}
+}
+
+const roleColumns = `id, nama, COALESCE(deskripsi, ''), COALESCE(permissions, '[]'), sistem, created_at, updated_at`
+
+// Create saves a new role
+func (r *RoleRepository) Create(role *models.Role) error {
+	now := time.Now().UTC()
+	role.CreatedAt = now
+	role.UpdatedAt = now
+
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return fmt.Errorf("failed to encode permissions: %w", err)
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		role.ID = database.GenerateOfflineID()
+		query := `
+			INSERT INTO role (id, nama, deskripsi, permissions, sistem, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, role.ID, role.Nama, role.Deskripsi, string(permissions), boolToInt(role.Sistem), now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create role: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO role (nama, deskripsi, permissions, sistem, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err = database.QueryRow(query, role.Nama, role.Deskripsi, string(permissions), boolToInt(role.Sistem), now, now).Scan(&role.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves the description and permissions of a role. The name is fixed because
+// users reference their role by name.
+func (r *RoleRepository) Update(role *models.Role) error {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return fmt.Errorf("failed to encode permissions: %w", err)
+	}
+
+	role.UpdatedAt = time.Now().UTC()
+	result, err := database.Exec(`UPDATE role SET deskripsi = ?, permissions = ?, updated_at = ? WHERE id = ?`,
+		role.Deskripsi, string(permissions), role.UpdatedAt, role.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("role not found")
+	}
+
+	return nil
+}
+
+// Delete removes a role
+func (r *RoleRepository) Delete(id int64) error {
+	result, err := database.Exec(`DELETE FROM role WHERE id = ? AND sistem = 0`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("role not found")
+	}
+
+	return nil
+}
+
+// GetAll retrieves all roles
+func (r *RoleRepository) GetAll() ([]*models.Role, error) {
+	rows, err := database.Query(`SELECT ` + roleColumns + ` FROM role ORDER BY sistem DESC, nama ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []*models.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+// GetByID retrieves a role by ID
+func (r *RoleRepository) GetByID(id int64) (*models.Role, error) {
+	return r.getOne(`SELECT `+roleColumns+` FROM role WHERE id = ?`, id)
+}
+
+// GetByNama retrieves a role by name
+func (r *RoleRepository) GetByNama(nama string) (*models.Role, error) {
+	return r.getOne(`SELECT `+roleColumns+` FROM role WHERE nama = ?`, nama)
+}
+
+// CountUsers counts the users (not deleted) that have a role
+func (r *RoleRepository) CountUsers(nama string) (int, error) {
+	var count int
+	err := database.QueryRow(`SELECT COUNT(*) FROM users WHERE role = ? AND deleted_at IS NULL`, nama).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users of role: %w", err)
+	}
+	return count, nil
+}
+
+func (r *RoleRepository) getOne(query string, args ...interface{}) (*models.Role, error) {
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	role, err := scanRole(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan role: %w", err)
+	}
+	return role, nil
+}
+
+func scanRole(row rowScanner) (*models.Role, error) {
+	role := &models.Role{}
+	var permissions string
+	var sistem int
+	if err := row.Scan(&role.ID, &role.Nama, &role.Deskripsi, &permissions, &sistem, &role.CreatedAt, &role.UpdatedAt); err != nil {
+		return nil, err
+	}
+	role.Sistem = sistem == 1
+	if err := decodeJSONKolom(permissions, &role.Permissions); err != nil {
+		return nil, err
+	}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	return role, nil
+}
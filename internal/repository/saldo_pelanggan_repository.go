@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// SaldoPelangganRepository handles the customer store credit ledger
+type SaldoPelangganRepository struct{}
+
+// NewSaldoPelangganRepository creates a new repository instance
+func NewSaldoPelangganRepository() *SaldoPelangganRepository {
+	return &SaldoPelangganRepository{}
+}
+
+// Mutasi changes a customer's balance and records the movement in its own database transaction
+func (r *SaldoPelangganRepository) Mutasi(h *models.SaldoPelangganHistory) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.MutasiTx(tx, h); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// MutasiTx changes a customer's balance and records the movement inside an existing transaction.
+// The balance can never go below zero.
+func (r *SaldoPelangganRepository) MutasiTx(tx *sql.Tx, h *models.SaldoPelangganHistory) error {
+	var saldo int
+	err := tx.QueryRow(database.TranslateQuery(`
+		SELECT COALESCE(saldo, 0) FROM pelanggan WHERE id = ? AND deleted_at IS NULL
+	`), h.PelangganID).Scan(&saldo)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("pelanggan tidak ditemukan")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get customer balance: %w", err)
+	}
+
+	if saldo+h.Jumlah < 0 {
+		return fmt.Errorf("saldo pelanggan tidak mencukupi (saldo: Rp %d, dibutuhkan: Rp %d)", saldo, -h.Jumlah)
+	}
+
+	now := time.Now().UTC()
+	h.SaldoSebelum = saldo
+	h.SaldoSesudah = saldo + h.Jumlah
+	h.CreatedAt = now
+
+	// Only apply when the balance is still the one we read
+	result, err := tx.Exec(database.TranslateQuery(`
+		UPDATE pelanggan SET saldo = ?, updated_at = ? WHERE id = ? AND COALESCE(saldo, 0) = ?
+	`), h.SaldoSesudah, now, h.PelangganID, saldo)
+	if err != nil {
+		return fmt.Errorf("failed to update customer balance: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("saldo pelanggan berubah saat diproses, silakan ulangi")
+	}
+
+	var transaksiID, returnID, staffID interface{}
+	if h.TransaksiID != 0 {
+		transaksiID = h.TransaksiID
+	}
+	if h.ReturnID != 0 {
+		returnID = h.ReturnID
+	}
+	if h.StaffID != 0 {
+		staffID = h.StaffID
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		h.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO saldo_pelanggan_history (
+				id, pelanggan_id, tipe, jumlah, saldo_sebelum, saldo_sesudah, metode,
+				transaksi_id, return_id, referensi, keterangan, staff_id, staff_nama, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`), h.ID, h.PelangganID, h.Tipe, h.Jumlah, h.SaldoSebelum, h.SaldoSesudah, h.Metode,
+			transaksiID, returnID, h.Referensi, h.Keterangan, staffID, h.StaffNama, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO saldo_pelanggan_history (
+				pelanggan_id, tipe, jumlah, saldo_sebelum, saldo_sesudah, metode,
+				transaksi_id, return_id, referensi, keterangan, staff_id, staff_nama, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), h.PelangganID, h.Tipe, h.Jumlah, h.SaldoSebelum, h.SaldoSesudah, h.Metode,
+			transaksiID, returnID, h.Referensi, h.Keterangan, staffID, h.StaffNama, now).Scan(&h.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record balance movement: %w", err)
+	}
+
+	return nil
+}
+
+// GetByPelangganID retrieves the balance movements of a customer, newest first
+func (r *SaldoPelangganRepository) GetByPelangganID(pelangganID int64, limit int) ([]*models.SaldoPelangganHistory, error) {
+	query := `
+		SELECT id, pelanggan_id, tipe, jumlah, saldo_sebelum, saldo_sesudah, COALESCE(metode, ''),
+			COALESCE(transaksi_id, 0), COALESCE(return_id, 0), COALESCE(referensi, ''), COALESCE(keterangan, ''),
+			COALESCE(staff_id, 0), COALESCE(staff_nama, ''), created_at
+		FROM saldo_pelanggan_history
+		WHERE pelanggan_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := database.Query(query, pelangganID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*models.SaldoPelangganHistory
+	for rows.Next() {
+		var h models.SaldoPelangganHistory
+		err := rows.Scan(
+			&h.ID, &h.PelangganID, &h.Tipe, &h.Jumlah, &h.SaldoSebelum, &h.SaldoSesudah, &h.Metode,
+			&h.TransaksiID, &h.ReturnID, &h.Referensi, &h.Keterangan,
+			&h.StaffID, &h.StaffNama, &h.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan balance history: %w", err)
+		}
+		history = append(history, &h)
+	}
+
+	return history, nil
+}
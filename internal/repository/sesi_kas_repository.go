@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// SesiKasRepository handles database operations for cash drawer sessions
+type SesiKasRepository struct{}
+
+// NewSesiKasRepository creates a new repository instance
+func NewSesiKasRepository() *SesiKasRepository {
+	return &SesiKasRepository{}
+}
+
+const sesiKasColumns = `
+	id, COALESCE(terminal_id, ''), staff_id, COALESCE(staff_nama, ''), COALESCE(shift_id, 0), status, modal_awal,
+	COALESCE(penjualan_tunai, 0), COALESCE(pelunasan_tunai, 0), COALESCE(topup_tunai, 0), COALESCE(refund_tunai, 0),
+	COALESCE(kas_masuk, 0), COALESCE(kas_keluar, 0), COALESCE(kas_diharapkan, 0),
+	COALESCE(kas_dihitung, 0), COALESCE(selisih, 0), COALESCE(pecahan, ''),
+	COALESCE(catatan_tutup, ''), COALESCE(ditutup_oleh, ''), dibuka_at, ditutup_at, created_at, updated_at
+`
+
+// Create opens a new drawer session
+func (r *SesiKasRepository) Create(sesi *models.SesiKas) error {
+	now := time.Now().UTC()
+	sesi.Status = "buka"
+	sesi.DibukaAt = now
+	sesi.CreatedAt = now
+	sesi.UpdatedAt = now
+
+	var shiftID interface{}
+	if sesi.ShiftID != 0 {
+		shiftID = sesi.ShiftID
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		sesi.ID = database.GenerateOfflineID()
+		query := `
+			INSERT INTO sesi_kas (id, terminal_id, staff_id, staff_nama, shift_id, status, modal_awal, dibuka_at, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, sesi.ID, sesi.TerminalID, sesi.StaffID, sesi.StaffNama, shiftID, sesi.Status,
+			sesi.ModalAwal, now, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to open cash session: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO sesi_kas (terminal_id, staff_id, staff_nama, shift_id, status, modal_awal, dibuka_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err := database.QueryRow(query, sesi.TerminalID, sesi.StaffID, sesi.StaffNama, shiftID, sesi.Status,
+		sesi.ModalAwal, now, now, now).Scan(&sesi.ID)
+	if err != nil {
+		return fmt.Errorf("failed to open cash session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a drawer session by ID
+func (r *SesiKasRepository) GetByID(id int64) (*models.SesiKas, error) {
+	row := database.QueryRow(`SELECT `+sesiKasColumns+` FROM sesi_kas WHERE id = ?`, id)
+	sesi, err := scanSesiKas(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash session: %w", err)
+	}
+	return sesi, nil
+}
+
+// GetAktifByStaff retrieves the open drawer session of a staff member
+func (r *SesiKasRepository) GetAktifByStaff(staffID int64) (*models.SesiKas, error) {
+	row := database.QueryRow(`
+		SELECT `+sesiKasColumns+` FROM sesi_kas
+		WHERE staff_id = ? AND status = 'buka'
+		ORDER BY dibuka_at DESC LIMIT 1
+	`, staffID)
+	sesi, err := scanSesiKas(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open cash session: %w", err)
+	}
+	return sesi, nil
+}
+
+// GetAll retrieves drawer sessions, newest first, optionally filtered by staff and status
+func (r *SesiKasRepository) GetAll(staffID int64, status string, limit int) ([]*models.SesiKas, error) {
+	query := `SELECT ` + sesiKasColumns + ` FROM sesi_kas WHERE 1 = 1`
+	var args []interface{}
+	if staffID != 0 {
+		query += ` AND staff_id = ?`
+		args = append(args, staffID)
+	}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY dibuka_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.SesiKas
+	for rows.Next() {
+		sesi, err := scanSesiKas(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cash session: %w", err)
+		}
+		list = append(list, sesi)
+	}
+
+	return list, nil
+}
+
+// GetByDibukaRange retrieves the sessions opened within a time range
+func (r *SesiKasRepository) GetByDibukaRange(startDate, endDate time.Time) ([]*models.SesiKas, error) {
+	rows, err := database.Query(`
+		SELECT `+sesiKasColumns+` FROM sesi_kas
+		WHERE dibuka_at >= ? AND dibuka_at <= ?
+		ORDER BY dibuka_at ASC
+	`, startDate.UTC(), endDate.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.SesiKas
+	for rows.Next() {
+		sesi, err := scanSesiKas(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cash session: %w", err)
+		}
+		list = append(list, sesi)
+	}
+
+	return list, nil
+}
+
+// CreateMutasi records a paid-in or paid-out entry; the session must still be open
+func (r *SesiKasRepository) CreateMutasi(m *models.MutasiKas) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(database.TranslateQuery(`SELECT status FROM sesi_kas WHERE id = ?`), m.SesiID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("sesi kas tidak ditemukan")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cash session: %w", err)
+	}
+	if status != "buka" {
+		return fmt.Errorf("sesi kas sudah ditutup")
+	}
+
+	now := time.Now().UTC()
+	m.CreatedAt = now
+	var staffID interface{}
+	if m.StaffID != 0 {
+		staffID = m.StaffID
+	}
+
+	if database.UseDualMode && database.IsSQLite() {
+		m.ID = database.GenerateOfflineID()
+		_, err = tx.Exec(database.TranslateQuery(`
+			INSERT INTO sesi_kas_mutasi (id, sesi_id, tipe, jumlah, alasan, staff_id, staff_nama, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`), m.ID, m.SesiID, m.Tipe, m.Jumlah, m.Alasan, staffID, m.StaffNama, now)
+	} else {
+		err = tx.QueryRow(database.TranslateQuery(`
+			INSERT INTO sesi_kas_mutasi (sesi_id, tipe, jumlah, alasan, staff_id, staff_nama, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id
+		`), m.SesiID, m.Tipe, m.Jumlah, m.Alasan, staffID, m.StaffNama, now).Scan(&m.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record cash movement: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetMutasi retrieves the paid-in and paid-out entries of a session
+func (r *SesiKasRepository) GetMutasi(sesiID int64) ([]*models.MutasiKas, error) {
+	query := `
+		SELECT id, sesi_id, tipe, jumlah, alasan, COALESCE(staff_id, 0), COALESCE(staff_nama, ''), created_at
+		FROM sesi_kas_mutasi
+		WHERE sesi_id = ?
+		ORDER BY created_at ASC
+	`
+
+	rows, err := database.Query(query, sesiID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash movements: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*models.MutasiKas
+	for rows.Next() {
+		var m models.MutasiKas
+		if err := rows.Scan(&m.ID, &m.SesiID, &m.Tipe, &m.Jumlah, &m.Alasan, &m.StaffID, &m.StaffNama, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cash movement: %w", err)
+		}
+		list = append(list, &m)
+	}
+
+	return list, nil
+}
+
+// HitungKas fills the running cash totals of a session up to the given time.
+// Cash sales are cash payments minus change of the transactions linked to the session;
+// unlinked transactions of the session's staff within the session window are counted as well.
+// Cash refunds of returns the session's staff processed in the window are paid out of the drawer.
+func (r *SesiKasRepository) HitungKas(sesi *models.SesiKas, sampai time.Time) error {
+	// transaksi.tanggal is stored in WIB, the other ledgers in UTC
+	wib := time.FixedZone("WIB", 7*3600)
+	dibukaWIB, sampaiWIB := sesi.DibukaAt.In(wib), sampai.In(wib)
+	dibuka, sampai := sesi.DibukaAt.UTC(), sampai.UTC()
+
+	var dibayarTunai, kembalian int
+	err := database.QueryRow(`
+		SELECT COALESCE(SUM(p.jumlah), 0)
+		FROM pembayaran p
+		JOIN transaksi t ON t.id = p.transaksi_id
+		JOIN metode_pembayaran m ON m.kode = p.metode
+		WHERE m.tipe = 'tunai' AND t.status != 'void' AND (t.shift_session_id = ? OR
+			(t.shift_session_id IS NULL AND t.staff_id = ? AND t.tanggal >= ? AND t.tanggal <= ?))
+	`, sesi.ID, sesi.StaffID, dibukaWIB, sampaiWIB).Scan(&dibayarTunai)
+	if err != nil {
+		return fmt.Errorf("failed to sum cash payments: %w", err)
+	}
+
+	err = database.QueryRow(`
+		SELECT COALESCE(SUM(kembalian), 0)
+		FROM transaksi
+		WHERE status != 'void' AND (shift_session_id = ? OR
+			(shift_session_id IS NULL AND staff_id = ? AND tanggal >= ? AND tanggal <= ?))
+	`, sesi.ID, sesi.StaffID, dibukaWIB, sampaiWIB).Scan(&kembalian)
+	if err != nil {
+		return fmt.Errorf("failed to sum change: %w", err)
+	}
+	sesi.PenjualanTunai = dibayarTunai - kembalian
+
+	err = database.QueryRow(`
+		SELECT COALESCE(SUM(pp.jumlah), 0)
+		FROM pembayaran_piutang pp
+		JOIN metode_pembayaran m ON m.kode = pp.metode
+		WHERE m.tipe = 'tunai' AND pp.staff_id = ? AND pp.created_at >= ? AND pp.created_at <= ?
+	`, sesi.StaffID, dibuka, sampai).Scan(&sesi.PelunasanTunai)
+	if err != nil {
+		return fmt.Errorf("failed to sum cash repayments: %w", err)
+	}
+
+	err = database.QueryRow(`
+		SELECT COALESCE(SUM(h.jumlah), 0)
+		FROM saldo_pelanggan_history h
+		JOIN metode_pembayaran m ON m.kode = h.metode
+		WHERE h.tipe = 'topup' AND m.tipe = 'tunai' AND h.staff_id = ? AND h.created_at >= ? AND h.created_at <= ?
+	`, sesi.StaffID, dibuka, sampai).Scan(&sesi.TopUpTunai)
+	if err != nil {
+		return fmt.Errorf("failed to sum cash top ups: %w", err)
+	}
+
+	err = database.QueryRow(`
+		SELECT COALESCE(SUM(r.refund_amount), 0)
+		FROM returns r
+		JOIN metode_pembayaran m ON m.kode = r.refund_method
+		WHERE m.tipe = 'tunai' AND COALESCE(r.refund_status, '') != 'cancelled'
+			AND r.staff_id = ? AND r.created_at >= ? AND r.created_at <= ?
+	`, sesi.StaffID, dibuka, sampai).Scan(&sesi.RefundTunai)
+	if err != nil {
+		return fmt.Errorf("failed to sum cash refunds: %w", err)
+	}
+
+	err = database.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN tipe = 'masuk' THEN jumlah ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN tipe = 'keluar' THEN jumlah ELSE 0 END), 0)
+		FROM sesi_kas_mutasi
+		WHERE sesi_id = ?
+	`, sesi.ID).Scan(&sesi.KasMasuk, &sesi.KasKeluar)
+	if err != nil {
+		return fmt.Errorf("failed to sum cash movements: %w", err)
+	}
+
+	sesi.KasDiharapkan = sesi.ModalAwal + sesi.PenjualanTunai + sesi.PelunasanTunai + sesi.TopUpTunai -
+		sesi.RefundTunai + sesi.KasMasuk - sesi.KasKeluar
+	return nil
+}
+
+// Tutup freezes the totals of a session. A closed session is never updated again.
+func (r *SesiKasRepository) Tutup(sesi *models.SesiKas) error {
+	pecahan, err := json.Marshal(sesi.Pecahan)
+	if err != nil {
+		return fmt.Errorf("failed to encode denominations: %w", err)
+	}
+
+	now := time.Now().UTC()
+	query := `
+		UPDATE sesi_kas
+		SET status = 'tutup', penjualan_tunai = ?, pelunasan_tunai = ?, topup_tunai = ?, refund_tunai = ?,
+			kas_masuk = ?, kas_keluar = ?, kas_diharapkan = ?, kas_dihitung = ?, selisih = ?,
+			pecahan = ?, catatan_tutup = ?, ditutup_oleh = ?, ditutup_at = ?, updated_at = ?
+		WHERE id = ? AND status = 'buka'
+	`
+	result, err := database.Exec(query, sesi.PenjualanTunai, sesi.PelunasanTunai, sesi.TopUpTunai, sesi.RefundTunai,
+		sesi.KasMasuk, sesi.KasKeluar, sesi.KasDiharapkan, sesi.KasDihitung, sesi.Selisih,
+		string(pecahan), sesi.CatatanTutup, sesi.DitutupOleh, now, now, sesi.ID)
+	if err != nil {
+		return fmt.Errorf("failed to close cash session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("sesi kas sudah ditutup")
+	}
+
+	sesi.Status = "tutup"
+	sesi.DitutupAt = &now
+	sesi.UpdatedAt = now
+	return nil
+}
+
+func scanSesiKas(row rowScanner) (*models.SesiKas, error) {
+	sesi := &models.SesiKas{}
+	var pecahan string
+	var ditutupAt sql.NullTime
+	err := row.Scan(
+		&sesi.ID, &sesi.TerminalID, &sesi.StaffID, &sesi.StaffNama, &sesi.ShiftID, &sesi.Status, &sesi.ModalAwal,
+		&sesi.PenjualanTunai, &sesi.PelunasanTunai, &sesi.TopUpTunai, &sesi.RefundTunai,
+		&sesi.KasMasuk, &sesi.KasKeluar, &sesi.KasDiharapkan,
+		&sesi.KasDihitung, &sesi.Selisih, &pecahan,
+		&sesi.CatatanTutup, &sesi.DitutupOleh, &sesi.DibukaAt, &ditutupAt, &sesi.CreatedAt, &sesi.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ditutupAt.Valid {
+		sesi.DitutupAt = &ditutupAt.Time
+	}
+	sesi.Pecahan = []models.PecahanKas{}
+	if pecahan != "" {
+		if err := json.Unmarshal([]byte(pecahan), &sesi.Pecahan); err != nil {
+			return nil, fmt.Errorf("failed to decode denominations: %w", err)
+		}
+	}
+	return sesi, nil
+}
@@ -160,3 +160,482 @@ func (r *SettingsRepository) createDefaultSettings() (*models.PoinSettings, erro
 
 	return defaultSettings, nil
 }
+
+// GetPenomoranSettings retrieves the transaction numbering scheme of this terminal
+func (r *SettingsRepository) GetPenomoranSettings() (*models.PenomoranSettings, error) {
+	query := `
+		SELECT kode_toko, kode_terminal, pemisah, format_tanggal, panjang_urutan, reset_harian
+		FROM penomoran_settings
+		WHERE id = 1
+	`
+
+	settings := &models.PenomoranSettings{}
+	var kodeToko, kodeTerminal, pemisah, formatTanggal sql.NullString
+	var resetHarian int
+	err := database.QueryRow(query).Scan(
+		&kodeToko,
+		&kodeTerminal,
+		&pemisah,
+		&formatTanggal,
+		&settings.PanjangUrutan,
+		&resetHarian,
+	)
+
+	if err == sql.ErrNoRows {
+		return DefaultPenomoranSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penomoran settings: %w", err)
+	}
+
+	settings.KodeToko = kodeToko.String
+	settings.KodeTerminal = kodeTerminal.String
+	settings.Pemisah = pemisah.String
+	settings.FormatTanggal = formatTanggal.String
+	settings.ResetHarian = resetHarian == 1
+
+	return settings, nil
+}
+
+// UpdatePenomoranSettings saves the transaction numbering scheme of this terminal
+func (r *SettingsRepository) UpdatePenomoranSettings(settings *models.PenomoranSettings) error {
+	resetHarian := 0
+	if settings.ResetHarian {
+		resetHarian = 1
+	}
+
+	query := `
+		INSERT INTO penomoran_settings (
+			id, kode_toko, kode_terminal, pemisah, format_tanggal, panjang_urutan, reset_harian, updated_at
+		) VALUES (1, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			kode_toko = excluded.kode_toko,
+			kode_terminal = excluded.kode_terminal,
+			pemisah = excluded.pemisah,
+			format_tanggal = excluded.format_tanggal,
+			panjang_urutan = excluded.panjang_urutan,
+			reset_harian = excluded.reset_harian,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := database.Exec(query,
+		settings.KodeToko,
+		settings.KodeTerminal,
+		settings.Pemisah,
+		settings.FormatTanggal,
+		settings.PanjangUrutan,
+		resetHarian,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update penomoran settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultPenomoranSettings returns the numbering scheme used until one is configured
+func DefaultPenomoranSettings() *models.PenomoranSettings {
+	return &models.PenomoranSettings{
+		KodeToko:      "STORE1",
+		KodeTerminal:  "T01",
+		Pemisah:       "-",
+		FormatTanggal: "20060102",
+		PanjangUrutan: 6,
+		ResetHarian:   true,
+	}
+}
+
+// GetPembulatanSettings retrieves the cash rounding policy
+func (r *SettingsRepository) GetPembulatanSettings() (*models.PembulatanSettings, error) {
+	query := `
+		SELECT aktif, kelipatan, mode, donasi_aktif
+		FROM pembulatan_settings
+		WHERE id = 1
+	`
+
+	settings := &models.PembulatanSettings{}
+	var aktif, donasiAktif int
+	var mode sql.NullString
+	err := database.QueryRow(query).Scan(
+		&aktif,
+		&settings.Kelipatan,
+		&mode,
+		&donasiAktif,
+	)
+
+	if err == sql.ErrNoRows {
+		return DefaultPembulatanSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pembulatan settings: %w", err)
+	}
+
+	settings.Aktif = aktif == 1
+	settings.Mode = mode.String
+	settings.DonasiAktif = donasiAktif == 1
+
+	return settings, nil
+}
+
+// UpdatePembulatanSettings saves the cash rounding policy
+func (r *SettingsRepository) UpdatePembulatanSettings(settings *models.PembulatanSettings) error {
+	aktif := 0
+	if settings.Aktif {
+		aktif = 1
+	}
+	donasiAktif := 0
+	if settings.DonasiAktif {
+		donasiAktif = 1
+	}
+
+	query := `
+		INSERT INTO pembulatan_settings (
+			id, aktif, kelipatan, mode, donasi_aktif, updated_at
+		) VALUES (1, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			aktif = excluded.aktif,
+			kelipatan = excluded.kelipatan,
+			mode = excluded.mode,
+			donasi_aktif = excluded.donasi_aktif,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := database.Exec(query, aktif, settings.Kelipatan, settings.Mode, donasiAktif)
+	if err != nil {
+		return fmt.Errorf("failed to update pembulatan settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultPembulatanSettings returns the rounding policy used until one is configured (no rounding)
+func DefaultPembulatanSettings() *models.PembulatanSettings {
+	return &models.PembulatanSettings{
+		Aktif:       false,
+		Kelipatan:   100,
+		Mode:        "nearest",
+		DonasiAktif: false,
+	}
+}
+
+// GetPajakSettings retrieves the store tax (PPN) settings
+func (r *SettingsRepository) GetPajakSettings() (*models.PajakSettings, error) {
+	query := `
+		SELECT aktif, nama_pajak, tarif_default, mode
+		FROM pajak_settings
+		WHERE id = 1
+	`
+
+	settings := &models.PajakSettings{}
+	var aktif int
+	var namaPajak, mode sql.NullString
+	err := database.QueryRow(query).Scan(
+		&aktif,
+		&namaPajak,
+		&settings.TarifDefault,
+		&mode,
+	)
+
+	if err == sql.ErrNoRows {
+		return DefaultPajakSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pajak settings: %w", err)
+	}
+
+	settings.Aktif = aktif == 1
+	settings.NamaPajak = namaPajak.String
+	settings.Mode = mode.String
+
+	return settings, nil
+}
+
+// UpdatePajakSettings saves the store tax (PPN) settings
+func (r *SettingsRepository) UpdatePajakSettings(settings *models.PajakSettings) error {
+	aktif := 0
+	if settings.Aktif {
+		aktif = 1
+	}
+
+	query := `
+		INSERT INTO pajak_settings (
+			id, aktif, nama_pajak, tarif_default, mode, updated_at
+		) VALUES (1, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			aktif = excluded.aktif,
+			nama_pajak = excluded.nama_pajak,
+			tarif_default = excluded.tarif_default,
+			mode = excluded.mode,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := database.Exec(query, aktif, settings.NamaPajak, settings.TarifDefault, settings.Mode)
+	if err != nil {
+		return fmt.Errorf("failed to update pajak settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultPajakSettings returns the tax settings used until one is configured (tax disabled)
+func DefaultPajakSettings() *models.PajakSettings {
+	return &models.PajakSettings{
+		Aktif:        false,
+		NamaPajak:    "PPN",
+		TarifDefault: 11,
+		Mode:         "exclusive",
+	}
+}
+
+// GetLaciKasSettings retrieves the cash drawer settings of this terminal
+func (r *SettingsRepository) GetLaciKasSettings() (*models.LaciKasSettings, error) {
+	query := `
+		SELECT aktif, driver, printer_name, port, baud_rate, pin
+		FROM laci_kas_settings
+		WHERE id = 1
+	`
+
+	settings := &models.LaciKasSettings{}
+	var aktif int
+	var driver, printerName, port sql.NullString
+	err := database.QueryRow(query).Scan(
+		&aktif,
+		&driver,
+		&printerName,
+		&port,
+		&settings.BaudRate,
+		&settings.Pin,
+	)
+
+	if err == sql.ErrNoRows {
+		return DefaultLaciKasSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get laci kas settings: %w", err)
+	}
+
+	settings.Aktif = aktif == 1
+	settings.Driver = driver.String
+	settings.PrinterName = printerName.String
+	settings.Port = port.String
+
+	return settings, nil
+}
+
+// UpdateLaciKasSettings saves the cash drawer settings of this terminal
+func (r *SettingsRepository) UpdateLaciKasSettings(settings *models.LaciKasSettings) error {
+	aktif := 0
+	if settings.Aktif {
+		aktif = 1
+	}
+
+	query := `
+		INSERT INTO laci_kas_settings (
+			id, aktif, driver, printer_name, port, baud_rate, pin, updated_at
+		) VALUES (1, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			aktif = excluded.aktif,
+			driver = excluded.driver,
+			printer_name = excluded.printer_name,
+			port = excluded.port,
+			baud_rate = excluded.baud_rate,
+			pin = excluded.pin,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := database.Exec(query, aktif, settings.Driver, settings.PrinterName, settings.Port, settings.BaudRate, settings.Pin)
+	if err != nil {
+		return fmt.Errorf("failed to update laci kas settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultLaciKasSettings returns the drawer settings used until one is configured (kick through the receipt printer)
+func DefaultLaciKasSettings() *models.LaciKasSettings {
+	return &models.LaciKasSettings{
+		Aktif:    false,
+		Driver:   "printer",
+		BaudRate: 9600,
+		Pin:      0,
+	}
+}
+
+// GetOtorisasiSettings retrieves the thresholds for supervisor overrides
+func (r *SettingsRepository) GetOtorisasiSettings() (*models.OtorisasiSettings, error) {
+	query := `
+		SELECT batas_diskon_manual, batas_refund, wajib_no_sale, wajib_penyesuaian_stok, masa_berlaku_detik
+		FROM otorisasi_settings
+		WHERE id = 1
+	`
+
+	settings := &models.OtorisasiSettings{}
+	var wajibNoSale, wajibPenyesuaianStok int
+	err := database.QueryRow(query).Scan(
+		&settings.BatasDiskonManual,
+		&settings.BatasRefund,
+		&wajibNoSale,
+		&wajibPenyesuaianStok,
+		&settings.MasaBerlakuDetik,
+	)
+
+	if err == sql.ErrNoRows {
+		return DefaultOtorisasiSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otorisasi settings: %w", err)
+	}
+
+	settings.WajibNoSale = wajibNoSale == 1
+	settings.WajibPenyesuaianStok = wajibPenyesuaianStok == 1
+
+	return settings, nil
+}
+
+// UpdateOtorisasiSettings saves the thresholds for supervisor overrides
+func (r *SettingsRepository) UpdateOtorisasiSettings(settings *models.OtorisasiSettings) error {
+	query := `
+		INSERT INTO otorisasi_settings (
+			id, batas_diskon_manual, batas_refund, wajib_no_sale, wajib_penyesuaian_stok, masa_berlaku_detik, updated_at
+		) VALUES (1, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			batas_diskon_manual = excluded.batas_diskon_manual,
+			batas_refund = excluded.batas_refund,
+			wajib_no_sale = excluded.wajib_no_sale,
+			wajib_penyesuaian_stok = excluded.wajib_penyesuaian_stok,
+			masa_berlaku_detik = excluded.masa_berlaku_detik,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := database.Exec(query, settings.BatasDiskonManual, settings.BatasRefund,
+		boolToInt(settings.WajibNoSale), boolToInt(settings.WajibPenyesuaianStok), settings.MasaBerlakuDetik)
+	if err != nil {
+		return fmt.Errorf("failed to update otorisasi settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultOtorisasiSettings returns the override thresholds used until one is configured
+func DefaultOtorisasiSettings() *models.OtorisasiSettings {
+	return &models.OtorisasiSettings{
+		BatasDiskonManual:    0,
+		BatasRefund:          100000,
+		WajibNoSale:          true,
+		WajibPenyesuaianStok: false,
+		MasaBerlakuDetik:     120,
+	}
+}
+
+// GetKeamananSettings retrieves the account and terminal lock settings
+func (r *SettingsRepository) GetKeamananSettings() (*models.KeamananSettings, error) {
+	query := `
+		SELECT auto_lock_menit, maks_gagal_login, durasi_kunci_menit,
+			COALESCE(durasi_kunci_maks_menit, 1440), COALESCE(panjang_min_password, 6), COALESCE(riwayat_password, 3)
+		FROM keamanan_settings
+		WHERE id = 1
+	`
+
+	settings := &models.KeamananSettings{}
+	err := database.QueryRow(query).Scan(
+		&settings.AutoLockMenit,
+		&settings.MaksGagalLogin,
+		&settings.DurasiKunciMenit,
+		&settings.DurasiKunciMaksMenit,
+		&settings.PanjangMinPassword,
+		&settings.RiwayatPassword,
+	)
+
+	if err == sql.ErrNoRows {
+		return DefaultKeamananSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keamanan settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpdateKeamananSettings saves the lockout, password policy and terminal lock settings
+func (r *SettingsRepository) UpdateKeamananSettings(settings *models.KeamananSettings) error {
+	query := `
+		INSERT INTO keamanan_settings (
+			id, auto_lock_menit, maks_gagal_login, durasi_kunci_menit,
+			durasi_kunci_maks_menit, panjang_min_password, riwayat_password, updated_at
+		) VALUES (1, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			auto_lock_menit = excluded.auto_lock_menit,
+			maks_gagal_login = excluded.maks_gagal_login,
+			durasi_kunci_menit = excluded.durasi_kunci_menit,
+			durasi_kunci_maks_menit = excluded.durasi_kunci_maks_menit,
+			panjang_min_password = excluded.panjang_min_password,
+			riwayat_password = excluded.riwayat_password,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := database.Exec(query, settings.AutoLockMenit, settings.MaksGagalLogin, settings.DurasiKunciMenit,
+		settings.DurasiKunciMaksMenit, settings.PanjangMinPassword, settings.RiwayatPassword)
+	if err != nil {
+		return fmt.Errorf("failed to update keamanan settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultKeamananSettings returns the lock settings used until one is configured
+func DefaultKeamananSettings() *models.KeamananSettings {
+	return &models.KeamananSettings{
+		AutoLockMenit:        5,
+		MaksGagalLogin:       5,
+		DurasiKunciMenit:     15,
+		DurasiKunciMaksMenit: 1440,
+		PanjangMinPassword:   6,
+		RiwayatPassword:      3,
+	}
+}
+
+// GetPembelianSettings retrieves how product costs are updated on goods receipts
+func (r *SettingsRepository) GetPembelianSettings() (*models.PembelianSettings, error) {
+	settings := &models.PembelianSettings{}
+	err := database.QueryRow(`SELECT metode_harga_beli FROM pembelian_settings WHERE id = 1`).Scan(&settings.MetodeHargaBeli)
+
+	if err == sql.ErrNoRows {
+		return DefaultPembelianSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pembelian settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpdatePembelianSettings saves the product cost update method
+func (r *SettingsRepository) UpdatePembelianSettings(settings *models.PembelianSettings) error {
+	query := `
+		INSERT INTO pembelian_settings (id, metode_harga_beli, updated_at)
+		VALUES (1, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			metode_harga_beli = excluded.metode_harga_beli,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := database.Exec(query, settings.MetodeHargaBeli); err != nil {
+		return fmt.Errorf("failed to update pembelian settings: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultPembelianSettings keeps product costs unchanged until a method is chosen
+func DefaultPembelianSettings() *models.PembelianSettings {
+	return &models.PembelianSettings{
+		MetodeHargaBeli: models.MetodeHargaBeliTetap,
+	}
+}
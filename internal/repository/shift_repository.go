@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -67,6 +68,20 @@ func (r *ShiftRepository) Init() error {
 		}
 	}
 
+	// Staff assignments live in a many-to-many table
+	_, err = r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS shift_staff (
+			shift_id BIGINT NOT NULL,
+			staff_id BIGINT NOT NULL,
+			PRIMARY KEY (shift_id, staff_id)
+		);`)
+	if err != nil {
+		return err
+	}
+	if err := r.migrateStaffIDs(); err != nil {
+		return err
+	}
+
 	// Check if empty, if so seed defaults
 	var count int
 	r.db.QueryRow("SELECT COUNT(*) FROM shift_settings").Scan(&count)
@@ -78,6 +93,34 @@ func (r *ShiftRepository) Init() error {
 	return nil
 }
 
+// migrateStaffIDs moves the legacy comma-separated staff_ids column into shift_staff
+func (r *ShiftRepository) migrateStaffIDs() error {
+	rows, err := r.db.Query(`SELECT id, staff_ids FROM shift_settings WHERE staff_ids IS NOT NULL AND staff_ids <> ''`)
+	if err != nil {
+		return err
+	}
+	legacy := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var staffIDs string
+		if err := rows.Scan(&id, &staffIDs); err != nil {
+			rows.Close()
+			return err
+		}
+		legacy[id] = staffIDs
+	}
+	rows.Close()
+
+	for id, staffIDs := range legacy {
+		// Invalid entries were ignored by the old comma-separated logic as well
+		ids, _ := parseStaffIDs(staffIDs)
+		if err := r.setStaff(id, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *ShiftRepository) replacePlaceholders(query string) string {
 	if !database.IsPostgreSQL() {
 		return query
@@ -91,14 +134,35 @@ func (r *ShiftRepository) replacePlaceholders(query string) string {
 }
 
 func (r *ShiftRepository) Create(shift *models.ShiftSetting) error {
-	query := `INSERT INTO shift_settings (name, start_time, end_time, staff_ids) VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO shift_settings (name, start_time, end_time, staff_ids) VALUES (?, ?, ?, '')`
 	query = r.replacePlaceholders(query)
-	_, err := r.db.Exec(query, shift.Name, shift.StartTime, shift.EndTime, shift.StaffIDs)
+	_, err := r.db.Exec(query, shift.Name, shift.StartTime, shift.EndTime)
 	return err
 }
 
 func (r *ShiftRepository) GetAll() ([]models.ShiftSetting, error) {
-	query := `SELECT id, name, start_time, end_time, staff_ids FROM shift_settings ORDER BY id ASC`
+	shifts, err := r.listShifts()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range shifts {
+		ids, err := r.GetStaffIDs(shifts[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		parts := make([]string, len(ids))
+		for j, id := range ids {
+			parts[j] = strconv.FormatInt(id, 10)
+		}
+		shifts[i].StaffIDs = strings.Join(parts, ",")
+	}
+	return shifts, nil
+}
+
+// listShifts returns the shift hours without staff assignments (used for time lookups)
+func (r *ShiftRepository) listShifts() ([]models.ShiftSetting, error) {
+	query := `SELECT id, name, start_time, end_time FROM shift_settings ORDER BY id ASC`
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -108,15 +172,67 @@ func (r *ShiftRepository) GetAll() ([]models.ShiftSetting, error) {
 	var shifts []models.ShiftSetting
 	for rows.Next() {
 		var s models.ShiftSetting
-		// Handle NULL for staff_ids if any
-		var staffIDs sql.NullString
-		if err := rows.Scan(&s.ID, &s.Name, &s.StartTime, &s.EndTime, &staffIDs); err != nil {
+		if err := rows.Scan(&s.ID, &s.Name, &s.StartTime, &s.EndTime); err != nil {
 			return nil, err
 		}
-		if staffIDs.Valid {
-			s.StaffIDs = staffIDs.String
-		} else {
-			s.StaffIDs = ""
+		shifts = append(shifts, s)
+	}
+	return shifts, nil
+}
+
+// GetByID returns a shift setting, or nil when it does not exist
+func (r *ShiftRepository) GetByID(id int) (*models.ShiftSetting, error) {
+	shifts, err := r.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := range shifts {
+		if shifts[i].ID == id {
+			return &shifts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetStaffIDs returns the staff assigned to a shift
+func (r *ShiftRepository) GetStaffIDs(shiftID int) ([]int64, error) {
+	query := r.replacePlaceholders(`SELECT staff_id FROM shift_staff WHERE shift_id = ? ORDER BY staff_id ASC`)
+	rows, err := r.db.Query(query, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// GetByStaff returns the shifts a staff member is assigned to
+func (r *ShiftRepository) GetByStaff(staffID int64) ([]models.ShiftSetting, error) {
+	query := r.replacePlaceholders(`
+		SELECT s.id, s.name, s.start_time, s.end_time
+		FROM shift_settings s
+		JOIN shift_staff ss ON ss.shift_id = s.id
+		WHERE ss.staff_id = ?
+		ORDER BY s.id ASC`)
+	rows, err := r.db.Query(query, staffID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shifts []models.ShiftSetting
+	for rows.Next() {
+		var s models.ShiftSetting
+		if err := rows.Scan(&s.ID, &s.Name, &s.StartTime, &s.EndTime); err != nil {
+			return nil, err
 		}
 		shifts = append(shifts, s)
 	}
@@ -124,9 +240,14 @@ func (r *ShiftRepository) GetAll() ([]models.ShiftSetting, error) {
 }
 
 func (r *ShiftRepository) Update(id int, startTime, endTime, staffIDs string) error {
-	query := `UPDATE shift_settings SET start_time = ?, end_time = ?, staff_ids = ? WHERE id = ?`
+	ids, err := parseStaffIDs(staffIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE shift_settings SET start_time = ?, end_time = ? WHERE id = ?`
 	query = r.replacePlaceholders(query)
-	res, err := r.db.Exec(query, startTime, endTime, staffIDs, id)
+	res, err := r.db.Exec(query, startTime, endTime, id)
 	if err != nil {
 		return err
 	}
@@ -137,7 +258,48 @@ func (r *ShiftRepository) Update(id int, startTime, endTime, staffIDs string) er
 	if rows == 0 {
 		return fmt.Errorf("shift setting with id %d not found", id)
 	}
-	return nil
+	return r.setStaff(id, ids)
+}
+
+// setStaff replaces the staff assignments of a shift and clears the legacy staff_ids column
+func (r *ShiftRepository) setStaff(shiftID int, staffIDs []int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(r.replacePlaceholders(`DELETE FROM shift_staff WHERE shift_id = ?`), shiftID); err != nil {
+		return err
+	}
+	insert := r.replacePlaceholders(`INSERT INTO shift_staff (shift_id, staff_id) VALUES (?, ?) ON CONFLICT DO NOTHING`)
+	for _, staffID := range staffIDs {
+		if _, err := tx.Exec(insert, shiftID, staffID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(r.replacePlaceholders(`UPDATE shift_settings SET staff_ids = '' WHERE id = ?`), shiftID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// parseStaffIDs parses a comma-separated staff ID list such as "1,2,5"
+func parseStaffIDs(staffIDs string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(staffIDs, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("staff ID '%s' tidak valid", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
 // Helper to parse time string "HH:MM"
@@ -149,7 +311,7 @@ func parseTime(tStr string) (int, int) {
 // GetActiveShiftName returns the active shift name (e.g. "shift1", "shift2") based on time
 // It mimics the old logic but uses DB values
 func (r *ShiftRepository) DetermineShift(t time.Time) string {
-	shifts, err := r.GetAll()
+	shifts, err := r.listShifts()
 	if err != nil {
 		// Fallback to defaults if DB fails
 		hour := t.Hour()
@@ -165,19 +327,53 @@ func (r *ShiftRepository) DetermineShift(t time.Time) string {
 	// Assuming shifts don't cross midnight for this MVP as per existing logic structure
 	// FORCE LOCAL TIME: Database might return UTC, so we must convert to Local (WIB)
 	// to match the shift settings which are in local wall-clock time.
+	if s := findShift(shifts, t); s != nil {
+		return s.Key()
+	}
+
+	return ""
+}
+
+// FindByTime returns the shift setting whose hours contain t, or nil
+func (r *ShiftRepository) FindByTime(t time.Time) (*models.ShiftSetting, error) {
+	shifts, err := r.listShifts()
+	if err != nil {
+		return nil, err
+	}
+	return findShift(shifts, t), nil
+}
+
+func findShift(shifts []models.ShiftSetting, t time.Time) *models.ShiftSetting {
 	localT := t.Local()
 	checkTime := localT.Format("15:04")
 
-	for _, s := range shifts {
+	for i := range shifts {
 		// Basic string comparison works for HH:MM format
+		if checkTime >= shifts[i].StartTime && checkTime < shifts[i].EndTime {
+			return &shifts[i]
+		}
+	}
+	return nil
+}
+
+// GetShiftEnd returns the end time of the shift that contains t.
+// If t falls outside every configured shift, the end of that day is returned.
+func (r *ShiftRepository) GetShiftEnd(t time.Time) time.Time {
+	localT := t.Local()
+	endOfDay := time.Date(localT.Year(), localT.Month(), localT.Day(), 23, 59, 59, 0, localT.Location())
+
+	shifts, err := r.GetAll()
+	if err != nil {
+		return endOfDay
+	}
+
+	checkTime := localT.Format("15:04")
+	for _, s := range shifts {
 		if checkTime >= s.StartTime && checkTime < s.EndTime {
-			if s.Name == "Shift 1" {
-				return "shift1"
-			} else if s.Name == "Shift 2" {
-				return "shift2"
-			}
+			hour, minute := parseTime(s.EndTime)
+			return time.Date(localT.Year(), localT.Month(), localT.Day(), hour, minute, 0, 0, localT.Location())
 		}
 	}
 
-	return ""
+	return endOfDay
 }
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// SupplierRepository handles database operations for suppliers
+type SupplierRepository struct{}
+
+// NewSupplierRepository creates a new repository instance
+func NewSupplierRepository() *SupplierRepository {
+	return &SupplierRepository{}
+}
+
+// Create saves a new supplier
+func (r *SupplierRepository) Create(s *models.Supplier) error {
+	now := time.Now().UTC()
+	s.CreatedAt = now
+	s.UpdatedAt = now
+
+	if database.UseDualMode && database.IsSQLite() {
+		id := database.GenerateOfflineID()
+		query := `
+			INSERT INTO supplier (id, kode, nama, kontak, telepon, email, alamat, catatan, aktif, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, id, s.Kode, s.Nama, s.Kontak, s.Telepon, s.Email, s.Alamat, s.Catatan,
+			boolToInt(s.Aktif), now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create supplier: %w", err)
+		}
+		s.ID = id
+		return nil
+	}
+
+	query := `
+		INSERT INTO supplier (kode, nama, kontak, telepon, email, alamat, catatan, aktif, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err := database.QueryRow(query, s.Kode, s.Nama, s.Kontak, s.Telepon, s.Email, s.Alamat, s.Catatan,
+		boolToInt(s.Aktif), now, now).Scan(&s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create supplier: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves changes to a supplier
+func (r *SupplierRepository) Update(s *models.Supplier) error {
+	query := `
+		UPDATE supplier
+		SET kode = ?, nama = ?, kontak = ?, telepon = ?, email = ?, alamat = ?, catatan = ?, aktif = ?, updated_at = ?
+		WHERE id = ?
+	`
+	s.UpdatedAt = time.Now().UTC()
+	result, err := database.Exec(query, s.Kode, s.Nama, s.Kontak, s.Telepon, s.Email, s.Alamat, s.Catatan,
+		boolToInt(s.Aktif), s.UpdatedAt, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update supplier: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier not found")
+	}
+
+	return nil
+}
+
+// Delete removes a supplier
+func (r *SupplierRepository) Delete(id int64) error {
+	result, err := database.Exec(`DELETE FROM supplier WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete supplier: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier not found")
+	}
+
+	return nil
+}
+
+// GetAll retrieves suppliers ordered by name, optionally only active ones
+func (r *SupplierRepository) GetAll(hanyaAktif bool) ([]*models.Supplier, error) {
+	query := `
+		SELECT id, kode, nama, kontak, telepon, email, alamat, catatan, aktif, created_at, updated_at
+		FROM supplier
+	`
+	if hanyaAktif {
+		query += ` WHERE aktif = 1`
+	}
+	query += ` ORDER BY nama ASC`
+
+	rows, err := database.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suppliers: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.Supplier{}
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier: %w", err)
+		}
+		list = append(list, s)
+	}
+
+	return list, nil
+}
+
+// GetByID retrieves a supplier by ID
+func (r *SupplierRepository) GetByID(id int64) (*models.Supplier, error) {
+	query := `
+		SELECT id, kode, nama, kontak, telepon, email, alamat, catatan, aktif, created_at, updated_at
+		FROM supplier WHERE id = ?
+	`
+
+	s, err := scanSupplier(database.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	return s, nil
+}
+
+// GetByKode retrieves a supplier by its code
+func (r *SupplierRepository) GetByKode(kode string) (*models.Supplier, error) {
+	query := `
+		SELECT id, kode, nama, kontak, telepon, email, alamat, catatan, aktif, created_at, updated_at
+		FROM supplier WHERE kode = ?
+	`
+
+	s, err := scanSupplier(database.QueryRow(query, kode))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	return s, nil
+}
+
+// CountUsage returns how many purchase orders, goods receipts and supplier returns reference the supplier
+func (r *SupplierRepository) CountUsage(id int64) (int, error) {
+	var count int
+	if err := database.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM pesanan_pembelian WHERE supplier_id = ?)
+		     + (SELECT COUNT(*) FROM penerimaan_barang WHERE supplier_id = ?)
+		     + (SELECT COUNT(*) FROM retur_supplier WHERE supplier_id = ?)
+	`, id, id, id).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count supplier usage: %w", err)
+	}
+	return count, nil
+}
+
+func scanSupplier(row rowScanner) (*models.Supplier, error) {
+	s := &models.Supplier{}
+	var kontak, telepon, email, alamat, catatan sql.NullString
+	var aktif int
+	err := row.Scan(&s.ID, &s.Kode, &s.Nama, &kontak, &telepon, &email, &alamat, &catatan, &aktif,
+		&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.Kontak = kontak.String
+	s.Telepon = telepon.String
+	s.Email = email.String
+	s.Alamat = alamat.String
+	s.Catatan = catatan.String
+	s.Aktif = aktif == 1
+	return s, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"ritel-app/internal/database"
+	"ritel-app/internal/models"
+)
+
+// TargetRepository handles database operations for sales targets
+type TargetRepository struct{}
+
+// NewTargetRepository creates a new repository instance
+func NewTargetRepository() *TargetRepository {
+	return &TargetRepository{}
+}
+
+const targetColumns = `id, periode, metrik, lingkup, COALESCE(shift_id, 0), COALESCE(staff_id, 0), nilai, aktif, created_at, updated_at`
+
+// Create saves a new target
+func (r *TargetRepository) Create(t *models.TargetPenjualan) error {
+	now := time.Now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	if database.UseDualMode && database.IsSQLite() {
+		t.ID = database.GenerateOfflineID()
+		query := `
+			INSERT INTO target_penjualan (id, periode, metrik, lingkup, shift_id, staff_id, nilai, aktif, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := database.Exec(query, t.ID, t.Periode, t.Metrik, t.Lingkup, t.ShiftID, t.StaffID, t.Nilai,
+			boolToInt(t.Aktif), now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create target: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO target_penjualan (periode, metrik, lingkup, shift_id, staff_id, nilai, aktif, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`
+	err := database.QueryRow(query, t.Periode, t.Metrik, t.Lingkup, t.ShiftID, t.StaffID, t.Nilai,
+		boolToInt(t.Aktif), now, now).Scan(&t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create target: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves changes to a target
+func (r *TargetRepository) Update(t *models.TargetPenjualan) error {
+	query := `
+		UPDATE target_penjualan
+		SET periode = ?, metrik = ?, lingkup = ?, shift_id = ?, staff_id = ?, nilai = ?, aktif = ?, updated_at = ?
+		WHERE id = ?
+	`
+	t.UpdatedAt = time.Now().UTC()
+	result, err := database.Exec(query, t.Periode, t.Metrik, t.Lingkup, t.ShiftID, t.StaffID, t.Nilai,
+		boolToInt(t.Aktif), t.UpdatedAt, t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update target: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("target not found")
+	}
+
+	return nil
+}
+
+// Delete removes a target
+func (r *TargetRepository) Delete(id int64) error {
+	result, err := database.Exec(`DELETE FROM target_penjualan WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete target: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("target not found")
+	}
+
+	return nil
+}
+
+// GetAll retrieves all targets, optionally filtered by scope
+func (r *TargetRepository) GetAll(lingkup string) ([]*models.TargetPenjualan, error) {
+	query := `SELECT ` + targetColumns + ` FROM target_penjualan`
+	var args []interface{}
+	if lingkup != "" {
+		query += ` WHERE lingkup = ?`
+		args = append(args, lingkup)
+	}
+	query += ` ORDER BY lingkup ASC, shift_id ASC, staff_id ASC, metrik ASC, periode ASC`
+
+	return r.query(query, args...)
+}
+
+// GetAktif retrieves the active targets of a scope. For shift and staff scopes both the
+// targets of the given shift/staff and the generic ones (ID 0) are returned.
+func (r *TargetRepository) GetAktif(lingkup string, shiftID int, staffID int64) ([]*models.TargetPenjualan, error) {
+	query := `SELECT ` + targetColumns + ` FROM target_penjualan WHERE aktif = 1 AND lingkup = ?`
+	args := []interface{}{lingkup}
+	switch lingkup {
+	case "shift":
+		query += ` AND (shift_id = ? OR shift_id = 0)`
+		args = append(args, shiftID)
+	case "staff":
+		query += ` AND (staff_id = ? OR staff_id = 0)`
+		args = append(args, staffID)
+	}
+
+	return r.query(query, args...)
+}
+
+// GetByID retrieves a target by ID
+func (r *TargetRepository) GetByID(id int64) (*models.TargetPenjualan, error) {
+	list, err := r.query(`SELECT `+targetColumns+` FROM target_penjualan WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (r *TargetRepository) query(query string, args ...interface{}) ([]*models.TargetPenjualan, error) {
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query targets: %w", err)
+	}
+	defer rows.Close()
+
+	list := []*models.TargetPenjualan{}
+	for rows.Next() {
+		t, err := scanTargetPenjualan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan target: %w", err)
+		}
+		list = append(list, t)
+	}
+
+	return list, nil
+}
+
+func scanTargetPenjualan(row rowScanner) (*models.TargetPenjualan, error) {
+	t := &models.TargetPenjualan{}
+	var aktif int
+	err := row.Scan(&t.ID, &t.Periode, &t.Metrik, &t.Lingkup, &t.ShiftID, &t.StaffID, &t.Nilai, &aktif,
+		&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.Aktif = aktif == 1
+	return t, nil
+}
@@ -2,10 +2,10 @@ package repository
 
 import (
 	"context"
-	"crypto/rand"
 	"database/sql"
 	"fmt"
-	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
 	"ritel-app/internal/database"
@@ -13,49 +13,100 @@ import (
 )
 
 type TransaksiRepository struct {
-	db        *sql.DB
-	batchRepo *BatchRepository
+	db            *sql.DB
+	batchRepo     *BatchRepository
+	saldoRepo     *SaldoPelangganRepository
+	piutangRepo   *PiutangRepository
+	otorisasiRepo *OtorisasiRepository
 }
 
 func NewTransaksiRepository() *TransaksiRepository {
 	return &TransaksiRepository{
-		db:        database.DB,
-		batchRepo: NewBatchRepository(),
+		db:            database.DB,
+		batchRepo:     NewBatchRepository(),
+		saldoRepo:     NewSaldoPelangganRepository(),
+		piutangRepo:   NewPiutangRepository(),
+		otorisasiRepo: NewOtorisasiRepository(),
 	}
 }
 
-// GenerateNomorTransaksi generates a unique transaction number with random digits
-func (r *TransaksiRepository) GenerateNomorTransaksi() (string, error) {
-	if database.DB == nil {
-		return "", fmt.Errorf("database connection is not initialized")
+// NomorTransaksiPrefix builds the part of a transaction number before the sequence
+// (e.g. "STORE1-T02-20261016-") and the key of the counter that numbers it.
+func NomorTransaksiPrefix(settings *models.PenomoranSettings, kodeTerminal string, tanggal time.Time) (prefix string, counterKey string) {
+	parts := []string{settings.KodeToko, kodeTerminal}
+	if settings.FormatTanggal != "" {
+		parts = append(parts, tanggal.Format(settings.FormatTanggal))
 	}
 
-	// Try to generate a unique number (max 10 attempts)
-	for attempt := 0; attempt < 10; attempt++ {
-		// Generate random 8-digit number using crypto/rand for better randomness
-		randomBig, err := rand.Int(rand.Reader, big.NewInt(90000000))
-		if err != nil {
-			return "", fmt.Errorf("failed to generate random number: %w", err)
+	var nonEmpty []string
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
 		}
-		randomNum := randomBig.Int64() + 10000000 // Generates 10000000-99999999
-		nomorTransaksi := fmt.Sprintf("TRX-%d", randomNum)
+	}
+	prefix = strings.Join(nonEmpty, settings.Pemisah) + settings.Pemisah
 
-		// Check if this number already exists
-		var exists int
-		query := `SELECT COUNT(*) FROM transaksi WHERE nomor_transaksi = ?`
-		err = database.QueryRow(query, nomorTransaksi).Scan(&exists)
-		if err != nil {
-			return "", fmt.Errorf("failed to check transaction number uniqueness: %w", err)
-		}
+	counterKey = prefix
+	if !settings.ResetHarian && settings.FormatTanggal != "" {
+		// Sequence continues across days: one counter per store/terminal
+		counterKey = settings.KodeToko + settings.Pemisah + kodeTerminal + settings.Pemisah + "*"
+	}
+
+	return prefix, counterKey
+}
+
+// reserveNomorTransaksi reserves the next sequential transaction number inside the insert transaction.
+// A rolled-back sale releases its number, so the sequence stays gap-free.
+func (r *TransaksiRepository) reserveNomorTransaksi(tx *sql.Tx, settings *models.PenomoranSettings, kodeTerminal string, tanggal time.Time) (string, error) {
+	prefix, counterKey := NomorTransaksiPrefix(settings, kodeTerminal, tanggal)
+
+	// 1. Ambil (dan kunci) counter saat ini
+	selectQuery := `SELECT last_number FROM nomor_transaksi_counter WHERE counter_key = ?`
+	if database.IsPostgreSQL() {
+		selectQuery += ` FOR UPDATE`
+	}
+	var lastNumber int64
+	err := tx.QueryRow(database.TranslateQuery(selectQuery), counterKey).Scan(&lastNumber)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to read transaction counter: %w", err)
+	}
 
-		// If number doesn't exist, use it
-		if exists == 0 {
-			return nomorTransaksi, nil
+	// 2. Jangan pernah di bawah nomor yang sudah ada (mis. hasil sync atau database dipulihkan)
+	likePattern := prefix + "%"
+	if counterKey != prefix {
+		likePattern = settings.KodeToko + settings.Pemisah + kodeTerminal + settings.Pemisah + "%"
+	}
+	var highest string
+	err = tx.QueryRow(database.TranslateQuery(`
+		SELECT nomor_transaksi FROM transaksi
+		WHERE nomor_transaksi LIKE ?
+		ORDER BY LENGTH(nomor_transaksi) DESC, nomor_transaksi DESC
+		LIMIT 1
+	`), likePattern).Scan(&highest)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to read last transaction number: %w", err)
+	}
+	if highest != "" {
+		seqPart := highest[strings.LastIndex(highest, settings.Pemisah)+len(settings.Pemisah):]
+		if existing, convErr := strconv.ParseInt(seqPart, 10, 64); convErr == nil && existing > lastNumber {
+			lastNumber = existing
 		}
 	}
 
-	// If we couldn't generate a unique number after 10 attempts, return error
-	return "", fmt.Errorf("failed to generate unique transaction number after 10 attempts")
+	// 3. Simpan counter baru
+	nextNumber := lastNumber + 1
+	_, err = tx.Exec(database.TranslateQuery(`
+		INSERT INTO nomor_transaksi_counter (counter_key, last_number, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(counter_key) DO UPDATE SET
+			last_number = excluded.last_number,
+			updated_at = excluded.updated_at
+	`), counterKey, nextNumber, time.Now().UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to reserve transaction number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%0*d", prefix, settings.PanjangUrutan, nextNumber), nil
 }
 
 // Create creates a new transaction with items and payments
@@ -96,13 +147,11 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 	}
 	defer tx.Rollback()
 
-	// Generate transaction number (pass nil DB to likely use global or fix if needed,
-	// but better to just use connection pool since we fixed the deadlock).
-	// Ideally we should use tx, but GenerateNomorTransaksi is on Repository which uses global DB.
-	// Since we fixed the pool size, this dead-lock should be gone.
-	nomorTransaksi, err := r.GenerateNomorTransaksi()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate transaction number: %w", err)
+	// Spend the supervisor override tokens with the sale, so a failed sale leaves them unused
+	for _, pemakaian := range req.Otorisasi {
+		if err := r.otorisasiRepo.PakaiTx(tx, pemakaian, time.Now()); err != nil {
+			return nil, err
+		}
 	}
 
 	// Calculate totals (support berat or quantity)
@@ -120,6 +169,10 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 	}
 
 	total := subtotal - req.Diskon
+	if req.ModePajak == "exclusive" {
+		// PPN dihitung di service layer dan ditambahkan di atas harga
+		total += req.TotalPajak
+	}
 
 	// Calculate total payment
 	totalBayar := 0
@@ -127,7 +180,8 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 		totalBayar += payment.Jumlah
 	}
 
-	kembalian := totalBayar - total
+	// Pembulatan tunai dan donasi dihitung di service layer
+	kembalian := totalBayar - (total + req.Pembulatan + req.Donasi)
 	if kembalian < 0 {
 		return nil, fmt.Errorf("pembayaran tidak mencukupi")
 	}
@@ -140,13 +194,14 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 	query := `INSERT INTO transaksi (
 		nomor_transaksi, pelanggan_id, pelanggan_nama, pelanggan_telp,
 		subtotal, diskon_promo, diskon_pelanggan, poin_ditukar, diskon_poin, diskon, total, total_bayar, kembalian,
-		status, catatan, kasir, staff_id, staff_nama, created_at, tanggal
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
+		status, catatan, kasir, staff_id, staff_nama, created_at, tanggal, idempotency_key, pembulatan, donasi,
+		total_pajak, mode_pajak, shift_session_id, diskon_manual, disetujui_oleh
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
 	query = database.TranslateQuery(query)
 
-	// Calculate diskon_poin from poin_ditukar (will be set in service layer)
+	// diskon_poin is calculated in service layer
 	poinDitukar := req.PoinDitukar
-	diskonPoin := 0 // Will be calculated in service layer
+	diskonPoin := req.DiskonPoin
 
 	// Decouple created_at (Display/System Time) and tanggal (Business/Report Date)
 	// created_at: Real UTC time, allows frontend/browser to convert to local time correctly
@@ -157,19 +212,47 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 	wib := time.FixedZone("WIB", 7*3600)
 	tanggal := time.Now().In(wib)
 
+	// Reserve the sequential transaction number inside this transaction
+	penomoran, err := NewSettingsRepository().GetPenomoranSettings()
+	if err != nil {
+		return nil, err
+	}
+	kodeTerminal := penomoran.KodeTerminal
+	if req.TerminalID != "" {
+		kodeTerminal = req.TerminalID
+	}
+	nomorTransaksi, err := r.reserveNomorTransaksi(tx, penomoran, kodeTerminal, tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transactions outside a cash session keep NULL and fall back to clock-time shift reports
+	var shiftSessionID interface{}
+	if req.ShiftSessionID != 0 {
+		shiftSessionID = req.ShiftSessionID
+	}
+
+	// Empty keys are stored as NULL so the unique index only applies to real keys
+	var idempotencyKey interface{}
+	if req.IdempotencyKey != "" {
+		idempotencyKey = req.IdempotencyKey
+	}
+
 	var transaksiID int64
 	if database.UseDualMode && database.IsSQLite() {
 		transaksiID = database.GenerateOfflineID()
 		query := `INSERT INTO transaksi (
 			id, nomor_transaksi, pelanggan_id, pelanggan_nama, pelanggan_telp,
 			subtotal, diskon_promo, diskon_pelanggan, poin_ditukar, diskon_poin, diskon, total, total_bayar, kembalian,
-			status, catatan, kasir, staff_id, staff_nama, created_at, tanggal
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			status, catatan, kasir, staff_id, staff_nama, created_at, tanggal, idempotency_key, pembulatan, donasi,
+			total_pajak, mode_pajak, shift_session_id, diskon_manual, disetujui_oleh
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 		query = database.TranslateQuery(query)
 		_, err = tx.Exec(query,
 			transaksiID, nomorTransaksi, req.PelangganID, req.PelangganNama, req.PelangganTelp,
 			subtotal, diskonPromo, diskonPelanggan, poinDitukar, diskonPoin, req.Diskon, total, totalBayar, kembalian,
-			"selesai", req.Catatan, req.Kasir, req.StaffID, req.StaffNama, createdAt, tanggal,
+			"selesai", req.Catatan, req.Kasir, req.StaffID, req.StaffNama, createdAt, tanggal, idempotencyKey,
+			req.Pembulatan, req.Donasi, req.TotalPajak, req.ModePajak, shiftSessionID, req.DiskonManual, req.DisetujuiOleh,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert transaction: %w", err)
@@ -178,7 +261,8 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 		err = tx.QueryRow(query,
 			nomorTransaksi, req.PelangganID, req.PelangganNama, req.PelangganTelp,
 			subtotal, diskonPromo, diskonPelanggan, poinDitukar, diskonPoin, req.Diskon, total, totalBayar, kembalian,
-			"selesai", req.Catatan, req.Kasir, req.StaffID, req.StaffNama, createdAt, tanggal,
+			"selesai", req.Catatan, req.Kasir, req.StaffID, req.StaffNama, createdAt, tanggal, idempotencyKey,
+			req.Pembulatan, req.Donasi, req.TotalPajak, req.ModePajak, shiftSessionID, req.DiskonManual, req.DisetujuiOleh,
 		).Scan(&transaksiID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert transaction: %w", err)
@@ -188,15 +272,20 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 	// Insert transaction items
 	itemQuery := `INSERT INTO transaksi_item (
 		transaksi_id, produk_id, produk_sku, produk_nama,
-		produk_kategori, harga_satuan, jumlah, beratgram, subtotal, created_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		produk_kategori, harga_satuan, jumlah, beratgram, subtotal,
+		tarif_pajak, dpp, pajak, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
 	itemQuery = database.TranslateQuery(itemQuery)
 	itemQueryWithID := database.TranslateQuery(`INSERT INTO transaksi_item (
 		id, transaksi_id, produk_id, produk_sku, produk_nama,
-		produk_kategori, harga_satuan, jumlah, beratgram, subtotal, created_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		produk_kategori, harga_satuan, jumlah, beratgram, subtotal,
+		tarif_pajak, dpp, pajak, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 
-	for _, item := range req.Items {
+	// Keep inserted item IDs so the promo breakdown can reference them
+	itemIDs := make([]int64, len(req.Items))
+
+	for idx, item := range req.Items {
 		// Get product details
 		var produk models.Produk
 		productQuery := database.TranslateQuery(`SELECT sku, nama, kategori, stok, satuan FROM produk WHERE id = ?`)
@@ -238,23 +327,25 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 		}
 
 		// Insert item
+		var itemID int64
 		if database.UseDualMode && database.IsSQLite() {
-			itemID := database.GenerateOfflineID()
+			itemID = database.GenerateOfflineID()
 			_, err = tx.Exec(itemQueryWithID,
 				itemID, transaksiID, item.ProdukID, produk.SKU, produk.Nama,
 				produk.Kategori, item.HargaSatuan, item.Jumlah, item.BeratGram, itemSubtotal,
-				createdAt,
+				item.TarifPajak, item.DPP, item.Pajak, createdAt,
 			)
 		} else {
-			_, err = tx.Exec(itemQuery,
+			err = tx.QueryRow(itemQuery,
 				transaksiID, item.ProdukID, produk.SKU, produk.Nama,
 				produk.Kategori, item.HargaSatuan, item.Jumlah, item.BeratGram, itemSubtotal,
-				createdAt,
-			)
+				item.TarifPajak, item.DPP, item.Pajak, createdAt,
+			).Scan(&itemID)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert transaction item: %w", err)
 		}
+		itemIDs[idx] = itemID
 
 		// Update product stock
 		updateStockQuery := database.TranslateQuery(`UPDATE produk SET stok = stok - ? WHERE id = ?`)
@@ -344,6 +435,29 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 		}
 	}
 
+	// Insert per-item promo breakdown
+	promoQuery := database.TranslateQuery(`INSERT INTO transaksi_item_promo (
+		transaksi_id, transaksi_item_id, produk_id, promo_id, promo_kode, promo_nama, tipe_promo, diskon, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	promoQueryWithID := database.TranslateQuery(`INSERT INTO transaksi_item_promo (
+		id, transaksi_id, transaksi_item_id, produk_id, promo_id, promo_kode, promo_nama, tipe_promo, diskon, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	for _, alloc := range req.PromoBreakdown {
+		if alloc.ItemIndex < 0 || alloc.ItemIndex >= len(itemIDs) {
+			return nil, fmt.Errorf("invalid promo allocation for item index %d", alloc.ItemIndex)
+		}
+		if database.UseDualMode && database.IsSQLite() {
+			_, err = tx.Exec(promoQueryWithID, database.GenerateOfflineID(), transaksiID, itemIDs[alloc.ItemIndex], alloc.ProdukID,
+				alloc.PromoID, alloc.PromoKode, alloc.PromoNama, alloc.TipePromo, alloc.Diskon, createdAt)
+		} else {
+			_, err = tx.Exec(promoQuery, transaksiID, itemIDs[alloc.ItemIndex], alloc.ProdukID,
+				alloc.PromoID, alloc.PromoKode, alloc.PromoNama, alloc.TipePromo, alloc.Diskon, createdAt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert promo breakdown: %w", err)
+		}
+	}
+
 	// Insert payments
 	paymentQuery := `INSERT INTO pembayaran (transaksi_id, metode, jumlah, referensi, created_at) VALUES (?, ?, ?, ?, ?)`
 	paymentQuery = database.TranslateQuery(paymentQuery)
@@ -358,6 +472,43 @@ func (r *TransaksiRepository) Create(req *models.CreateTransaksiRequest) (*model
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert payment: %w", err)
 		}
+
+		// Pembayaran dengan saldo pelanggan langsung memotong saldo di transaksi yang sama
+		if payment.Metode == models.MetodeSaldo {
+			if req.PelangganID == 0 {
+				return nil, fmt.Errorf("pembayaran saldo hanya untuk pelanggan terdaftar")
+			}
+			mutasi := &models.SaldoPelangganHistory{
+				PelangganID: req.PelangganID,
+				Tipe:        "pembayaran",
+				Jumlah:      -payment.Jumlah,
+				TransaksiID: transaksiID,
+				Referensi:   nomorTransaksi,
+				Keterangan:  fmt.Sprintf("Pembayaran transaksi %s", nomorTransaksi),
+				StaffID:     req.StaffID,
+				StaffNama:   req.StaffNama,
+			}
+			if err := r.saldoRepo.MutasiTx(tx, mutasi); err != nil {
+				return nil, err
+			}
+		}
+
+		// Pembayaran tempo menjadi piutang pelanggan
+		if payment.Metode == models.MetodeTempo {
+			if req.PelangganID == 0 {
+				return nil, fmt.Errorf("pembayaran tempo hanya untuk pelanggan terdaftar")
+			}
+			piutang := &models.Piutang{
+				TransaksiID:    transaksiID,
+				NomorTransaksi: nomorTransaksi,
+				PelangganID:    req.PelangganID,
+				Jumlah:         payment.Jumlah,
+				JatuhTempo:     req.JatuhTempo,
+			}
+			if err := r.piutangRepo.CreateTx(tx, piutang); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Commit transaction
@@ -380,6 +531,8 @@ func (r *TransaksiRepository) GetByNomorTransaksi(nomorTransaksi string) (*model
 	query := `SELECT
 		id, nomor_transaksi, tanggal, pelanggan_id, pelanggan_nama, pelanggan_telp,
 		subtotal, diskon_promo, diskon_pelanggan, poin_ditukar, diskon_poin, diskon, total, total_bayar, kembalian,
+		COALESCE(pembulatan, 0), COALESCE(donasi, 0), COALESCE(total_pajak, 0), COALESCE(mode_pajak, ''),
+		COALESCE(diskon_manual, 0), COALESCE(disetujui_oleh, ''),
 		status, catatan, kasir, created_at
 	FROM transaksi WHERE nomor_transaksi = ?`
 
@@ -388,7 +541,8 @@ func (r *TransaksiRepository) GetByNomorTransaksi(nomorTransaksi string) (*model
 		&transaksi.ID, &transaksi.NomorTransaksi, &transaksi.Tanggal,
 		&transaksi.PelangganID, &transaksi.PelangganNama, &transaksi.PelangganTelp,
 		&transaksi.Subtotal, &transaksi.DiskonPromo, &transaksi.DiskonPelanggan, &transaksi.PoinDitukar, &transaksi.DiskonPoin, &transaksi.Diskon, &transaksi.Total,
-		&transaksi.TotalBayar, &transaksi.Kembalian,
+		&transaksi.TotalBayar, &transaksi.Kembalian, &transaksi.Pembulatan, &transaksi.Donasi, &transaksi.TotalPajak, &transaksi.ModePajak,
+		&transaksi.DiskonManual, &transaksi.DisetujuiOleh,
 		&transaksi.Status, &transaksi.Catatan, &transaksi.Kasir,
 		&transaksi.CreatedAt,
 	)
@@ -402,7 +556,8 @@ func (r *TransaksiRepository) GetByNomorTransaksi(nomorTransaksi string) (*model
 	// Get transaction items
 	itemQuery := `SELECT
 		id, transaksi_id, produk_id, produk_sku, produk_nama,
-		produk_kategori, harga_satuan, jumlah, beratgram, subtotal, created_at
+		produk_kategori, harga_satuan, jumlah, beratgram, subtotal,
+		COALESCE(tarif_pajak, 0), COALESCE(dpp, 0), COALESCE(pajak, 0), created_at
 	FROM transaksi_item WHERE transaksi_id = ?`
 
 	rows, err := database.Query(itemQuery, transaksi.ID)
@@ -418,7 +573,8 @@ func (r *TransaksiRepository) GetByNomorTransaksi(nomorTransaksi string) (*model
 		err := rows.Scan(
 			&item.ID, &item.TransaksiID, &produkID, &item.ProdukSKU,
 			&item.ProdukNama, &item.ProdukKategori, &item.HargaSatuan,
-			&item.Jumlah, &item.BeratGram, &item.Subtotal, &item.CreatedAt,
+			&item.Jumlah, &item.BeratGram, &item.Subtotal,
+			&item.TarifPajak, &item.DPP, &item.Pajak, &item.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan transaction item: %w", err)
@@ -458,13 +614,32 @@ func (r *TransaksiRepository) GetByNomorTransaksi(nomorTransaksi string) (*model
 		pembayaran = append(pembayaran, payment)
 	}
 
+	promos, err := r.GetItemPromos(transaksi.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.TransaksiDetail{
 		Transaksi:  transaksi,
 		Items:      items,
 		Pembayaran: pembayaran,
+		Promos:     promos,
 	}, nil
 }
 
+// GetByIdempotencyKey returns the transaction created with the given idempotency key, or nil
+func (r *TransaksiRepository) GetByIdempotencyKey(key string) (*models.TransaksiDetail, error) {
+	var id int64
+	err := database.QueryRow("SELECT id FROM transaksi WHERE idempotency_key = ?", key).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction by idempotency key: %w", err)
+	}
+	return r.GetByID(id)
+}
+
 func (r *TransaksiRepository) GetByID(id int64) (*models.TransaksiDetail, error) {
 
 	// Check if database connection is nil
@@ -476,6 +651,8 @@ func (r *TransaksiRepository) GetByID(id int64) (*models.TransaksiDetail, error)
 	query := `SELECT
 		id, nomor_transaksi, tanggal, pelanggan_id, pelanggan_nama, pelanggan_telp,
 		subtotal, diskon_promo, diskon_pelanggan, poin_ditukar, diskon_poin, diskon, total, total_bayar, kembalian,
+		COALESCE(pembulatan, 0), COALESCE(donasi, 0), COALESCE(total_pajak, 0), COALESCE(mode_pajak, ''),
+		COALESCE(diskon_manual, 0), COALESCE(disetujui_oleh, ''),
 		status, catatan, kasir, created_at
 	FROM transaksi WHERE id = ?`
 
@@ -484,7 +661,8 @@ func (r *TransaksiRepository) GetByID(id int64) (*models.TransaksiDetail, error)
 		&transaksi.ID, &transaksi.NomorTransaksi, &transaksi.Tanggal,
 		&transaksi.PelangganID, &transaksi.PelangganNama, &transaksi.PelangganTelp,
 		&transaksi.Subtotal, &transaksi.DiskonPromo, &transaksi.DiskonPelanggan, &transaksi.PoinDitukar, &transaksi.DiskonPoin, &transaksi.Diskon, &transaksi.Total,
-		&transaksi.TotalBayar, &transaksi.Kembalian,
+		&transaksi.TotalBayar, &transaksi.Kembalian, &transaksi.Pembulatan, &transaksi.Donasi, &transaksi.TotalPajak, &transaksi.ModePajak,
+		&transaksi.DiskonManual, &transaksi.DisetujuiOleh,
 		&transaksi.Status, &transaksi.Catatan, &transaksi.Kasir,
 		&transaksi.CreatedAt,
 	)
@@ -495,7 +673,8 @@ func (r *TransaksiRepository) GetByID(id int64) (*models.TransaksiDetail, error)
 	// Get transaction items
 	itemQuery := `SELECT
 		id, transaksi_id, produk_id, produk_sku, produk_nama,
-		produk_kategori, harga_satuan, jumlah, beratgram, subtotal, created_at
+		produk_kategori, harga_satuan, jumlah, beratgram, subtotal,
+		COALESCE(tarif_pajak, 0), COALESCE(dpp, 0), COALESCE(pajak, 0), created_at
 	FROM transaksi_item WHERE transaksi_id = ?`
 
 	rows, err := database.Query(itemQuery, id)
@@ -512,7 +691,8 @@ func (r *TransaksiRepository) GetByID(id int64) (*models.TransaksiDetail, error)
 		err := rows.Scan(
 			&item.ID, &item.TransaksiID, &produkID, &item.ProdukSKU,
 			&item.ProdukNama, &item.ProdukKategori, &item.HargaSatuan,
-			&item.Jumlah, &item.BeratGram, &item.Subtotal, &item.CreatedAt,
+			&item.Jumlah, &item.BeratGram, &item.Subtotal,
+			&item.TarifPajak, &item.DPP, &item.Pajak, &item.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan transaction item: %w", err)
@@ -558,15 +738,261 @@ func (r *TransaksiRepository) GetByID(id int64) (*models.TransaksiDetail, error)
 
 	}
 
+	promos, err := r.GetItemPromos(id)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &models.TransaksiDetail{
 		Transaksi:  transaksi,
 		Items:      items,
 		Pembayaran: pembayaran,
+		Promos:     promos,
 	}
 
 	return result, nil
 }
 
+// GetItemPromos retrieves the per-item promo discount breakdown of a transaction
+func (r *TransaksiRepository) GetItemPromos(transaksiID int64) ([]*models.TransaksiItemPromo, error) {
+	query := `SELECT id, transaksi_id, transaksi_item_id, produk_id, promo_id, promo_kode, promo_nama, tipe_promo, diskon, created_at
+		FROM transaksi_item_promo WHERE transaksi_id = ? ORDER BY id`
+
+	rows, err := database.Query(query, transaksiID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promo breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	var promos []*models.TransaksiItemPromo
+	for rows.Next() {
+		p := &models.TransaksiItemPromo{}
+		var promoNama, tipePromo sql.NullString
+		if err := rows.Scan(
+			&p.ID, &p.TransaksiID, &p.TransaksiItemID, &p.ProdukID, &p.PromoID,
+			&p.PromoKode, &promoNama, &tipePromo, &p.Diskon, &p.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan promo breakdown: %w", err)
+		}
+		p.PromoNama = promoNama.String
+		p.TipePromo = tipePromo.String
+		promos = append(promos, p)
+	}
+
+	return promos, nil
+}
+
+// UpdatePoinDidapat records the reward points a customer earned from a transaction
+func (r *TransaksiRepository) UpdatePoinDidapat(transaksiID int64, poin int) error {
+	query := `UPDATE transaksi SET poin_didapat = ? WHERE id = ?`
+	if _, err := database.Exec(query, poin, transaksiID); err != nil {
+		return fmt.Errorf("failed to update earned points: %w", err)
+	}
+	return nil
+}
+
+// GetPoinDidapat returns the reward points recorded for a transaction
+func (r *TransaksiRepository) GetPoinDidapat(transaksiID int64) (int, error) {
+	var poin sql.NullInt64
+	query := `SELECT poin_didapat FROM transaksi WHERE id = ?`
+	if err := database.QueryRow(query, transaksiID).Scan(&poin); err != nil {
+		return 0, fmt.Errorf("failed to get earned points: %w", err)
+	}
+	return int(poin.Int64), nil
+}
+
+// Void cancels a completed transaction in a single database transaction:
+// batch quantities recorded in transaksi_batch are restored, product stock is
+// returned (with stok_history entries) and the transaction is marked "void".
+// An override token that approved the void is spent in the same transaction.
+func (r *TransaksiRepository) Void(transaksiID int64, alasan, voidBy, approvedBy string, otorisasi *models.PemakaianOtorisasi) error {
+	db := database.DB
+	if db == nil {
+		return fmt.Errorf("database connection is not initialized")
+	}
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Hanya transaksi "selesai" yang bisa di-void
+	var status, nomorTransaksi string
+	var pelangganID sql.NullInt64
+	err = tx.QueryRow(database.TranslateQuery(`SELECT status, nomor_transaksi, pelanggan_id FROM transaksi WHERE id = ?`), transaksiID).
+		Scan(&status, &nomorTransaksi, &pelangganID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("transaksi tidak ditemukan")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if status != "selesai" {
+		return fmt.Errorf("transaksi dengan status '%s' tidak dapat di-void", status)
+	}
+
+	if otorisasi != nil {
+		if err := r.otorisasiRepo.PakaiTx(tx, *otorisasi, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// 2. Kembalikan qty ke batch yang persis dipakai saat penjualan
+	batchRows, err := tx.Query(database.TranslateQuery(`
+		SELECT batch_id, qty_diambil FROM transaksi_batch WHERE transaksi_id = ?
+	`), transaksiID)
+	if err != nil {
+		return fmt.Errorf("failed to get batch usage: %w", err)
+	}
+	var usages []models.TransaksiBatch
+	for batchRows.Next() {
+		var tb models.TransaksiBatch
+		if err := batchRows.Scan(&tb.BatchID, &tb.QtyDiambil); err != nil {
+			batchRows.Close()
+			return fmt.Errorf("failed to scan batch usage: %w", err)
+		}
+		usages = append(usages, tb)
+	}
+	batchRows.Close()
+
+	for _, usage := range usages {
+		if err := r.batchRepo.RestoreQtyTx(tx, usage.BatchID, usage.QtyDiambil); err != nil {
+			return fmt.Errorf("failed to restore batch %s: %w", usage.BatchID, err)
+		}
+	}
+
+	// 3. Kembalikan stok produk per item
+	itemRows, err := tx.Query(database.TranslateQuery(`
+		SELECT produk_id, jumlah, beratgram FROM transaksi_item
+		WHERE transaksi_id = ? AND produk_id IS NOT NULL
+	`), transaksiID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction items: %w", err)
+	}
+	type voidItem struct {
+		ProdukID  int
+		Jumlah    int
+		BeratGram float64
+	}
+	var items []voidItem
+	for itemRows.Next() {
+		var it voidItem
+		var beratGram sql.NullFloat64
+		if err := itemRows.Scan(&it.ProdukID, &it.Jumlah, &beratGram); err != nil {
+			itemRows.Close()
+			return fmt.Errorf("failed to scan transaction item: %w", err)
+		}
+		it.BeratGram = beratGram.Float64
+		items = append(items, it)
+	}
+	itemRows.Close()
+
+	historyQuery := database.TranslateQuery(`
+		INSERT INTO stok_history (
+			produk_id, stok_sebelum, stok_sesudah, perubahan,
+			jenis_perubahan, keterangan, tipe_kerugian, nilai_kerugian
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	for _, item := range items {
+		var stok float64
+		var satuan sql.NullString
+		err := tx.QueryRow(database.TranslateQuery(`SELECT stok, satuan FROM produk WHERE id = ?`), item.ProdukID).
+			Scan(&stok, &satuan)
+		if err == sql.ErrNoRows {
+			continue // Produk sudah dihapus permanen
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		// Same conversion as Create: gram for "gram" products, kg otherwise
+		var stockToRestore float64
+		if item.BeratGram > 0 {
+			if satuan.String == "gram" {
+				stockToRestore = item.BeratGram
+			} else {
+				stockToRestore = item.BeratGram / 1000.0
+			}
+		} else {
+			stockToRestore = float64(item.Jumlah)
+		}
+
+		if _, err := tx.Exec(database.TranslateQuery(`UPDATE produk SET stok = stok + ? WHERE id = ?`), stockToRestore, item.ProdukID); err != nil {
+			return fmt.Errorf("failed to restore product stock: %w", err)
+		}
+
+		_, err = tx.Exec(historyQuery,
+			item.ProdukID, stok, stok+stockToRestore, stockToRestore,
+			"void", fmt.Sprintf("Void transaksi %s", nomorTransaksi), "", 0,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create stock history: %w", err)
+		}
+	}
+
+	// 4. Kembalikan pembayaran yang memakai saldo pelanggan
+	var saldoDipakai int
+	err = tx.QueryRow(database.TranslateQuery(`
+		SELECT COALESCE(SUM(jumlah), 0) FROM pembayaran WHERE transaksi_id = ? AND metode = ?
+	`), transaksiID, models.MetodeSaldo).Scan(&saldoDipakai)
+	if err != nil {
+		return fmt.Errorf("failed to get balance payments: %w", err)
+	}
+	if saldoDipakai > 0 && pelangganID.Valid && pelangganID.Int64 != 0 {
+		mutasi := &models.SaldoPelangganHistory{
+			PelangganID: pelangganID.Int64,
+			Tipe:        "void",
+			Jumlah:      saldoDipakai,
+			TransaksiID: transaksiID,
+			Referensi:   nomorTransaksi,
+			Keterangan:  fmt.Sprintf("Void transaksi %s", nomorTransaksi),
+			StaffNama:   voidBy,
+		}
+		if err := r.saldoRepo.MutasiTx(tx, mutasi); err != nil {
+			return err
+		}
+	}
+
+	// 5. Batalkan piutang tempo; pelunasan yang sudah diterima menjadi saldo pelanggan
+	terbayar, err := r.piutangRepo.VoidTx(tx, transaksiID)
+	if err != nil {
+		return err
+	}
+	if terbayar > 0 && pelangganID.Valid && pelangganID.Int64 != 0 {
+		mutasi := &models.SaldoPelangganHistory{
+			PelangganID: pelangganID.Int64,
+			Tipe:        "void",
+			Jumlah:      terbayar,
+			TransaksiID: transaksiID,
+			Referensi:   nomorTransaksi,
+			Keterangan:  fmt.Sprintf("Pelunasan piutang transaksi %s yang di-void", nomorTransaksi),
+			StaffNama:   voidBy,
+		}
+		if err := r.saldoRepo.MutasiTx(tx, mutasi); err != nil {
+			return err
+		}
+	}
+
+	// 6. Tandai transaksi sebagai void
+	_, err = tx.Exec(database.TranslateQuery(`
+		UPDATE transaksi
+		SET status = 'void', void_alasan = ?, void_by = ?, void_approved_by = ?, void_at = ?
+		WHERE id = ?
+	`), alasan, voidBy, approvedBy, time.Now().UTC(), transaksiID)
+	if err != nil {
+		return fmt.Errorf("failed to void transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // GetAll retrieves all transactions with pagination
 func (r *TransaksiRepository) GetAll(limit, offset int) ([]*models.Transaksi, error) {
 	// Use global database.DB
@@ -645,7 +1071,8 @@ func (r *TransaksiRepository) GetByDateRange(startDate, endDate time.Time) ([]*m
 	query := `SELECT
 		id, nomor_transaksi, tanggal, pelanggan_id, pelanggan_nama, pelanggan_telp,
 		subtotal, diskon_promo, diskon_pelanggan, poin_ditukar, diskon_poin, diskon, total, total_bayar, kembalian,
-		status, catatan, kasir, created_at
+		COALESCE(pembulatan, 0), COALESCE(donasi, 0), COALESCE(total_pajak, 0), COALESCE(mode_pajak, ''),
+		status, catatan, kasir, staff_id, staff_nama, shift_session_id, created_at
 	FROM transaksi
 	WHERE tanggal >= ? AND tanggal < ?
 	ORDER BY tanggal DESC`
@@ -659,13 +1086,14 @@ func (r *TransaksiRepository) GetByDateRange(startDate, endDate time.Time) ([]*m
 	var transaksis []*models.Transaksi
 	for rows.Next() {
 		t := &models.Transaksi{}
-		var pelangganTelp, catatan, kasir sql.NullString
+		var pelangganTelp, catatan, kasir, staffNama sql.NullString
+		var staffID, shiftSessionID sql.NullInt64
 		err := rows.Scan(
 			&t.ID, &t.NomorTransaksi, &t.Tanggal,
 			&t.PelangganID, &t.PelangganNama, &pelangganTelp,
 			&t.Subtotal, &t.DiskonPromo, &t.DiskonPelanggan, &t.PoinDitukar, &t.DiskonPoin, &t.Diskon, &t.Total,
-			&t.TotalBayar, &t.Kembalian,
-			&t.Status, &catatan, &kasir,
+			&t.TotalBayar, &t.Kembalian, &t.Pembulatan, &t.Donasi, &t.TotalPajak, &t.ModePajak,
+			&t.Status, &catatan, &kasir, &staffID, &staffNama, &shiftSessionID,
 			&t.CreatedAt,
 		)
 		if err != nil {
@@ -673,6 +1101,15 @@ func (r *TransaksiRepository) GetByDateRange(startDate, endDate time.Time) ([]*m
 		}
 
 		// Handle NULL values
+		if staffID.Valid {
+			t.StaffID = &staffID.Int64
+		}
+		if staffNama.Valid {
+			t.StaffNama = staffNama.String
+		}
+		if shiftSessionID.Valid {
+			t.ShiftSessionID = &shiftSessionID.Int64
+		}
 		if pelangganTelp.Valid {
 			t.PelangganTelp = pelangganTelp.String
 		}
@@ -694,6 +1131,23 @@ func (r *TransaksiRepository) GetByDateRange(startDate, endDate time.Time) ([]*m
 	return transaksis, nil
 }
 
+// GetSalesByDateRange retrieves transactions within a date range, excluding voided ones
+func (r *TransaksiRepository) GetSalesByDateRange(startDate, endDate time.Time) ([]*models.Transaksi, error) {
+	transaksis, err := r.GetByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	sales := make([]*models.Transaksi, 0, len(transaksis))
+	for _, t := range transaksis {
+		if t.Status != "void" {
+			sales = append(sales, t)
+		}
+	}
+
+	return sales, nil
+}
+
 // GetTodayStats gets statistics for today's transactions
 func (r *TransaksiRepository) GetTodayStats() (totalTransaksi int, totalPendapatan int, totalItem int, err error) {
 	// Check if database connection is nil
@@ -967,6 +1421,7 @@ func (r *TransaksiRepository) GetDailyBreakdownByStaffID(staffID int64, startDat
 		FROM transaksi
 		WHERE (staff_id = ? OR LOWER(kasir) = LOWER(?))
 		  AND DATE(tanggal) >= DATE(?) AND DATE(tanggal) <= DATE(?)
+		  AND status <> 'void'
 		GROUP BY DATE(tanggal)
 		ORDER BY DATE(tanggal) ASC
 	`
@@ -1008,7 +1463,7 @@ func (r *TransaksiRepository) GetTopProductLast30Days() (string, error) {
 		SELECT ti.produk_nama, SUM(ti.jumlah) as total_terjual
 		FROM transaksi_item ti
 		JOIN transaksi t ON ti.transaksi_id = t.id
-		WHERE t.tanggal >= CURRENT_DATE - INTERVAL '30 days'
+		WHERE t.tanggal >= CURRENT_DATE - INTERVAL '30 days' AND t.status <> 'void'
 		GROUP BY ti.produk_nama
 		ORDER BY total_terjual DESC
 		LIMIT 1
@@ -1041,6 +1496,7 @@ func (r *TransaksiRepository) GetItemCountsByDateForStaff(staffID int64, startDa
 		JOIN transaksi_item ti ON t.id = ti.transaksi_id
 		WHERE (t.staff_id = ? OR LOWER(t.kasir) = LOWER(?))
 		  AND DATE(t.tanggal) >= DATE(?) AND DATE(t.tanggal) <= DATE(?)
+		  AND t.status <> 'void'
 		GROUP BY DATE(t.tanggal)
 	`
 
@@ -1083,6 +1539,9 @@ func (r *TransaksiRepository) GetSalesByShift() (map[string]int, error) {
 	}
 
 	for _, t := range transaksiList {
+		if t.Status == "void" {
+			continue
+		}
 		hour := t.Tanggal.In(time.Local).Hour()
 
 		if hour >= 6 && hour < 14 {
@@ -1122,6 +1581,9 @@ func (r *TransaksiRepository) GetShiftDataByStaffIDAndDateRange(staffID int64, s
 	}
 
 	for _, t := range transaksiList {
+		if t.Status == "void" {
+			continue
+		}
 		hour := t.Tanggal.In(time.Local).Hour()
 
 		var shift string
@@ -1155,6 +1617,7 @@ func (r *TransaksiRepository) GetTransactionCountsByDateForStaff(staffID int64,
 		FROM transaksi
 		WHERE (staff_id = ? OR LOWER(kasir) = LOWER(?))
 		  AND DATE(tanggal) >= DATE(?) AND DATE(tanggal) <= DATE(?)
+		  AND status <> 'void'
 		GROUP BY DATE(tanggal)
 	`
 
@@ -1186,7 +1649,7 @@ func (r *TransaksiRepository) GetTotalProductsSoldByDateRange(startDate, endDate
 	query := `SELECT COALESCE(SUM(ti.jumlah), 0) as total_products
 	FROM transaksi t
 	INNER JOIN transaksi_item ti ON t.id = ti.transaksi_id
-	WHERE t.created_at BETWEEN ? AND ?`
+	WHERE t.created_at BETWEEN ? AND ? AND t.status <> 'void'`
 
 	var totalProducts int
 	err := database.QueryRow(query, startDate, endDate).Scan(&totalProducts)
@@ -1197,6 +1660,48 @@ func (r *TransaksiRepository) GetTotalProductsSoldByDateRange(startDate, endDate
 	return totalProducts, nil
 }
 
+// GetPajakByDateRange sums tax base and tax per tax rate for non-voided sales in a date range
+func (r *TransaksiRepository) GetPajakByDateRange(startDate, endDate time.Time) ([]*models.LaporanPajakTarif, int, error) {
+	if r.db == nil {
+		return []*models.LaporanPajakTarif{}, 0, nil
+	}
+
+	query := `SELECT
+		COALESCE(ti.tarif_pajak, 0) as tarif,
+		COALESCE(SUM(ti.dpp), 0) as dpp,
+		COALESCE(SUM(ti.pajak), 0) as pajak,
+		COUNT(*) as jumlah_item
+	FROM transaksi t
+	INNER JOIN transaksi_item ti ON t.id = ti.transaksi_id
+	WHERE t.tanggal >= ? AND t.tanggal <= ? AND t.status <> 'void' AND COALESCE(t.mode_pajak, '') <> ''
+	GROUP BY COALESCE(ti.tarif_pajak, 0)
+	ORDER BY tarif ASC`
+
+	rows, err := database.Query(query, startDate, endDate)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get tax by date range: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*models.LaporanPajakTarif{}
+	for rows.Next() {
+		row := &models.LaporanPajakTarif{}
+		if err := rows.Scan(&row.TarifPajak, &row.DPP, &row.Pajak, &row.JumlahItem); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan tax row: %w", err)
+		}
+		result = append(result, row)
+	}
+
+	var jumlahTransaksi int
+	countQuery := `SELECT COUNT(*) FROM transaksi
+	WHERE tanggal >= ? AND tanggal <= ? AND status <> 'void' AND COALESCE(mode_pajak, '') <> ''`
+	if err := database.QueryRow(countQuery, startDate, endDate).Scan(&jumlahTransaksi); err != nil {
+		return nil, 0, fmt.Errorf("failed to count taxed transactions: %w", err)
+	}
+
+	return result, jumlahTransaksi, nil
+}
+
 func (r *TransaksiRepository) GetPaymentMethodBreakdownByDateRange(startDate, endDate time.Time) (map[string]int, error) {
 	if r.db == nil {
 		return make(map[string]int), nil
@@ -1325,7 +1830,7 @@ func (r *TransaksiRepository) GetSalesGroupedByPeriod(startDate, endDate time.Ti
                 SUM(total) as revenue,
                 COUNT(*) as transactions
             FROM transaksi
-            WHERE created_at BETWEEN $1 AND $2
+            WHERE created_at BETWEEN $1 AND $2 AND status <> 'void'
             GROUP BY day_key, label
             ORDER BY day_key;
         `
@@ -1337,7 +1842,7 @@ func (r *TransaksiRepository) GetSalesGroupedByPeriod(startDate, endDate time.Ti
                 SUM(total) as revenue,
                 COUNT(*) as transactions
             FROM transaksi
-            WHERE created_at BETWEEN $1 AND $2
+            WHERE created_at BETWEEN $1 AND $2 AND status <> 'void'
             GROUP BY label
             ORDER BY label;
         `
@@ -1349,7 +1854,7 @@ func (r *TransaksiRepository) GetSalesGroupedByPeriod(startDate, endDate time.Ti
                 SUM(total) as revenue,
                 COUNT(*) as transactions
             FROM transaksi
-            WHERE created_at BETWEEN $1 AND $2
+            WHERE created_at BETWEEN $1 AND $2 AND status <> 'void'
             GROUP BY label
             ORDER BY label;
         `
@@ -1361,7 +1866,7 @@ func (r *TransaksiRepository) GetSalesGroupedByPeriod(startDate, endDate time.Ti
                 SUM(total) as revenue,
                 COUNT(*) as transactions
             FROM transaksi
-            WHERE created_at BETWEEN $1 AND $2
+            WHERE created_at BETWEEN $1 AND $2 AND status <> 'void'
             GROUP BY label
             ORDER BY label;
         `
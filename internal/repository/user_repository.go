@@ -333,6 +333,155 @@ func (r *UserRepository) UpdatePassword(userID int64, newPassword string) error
 	return nil
 }
 
+// UpdatePin updates user PIN (stored hashed, like the password)
+func (r *UserRepository) UpdatePin(userID int64, pin string) error {
+	hashedPin, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash pin: %w", err)
+	}
+
+	query := `
+		UPDATE users
+		SET pin = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := database.Exec(query, string(hashedPin), time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to update pin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// GetPinHash returns the hashed PIN of a user (empty if no PIN is set)
+func (r *UserRepository) GetPinHash(userID int64) (string, error) {
+	var pin sql.NullString
+	query := `SELECT pin FROM users WHERE id = ? AND deleted_at IS NULL`
+	err := database.QueryRow(query, userID).Scan(&pin)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get pin: %w", err)
+	}
+
+	return pin.String, nil
+}
+
+// GetTerkunciSampai returns until when a user is locked out after failed logins (nil if not locked)
+func (r *UserRepository) GetTerkunciSampai(userID int64) (*time.Time, error) {
+	var terkunciSampai sql.NullTime
+	query := `SELECT terkunci_sampai FROM users WHERE id = ? AND deleted_at IS NULL`
+	err := database.QueryRow(query, userID).Scan(&terkunciSampai)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lockout: %w", err)
+	}
+	if !terkunciSampai.Valid {
+		return nil, nil
+	}
+
+	return &terkunciSampai.Time, nil
+}
+
+// CatatGagalLogin counts a failed password or PIN attempt. Returns the failed attempts since the
+// last lockout and how many times the user has been locked since the last successful login.
+func (r *UserRepository) CatatGagalLogin(userID int64) (gagal int, jumlahKunci int, err error) {
+	_, err = database.Exec(`UPDATE users SET gagal_login = COALESCE(gagal_login, 0) + 1 WHERE id = ?`, userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count failed login: %w", err)
+	}
+
+	err = database.QueryRow(`SELECT COALESCE(gagal_login, 0), COALESCE(jumlah_kunci, 0) FROM users WHERE id = ?`, userID).
+		Scan(&gagal, &jumlahKunci)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get failed logins: %w", err)
+	}
+	return gagal, jumlahKunci, nil
+}
+
+// KunciAkun locks the user until terkunciSampai and starts counting failed attempts over
+func (r *UserRepository) KunciAkun(userID int64, terkunciSampai time.Time) error {
+	_, err := database.Exec(`UPDATE users SET gagal_login = 0, jumlah_kunci = COALESCE(jumlah_kunci, 0) + 1, terkunci_sampai = ?
+		WHERE id = ?`, terkunciSampai.UTC(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+// ResetGagalLogin clears the failed attempt count and any lockout after a successful login
+func (r *UserRepository) ResetGagalLogin(userID int64) error {
+	_, err := database.Exec(`UPDATE users SET gagal_login = 0, jumlah_kunci = 0, terkunci_sampai = NULL WHERE id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+	return nil
+}
+
+// GetPasswordHashes returns the current password hash followed by up to n-1 previous hashes, newest first
+func (r *UserRepository) GetPasswordHashes(userID int64, n int) ([]string, error) {
+	var current string
+	err := database.QueryRow(`SELECT password FROM users WHERE id = ? AND deleted_at IS NULL`, userID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password: %w", err)
+	}
+
+	hashes := []string{current}
+	if n <= 1 {
+		return hashes, nil
+	}
+
+	rows, err := database.Query(`SELECT password_hash FROM password_history WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?`, userID, n-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query password history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan password history: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
+
+// SimpanRiwayatPassword keeps a replaced password hash so it cannot be reused
+func (r *UserRepository) SimpanRiwayatPassword(userID int64, passwordHash string) error {
+	now := time.Now()
+	var err error
+	if database.UseDualMode && database.IsSQLite() {
+		_, err = database.Exec(`INSERT INTO password_history (id, user_id, password_hash, created_at) VALUES (?, ?, ?, ?)`,
+			database.GenerateOfflineID(), userID, passwordHash, now)
+	} else {
+		_, err = database.Exec(`INSERT INTO password_history (user_id, password_hash, created_at) VALUES (?, ?, ?)`,
+			userID, passwordHash, now)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save password history: %w", err)
+	}
+	return nil
+}
+
 // Delete soft deletes a user
 func (r *UserRepository) Delete(id int64) error {
 	query := `
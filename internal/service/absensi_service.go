@@ -0,0 +1,272 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// AbsensiService handles the staff time clock: clock-in/out, breaks, lateness and overtime
+type AbsensiService struct {
+	repo        *repository.AbsensiRepository
+	shiftRepo   *repository.ShiftRepository
+	userService *UserService
+}
+
+// NewAbsensiService creates a new instance
+func NewAbsensiService() *AbsensiService {
+	return &AbsensiService{
+		repo:        repository.NewAbsensiRepository(),
+		shiftRepo:   repository.NewShiftRepository(),
+		userService: NewUserService(),
+	}
+}
+
+// ClockIn starts an attendance record. The schedule is the staff member's assigned shift
+// closest to the clock-in time, or the shift running at clock-in when none is assigned.
+func (s *AbsensiService) ClockIn(req *models.AbsensiRequest) (*models.Absensi, error) {
+	staffID, staffNama, metode, err := s.resolveStaff(req)
+	if err != nil {
+		return nil, err
+	}
+
+	aktif, err := s.repo.GetAktifByStaff(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if aktif != nil {
+		return nil, fmt.Errorf("%s sudah absen masuk sejak %s", staffNama, formatTimeInWIB(aktif.JamMasuk))
+	}
+
+	now := time.Now()
+	shift, err := s.jadwalStaff(staffID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	absensi := &models.Absensi{
+		StaffID:     staffID,
+		StaffNama:   staffNama,
+		Tanggal:     now.Local().Format("2006-01-02"),
+		MetodeMasuk: metode,
+		JamMasuk:    now.UTC(),
+		Catatan:     strings.TrimSpace(req.Catatan),
+		Istirahat:   []*models.AbsensiIstirahat{},
+	}
+	if shift != nil {
+		masuk, _ := jadwalShift(shift.StartTime, shift.EndTime, now)
+		absensi.Tanggal = masuk.Format("2006-01-02")
+		absensi.ShiftID = shift.ID
+		absensi.ShiftNama = shift.Name
+		absensi.JadwalMasuk = shift.StartTime
+		absensi.JadwalPulang = shift.EndTime
+		absensi.TerlambatMenit = selisihMenit(masuk, now)
+	}
+
+	if err := s.repo.Create(absensi); err != nil {
+		return nil, err
+	}
+
+	return absensi, nil
+}
+
+// MulaiIstirahat starts a break for a clocked-in staff member
+func (s *AbsensiService) MulaiIstirahat(req *models.AbsensiRequest) (*models.Absensi, error) {
+	absensi, err := s.absensiAktif(req)
+	if err != nil {
+		return nil, err
+	}
+	if absensi.Status == "istirahat" {
+		return nil, fmt.Errorf("%s sedang istirahat", absensi.StaffNama)
+	}
+
+	if _, err := s.repo.MulaiIstirahat(absensi, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.lengkapi(absensi)
+}
+
+// SelesaiIstirahat ends the current break
+func (s *AbsensiService) SelesaiIstirahat(req *models.AbsensiRequest) (*models.Absensi, error) {
+	absensi, err := s.absensiAktif(req)
+	if err != nil {
+		return nil, err
+	}
+	if absensi.Status != "istirahat" {
+		return nil, fmt.Errorf("%s tidak sedang istirahat", absensi.StaffNama)
+	}
+
+	if _, err := s.repo.SelesaiIstirahat(absensi, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.lengkapi(absensi)
+}
+
+// ClockOut finishes the attendance record; an open break is ended first.
+// Early leave and overtime are measured against the scheduled end of the shift.
+func (s *AbsensiService) ClockOut(req *models.AbsensiRequest) (*models.Absensi, error) {
+	absensi, err := s.absensiAktif(req)
+	if err != nil {
+		return nil, err
+	}
+	metode := "login"
+	if strings.TrimSpace(req.Username) != "" {
+		metode = "pin"
+	}
+
+	now := time.Now()
+	if absensi.Status == "istirahat" {
+		if _, err := s.repo.SelesaiIstirahat(absensi, now); err != nil {
+			return nil, err
+		}
+	}
+
+	absensi.MetodePulang = metode
+	absensi.JamPulang = &now
+	absensi.KerjaMenit = selisihMenit(absensi.JamMasuk, now) - absensi.IstirahatMenit
+	if absensi.KerjaMenit < 0 {
+		absensi.KerjaMenit = 0
+	}
+	if absensi.JadwalMasuk != "" && absensi.JadwalPulang != "" {
+		_, pulang := jadwalShift(absensi.JadwalMasuk, absensi.JadwalPulang, absensi.JamMasuk)
+		absensi.LemburMenit = selisihMenit(pulang, now)
+		absensi.PulangCepatMenit = selisihMenit(now, pulang)
+	}
+	if catatan := strings.TrimSpace(req.Catatan); catatan != "" {
+		if absensi.Catatan != "" {
+			absensi.Catatan += "; "
+		}
+		absensi.Catatan += catatan
+	}
+
+	if err := s.repo.Pulang(absensi); err != nil {
+		return nil, err
+	}
+
+	return s.lengkapi(absensi)
+}
+
+// GetAbsensiAktif returns the attendance record a staff member has not clocked out of yet, or nil
+func (s *AbsensiService) GetAbsensiAktif(staffID int64) (*models.Absensi, error) {
+	absensi, err := s.repo.GetAktifByStaff(staffID)
+	if err != nil || absensi == nil {
+		return nil, err
+	}
+	return s.lengkapi(absensi)
+}
+
+// resolveStaff identifies the staff member: by username and PIN at a shared POS, otherwise the logged-in user
+func (s *AbsensiService) resolveStaff(req *models.AbsensiRequest) (int64, string, string, error) {
+	username := strings.TrimSpace(req.Username)
+	if username == "" {
+		if req.StaffID == 0 {
+			return 0, "", "", fmt.Errorf("staff harus login atau memasukkan username dan PIN")
+		}
+		return req.StaffID, req.StaffNama, "login", nil
+	}
+
+	if req.Pin == "" {
+		return 0, "", "", fmt.Errorf("PIN wajib diisi")
+	}
+	user, err := s.userService.VerifyPin(username, req.Pin)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	return user.ID, user.NamaLengkap, "pin", nil
+}
+
+// absensiAktif resolves the staff member and returns their open attendance record
+func (s *AbsensiService) absensiAktif(req *models.AbsensiRequest) (*models.Absensi, error) {
+	staffID, staffNama, _, err := s.resolveStaff(req)
+	if err != nil {
+		return nil, err
+	}
+
+	absensi, err := s.repo.GetAktifByStaff(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if absensi == nil {
+		return nil, fmt.Errorf("%s belum absen masuk", staffNama)
+	}
+	return absensi, nil
+}
+
+// jadwalStaff picks the assigned shift whose start is closest to t,
+// falling back to the shift running at t
+func (s *AbsensiService) jadwalStaff(staffID int64, t time.Time) (*models.ShiftSetting, error) {
+	shifts, err := s.shiftRepo.GetByStaff(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if len(shifts) == 0 {
+		return s.shiftRepo.FindByTime(t)
+	}
+
+	var terdekat *models.ShiftSetting
+	var jarak time.Duration
+	for i := range shifts {
+		masuk, _ := jadwalShift(shifts[i].StartTime, shifts[i].EndTime, t)
+		d := absDuration(t.Sub(masuk))
+		if terdekat == nil || d < jarak {
+			terdekat, jarak = &shifts[i], d
+		}
+	}
+	return terdekat, nil
+}
+
+// lengkapi attaches the breaks of an attendance record
+func (s *AbsensiService) lengkapi(absensi *models.Absensi) (*models.Absensi, error) {
+	istirahat, err := s.repo.GetIstirahat(absensi.ID)
+	if err != nil {
+		return nil, err
+	}
+	if istirahat == nil {
+		istirahat = []*models.AbsensiIstirahat{}
+	}
+	absensi.Istirahat = istirahat
+	return absensi, nil
+}
+
+// jadwalShift returns the scheduled start and end ("HH:MM", local time) of the shift occurrence
+// whose start is closest to t. Shifts ending at or before their start time end the next day.
+func jadwalShift(startTime, endTime string, t time.Time) (time.Time, time.Time) {
+	local := t.Local()
+	startHour, startMinute := parseTime(startTime)
+	endHour, endMinute := parseTime(endTime)
+
+	var masuk time.Time
+	for _, offset := range []int{-1, 0, 1} {
+		kandidat := time.Date(local.Year(), local.Month(), local.Day()+offset, startHour, startMinute, 0, 0, time.Local)
+		if masuk.IsZero() || absDuration(local.Sub(kandidat)) < absDuration(local.Sub(masuk)) {
+			masuk = kandidat
+		}
+	}
+
+	pulang := time.Date(masuk.Year(), masuk.Month(), masuk.Day(), endHour, endMinute, 0, 0, time.Local)
+	if !pulang.After(masuk) {
+		pulang = pulang.AddDate(0, 0, 1)
+	}
+	return masuk, pulang
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// selisihMenit returns the whole minutes from a to b, or 0 when b is not after a
+func selisihMenit(a, b time.Time) int {
+	if !b.After(a) {
+		return 0
+	}
+	return int(b.Sub(a) / time.Minute)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// Fields that change on every write and would only add noise to the audit diff
+var auditFieldDiabaikan = map[string]bool{
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+// AuditService records who changed what. Entries are append-only.
+type AuditService struct {
+	repo *repository.AuditRepository
+}
+
+// NewAuditService creates a new instance
+func NewAuditService() *AuditService {
+	return &AuditService{
+		repo: repository.NewAuditRepository(),
+	}
+}
+
+// Catat appends an audit entry with the JSON fields that differ between sebelum and sesudah.
+// Either side may be nil for a create or delete. The change itself has already been saved,
+// so a failure to record is logged instead of returned.
+func (s *AuditService) Catat(aktor models.Aktor, entitas, entitasID, aksi string, sebelum, sesudah interface{}, keterangan string) {
+	perubahan, err := diffAudit(sebelum, sesudah)
+	if err != nil {
+		log.Printf("[AUDIT] Failed to diff %s %s: %v", entitas, entitasID, err)
+		return
+	}
+	if aksi == models.AuditAksiUpdate && len(perubahan) == 0 && keterangan == "" {
+		return
+	}
+
+	entry := &models.AuditLog{
+		AktorID:    aktor.ID,
+		AktorNama:  aktor.Nama,
+		Entitas:    entitas,
+		EntitasID:  entitasID,
+		Aksi:       aksi,
+		Perubahan:  perubahan,
+		Keterangan: keterangan,
+	}
+	if err := s.repo.Create(entry); err != nil {
+		log.Printf("[AUDIT] Failed to record %s %s %s by %s: %v", aksi, entitas, entitasID, aktor.Nama, err)
+	}
+}
+
+// GetAll retrieves audit entries matching the filter
+func (s *AuditService) GetAll(filter *models.AuditFilter) ([]*models.AuditLog, error) {
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.GetAll(filter)
+}
+
+// diffAudit compares two values by their JSON representation, field by field
+func diffAudit(sebelum, sesudah interface{}) (map[string]models.AuditPerubahan, error) {
+	lama, err := keMapJSON(sebelum)
+	if err != nil {
+		return nil, err
+	}
+	baru, err := keMapJSON(sesudah)
+	if err != nil {
+		return nil, err
+	}
+
+	perubahan := make(map[string]models.AuditPerubahan)
+	for field, nilai := range lama {
+		if auditFieldDiabaikan[field] {
+			continue
+		}
+		if !reflect.DeepEqual(nilai, baru[field]) {
+			perubahan[field] = models.AuditPerubahan{Sebelum: nilai, Sesudah: baru[field]}
+		}
+	}
+	for field, nilai := range baru {
+		if _, ada := lama[field]; ada || auditFieldDiabaikan[field] {
+			continue
+		}
+		perubahan[field] = models.AuditPerubahan{Sebelum: nil, Sesudah: nilai}
+	}
+	return perubahan, nil
+}
+
+func keMapJSON(v interface{}) (map[string]interface{}, error) {
+	hasil := make(map[string]interface{})
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return hasil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &hasil); err != nil {
+		return nil, err
+	}
+	return hasil, nil
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"ritel-app/internal/database"
 	"ritel-app/internal/models"
 	"ritel-app/internal/repository"
 )
@@ -79,44 +81,34 @@ func (s *BatchService) CreateBatchFromRestok(req *models.UpdateStokRequest) (*mo
 	if err != nil {
 		return nil, fmt.Errorf("product not found: %w", err)
 	}
-
-	// Check if there's an existing batch from today with the same shelf life
-	today := time.Now().Format("2006-01-02")
-	existingBatch, err := s.batchRepo.FindBatchByDateAndShelfLife(req.ProdukID, today, req.MasaSimpanHari)
-	if err == nil && existingBatch != nil {
-		// Merge into existing batch
-		existingBatch.Qty += req.Perubahan
-		existingBatch.QtyTersisa += req.Perubahan
-
-		// Update the batch
-		err = s.batchRepo.UpdateBatch(existingBatch)
-		if err != nil {
-			return nil, fmt.Errorf("failed to update existing batch: %w", err)
-		}
-
-		return existingBatch, nil
+	if produk == nil {
+		return nil, fmt.Errorf("product not found")
 	}
 
-	// Create new batch if no matching batch found
-	// Normalize restock date to midnight for consistent date calculations
-	// Use WIB timezone (UTC+7)
-	restockDate := time.Now().UTC().Add(7 * time.Hour)
-	restockDate = time.Date(restockDate.Year(), restockDate.Month(), restockDate.Day(), 0, 0, 0, 0, restockDate.Location())
-
 	batch := &models.Batch{
 		ProdukID:       produk.ID,
 		Qty:            req.Perubahan,
 		QtyTersisa:     req.Perubahan,
-		TanggalRestok:  restockDate,
+		TanggalRestok:  repository.TanggalRestokHariIni(),
 		MasaSimpanHari: req.MasaSimpanHari,
 		Supplier:       req.Supplier,
 		Keterangan:     req.Keterangan,
 	}
 
-	// Save batch
-	err = s.batchRepo.CreateBatch(batch)
+	tx, err := database.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Merge into today's batch with the same shelf life, or create a new one
+	batch, err = s.batchRepo.RestokBatchTx(tx, batch)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create batch: %w", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return batch, nil
@@ -15,6 +15,7 @@ type DashboardService struct {
 	batchRepo     *repository.BatchRepository
 	promoRepo     *repository.PromoRepository
 	returnRepo    *repository.ReturnRepository
+	targetService *TargetService
 }
 
 // NewDashboardService creates a new dashboard service
@@ -25,6 +26,7 @@ func NewDashboardService() *DashboardService {
 		batchRepo:     repository.NewBatchRepository(),
 		promoRepo:     repository.NewPromoRepository(),
 		returnRepo:    repository.NewReturnRepository(),
+		targetService: NewTargetService(),
 	}
 }
 
@@ -86,13 +88,13 @@ func (s *DashboardService) GetStatistikBulanan() (*models.DashboardStatistikBula
 	previousMonthEnd := currentMonthStart.Add(-time.Second)
 
 	// Get current month data
-	currentMonthTransactions, err := s.transaksiRepo.GetByDateRange(currentMonthStart, currentMonthEnd)
+	currentMonthTransactions, err := s.transaksiRepo.GetSalesByDateRange(currentMonthStart, currentMonthEnd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current month transactions: %w", err)
 	}
 
 	// Get previous month data
-	previousMonthTransactions, err := s.transaksiRepo.GetByDateRange(previousMonthStart, previousMonthEnd)
+	previousMonthTransactions, err := s.transaksiRepo.GetSalesByDateRange(previousMonthStart, previousMonthEnd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get previous month transactions: %w", err)
 	}
@@ -281,13 +283,13 @@ func (s *DashboardService) GetPerformaHariIni() ([]models.DashboardPerforma, err
 	yesterdayStart := todayStart.Add(-24 * time.Hour)
 
 	// Get today's transactions
-	todayTransactions, err := s.transaksiRepo.GetByDateRange(todayStart, todayEnd)
+	todayTransactions, err := s.transaksiRepo.GetSalesByDateRange(todayStart, todayEnd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get today transactions: %w", err)
 	}
 
 	// Get yesterday's transactions for comparison
-	yesterdayTransactions, err := s.transaksiRepo.GetByDateRange(yesterdayStart, todayStart)
+	yesterdayTransactions, err := s.transaksiRepo.GetSalesByDateRange(yesterdayStart, todayStart)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get yesterday transactions: %w", err)
 	}
@@ -371,12 +373,22 @@ func (s *DashboardService) GetPerformaHariIni() ([]models.DashboardPerforma, err
 
 	customerTrend := calculateTrend(float64(uniqueCustomers), float64(yesterdayCustomers))
 
+	// Store targets for today, default targets when none are configured
+	target := map[string]float64{"omzet": 3000000, "transaksi": 100, "item": 300}
+	pencapaian, err := s.targetService.Pencapaian("toko", 0, 0,
+		realisasiTarget(todayOmzet, todayTransaksiCount, todayProdukTerjual), todayStart, todayStart)
+	if err == nil {
+		for _, p := range pencapaian {
+			target[p.Metrik] = p.Target
+		}
+	}
+
 	performa := []models.DashboardPerforma{
 		{
 			ID:     1,
 			Title:  "Omzet Hari Ini",
 			Value:  todayOmzet,
-			Target: 3000000, // Default target
+			Target: target["omzet"],
 			Trend:  omzetTrend,
 			Icon:   "faMoneyBillWave",
 			Color:  "green",
@@ -385,7 +397,7 @@ func (s *DashboardService) GetPerformaHariIni() ([]models.DashboardPerforma, err
 			ID:     2,
 			Title:  "Transaksi Hari Ini",
 			Value:  float64(todayTransaksiCount),
-			Target: 100, // Default target
+			Target: target["transaksi"],
 			Trend:  transaksiTrend,
 			Icon:   "faReceipt",
 			Color:  "blue",
@@ -394,7 +406,7 @@ func (s *DashboardService) GetPerformaHariIni() ([]models.DashboardPerforma, err
 			ID:     3,
 			Title:  "Produk Terjual",
 			Value:  float64(todayProdukTerjual),
-			Target: 300, // Default target
+			Target: target["item"],
 			Trend:  produkTrend,
 			Icon:   "faShoppingBasket",
 			Color:  "purple",
@@ -423,7 +435,7 @@ func (s *DashboardService) GetProdukTerlaris(limit int) ([]models.DashboardProdu
 	todayEnd := todayStart.AddDate(0, 0, 2) // + Buffer for timezone drift
 	thirtyDaysAgo := todayStart.AddDate(0, 0, -30)
 
-	transactions, err := s.transaksiRepo.GetByDateRange(thirtyDaysAgo, todayEnd)
+	transactions, err := s.transaksiRepo.GetSalesByDateRange(thirtyDaysAgo, todayEnd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
@@ -654,7 +666,7 @@ func (s *DashboardService) GetSalesChartData() (*models.DashboardSalesData, erro
 		slotEnd := slotStart.Add(3 * time.Hour)
 
 		// Get transactions in this slot
-		transactions, err := s.transaksiRepo.GetByDateRange(slotStart, slotEnd)
+		transactions, err := s.transaksiRepo.GetSalesByDateRange(slotStart, slotEnd)
 		if err != nil {
 			hariData = append(hariData, 0)
 			continue
@@ -684,7 +696,7 @@ func (s *DashboardService) GetSalesChartData() (*models.DashboardSalesData, erro
 		dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 		dayEnd := dayStart.Add(24 * time.Hour)
 
-		transactions, err := s.transaksiRepo.GetByDateRange(dayStart, dayEnd)
+		transactions, err := s.transaksiRepo.GetSalesByDateRange(dayStart, dayEnd)
 		if err != nil {
 			mingguData = append(mingguData, 0)
 			continue
@@ -713,7 +725,7 @@ func (s *DashboardService) GetSalesChartData() (*models.DashboardSalesData, erro
 		dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 		dayEnd := dayStart.Add(24 * time.Hour)
 
-		transactions, err := s.transaksiRepo.GetByDateRange(dayStart, dayEnd)
+		transactions, err := s.transaksiRepo.GetSalesByDateRange(dayStart, dayEnd)
 		if err != nil {
 			bulanData = append(bulanData, 0)
 			continue
@@ -777,7 +789,7 @@ func (s *DashboardService) GetCompositionChartData() (map[string]interface{}, er
 // calculateCategoryComposition calculates percentage composition by category for a donut chart
 func (s *DashboardService) calculateCategoryComposition(start, end time.Time) models.DashboardCompositionPeriod {
 
-	transactions, err := s.transaksiRepo.GetByDateRange(start, end)
+	transactions, err := s.transaksiRepo.GetSalesByDateRange(start, end)
 	if err != nil {
 		return models.DashboardCompositionPeriod{Labels: []string{}, Data: []float64{}}
 	}
@@ -951,7 +963,7 @@ func (s *DashboardService) calculateCategoryTrends(endTime time.Time, periods in
 	}
 
 	// Fetch all transactions in the overall range
-	overallTransactions, err := s.transaksiRepo.GetByDateRange(overallStart, overallEnd)
+	overallTransactions, err := s.transaksiRepo.GetSalesByDateRange(overallStart, overallEnd)
 	if err != nil {
 		return models.DashboardCategoryPeriod{Labels: periodLabels, Datasets: []models.CategoryChartDataset{}}
 	}
@@ -1040,7 +1052,7 @@ func (s *DashboardService) calculateCategoryTrends(endTime time.Time, periods in
 			periodStart = periodEnd.AddDate(0, -1, 0)
 		}
 
-		transactions, err := s.transaksiRepo.GetByDateRange(periodStart, periodEnd)
+		transactions, err := s.transaksiRepo.GetSalesByDateRange(periodStart, periodEnd)
 		if err != nil {
 			continue // Skip this period if error
 		}
@@ -282,31 +282,12 @@ func (s *HardwareService) TestCashDrawer(port string) (*models.TestHardwareRespo
 		}, nil
 	}
 
-	// Try to open the port
-	mode := &serial.Mode{
-		BaudRate: 9600,
-		Parity:   serial.NoParity,
-		DataBits: 8,
-		StopBits: serial.OneStopBit,
-	}
-
-	port_handle, err := serial.Open(port, mode)
-	if err != nil {
-		return &models.TestHardwareResponse{
-			Success: false,
-			Message: fmt.Sprintf("Failed to open port: %v", err),
-		}, nil
-	}
-	defer port_handle.Close()
-
-	// Send cash drawer open command (ESC/POS)
-	openCommand := []byte{0x1B, 0x70, 0x00, 0x19, 0xFA}
-
-	_, err = port_handle.Write(openCommand)
-	if err != nil {
+	// Send cash drawer open command (ESC/POS) through the serial driver
+	drawer := &serialDrawer{port: port, baudRate: 9600}
+	if err := drawer.Buka(); err != nil {
 		return &models.TestHardwareResponse{
 			Success: false,
-			Message: fmt.Sprintf("Failed to send open command: %v", err),
+			Message: err.Error(),
 		}, nil
 	}
 
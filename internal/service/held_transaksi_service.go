@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// HeldTransaksiService handles parking and resuming of unfinished sales
+type HeldTransaksiService struct {
+	heldRepo  *repository.HeldTransaksiRepository
+	shiftRepo *repository.ShiftRepository
+}
+
+// NewHeldTransaksiService creates a new instance
+func NewHeldTransaksiService() *HeldTransaksiService {
+	return &HeldTransaksiService{
+		heldRepo:  repository.NewHeldTransaksiRepository(),
+		shiftRepo: repository.NewShiftRepository(),
+	}
+}
+
+// HoldTransaksi parks the current cart as a draft that expires at the end of the current shift
+func (s *HeldTransaksiService) HoldTransaksi(req *models.HoldTransaksiRequest) (*models.HeldTransaksi, error) {
+	if len(req.Draft.Items) == 0 {
+		return nil, fmt.Errorf("transaksi yang ditahan harus memiliki minimal 1 item")
+	}
+
+	staffID := req.StaffID
+	if staffID == 0 {
+		staffID = req.Draft.StaffID
+	}
+	staffNama := req.StaffNama
+	if staffNama == "" {
+		staffNama = req.Draft.StaffNama
+	}
+
+	subtotal := 0
+	for _, item := range req.Draft.Items {
+		subtotal += itemRequestSubtotal(item)
+	}
+
+	label := strings.TrimSpace(req.Label)
+	now := time.Now()
+	if label == "" {
+		label = fmt.Sprintf("Ditahan %s", now.Format("15:04"))
+	}
+
+	held := &models.HeldTransaksi{
+		Label:      label,
+		TerminalID: strings.TrimSpace(req.TerminalID),
+		StaffID:    staffID,
+		StaffNama:  staffNama,
+		Draft:      req.Draft,
+		JumlahItem: len(req.Draft.Items),
+		Subtotal:   subtotal,
+		Status:     "held",
+		ExpiresAt:  s.shiftRepo.GetShiftEnd(now),
+	}
+
+	if err := s.heldRepo.Create(held); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("[HELD TRANSACTION] Draft %d held: %s (%d items, terminal=%s)\n",
+		held.ID, held.Label, held.JumlahItem, held.TerminalID)
+
+	return held, nil
+}
+
+// GetHeldTransaksi lists drafts that are still held for a terminal and/or staff
+func (s *HeldTransaksiService) GetHeldTransaksi(terminalID string, staffID int64) ([]*models.HeldTransaksi, error) {
+	if _, err := s.ExpireHeldTransaksi(); err != nil {
+		fmt.Printf("[WARNING] Failed to expire held transactions: %v\n", err)
+	}
+
+	return s.heldRepo.GetActive(terminalID, staffID)
+}
+
+// ResumeHeldTransaksi takes a held draft back into checkout.
+// The draft is marked resumed so it cannot be picked up twice.
+func (s *HeldTransaksiService) ResumeHeldTransaksi(id int64) (*models.HeldTransaksi, error) {
+	held, err := s.heldRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if held == nil {
+		return nil, fmt.Errorf("transaksi yang ditahan tidak ditemukan")
+	}
+
+	if held.Status == "held" && !time.Now().Before(held.ExpiresAt) {
+		if _, err := s.ExpireHeldTransaksi(); err != nil {
+			fmt.Printf("[WARNING] Failed to expire held transactions: %v\n", err)
+		}
+		return nil, fmt.Errorf("transaksi yang ditahan sudah kedaluwarsa (shift berakhir)")
+	}
+	if held.Status != "held" {
+		return nil, fmt.Errorf("transaksi yang ditahan sudah tidak aktif (status: %s)", held.Status)
+	}
+
+	if err := s.heldRepo.UpdateStatus(id, "resumed"); err != nil {
+		return nil, err
+	}
+	held.Status = "resumed"
+
+	return held, nil
+}
+
+// DiscardHeldTransaksi drops a held draft without checking it out
+func (s *HeldTransaksiService) DiscardHeldTransaksi(id int64) error {
+	return s.heldRepo.UpdateStatus(id, "discarded")
+}
+
+// ExpireHeldTransaksi expires every draft whose shift has ended
+func (s *HeldTransaksiService) ExpireHeldTransaksi() (int64, error) {
+	return s.heldRepo.ExpireOverdue(time.Now())
+}
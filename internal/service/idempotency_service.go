@@ -0,0 +1,79 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// IdempotencyKeyTTL is how long a stored response can be replayed
+const IdempotencyKeyTTL = 24 * time.Hour
+
+var (
+	// ErrIdempotencyInProgress is returned when the original request is still running
+	ErrIdempotencyInProgress = errors.New("request dengan Idempotency-Key ini masih diproses")
+	// ErrIdempotencyMismatch is returned when a key is reused for a different request
+	ErrIdempotencyMismatch = errors.New("Idempotency-Key sudah dipakai untuk request yang berbeda")
+)
+
+// IdempotencyService stores responses per Idempotency-Key so retried requests are not executed twice
+type IdempotencyService struct {
+	repo *repository.IdempotencyRepository
+}
+
+// NewIdempotencyService creates a new instance
+func NewIdempotencyService() *IdempotencyService {
+	return &IdempotencyService{
+		repo: repository.NewIdempotencyRepository(),
+	}
+}
+
+// Begin claims a key before the request is executed.
+// It returns the stored record when the key already completed; the caller should replay it.
+func (s *IdempotencyService) Begin(scope, key string, request []byte) (*models.IdempotencyRecord, error) {
+	hash := HashIdempotencyRequest(request)
+
+	existing, reserved, err := s.repo.Reserve(scope, key, hash)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if existing.RequestHash != "" && existing.RequestHash != hash {
+		return nil, ErrIdempotencyMismatch
+	}
+	if existing.Status != "completed" {
+		return nil, ErrIdempotencyInProgress
+	}
+
+	fmt.Printf("[IDEMPOTENCY] Replaying stored response for %s key=%s\n", scope, key)
+	return existing, nil
+}
+
+// Complete stores the response of a request that started with Begin
+func (s *IdempotencyService) Complete(scope, key string, statusCode int, response []byte) error {
+	return s.repo.Complete(scope, key, statusCode, string(response))
+}
+
+// Release frees a key whose request failed before producing a result
+func (s *IdempotencyService) Release(scope, key string) error {
+	return s.repo.Release(scope, key)
+}
+
+// CleanupExpired removes keys older than IdempotencyKeyTTL
+func (s *IdempotencyService) CleanupExpired() (int64, error) {
+	return s.repo.DeleteOlderThan(time.Now().Add(-IdempotencyKeyTTL))
+}
+
+// HashIdempotencyRequest fingerprints a request body so a key cannot be reused for another payload
+func HashIdempotencyRequest(request []byte) string {
+	sum := sha256.Sum256(request)
+	return hex.EncodeToString(sum[:])
+}
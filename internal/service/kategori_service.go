@@ -26,6 +26,9 @@ func (s *KategoriService) CreateKategori(kategori *models.Kategori) error {
 	if strings.TrimSpace(kategori.Nama) == "" {
 		return fmt.Errorf("category name is required")
 	}
+	if err := ValidateTarifPajak(kategori.TarifPajak); err != nil {
+		return err
+	}
 
 	// Check if name already exists
 	existing, err := s.kategoriRepo.GetByNama(kategori.Nama)
@@ -72,6 +75,9 @@ func (s *KategoriService) UpdateKategori(kategori *models.Kategori) error {
 	if strings.TrimSpace(kategori.Nama) == "" {
 		return fmt.Errorf("category name is required")
 	}
+	if err := ValidateTarifPajak(kategori.TarifPajak); err != nil {
+		return err
+	}
 
 	// Check if category exists
 	existing, err := s.kategoriRepo.GetByID(kategori.ID)
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// KomisiService manages commission rules and calculates staff payouts per period
+type KomisiService struct {
+	repo               *repository.KomisiRepository
+	returnRepo         *repository.ReturnRepository
+	userRepo           *repository.UserRepository
+	staffReportService *StaffReportService
+}
+
+// NewKomisiService creates a new instance
+func NewKomisiService() *KomisiService {
+	return &KomisiService{
+		repo:               repository.NewKomisiRepository(),
+		returnRepo:         repository.NewReturnRepository(),
+		userRepo:           repository.NewUserRepository(),
+		staffReportService: NewStaffReportService(),
+	}
+}
+
+// GetAllAturan returns all commission rules
+func (s *KomisiService) GetAllAturan() ([]*models.AturanKomisi, error) {
+	return s.repo.GetAllAturan()
+}
+
+// CreateAturan validates and saves a new commission rule
+func (s *KomisiService) CreateAturan(a *models.AturanKomisi) error {
+	if err := validateAturanKomisi(a); err != nil {
+		return err
+	}
+	return s.repo.CreateAturan(a)
+}
+
+// UpdateAturan validates and saves changes to a commission rule
+func (s *KomisiService) UpdateAturan(a *models.AturanKomisi) error {
+	existing, err := s.repo.GetAturanByID(a.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("aturan komisi tidak ditemukan")
+	}
+	if err := validateAturanKomisi(a); err != nil {
+		return err
+	}
+	a.CreatedAt = existing.CreatedAt
+	return s.repo.UpdateAturan(a)
+}
+
+// DeleteAturan removes a commission rule
+func (s *KomisiService) DeleteAturan(id int64) error {
+	return s.repo.DeleteAturan(id)
+}
+
+// HitungKomisi calculates the commission of every staff member for a date range without saving it
+func (s *KomisiService) HitungKomisi(startDate, endDate time.Time) ([]*models.KomisiStaff, error) {
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("tanggal selesai tidak boleh sebelum tanggal mulai")
+	}
+
+	semuaAturan, err := s.repo.GetAllAturan()
+	if err != nil {
+		return nil, err
+	}
+	var aturan []*models.AturanKomisi
+	for _, a := range semuaAturan {
+		if a.Aktif {
+			aturan = append(aturan, a)
+		}
+	}
+
+	staffList, err := s.userRepo.GetAllTransactionStaff()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staff list: %w", err)
+	}
+
+	hasil := []*models.KomisiStaff{}
+	for _, staff := range staffList {
+		komisi, err := s.hitungKomisiStaff(staff, aturan, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		hasil = append(hasil, komisi)
+	}
+
+	return hasil, nil
+}
+
+// BuatPeriode calculates and saves the payout of a period as a draft.
+// Periods cannot overlap so no sale is paid twice.
+func (s *KomisiService) BuatPeriode(req *models.BuatPeriodeKomisiRequest) (*models.PeriodeKomisi, error) {
+	startDate, err := time.Parse("2006-01-02", req.PeriodeMulai)
+	if err != nil {
+		return nil, fmt.Errorf("tanggal mulai tidak valid")
+	}
+	endDate, err := time.Parse("2006-01-02", req.PeriodeSelesai)
+	if err != nil {
+		return nil, fmt.Errorf("tanggal selesai tidak valid")
+	}
+
+	overlap, err := s.repo.CountPeriodeOverlap(req.PeriodeMulai, req.PeriodeSelesai)
+	if err != nil {
+		return nil, err
+	}
+	if overlap > 0 {
+		return nil, fmt.Errorf("periode komisi bertumpang tindih dengan periode yang sudah ada")
+	}
+
+	// The end date covers the whole day, as in the staff reports
+	staff, err := s.HitungKomisi(startDate, endDate.Add(24*time.Hour-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	periode := &models.PeriodeKomisi{
+		PeriodeMulai:   req.PeriodeMulai,
+		PeriodeSelesai: req.PeriodeSelesai,
+		Catatan:        strings.TrimSpace(req.Catatan),
+		DibuatOleh:     req.DibuatOleh,
+		Staff:          staff,
+	}
+	for _, k := range staff {
+		periode.TotalKomisi += k.TotalKomisi
+	}
+
+	if err := s.repo.CreatePeriode(periode); err != nil {
+		return nil, err
+	}
+
+	return periode, nil
+}
+
+// GetAllPeriode returns the saved payouts without staff lines
+func (s *KomisiService) GetAllPeriode() ([]*models.PeriodeKomisi, error) {
+	return s.repo.GetAllPeriode()
+}
+
+// GetPeriode returns a saved payout with its staff lines
+func (s *KomisiService) GetPeriode(id int64) (*models.PeriodeKomisi, error) {
+	periode, err := s.repo.GetPeriodeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if periode == nil {
+		return nil, fmt.Errorf("periode komisi tidak ditemukan")
+	}
+	return periode, nil
+}
+
+// TandaiDibayar locks a draft payout as paid
+func (s *KomisiService) TandaiDibayar(id int64) (*models.PeriodeKomisi, error) {
+	if err := s.repo.TandaiDibayar(id); err != nil {
+		return nil, err
+	}
+	return s.GetPeriode(id)
+}
+
+// DeletePeriode removes a draft payout so it can be recalculated
+func (s *KomisiService) DeletePeriode(id int64) error {
+	return s.repo.DeletePeriode(id)
+}
+
+// ExportPayroll renders a saved payout as CSV for payroll: one row per staff member and rule
+func (s *KomisiService) ExportPayroll(id int64) (string, error) {
+	periode, err := s.GetPeriode(id)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"periode_mulai", "periode_selesai", "status", "staff_id", "staff_nama",
+		"total_penjualan", "total_profit", "total_item", "retur_penjualan", "retur_profit",
+		"aturan", "tipe", "basis", "komisi", "total_komisi",
+	})
+	for _, k := range periode.Staff {
+		base := []string{
+			periode.PeriodeMulai, periode.PeriodeSelesai, periode.Status,
+			strconv.FormatInt(k.StaffID, 10), k.StaffNama,
+			strconv.Itoa(k.TotalPenjualan), strconv.Itoa(k.TotalProfit), strconv.Itoa(k.TotalItem),
+			strconv.Itoa(k.ReturPenjualan), strconv.Itoa(k.ReturProfit),
+		}
+		if len(k.Rincian) == 0 {
+			w.Write(append(base, "", "", "0", "0", strconv.Itoa(k.TotalKomisi)))
+			continue
+		}
+		for _, r := range k.Rincian {
+			w.Write(append(append([]string{}, base...),
+				r.Nama, r.Tipe, strconv.FormatFloat(r.Basis, 'f', 2, 64), strconv.Itoa(r.Komisi), strconv.Itoa(k.TotalKomisi)))
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write payroll export: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// hitungKomisiStaff applies every rule to the sales of one staff member.
+// Returns are deducted from each rule's basis in the proportion they take of the staff member's
+// total sales, profit or units, so unfiltered rules are reduced by exactly the returned amount.
+func (s *KomisiService) hitungKomisiStaff(staff *models.User, aturan []*models.AturanKomisi, startDate, endDate time.Time) (*models.KomisiStaff, error) {
+	report, err := s.staffReportService.GetStaffReport(staff.ID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	retur, err := s.returnRepo.GetReturnImpactByStaffAndDateRange(staff.ID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	komisi := &models.KomisiStaff{
+		StaffID:        staff.ID,
+		StaffNama:      staff.NamaLengkap,
+		TotalPenjualan: report.TotalPenjualan,
+		TotalProfit:    report.TotalProfit,
+		TotalItem:      report.TotalItemTerjual,
+		ReturPenjualan: retur.TotalSaleReturned,
+		ReturProfit:    retur.TotalProfitLost,
+		ReturItem:      retur.TotalQuantityReturned,
+		Rincian:        []models.RincianKomisiStaff{},
+	}
+
+	var produk []*models.PenjualanProdukKomisi
+	produkLoaded := false
+	for _, a := range aturan {
+		if !berlakuUntukStaff(a, staff.ID) {
+			continue
+		}
+
+		// Unfiltered rules use the same totals as the staff report; filtered rules use item subtotals
+		penjualan, profit, unit := float64(report.TotalPenjualan), float64(report.TotalProfit), float64(report.TotalItemTerjual)
+		if len(a.ProdukIDs) > 0 || a.Kategori != "" {
+			if !produkLoaded {
+				produk, err = s.repo.GetPenjualanProduk(staff.ID, startDate, endDate)
+				if err != nil {
+					return nil, err
+				}
+				produkLoaded = true
+			}
+			penjualan, profit, unit = 0, 0, 0
+			for _, p := range produk {
+				if !cocokProduk(a, p) {
+					continue
+				}
+				penjualan += float64(p.Penjualan)
+				profit += float64(p.Penjualan - p.HPP)
+				unit += p.Jumlah
+			}
+		}
+
+		penjualan = kurangiRetur(penjualan, float64(retur.TotalSaleReturned), float64(report.TotalPenjualan))
+		profit = kurangiRetur(profit, float64(retur.TotalProfitLost), float64(report.TotalProfit))
+		unit = kurangiRetur(unit, retur.TotalQuantityReturned, float64(report.TotalItemTerjual))
+
+		rincian := models.RincianKomisiStaff{AturanID: a.ID, Nama: a.Nama, Tipe: a.Tipe}
+		switch a.Tipe {
+		case "persen_penjualan":
+			rincian.Basis = penjualan
+			rincian.Komisi = int(math.Round(penjualan * a.Persen / 100))
+		case "persen_profit":
+			rincian.Basis = profit
+			rincian.Komisi = int(math.Round(profit * a.Persen / 100))
+		case "per_unit":
+			rincian.Basis = unit
+			rincian.Komisi = int(math.Round(unit * float64(a.NominalPerUnit)))
+		case "target_bertingkat":
+			rincian.Basis = penjualan
+			if tingkat := tingkatTercapai(a.Tingkat, penjualan); tingkat != nil {
+				rincian.Komisi = tingkat.Bonus + int(math.Round(penjualan*tingkat.Persen/100))
+			}
+		}
+		if rincian.Komisi < 0 {
+			rincian.Komisi = 0
+		}
+
+		komisi.Rincian = append(komisi.Rincian, rincian)
+		komisi.TotalKomisi += rincian.Komisi
+	}
+
+	return komisi, nil
+}
+
+// kurangiRetur reduces a rule basis by its share of the returned amount, never below zero
+func kurangiRetur(basis, retur, total float64) float64 {
+	if basis <= 0 {
+		return 0
+	}
+	if total > 0 && retur > 0 {
+		basis -= retur * basis / total
+	}
+	if basis < 0 {
+		return 0
+	}
+	return basis
+}
+
+// tingkatTercapai returns the highest tier whose target is reached, or nil
+func tingkatTercapai(tingkat []models.TingkatKomisi, penjualan float64) *models.TingkatKomisi {
+	var hasil *models.TingkatKomisi
+	for i := range tingkat {
+		if penjualan >= float64(tingkat[i].Target) && (hasil == nil || tingkat[i].Target > hasil.Target) {
+			hasil = &tingkat[i]
+		}
+	}
+	return hasil
+}
+
+func berlakuUntukStaff(a *models.AturanKomisi, staffID int64) bool {
+	if len(a.StaffIDs) == 0 {
+		return true
+	}
+	for _, id := range a.StaffIDs {
+		if id == staffID {
+			return true
+		}
+	}
+	return false
+}
+
+func cocokProduk(a *models.AturanKomisi, p *models.PenjualanProdukKomisi) bool {
+	if a.Kategori != "" && !strings.EqualFold(a.Kategori, p.Kategori) {
+		return false
+	}
+	if len(a.ProdukIDs) == 0 {
+		return true
+	}
+	for _, id := range a.ProdukIDs {
+		if id == p.ProdukID {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAturanKomisi(a *models.AturanKomisi) error {
+	a.Nama = strings.TrimSpace(a.Nama)
+	a.Kategori = strings.TrimSpace(a.Kategori)
+	if a.Nama == "" {
+		return fmt.Errorf("nama aturan komisi harus diisi")
+	}
+	if a.ProdukIDs == nil {
+		a.ProdukIDs = []int{}
+	}
+	if a.StaffIDs == nil {
+		a.StaffIDs = []int64{}
+	}
+	if a.Tingkat == nil {
+		a.Tingkat = []models.TingkatKomisi{}
+	}
+
+	switch a.Tipe {
+	case "persen_penjualan", "persen_profit":
+		if a.Persen <= 0 || a.Persen > 100 {
+			return fmt.Errorf("persen komisi harus antara 0 dan 100")
+		}
+	case "per_unit":
+		if a.NominalPerUnit <= 0 {
+			return fmt.Errorf("nominal per unit harus lebih dari 0")
+		}
+		if len(a.ProdukIDs) == 0 && a.Kategori == "" {
+			return fmt.Errorf("pilih produk atau kategori untuk komisi per unit")
+		}
+	case "target_bertingkat":
+		if len(a.Tingkat) == 0 {
+			return fmt.Errorf("target bertingkat harus memiliki minimal satu tingkat")
+		}
+		sort.Slice(a.Tingkat, func(i, j int) bool { return a.Tingkat[i].Target < a.Tingkat[j].Target })
+		for i, t := range a.Tingkat {
+			if t.Target <= 0 || t.Bonus < 0 || t.Persen < 0 || t.Persen > 100 {
+				return fmt.Errorf("tingkat target %d tidak valid", i+1)
+			}
+			if t.Bonus == 0 && t.Persen == 0 {
+				return fmt.Errorf("tingkat target %d harus memiliki bonus atau persen", i+1)
+			}
+			if i > 0 && t.Target == a.Tingkat[i-1].Target {
+				return fmt.Errorf("target tingkat tidak boleh sama")
+			}
+		}
+	default:
+		return fmt.Errorf("tipe aturan komisi tidak valid")
+	}
+
+	return nil
+}
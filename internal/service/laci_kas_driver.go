@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"runtime"
+
+	"ritel-app/internal/models"
+
+	"go.bug.st/serial"
+)
+
+// DrawerDriver opens a cash drawer
+type DrawerDriver interface {
+	Buka() error
+}
+
+// drawerKickCommand builds the ESC/POS pulse command ESC p m t1 t2.
+// The pulse times stay below 0x80 so the bytes survive the printer's codepage encoding.
+func drawerKickCommand(pin int) []byte {
+	return []byte{ESC, 'p', byte(pin), 0x19, 0x78}
+}
+
+// escposDrawer kicks a drawer wired to the receipt printer's DK port
+type escposDrawer struct {
+	printerName string
+	pin         int
+}
+
+func (d *escposDrawer) Buka() error {
+	if d.printerName == "" {
+		return fmt.Errorf("printer untuk laci kas belum diatur")
+	}
+	command := string(drawerKickCommand(d.pin))
+	if runtime.GOOS == "windows" {
+		return printRaw(d.printerName, command)
+	}
+	return (&PrinterService{}).printContentFallback(d.printerName, command)
+}
+
+// serialDrawer kicks a drawer connected directly to a serial port
+type serialDrawer struct {
+	port     string
+	baudRate int
+	pin      int
+}
+
+func (d *serialDrawer) Buka() error {
+	if d.port == "" {
+		return fmt.Errorf("port serial laci kas belum diatur")
+	}
+	baudRate := d.baudRate
+	if baudRate == 0 {
+		baudRate = 9600
+	}
+
+	mode := &serial.Mode{
+		BaudRate: baudRate,
+		Parity:   serial.NoParity,
+		DataBits: 8,
+		StopBits: serial.OneStopBit,
+	}
+
+	portHandle, err := serial.Open(d.port, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open port: %v", err)
+	}
+	defer portHandle.Close()
+
+	if _, err := portHandle.Write(drawerKickCommand(d.pin)); err != nil {
+		return fmt.Errorf("failed to send open command: %v", err)
+	}
+	return nil
+}
+
+// newDrawerDriver returns the driver configured for this terminal.
+// defaultPrinter is used by the ESC/POS driver when no drawer printer is set.
+func newDrawerDriver(settings *models.LaciKasSettings, defaultPrinter string) (DrawerDriver, error) {
+	switch settings.Driver {
+	case "serial":
+		return &serialDrawer{port: settings.Port, baudRate: settings.BaudRate, pin: settings.Pin}, nil
+	case "printer", "":
+		printerName := settings.PrinterName
+		if printerName == "" {
+			printerName = defaultPrinter
+		}
+		return &escposDrawer{printerName: printerName, pin: settings.Pin}, nil
+	}
+	return nil, fmt.Errorf("driver laci kas '%s' tidak dikenal", settings.Driver)
+}
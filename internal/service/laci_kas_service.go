@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// LaciKasService opens the cash drawer and logs every opening against the staff member
+type LaciKasService struct {
+	repo             *repository.LaciKasRepository
+	settingsRepo     *repository.SettingsRepository
+	printerRepo      *repository.PrinterRepository
+	sesiKasRepo      *repository.SesiKasRepository
+	otorisasiService *OtorisasiService
+}
+
+// NewLaciKasService creates a new instance
+func NewLaciKasService() *LaciKasService {
+	return &LaciKasService{
+		repo:             repository.NewLaciKasRepository(),
+		settingsRepo:     repository.NewSettingsRepository(),
+		printerRepo:      repository.NewPrinterRepository(),
+		sesiKasRepo:      repository.NewSesiKasRepository(),
+		otorisasiService: NewOtorisasiService(),
+	}
+}
+
+// BukaUntukTransaksi kicks the drawer after checkout when one of the payment methods is configured to open it.
+// entry carries the transaction and staff the opening is logged against.
+func (s *LaciKasService) BukaUntukTransaksi(entry *models.LogLaciKas, metodeList []*models.MetodePembayaran) error {
+	bukaLaci := false
+	for _, metode := range metodeList {
+		if metode.BukaLaci {
+			bukaLaci = true
+			break
+		}
+	}
+	if !bukaLaci {
+		return nil
+	}
+
+	settings, err := s.settingsRepo.GetLaciKasSettings()
+	if err != nil || !settings.Aktif {
+		return err
+	}
+
+	entry.Tipe = "transaksi"
+	return s.buka(entry)
+}
+
+// NoSale opens the drawer without a sale; a reason is required and the opening is logged.
+// A supervisor override is required when the override settings say so.
+func (s *LaciKasService) NoSale(req *models.NoSaleRequest) (*models.LogLaciKas, error) {
+	req.Alasan = strings.TrimSpace(req.Alasan)
+	if req.Alasan == "" {
+		return nil, fmt.Errorf("alasan membuka laci harus diisi")
+	}
+	if req.StaffID == 0 {
+		return nil, fmt.Errorf("staff harus login untuk membuka laci")
+	}
+
+	otorisasiSettings, err := s.otorisasiService.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	// The token is spent when the no-sale log is saved
+	disetujuiOleh := ""
+	var pemakaianOtorisasi *models.PemakaianOtorisasi
+	if otorisasiSettings.WajibNoSale {
+		otorisasi, pemakaian, err := s.otorisasiService.Cek(req.OverrideToken, models.AksiBukaLaci, "")
+		if err != nil {
+			return nil, err
+		}
+		disetujuiOleh = otorisasi.SupervisorUsername
+		pemakaianOtorisasi = pemakaian
+	}
+
+	entry := &models.LogLaciKas{
+		Tipe:          "no_sale",
+		Alasan:        req.Alasan,
+		StaffID:       req.StaffID,
+		StaffNama:     req.StaffNama,
+		DisetujuiOleh: disetujuiOleh,
+		Otorisasi:     pemakaianOtorisasi,
+	}
+	if err := s.buka(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetLog retrieves drawer openings within a date range
+func (s *LaciKasService) GetLog(staffID int64, tipe string, startDate, endDate time.Time) ([]*models.LogLaciKas, error) {
+	if tipe != "" && tipe != "transaksi" && tipe != "no_sale" {
+		return nil, fmt.Errorf("tipe log laci tidak valid")
+	}
+	return s.repo.GetLog(staffID, tipe, startDate, endDate)
+}
+
+// buka runs the configured driver and records the result, failed attempts included
+func (s *LaciKasService) buka(entry *models.LogLaciKas) error {
+	settings, err := s.settingsRepo.GetLaciKasSettings()
+	if err != nil {
+		return err
+	}
+	if !settings.Aktif {
+		return fmt.Errorf("laci kas belum diaktifkan di pengaturan")
+	}
+
+	if entry.SesiID == 0 && entry.StaffID != 0 {
+		if sesi, err := s.sesiKasRepo.GetAktifByStaff(entry.StaffID); err == nil && sesi != nil {
+			entry.SesiID = sesi.ID
+		}
+	}
+
+	defaultPrinter := ""
+	if printSettings, err := s.printerRepo.GetPrintSettings(); err == nil && printSettings != nil {
+		defaultPrinter = printSettings.PrinterName
+	}
+
+	driver, err := newDrawerDriver(settings, defaultPrinter)
+	if err == nil {
+		err = driver.Buka()
+	}
+	entry.Berhasil = err == nil
+	if err != nil {
+		entry.Pesan = err.Error()
+	}
+
+	logErr := s.repo.CreateLog(entry)
+	if logErr != nil {
+		log.Printf("[LACI KAS] Failed to log drawer opening: %v", logErr)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal membuka laci kas: %w", err)
+	}
+	// An override is only spent together with its log entry, so report it when that fails
+	if logErr != nil && entry.Otorisasi != nil {
+		return logErr
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// metodeSistem are the methods backed by a ledger; their code and type are fixed
+var metodeSistem = []*models.MetodePembayaran{
+	{Kode: models.MetodeSaldo, Nama: "Saldo Pelanggan", Tipe: "saldo", Aktif: true, Urutan: 6},
+	{Kode: models.MetodeTempo, Nama: "Tempo (Hutang)", Tipe: "tempo", Aktif: true, Urutan: 7},
+}
+
+// tipeMetodePembayaran lists the supported payment method types
+var tipeMetodePembayaran = map[string]bool{
+	"tunai":          true,
+	"kartu":          true,
+	"dompet_digital": true,
+	"transfer":       true,
+	"saldo":          true,
+	"tempo":          true,
+	"lainnya":        true,
+}
+
+// MetodePembayaranService handles payment method configuration
+type MetodePembayaranService struct {
+	repo *repository.MetodePembayaranRepository
+}
+
+// NewMetodePembayaranService creates a new instance
+func NewMetodePembayaranService() *MetodePembayaranService {
+	return &MetodePembayaranService{
+		repo: repository.NewMetodePembayaranRepository(),
+	}
+}
+
+// EnsureDefaultMetode creates the standard payment methods when none are configured,
+// and the system methods (store credit, credit sale) when they are missing
+func (s *MetodePembayaranService) EnsureDefaultMetode() error {
+	count, err := s.repo.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return s.ensureMetodeSistem()
+	}
+
+	defaults := []*models.MetodePembayaran{
+		{Kode: "tunai", Nama: "Tunai", Tipe: "tunai", BukaLaci: true, Aktif: true, Urutan: 1},
+		{Kode: "qris", Nama: "QRIS", Tipe: "dompet_digital", PerluReferensi: true, Aktif: true, Urutan: 2},
+		{Kode: "debit", Nama: "Kartu Debit", Tipe: "kartu", PerluReferensi: true, Aktif: true, Urutan: 3},
+		{Kode: "kredit", Nama: "Kartu Kredit", Tipe: "kartu", PerluReferensi: true, Aktif: true, Urutan: 4},
+		{Kode: "transfer", Nama: "Transfer Bank", Tipe: "transfer", PerluReferensi: true, Aktif: true, Urutan: 5},
+	}
+	for _, m := range defaults {
+		if err := s.repo.Create(m); err != nil {
+			return fmt.Errorf("failed to create default payment method %s: %w", m.Kode, err)
+		}
+	}
+
+	return s.ensureMetodeSistem()
+}
+
+func (s *MetodePembayaranService) ensureMetodeSistem() error {
+	for _, sistem := range metodeSistem {
+		existing, err := s.repo.GetByKode(sistem.Kode)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			continue
+		}
+
+		m := *sistem
+		if err := s.repo.Create(&m); err != nil {
+			return fmt.Errorf("failed to create payment method %s: %w", m.Kode, err)
+		}
+	}
+	return nil
+}
+
+// isMetodeSistem reports whether the code belongs to a system method
+func isMetodeSistem(kode string) bool {
+	for _, sistem := range metodeSistem {
+		if sistem.Kode == kode {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAllMetode retrieves all payment methods, optionally only the active ones
+func (s *MetodePembayaranService) GetAllMetode(aktifSaja bool) ([]*models.MetodePembayaran, error) {
+	list, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if !aktifSaja {
+		return list, nil
+	}
+
+	aktif := make([]*models.MetodePembayaran, 0, len(list))
+	for _, m := range list {
+		if m.Aktif {
+			aktif = append(aktif, m)
+		}
+	}
+	return aktif, nil
+}
+
+// GetMetodeByKode retrieves a payment method by its code
+func (s *MetodePembayaranService) GetMetodeByKode(kode string) (*models.MetodePembayaran, error) {
+	return s.repo.GetByKode(normalizeKodeMetode(kode))
+}
+
+// CreateMetode validates and saves a new payment method
+func (s *MetodePembayaranService) CreateMetode(m *models.MetodePembayaran) error {
+	if err := s.validateMetode(m); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByKode(m.Kode)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("kode metode '%s' sudah dipakai", m.Kode)
+	}
+
+	return s.repo.Create(m)
+}
+
+// UpdateMetode validates and saves changes to a payment method
+func (s *MetodePembayaranService) UpdateMetode(m *models.MetodePembayaran) error {
+	if err := s.validateMetode(m); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByID(m.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("metode pembayaran tidak ditemukan")
+	}
+
+	if isMetodeSistem(existing.Kode) && (m.Kode != existing.Kode || m.Tipe != existing.Tipe) {
+		return fmt.Errorf("kode dan tipe metode %s tidak dapat diubah", existing.Nama)
+	}
+
+	if existing.Kode != m.Kode {
+		// Riwayat pembayaran menyimpan kode, jadi kode yang sudah dipakai tidak boleh diganti
+		used, err := s.repo.CountUsage(existing.Kode)
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return fmt.Errorf("kode metode '%s' sudah dipakai di %d pembayaran dan tidak dapat diubah", existing.Kode, used)
+		}
+		other, err := s.repo.GetByKode(m.Kode)
+		if err != nil {
+			return err
+		}
+		if other != nil {
+			return fmt.Errorf("kode metode '%s' sudah dipakai", m.Kode)
+		}
+	}
+
+	m.CreatedAt = existing.CreatedAt
+	return s.repo.Update(m)
+}
+
+// DeleteMetode removes a payment method that has never been used
+func (s *MetodePembayaranService) DeleteMetode(id int64) error {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("metode pembayaran tidak ditemukan")
+	}
+	if isMetodeSistem(existing.Kode) {
+		return fmt.Errorf("metode %s tidak dapat dihapus, nonaktifkan saja", existing.Nama)
+	}
+
+	used, err := s.repo.CountUsage(existing.Kode)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return fmt.Errorf("metode '%s' sudah dipakai di %d pembayaran, nonaktifkan saja", existing.Nama, used)
+	}
+
+	return s.repo.Delete(id)
+}
+
+func (s *MetodePembayaranService) validateMetode(m *models.MetodePembayaran) error {
+	m.Kode = normalizeKodeMetode(m.Kode)
+	m.Nama = strings.TrimSpace(m.Nama)
+	m.Tipe = strings.ToLower(strings.TrimSpace(m.Tipe))
+
+	if m.Kode == "" {
+		return fmt.Errorf("kode metode tidak boleh kosong")
+	}
+	if len(m.Kode) > 20 {
+		return fmt.Errorf("kode metode maksimal 20 karakter")
+	}
+	for _, c := range m.Kode {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' {
+			return fmt.Errorf("kode metode hanya boleh berisi huruf kecil, angka dan garis bawah")
+		}
+	}
+	if m.Nama == "" {
+		return fmt.Errorf("nama metode tidak boleh kosong")
+	}
+	if !tipeMetodePembayaran[m.Tipe] {
+		return fmt.Errorf("tipe metode tidak valid")
+	}
+	if (m.Tipe == "saldo" || m.Tipe == "tempo") && !isMetodeSistem(m.Kode) {
+		return fmt.Errorf("tipe %s hanya untuk metode sistem", m.Tipe)
+	}
+
+	return nil
+}
+
+// normalizeKodeMetode makes method codes case-insensitive
+func normalizeKodeMetode(kode string) string {
+	return strings.ToLower(strings.TrimSpace(kode))
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// OpnameService handles stock count sessions: opening, counting, variance review and posting
+type OpnameService struct {
+	repo       *repository.OpnameRepository
+	produkRepo *repository.ProdukRepository
+}
+
+// NewOpnameService creates a new instance
+func NewOpnameService() *OpnameService {
+	return &OpnameService{
+		repo:       repository.NewOpnameRepository(),
+		produkRepo: repository.NewProdukRepository(),
+	}
+}
+
+// GetAllOpname retrieves count sessions, optionally filtered by status
+func (s *OpnameService) GetAllOpname(status string) ([]*models.StokOpname, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "", models.StatusOpnameDibuka, models.StatusOpnameDiposting, models.StatusOpnameBatal:
+	default:
+		return nil, fmt.Errorf("status opname '%s' tidak dikenal", status)
+	}
+	return s.repo.GetAllOpname(status)
+}
+
+// GetOpnameByID retrieves a count session with all its lines
+func (s *OpnameService) GetOpnameByID(id int64) (*models.StokOpname, error) {
+	opname, err := s.repo.GetOpnameByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if opname == nil {
+		return nil, fmt.Errorf("sesi opname tidak ditemukan")
+	}
+
+	if opname.Items, err = s.repo.GetOpnameItems(id); err != nil {
+		return nil, err
+	}
+	return opname, nil
+}
+
+// BukaOpname opens a count session over all products or one category. Sessions may not
+// overlap: a full count excludes every other open session.
+func (s *OpnameService) BukaOpname(req *models.BukaOpnameRequest, aktor models.Aktor) (*models.StokOpname, error) {
+	kategori := strings.TrimSpace(req.Kategori)
+
+	dibuka, err := s.repo.GetAllOpname(models.StatusOpnameDibuka)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range dibuka {
+		if o.Kategori == "" || kategori == "" || o.Kategori == kategori {
+			cakupan := "semua produk"
+			if o.Kategori != "" {
+				cakupan = "kategori " + o.Kategori
+			}
+			return nil, fmt.Errorf("sesi opname %s untuk %s masih dibuka", o.NomorOpname, cakupan)
+		}
+	}
+
+	opname := &models.StokOpname{
+		Kategori:   kategori,
+		Catatan:    strings.TrimSpace(req.Catatan),
+		DibukaOleh: aktor.Nama,
+	}
+	if err := s.repo.CreateOpname(opname); err != nil {
+		return nil, err
+	}
+	return opname, nil
+}
+
+// CatatHitungan records a counted quantity for a product found by ID or barcode and
+// returns its line with the current variance
+func (s *OpnameService) CatatHitungan(opnameID int64, req *models.HitungOpnameRequest, aktor models.Aktor) (*models.StokOpnameItem, error) {
+	opname, err := s.repo.GetOpnameByID(opnameID)
+	if err != nil {
+		return nil, err
+	}
+	if opname == nil {
+		return nil, fmt.Errorf("sesi opname tidak ditemukan")
+	}
+	if opname.Status != models.StatusOpnameDibuka {
+		return nil, fmt.Errorf("sesi opname berstatus %s tidak dapat dihitung", opname.Status)
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	if mode == "" {
+		mode = models.ModeHitungTambah
+	}
+	switch mode {
+	case models.ModeHitungTambah:
+		if req.Qty <= 0 {
+			return nil, fmt.Errorf("jumlah hitungan harus lebih dari 0")
+		}
+	case models.ModeHitungGanti:
+		if req.Qty < 0 {
+			return nil, fmt.Errorf("jumlah hitungan tidak boleh negatif")
+		}
+	default:
+		return nil, fmt.Errorf("mode hitung '%s' tidak dikenal", req.Mode)
+	}
+
+	produkID := req.ProdukID
+	if produkID == 0 {
+		barcode := strings.TrimSpace(req.Barcode)
+		if barcode == "" {
+			return nil, fmt.Errorf("produk atau barcode harus diisi")
+		}
+		produk, err := s.produkRepo.GetByBarcode(barcode)
+		if err != nil {
+			return nil, err
+		}
+		if produk == nil {
+			return nil, fmt.Errorf("produk dengan barcode %s tidak ditemukan", barcode)
+		}
+		produkID = produk.ID
+	}
+
+	if err := s.repo.CatatHitungan(opnameID, produkID, req.Qty, mode, aktor.Nama, strings.TrimSpace(req.Perangkat)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOpnameItem(opnameID, produkID)
+}
+
+// LaporanSelisih builds the variance report of a count session, largest value first
+func (s *OpnameService) LaporanSelisih(id int64) (*models.LaporanSelisihOpname, error) {
+	opname, err := s.GetOpnameByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	laporan := &models.LaporanSelisihOpname{
+		JumlahItem:    len(opname.Items),
+		Selisih:       []*models.StokOpnameItem{},
+		BelumDihitung: []*models.StokOpnameItem{},
+	}
+	for _, item := range opname.Items {
+		if item.QtyHitung == nil {
+			laporan.BelumDihitung = append(laporan.BelumDihitung, item)
+			continue
+		}
+		laporan.JumlahDihitung++
+		if math.Abs(item.Selisih) < 0.0001 {
+			continue
+		}
+
+		laporan.Selisih = append(laporan.Selisih, item)
+		if item.Selisih > 0 {
+			laporan.QtyLebih += item.Selisih
+			laporan.NilaiLebih += item.NilaiSelisih
+		} else {
+			laporan.QtyKurang -= item.Selisih
+			laporan.NilaiKurang -= item.NilaiSelisih
+		}
+	}
+	sort.SliceStable(laporan.Selisih, func(i, j int) bool {
+		return absInt(laporan.Selisih[i].NilaiSelisih) > absInt(laporan.Selisih[j].NilaiSelisih)
+	})
+
+	laporan.JumlahBelumDihitung = len(laporan.BelumDihitung)
+	laporan.JumlahSelisih = len(laporan.Selisih)
+	laporan.NilaiBersih = laporan.NilaiLebih - laporan.NilaiKurang
+
+	opname.Items = nil
+	laporan.Opname = opname
+	return laporan, nil
+}
+
+// PostingOpname approves the variances of an open session, adjusts stock and batches and
+// returns the final variance report
+func (s *OpnameService) PostingOpname(id int64, req *models.PostingOpnameRequest, aktor models.Aktor) (*models.LaporanSelisihOpname, error) {
+	opname, err := s.repo.GetOpnameByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if opname == nil {
+		return nil, fmt.Errorf("sesi opname tidak ditemukan")
+	}
+	if opname.Status != models.StatusOpnameDibuka {
+		return nil, fmt.Errorf("sesi opname berstatus %s tidak dapat diposting", opname.Status)
+	}
+	if opname.JumlahDihitung == 0 && !req.NolkanBelumDihitung {
+		return nil, fmt.Errorf("belum ada produk yang dihitung")
+	}
+
+	if err := s.repo.PostingOpname(id, aktor.Nama, req.NolkanBelumDihitung); err != nil {
+		return nil, err
+	}
+	return s.LaporanSelisih(id)
+}
+
+// BatalkanOpname cancels an open session; stock is not changed
+func (s *OpnameService) BatalkanOpname(id int64) (*models.StokOpname, error) {
+	if err := s.repo.BatalkanOpname(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOpnameByID(id)
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
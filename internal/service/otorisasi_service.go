@@ -0,0 +1,144 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// OtorisasiService issues and redeems supervisor override tokens. A supervisor approves
+// an action at the register with username and PIN; the register attaches the returned token
+// to the restricted request, which spends it exactly once.
+type OtorisasiService struct {
+	repo         *repository.OtorisasiRepository
+	settingsRepo *repository.SettingsRepository
+	userService  *UserService
+}
+
+// NewOtorisasiService creates a new instance
+func NewOtorisasiService() *OtorisasiService {
+	return &OtorisasiService{
+		repo:         repository.NewOtorisasiRepository(),
+		settingsRepo: repository.NewSettingsRepository(),
+		userService:  NewUserService(),
+	}
+}
+
+// Minta verifies the supervisor and issues a single-use token for one action
+func (s *OtorisasiService) Minta(req *models.MintaOtorisasiRequest) (*models.OtorisasiResponse, error) {
+	if !aksiOtorisasiValid(req.Aksi) {
+		return nil, fmt.Errorf("aksi otorisasi '%s' tidak dikenal", req.Aksi)
+	}
+
+	supervisor, err := s.userService.VerifyApprover(req.Username, req.Pin)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	otorisasi := &models.Otorisasi{
+		Aksi:               req.Aksi,
+		Referensi:          strings.TrimSpace(req.Referensi),
+		SupervisorID:       supervisor.ID,
+		SupervisorUsername: supervisor.Username,
+		SupervisorNama:     supervisor.NamaLengkap,
+		DimintaOlehID:      req.StaffID,
+		DimintaOlehNama:    req.StaffNama,
+		ExpiresAt:          time.Now().UTC().Add(time.Duration(settings.MasaBerlakuDetik) * time.Second),
+	}
+	if err := s.repo.Create(otorisasi, hashTokenOtorisasi(token)); err != nil {
+		return nil, err
+	}
+
+	return &models.OtorisasiResponse{
+		Token:          token,
+		Aksi:           otorisasi.Aksi,
+		SupervisorNama: otorisasi.SupervisorNama,
+		ExpiresAt:      otorisasi.ExpiresAt,
+	}, nil
+}
+
+// Pakai spends a token for an action and returns the approving supervisor.
+// referensi is checked only when the token was issued for a specific record.
+func (s *OtorisasiService) Pakai(token, aksi, referensi string) (*models.Otorisasi, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("aksi ini membutuhkan otorisasi supervisor")
+	}
+
+	otorisasi, err := s.repo.Pakai(hashTokenOtorisasi(token), aksi, strings.TrimSpace(referensi), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if otorisasi == nil {
+		return nil, fmt.Errorf("token otorisasi tidak valid, kedaluwarsa, atau sudah dipakai")
+	}
+	return otorisasi, nil
+}
+
+// Cek checks that a token can be spent for an action without spending it.
+// The returned usage is spent by the repository that writes the approved action.
+func (s *OtorisasiService) Cek(token, aksi, referensi string) (*models.Otorisasi, *models.PemakaianOtorisasi, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, nil, fmt.Errorf("aksi ini membutuhkan otorisasi supervisor")
+	}
+
+	pemakaian := &models.PemakaianOtorisasi{
+		TokenHash: hashTokenOtorisasi(token),
+		Aksi:      aksi,
+		Referensi: strings.TrimSpace(referensi),
+	}
+	otorisasi, err := s.repo.Cek(pemakaian.TokenHash, pemakaian.Aksi, pemakaian.Referensi, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if otorisasi == nil {
+		return nil, nil, fmt.Errorf("token otorisasi tidak valid, kedaluwarsa, atau sudah dipakai")
+	}
+	return otorisasi, pemakaian, nil
+}
+
+// GetSettings returns the current override thresholds
+func (s *OtorisasiService) GetSettings() (*models.OtorisasiSettings, error) {
+	settings, err := s.settingsRepo.GetOtorisasiSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otorisasi settings: %w", err)
+	}
+	return settings, nil
+}
+
+// GetRiwayat retrieves issued tokens within a date range
+func (s *OtorisasiService) GetRiwayat(startDate, endDate time.Time) ([]*models.Otorisasi, error) {
+	return s.repo.GetRiwayat(startDate, endDate)
+}
+
+func aksiOtorisasiValid(aksi string) bool {
+	for _, a := range models.DaftarAksiOtorisasi {
+		if a == aksi {
+			return true
+		}
+	}
+	return false
+}
+
+func hashTokenOtorisasi(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
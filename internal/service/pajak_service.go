@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// PajakService calculates PPN for transaction items
+type PajakService struct {
+	settingsRepo *repository.SettingsRepository
+	produkRepo   *repository.ProdukRepository
+	kategoriRepo *repository.KategoriRepository
+}
+
+// NewPajakService creates a new instance
+func NewPajakService() *PajakService {
+	return &PajakService{
+		settingsRepo: repository.NewSettingsRepository(),
+		produkRepo:   repository.NewProdukRepository(),
+		kategoriRepo: repository.NewKategoriRepository(),
+	}
+}
+
+// HitungPajakTransaksi fills TarifPajak, DPP and Pajak of every item.
+// itemDiskon holds the discount allocated to each item; tax is charged on the price after discount.
+// It returns the total tax and the tax mode, or 0 and "" when tax is disabled.
+func (s *PajakService) HitungPajakTransaksi(items []models.TransaksiItemRequest, itemDiskon []int) (int, string, error) {
+	settings, err := s.settingsRepo.GetPajakSettings()
+	if err != nil {
+		return 0, "", err
+	}
+	if !settings.Aktif {
+		return 0, "", nil
+	}
+
+	kategoriCache := make(map[string]float64)
+	totalPajak := 0
+	for i := range items {
+		tarif, err := s.tarifProduk(items[i].ProdukID, settings, kategoriCache)
+		if err != nil {
+			return 0, "", err
+		}
+
+		netto := itemRequestSubtotal(items[i])
+		if i < len(itemDiskon) {
+			netto -= itemDiskon[i]
+		}
+		if netto < 0 {
+			netto = 0
+		}
+
+		dpp, pajak := HitungPajakItem(netto, tarif, settings.Mode)
+		items[i].TarifPajak = tarif
+		items[i].DPP = dpp
+		items[i].Pajak = pajak
+		totalPajak += pajak
+	}
+
+	return totalPajak, settings.Mode, nil
+}
+
+// tarifProduk resolves the tax rate of a product: product rate, then category rate, then store default
+func (s *PajakService) tarifProduk(produkID int, settings *models.PajakSettings, kategoriCache map[string]float64) (float64, error) {
+	produk, err := s.produkRepo.GetByID(produkID)
+	if err != nil {
+		return 0, err
+	}
+	if produk == nil {
+		return 0, fmt.Errorf("produk ID %d tidak ditemukan", produkID)
+	}
+	if produk.TarifPajak != nil {
+		return *produk.TarifPajak, nil
+	}
+
+	if tarif, ok := kategoriCache[produk.Kategori]; ok {
+		return tarif, nil
+	}
+
+	tarif := settings.TarifDefault
+	if produk.Kategori != "" {
+		kategori, err := s.kategoriRepo.GetByNama(produk.Kategori)
+		if err != nil {
+			return 0, err
+		}
+		if kategori != nil && kategori.TarifPajak != nil {
+			tarif = *kategori.TarifPajak
+		}
+	}
+	kategoriCache[produk.Kategori] = tarif
+
+	return tarif, nil
+}
+
+// HitungPajakItem splits a net item amount into tax base (DPP) and tax.
+// In exclusive mode the tax is added on top of netto; in inclusive mode netto already contains it.
+func HitungPajakItem(netto int, tarif float64, mode string) (int, int) {
+	if netto <= 0 || tarif <= 0 {
+		return netto, 0
+	}
+	if mode == "inclusive" {
+		dpp := int(math.Round(float64(netto) * 100 / (100 + tarif)))
+		return dpp, netto - dpp
+	}
+	return netto, int(math.Round(float64(netto) * tarif / 100))
+}
+
+// AlokasiDiskonItem returns the discount carried by each item: its promo allocation plus
+// a share of the transaction-level discount proportional to the item amount after promo.
+func AlokasiDiskonItem(items []models.TransaksiItemRequest, promoBreakdown []models.ItemPromoAllocation, diskonTransaksi int) []int {
+	itemDiskon := make([]int, len(items))
+	for _, alloc := range promoBreakdown {
+		if alloc.ItemIndex >= 0 && alloc.ItemIndex < len(items) {
+			itemDiskon[alloc.ItemIndex] += alloc.Diskon
+		}
+	}
+
+	if diskonTransaksi <= 0 {
+		return itemDiskon
+	}
+
+	basis := make([]int, len(items))
+	totalBasis := 0
+	for i, item := range items {
+		basis[i] = itemRequestSubtotal(item) - itemDiskon[i]
+		if basis[i] < 0 {
+			basis[i] = 0
+		}
+		totalBasis += basis[i]
+	}
+	if totalBasis == 0 {
+		return itemDiskon
+	}
+
+	// The last item with a basis takes the rounding remainder
+	sisa := diskonTransaksi
+	last := -1
+	for i := range items {
+		if basis[i] > 0 {
+			last = i
+		}
+	}
+	for i := range items {
+		if basis[i] == 0 {
+			continue
+		}
+		bagian := diskonTransaksi * basis[i] / totalBasis
+		if i == last {
+			bagian = sisa
+		}
+		itemDiskon[i] += bagian
+		sisa -= bagian
+	}
+
+	return itemDiskon
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"ritel-app/internal/models"
 	"ritel-app/internal/repository"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,6 +14,9 @@ type PelangganService struct {
 	pelangganRepo *repository.PelangganRepository
 	settingsRepo  *repository.SettingsRepository
 	transaksiRepo *repository.TransaksiRepository
+	saldoRepo     *repository.SaldoPelangganRepository
+	metodeRepo    *repository.MetodePembayaranRepository
+	auditService  *AuditService
 }
 
 // NewPelangganService creates a new instance
@@ -21,6 +25,9 @@ func NewPelangganService() *PelangganService {
 		pelangganRepo: repository.NewPelangganRepository(),
 		settingsRepo:  repository.NewSettingsRepository(),
 		transaksiRepo: repository.NewTransaksiRepository(),
+		saldoRepo:     repository.NewSaldoPelangganRepository(),
+		metodeRepo:    repository.NewMetodePembayaranRepository(),
+		auditService:  NewAuditService(),
 	}
 }
 
@@ -184,6 +191,15 @@ func (s *PelangganService) DeletePelanggan(id int64) error {
 		return fmt.Errorf("pelanggan dengan ID %d tidak ditemukan", id)
 	}
 
+	// Saldo deposit masih menjadi kewajiban toko
+	if pelanggan.Saldo != 0 {
+		return fmt.Errorf("tidak dapat menghapus pelanggan yang masih memiliki saldo (Rp %d)", pelanggan.Saldo)
+	}
+
+	if pelanggan.Piutang > 0 {
+		return fmt.Errorf("tidak dapat menghapus pelanggan yang masih memiliki piutang (Rp %d)", pelanggan.Piutang)
+	}
+
 	// 3. CEK APAKAH PELANGGAN MEMILIKI TRANSAKSI
 	// (Opsional: jika ingin mencegah delete pelanggan yang punya transaksi)
 	transaksi, err := s.transaksiRepo.GetByPelangganID(id)
@@ -250,6 +266,11 @@ func (s *PelangganService) IncrementStats(pelangganID int64, totalBelanja int) e
 	return s.pelangganRepo.IncrementStats(pelangganID, totalBelanja)
 }
 
+// DecrementStats reverses customer transaction statistics for a voided sale
+func (s *PelangganService) DecrementStats(pelangganID int64, totalBelanja int) error {
+	return s.pelangganRepo.DecrementStats(pelangganID, totalBelanja)
+}
+
 // ProcessTransaction updates customer stats after a transaction
 func (s *PelangganService) ProcessTransaction(pelangganID int64, totalBelanja int) error {
 	if pelangganID == 0 {
@@ -399,26 +420,35 @@ func (s *PelangganService) calculateLevel(poin int, settings *models.PoinSetting
 	}
 }
 
-// UpdatePoinWithReason updates customer points with a specific reason (for audit trail)
-func (s *PelangganService) UpdatePoinWithReason(pelangganID int64, newPoin int, reason string) error {
+// UpdatePoinWithReason updates customer points and records the change with its reason in the audit log
+func (s *PelangganService) UpdatePoinWithReason(pelangganID int64, newPoin int, reason string, aktor models.Aktor) error {
 	fmt.Printf("[PELANGGAN SERVICE] UpdatePoinWithReason - ID: %d, New Points: %d, Reason: %s\n",
 		pelangganID, newPoin, reason)
 
+	sebelum, err := s.pelangganRepo.GetByID(pelangganID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil data pelanggan: %w", err)
+	}
+
 	if err := s.UpdatePoin(pelangganID, newPoin); err != nil {
 		return err
 	}
 
-	// Here you can add audit logging for point changes
-	fmt.Printf("[AUDIT] Points updated for customer %d: %d points, Reason: %s\n",
-		pelangganID, newPoin, reason)
+	// Level and discount may follow the points, so diff the whole customer
+	sesudah, err := s.pelangganRepo.GetByID(pelangganID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil data pelanggan: %w", err)
+	}
+	s.auditService.Catat(aktor, models.AuditEntitasPelanggan, strconv.FormatInt(pelangganID, 10), models.AuditAksiUpdate,
+		sebelum, sesudah, reason)
 
 	return nil
 }
 
 // ResetPoin resets customer points to zero (for admin purposes)
-func (s *PelangganService) ResetPoin(pelangganID int64) error {
+func (s *PelangganService) ResetPoin(pelangganID int64, aktor models.Aktor) error {
 	fmt.Printf("[PELANGGAN SERVICE] ResetPoin called for ID: %d\n", pelangganID)
-	return s.UpdatePoinWithReason(pelangganID, 0, "Reset by admin")
+	return s.UpdatePoinWithReason(pelangganID, 0, "Reset by admin", aktor)
 }
 
 // GetPoinHistory retrieves point change history (placeholder - implement based on your needs)
@@ -432,6 +462,93 @@ func (s *PelangganService) GetPoinHistory(pelangganID int64) ([]map[string]inter
 	return []map[string]interface{}{}, nil
 }
 
+// TopUpSaldo records a deposit paid in by a customer
+func (s *PelangganService) TopUpSaldo(req *models.TopUpSaldoRequest) (*models.Pelanggan, error) {
+	if req.Jumlah <= 0 {
+		return nil, fmt.Errorf("jumlah top up harus lebih dari 0")
+	}
+
+	metode, err := s.metodeRepo.GetByKode(strings.ToLower(strings.TrimSpace(req.Metode)))
+	if err != nil {
+		return nil, err
+	}
+	if metode == nil || !metode.Aktif {
+		return nil, fmt.Errorf("metode pembayaran top up tidak valid")
+	}
+	if metode.Kode == models.MetodeSaldo {
+		return nil, fmt.Errorf("top up tidak dapat dibayar dengan saldo")
+	}
+	req.Referensi = strings.TrimSpace(req.Referensi)
+	if metode.PerluReferensi && !metode.IsTunai() && req.Referensi == "" {
+		return nil, fmt.Errorf("nomor referensi %s harus diisi", metode.Nama)
+	}
+
+	mutasi := &models.SaldoPelangganHistory{
+		PelangganID: req.PelangganID,
+		Tipe:        "topup",
+		Jumlah:      req.Jumlah,
+		Metode:      metode.Kode,
+		Referensi:   req.Referensi,
+		Keterangan:  fmt.Sprintf("Top up saldo via %s", metode.Nama),
+		StaffID:     req.StaffID,
+		StaffNama:   req.StaffNama,
+	}
+	if err := s.saldoRepo.Mutasi(mutasi); err != nil {
+		return nil, err
+	}
+
+	return s.pelangganRepo.GetByID(req.PelangganID)
+}
+
+// PenyesuaianSaldo manually corrects a customer's balance (admin only)
+func (s *PelangganService) PenyesuaianSaldo(req *models.PenyesuaianSaldoRequest) (*models.Pelanggan, error) {
+	if req.Jumlah == 0 {
+		return nil, fmt.Errorf("jumlah penyesuaian tidak boleh 0")
+	}
+	req.Alasan = strings.TrimSpace(req.Alasan)
+	if req.Alasan == "" {
+		return nil, fmt.Errorf("alasan penyesuaian harus diisi")
+	}
+
+	mutasi := &models.SaldoPelangganHistory{
+		PelangganID: req.PelangganID,
+		Tipe:        "penyesuaian",
+		Jumlah:      req.Jumlah,
+		Keterangan:  req.Alasan,
+		StaffID:     req.StaffID,
+		StaffNama:   req.StaffNama,
+	}
+	if err := s.saldoRepo.Mutasi(mutasi); err != nil {
+		return nil, err
+	}
+
+	return s.pelangganRepo.GetByID(req.PelangganID)
+}
+
+// UpdateKredit sets the credit limit and default credit term of a customer (admin only)
+func (s *PelangganService) UpdateKredit(req *models.UpdateKreditPelangganRequest) (*models.Pelanggan, error) {
+	if req.LimitKredit < 0 {
+		return nil, fmt.Errorf("limit kredit tidak boleh negatif")
+	}
+	if req.TempoHari < 1 || req.TempoHari > 365 {
+		return nil, fmt.Errorf("lama tempo harus antara 1 dan 365 hari")
+	}
+
+	if err := s.pelangganRepo.UpdateKredit(req.PelangganID, req.LimitKredit, req.TempoHari); err != nil {
+		return nil, err
+	}
+
+	return s.pelangganRepo.GetByID(req.PelangganID)
+}
+
+// GetSaldoHistory retrieves the latest balance movements of a customer
+func (s *PelangganService) GetSaldoHistory(pelangganID int64, limit int) ([]*models.SaldoPelangganHistory, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.saldoRepo.GetByPelangganID(pelangganID, limit)
+}
+
 // GetPelangganWithStats retrieves customer with transaction statistics
 func (s *PelangganService) GetPelangganWithStats(pelangganID int64) (*models.PelangganDetail, error) {
 	// Get basic customer info
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// PembelianService handles purchase orders and goods receipts
+type PembelianService struct {
+	repo         *repository.PembelianRepository
+	supplierRepo *repository.SupplierRepository
+	produkRepo   *repository.ProdukRepository
+	settingsRepo *repository.SettingsRepository
+	auditService *AuditService
+}
+
+// NewPembelianService creates a new instance
+func NewPembelianService() *PembelianService {
+	return &PembelianService{
+		repo:         repository.NewPembelianRepository(),
+		supplierRepo: repository.NewSupplierRepository(),
+		produkRepo:   repository.NewProdukRepository(),
+		settingsRepo: repository.NewSettingsRepository(),
+		auditService: NewAuditService(),
+	}
+}
+
+// GetAllPesanan retrieves purchase orders, optionally filtered by status
+func (s *PembelianService) GetAllPesanan(status string) ([]*models.PesananPembelian, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status != "" && !statusPOValid(status) {
+		return nil, fmt.Errorf("status pesanan pembelian '%s' tidak dikenal", status)
+	}
+	return s.repo.GetAllPesanan(status)
+}
+
+// GetPesananByID retrieves a purchase order with its lines and the goods received against it
+func (s *PembelianService) GetPesananByID(id int64) (*models.PesananPembelian, error) {
+	po, err := s.repo.GetPesananByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if po == nil {
+		return nil, fmt.Errorf("pesanan pembelian tidak ditemukan")
+	}
+
+	if po.Penerimaan, err = s.repo.GetPenerimaanByPesanan(id); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// CreatePesanan validates and saves a new draft purchase order
+func (s *PembelianService) CreatePesanan(req *models.PesananPembelianRequest) (*models.PesananPembelian, error) {
+	po, err := s.buildPesanan(req)
+	if err != nil {
+		return nil, err
+	}
+	po.DibuatOleh = req.DibuatOleh
+
+	if err := s.repo.CreatePesanan(po); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// UpdatePesanan replaces the supplier, lines and notes of a draft purchase order
+func (s *PembelianService) UpdatePesanan(id int64, req *models.PesananPembelianRequest) (*models.PesananPembelian, error) {
+	existing, err := s.GetPesananByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.StatusPODraft {
+		return nil, fmt.Errorf("hanya pesanan pembelian berstatus draft yang dapat diubah")
+	}
+
+	po, err := s.buildPesanan(req)
+	if err != nil {
+		return nil, err
+	}
+	po.ID = id
+
+	if err := s.repo.UpdatePesanan(po); err != nil {
+		return nil, err
+	}
+	return s.GetPesananByID(id)
+}
+
+// UbahStatusPesanan moves a purchase order along its lifecycle: a draft is sent to the
+// supplier (dipesan), an order without deliveries can be cancelled, and a partially
+// received order can be closed when the rest will not come.
+func (s *PembelianService) UbahStatusPesanan(id int64, status string) (*models.PesananPembelian, error) {
+	po, err := s.GetPesananByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var dari []string
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case models.StatusPODipesan:
+		if len(po.Items) == 0 {
+			return nil, fmt.Errorf("pesanan pembelian belum memiliki item")
+		}
+		status, dari = models.StatusPODipesan, []string{models.StatusPODraft}
+	case models.StatusPOBatal:
+		status, dari = models.StatusPOBatal, []string{models.StatusPODraft, models.StatusPODipesan}
+	case models.StatusPOSelesai:
+		status, dari = models.StatusPOSelesai, []string{models.StatusPOSebagian}
+	default:
+		return nil, fmt.Errorf("status pesanan pembelian tidak dapat diubah menjadi '%s'", status)
+	}
+
+	if err := s.repo.UpdateStatusPesanan(id, status, dari); err != nil {
+		return nil, err
+	}
+	return s.GetPesananByID(id)
+}
+
+// GetAllPenerimaan retrieves goods receipts within a date range, by default the last 30 days
+func (s *PembelianService) GetAllPenerimaan(startDate, endDate time.Time) ([]*models.PenerimaanBarang, error) {
+	if endDate.IsZero() {
+		endDate = time.Now()
+	}
+	if startDate.IsZero() {
+		startDate = endDate.AddDate(0, 0, -30)
+	}
+	return s.repo.GetAllPenerimaan(startDate, endDate)
+}
+
+// GetPenerimaanByID retrieves a goods receipt with its lines
+func (s *PembelianService) GetPenerimaanByID(id int64) (*models.PenerimaanBarang, error) {
+	penerimaan, err := s.repo.GetPenerimaanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if penerimaan == nil {
+		return nil, fmt.Errorf("penerimaan barang tidak ditemukan")
+	}
+	return penerimaan, nil
+}
+
+// TerimaBarang receives a (partial) delivery against a purchase order. Each line adds stock,
+// goes into an expiry batch at the real purchase cost and is written to the stock history.
+func (s *PembelianService) TerimaBarang(req *models.PenerimaanRequest, aktor models.Aktor) (*models.PenerimaanBarang, error) {
+	return s.terimaPesanan(req, aktor, false)
+}
+
+// terimaPesanan validates the received lines against the order; the cost defaults to the order price
+func (s *PembelianService) terimaPesanan(req *models.PenerimaanRequest, aktor models.Aktor, kosongkanKeranjang bool) (*models.PenerimaanBarang, error) {
+	po, err := s.GetPesananByID(req.PesananID)
+	if err != nil {
+		return nil, err
+	}
+	if po.Status != models.StatusPODipesan && po.Status != models.StatusPOSebagian {
+		return nil, fmt.Errorf("pesanan pembelian berstatus %s tidak dapat menerima barang", po.Status)
+	}
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("tidak ada barang yang diterima")
+	}
+
+	itemPesanan := make(map[int]*models.PesananPembelianItem, len(po.Items))
+	for _, item := range po.Items {
+		itemPesanan[item.ProdukID] = item
+	}
+
+	penerimaan := &models.PenerimaanBarang{
+		PesananID:    po.ID,
+		NomorPO:      po.NomorPO,
+		SupplierID:   po.SupplierID,
+		SupplierNama: po.SupplierNama,
+		NoFaktur:     strings.TrimSpace(req.NoFaktur),
+		Catatan:      strings.TrimSpace(req.Catatan),
+		DiterimaOleh: aktor.Nama,
+	}
+
+	diterima := make(map[int]bool, len(req.Items))
+	for _, line := range req.Items {
+		pesanan, ok := itemPesanan[line.ProdukID]
+		if !ok {
+			return nil, fmt.Errorf("produk ID %d tidak ada di pesanan %s", line.ProdukID, po.NomorPO)
+		}
+		if diterima[line.ProdukID] {
+			return nil, fmt.Errorf("%s tercantum lebih dari sekali", pesanan.ProdukNama)
+		}
+		diterima[line.ProdukID] = true
+
+		if line.Qty <= 0 {
+			return nil, fmt.Errorf("jumlah diterima untuk %s harus lebih dari 0", pesanan.ProdukNama)
+		}
+		if line.Qty > pesanan.Sisa()+0.0001 {
+			return nil, fmt.Errorf("jumlah diterima untuk %s (%g) melebihi sisa pesanan (%g)", pesanan.ProdukNama, line.Qty, pesanan.Sisa())
+		}
+		if line.HargaBeli < 0 {
+			return nil, fmt.Errorf("harga beli %s tidak boleh negatif", pesanan.ProdukNama)
+		}
+
+		hargaBeli := line.HargaBeli
+		if hargaBeli == 0 {
+			hargaBeli = pesanan.HargaBeli
+		}
+
+		masaSimpan, err := s.masaSimpanPenerimaan(line, pesanan.ProdukNama)
+		if err != nil {
+			return nil, err
+		}
+
+		item := &models.PenerimaanBarangItem{
+			PesananItemID:  pesanan.ID,
+			ProdukID:       line.ProdukID,
+			ProdukNama:     pesanan.ProdukNama,
+			Qty:            line.Qty,
+			HargaBeli:      hargaBeli,
+			Subtotal:       int(math.Round(line.Qty * float64(hargaBeli))),
+			MasaSimpanHari: masaSimpan,
+		}
+		penerimaan.Items = append(penerimaan.Items, item)
+		penerimaan.Total += item.Subtotal
+	}
+
+	if err := s.simpanPenerimaan(penerimaan, aktor, kosongkanKeranjang); err != nil {
+		return nil, err
+	}
+	return penerimaan, nil
+}
+
+// TerimaKeranjang turns the scanned cart into a goods receipt and empties the cart in the same
+// transaction. With a purchase order the cart is received against it; otherwise it is a free
+// receipt, optionally from a supplier, costed from the override or the price at scan time.
+func (s *PembelianService) TerimaKeranjang(req *models.ProsesKeranjangRequest, keranjang []*models.KeranjangItem, aktor models.Aktor) (*models.PenerimaanBarang, error) {
+	override := make(map[int]models.PenerimaanItemRequest, len(req.Items))
+	for _, item := range req.Items {
+		override[item.ProdukID] = item
+	}
+
+	if req.PesananID != 0 {
+		penerimaanReq := &models.PenerimaanRequest{
+			PesananID: req.PesananID,
+			NoFaktur:  req.NoFaktur,
+			Catatan:   req.Catatan,
+		}
+		for _, item := range keranjang {
+			line := override[item.Produk.ID]
+			line.ProdukID = item.Produk.ID
+			line.Qty = float64(item.Jumlah)
+			penerimaanReq.Items = append(penerimaanReq.Items, line)
+		}
+		return s.terimaPesanan(penerimaanReq, aktor, true)
+	}
+
+	penerimaan := &models.PenerimaanBarang{
+		NoFaktur:     strings.TrimSpace(req.NoFaktur),
+		Catatan:      strings.TrimSpace(req.Catatan),
+		DiterimaOleh: aktor.Nama,
+	}
+
+	if req.SupplierID != 0 {
+		supplier, err := s.supplierRepo.GetByID(req.SupplierID)
+		if err != nil {
+			return nil, err
+		}
+		if supplier == nil {
+			return nil, fmt.Errorf("supplier tidak ditemukan")
+		}
+		penerimaan.SupplierID = supplier.ID
+		penerimaan.SupplierNama = supplier.Nama
+	}
+
+	for _, item := range keranjang {
+		line := override[item.Produk.ID]
+		line.ProdukID = item.Produk.ID
+		if line.HargaBeli < 0 {
+			return nil, fmt.Errorf("harga beli %s tidak boleh negatif", item.Produk.Nama)
+		}
+
+		hargaBeli := line.HargaBeli
+		if hargaBeli == 0 {
+			hargaBeli = item.HargaBeli
+		}
+
+		masaSimpan, err := s.masaSimpanPenerimaan(line, item.Produk.Nama)
+		if err != nil {
+			return nil, err
+		}
+
+		qty := float64(item.Jumlah)
+		penerimaanItem := &models.PenerimaanBarangItem{
+			ProdukID:       item.Produk.ID,
+			ProdukNama:     item.Produk.Nama,
+			Qty:            qty,
+			HargaBeli:      hargaBeli,
+			Subtotal:       int(math.Round(qty * float64(hargaBeli))),
+			MasaSimpanHari: masaSimpan,
+		}
+		penerimaan.Items = append(penerimaan.Items, penerimaanItem)
+		penerimaan.Total += penerimaanItem.Subtotal
+	}
+
+	if err := s.simpanPenerimaan(penerimaan, aktor, true); err != nil {
+		return nil, err
+	}
+	return penerimaan, nil
+}
+
+// simpanPenerimaan saves a goods receipt with the configured cost method and audits every
+// product cost it changed
+func (s *PembelianService) simpanPenerimaan(penerimaan *models.PenerimaanBarang, aktor models.Aktor, kosongkanKeranjang bool) error {
+	settings, err := s.settingsRepo.GetPembelianSettings()
+	if err != nil {
+		return err
+	}
+
+	opsi := repository.OpsiPenerimaan{
+		MetodeHargaBeli:    settings.MetodeHargaBeli,
+		KosongkanKeranjang: kosongkanKeranjang,
+	}
+	if err := s.repo.CreatePenerimaan(penerimaan, opsi); err != nil {
+		return err
+	}
+
+	for _, perubahan := range penerimaan.PerubahanHarga {
+		s.auditService.Catat(aktor, models.AuditEntitasProduk, strconv.Itoa(perubahan.ProdukID), models.AuditAksiUpdate,
+			map[string]interface{}{"hargaBeli": perubahan.Sebelum},
+			map[string]interface{}{"hargaBeli": perubahan.Sesudah},
+			"Penerimaan "+penerimaan.NomorPenerimaan)
+	}
+	return nil
+}
+
+// masaSimpanPenerimaan returns the shelf life of a received line: from its expiry date,
+// else its explicit shelf life, else the product's default. 0 means no expiry batch.
+func (s *PembelianService) masaSimpanPenerimaan(line models.PenerimaanItemRequest, produkNama string) (int, error) {
+	if tanggal := strings.TrimSpace(line.TanggalKadaluarsa); tanggal != "" {
+		kadaluarsa, err := time.Parse("2006-01-02", tanggal)
+		if err != nil {
+			return 0, fmt.Errorf("tanggal kedaluwarsa %s tidak valid, gunakan format YYYY-MM-DD", produkNama)
+		}
+		// Hitung dari tanggal restok batch agar tanggal kedaluwarsa batch sama persis
+		hari := int(kadaluarsa.Sub(repository.TanggalRestokHariIni()).Hours() / 24)
+		if hari <= 0 {
+			return 0, fmt.Errorf("tanggal kedaluwarsa %s harus setelah hari ini", produkNama)
+		}
+		return hari, nil
+	}
+
+	if line.MasaSimpanHari < 0 {
+		return 0, fmt.Errorf("masa simpan %s tidak boleh negatif", produkNama)
+	}
+	if line.MasaSimpanHari > 0 {
+		return line.MasaSimpanHari, nil
+	}
+
+	produk, err := s.produkRepo.GetByID(line.ProdukID)
+	if err != nil {
+		return 0, err
+	}
+	if produk == nil {
+		return 0, fmt.Errorf("produk %s tidak ditemukan", produkNama)
+	}
+	return produk.MasaSimpanHari, nil
+}
+
+// buildPesanan validates a purchase order request and prices its lines
+func (s *PembelianService) buildPesanan(req *models.PesananPembelianRequest) (*models.PesananPembelian, error) {
+	supplier, err := s.supplierRepo.GetByID(req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, fmt.Errorf("supplier tidak ditemukan")
+	}
+	if !supplier.Aktif {
+		return nil, fmt.Errorf("supplier %s tidak aktif", supplier.Nama)
+	}
+
+	tanggalDiharapkan := strings.TrimSpace(req.TanggalDiharapkan)
+	if tanggalDiharapkan != "" {
+		if _, err := time.Parse("2006-01-02", tanggalDiharapkan); err != nil {
+			return nil, fmt.Errorf("tanggal diharapkan tidak valid, gunakan format YYYY-MM-DD")
+		}
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("pesanan pembelian harus memiliki minimal satu item")
+	}
+
+	po := &models.PesananPembelian{
+		SupplierID:        supplier.ID,
+		SupplierNama:      supplier.Nama,
+		TanggalDiharapkan: tanggalDiharapkan,
+		Catatan:           strings.TrimSpace(req.Catatan),
+	}
+
+	dipesan := make(map[int]bool, len(req.Items))
+	for _, line := range req.Items {
+		produk, err := s.produkRepo.GetByID(line.ProdukID)
+		if err != nil {
+			return nil, err
+		}
+		if produk == nil {
+			return nil, fmt.Errorf("produk ID %d tidak ditemukan", line.ProdukID)
+		}
+		if dipesan[produk.ID] {
+			return nil, fmt.Errorf("%s tercantum lebih dari sekali", produk.Nama)
+		}
+		dipesan[produk.ID] = true
+
+		if line.Qty <= 0 {
+			return nil, fmt.Errorf("jumlah pesanan %s harus lebih dari 0", produk.Nama)
+		}
+		if line.HargaBeli < 0 {
+			return nil, fmt.Errorf("harga beli %s tidak boleh negatif", produk.Nama)
+		}
+
+		hargaBeli := line.HargaBeli
+		if hargaBeli == 0 {
+			hargaBeli = produk.HargaBeli
+		}
+
+		item := &models.PesananPembelianItem{
+			ProdukID:   produk.ID,
+			ProdukNama: produk.Nama,
+			QtyPesan:   line.Qty,
+			HargaBeli:  hargaBeli,
+			Subtotal:   int(math.Round(line.Qty * float64(hargaBeli))),
+		}
+		po.Items = append(po.Items, item)
+		po.Total += item.Subtotal
+	}
+
+	return po, nil
+}
+
+func statusPOValid(status string) bool {
+	switch status {
+	case models.StatusPODraft, models.StatusPODipesan, models.StatusPOSebagian, models.StatusPOSelesai, models.StatusPOBatal:
+		return true
+	}
+	return false
+}
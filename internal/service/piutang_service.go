@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// PiutangService handles credit sales (tempo), repayments and the aging report
+type PiutangService struct {
+	repo          *repository.PiutangRepository
+	pelangganRepo *repository.PelangganRepository
+	metodeRepo    *repository.MetodePembayaranRepository
+}
+
+// NewPiutangService creates a new instance
+func NewPiutangService() *PiutangService {
+	return &PiutangService{
+		repo:          repository.NewPiutangRepository(),
+		pelangganRepo: repository.NewPelangganRepository(),
+		metodeRepo:    repository.NewMetodePembayaranRepository(),
+	}
+}
+
+// GetPiutang retrieves receivables, optionally filtered by customer and status
+func (s *PiutangService) GetPiutang(pelangganID int64, status string) ([]*models.Piutang, error) {
+	if status != "" && status != "belum_lunas" && status != "lunas" && status != "void" {
+		return nil, fmt.Errorf("status piutang tidak valid")
+	}
+	return s.repo.GetAll(pelangganID, status)
+}
+
+// BayarPiutang records a repayment from a customer
+func (s *PiutangService) BayarPiutang(req *models.BayarPiutangRequest) ([]*models.PembayaranPiutang, error) {
+	if req.PelangganID == 0 {
+		return nil, fmt.Errorf("pelanggan harus dipilih")
+	}
+	if req.Jumlah <= 0 {
+		return nil, fmt.Errorf("jumlah pembayaran harus lebih dari 0")
+	}
+
+	metode, err := s.metodeRepo.GetByKode(normalizeKodeMetode(req.Metode))
+	if err != nil {
+		return nil, err
+	}
+	if metode == nil || !metode.Aktif {
+		return nil, fmt.Errorf("metode pembayaran tidak valid")
+	}
+	if metode.Kode == models.MetodeTempo || metode.Kode == models.MetodeSaldo {
+		return nil, fmt.Errorf("piutang tidak dapat dilunasi dengan metode %s", metode.Nama)
+	}
+	req.Metode = metode.Kode
+	req.Referensi = strings.TrimSpace(req.Referensi)
+	if metode.PerluReferensi && !metode.IsTunai() && req.Referensi == "" {
+		return nil, fmt.Errorf("nomor referensi %s harus diisi", metode.Nama)
+	}
+
+	return s.repo.Bayar(req)
+}
+
+// GetPembayaranPiutang retrieves repayments received within a date range
+func (s *PiutangService) GetPembayaranPiutang(startDate, endDate time.Time) ([]*models.PembayaranPiutang, error) {
+	return s.repo.GetPembayaranByDateRange(startDate, endDate)
+}
+
+// GetLaporanUmurPiutang groups the outstanding receivables by age since the sale date (0-30/31-60/61-90/90+ days)
+func (s *PiutangService) GetLaporanUmurPiutang(tanggal time.Time) (*models.LaporanUmurPiutang, error) {
+	list, err := s.repo.GetAll(0, "belum_lunas")
+	if err != nil {
+		return nil, err
+	}
+
+	laporan := &models.LaporanUmurPiutang{
+		Tanggal:   tanggal,
+		Pelanggan: make([]*models.UmurPiutangPelanggan, 0),
+	}
+	perPelanggan := make(map[int64]*models.UmurPiutangPelanggan)
+
+	for _, p := range list {
+		row, ok := perPelanggan[p.PelangganID]
+		if !ok {
+			row = &models.UmurPiutangPelanggan{
+				PelangganID:   p.PelangganID,
+				PelangganNama: p.PelangganNama,
+			}
+			if pelanggan, err := s.pelangganRepo.GetByID(p.PelangganID); err == nil && pelanggan != nil {
+				row.Telepon = pelanggan.Telepon
+				row.LimitKredit = pelanggan.LimitKredit
+			}
+			perPelanggan[p.PelangganID] = row
+			laporan.Pelanggan = append(laporan.Pelanggan, row)
+		}
+
+		umurHari := int(tanggal.Sub(p.CreatedAt).Hours() / 24)
+		lewatTempo := tanggal.After(p.JatuhTempo)
+		tambahUmurPiutang(&row.UmurPiutang, p.Sisa, umurHari, lewatTempo)
+		tambahUmurPiutang(&laporan.Total, p.Sisa, umurHari, lewatTempo)
+	}
+
+	// Largest outstanding first
+	sort.Slice(laporan.Pelanggan, func(i, j int) bool {
+		return laporan.Pelanggan[i].Total > laporan.Pelanggan[j].Total
+	})
+
+	return laporan, nil
+}
+
+// tambahUmurPiutang adds an outstanding amount to its age bucket
+func tambahUmurPiutang(u *models.UmurPiutang, sisa int, umurHari int, lewatTempo bool) {
+	switch {
+	case umurHari <= 30:
+		u.Hari0Sampai30 += sisa
+	case umurHari <= 60:
+		u.Hari31Sampai60 += sisa
+	case umurHari <= 90:
+		u.Hari61Sampai90 += sisa
+	default:
+		u.HariLebih90 += sisa
+	}
+	u.Total += sisa
+	if lewatTempo {
+		u.LewatTempo += sisa
+	}
+}
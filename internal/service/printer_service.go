@@ -8,6 +8,8 @@ import (
 	"ritel-app/internal/models"
 	"ritel-app/internal/repository"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,12 +17,14 @@ import (
 type PrinterService struct {
 	repo             *repository.PrinterRepository
 	transaksiService *TransaksiService
+	sesiKasService   *SesiKasService
 }
 
 func NewPrinterService() *PrinterService {
 	return &PrinterService{
 		repo:             repository.NewPrinterRepository(),
 		transaksiService: NewTransaksiService(),
+		sesiKasService:   NewSesiKasService(),
 	}
 }
 
@@ -563,23 +567,9 @@ func (s *PrinterService) PrintReceipt(req *models.PrintReceiptRequest) (err erro
 		settings = s.repo.GetDefaultSettings()
 	}
 
-	// Use specified printer or default from settings
-	printerName := req.PrinterName
-	if printerName == "" {
-		printerName = settings.PrinterName
-	}
-
-	// If still no printer specified, try to auto-detect first available printer
-	if printerName == "" {
-		installedPrinters, err := s.GetInstalledPrinters()
-		if err != nil {
-			return fmt.Errorf("tidak ada printer yang dikonfigurasi. Silakan atur printer di menu Pengaturan > Pengaturan Struk")
-		}
-		if len(installedPrinters) == 0 {
-			return fmt.Errorf("tidak ada printer yang terdeteksi di sistem. Silakan install printer terlebih dahulu")
-		}
-		// Use first available printer
-		printerName = installedPrinters[0].Name
+	printerName, err := s.resolvePrinterName(req.PrinterName, settings)
+	if err != nil {
+		return err
 	}
 
 	// Get transaction data and generate receipt
@@ -622,6 +612,169 @@ func (s *PrinterService) PrintReceipt(req *models.PrintReceiptRequest) (err erro
 	return nil
 }
 
+// resolvePrinterName uses the requested printer, then the configured one, then the first installed printer
+func (s *PrinterService) resolvePrinterName(printerName string, settings *models.PrintSettings) (string, error) {
+	if printerName == "" {
+		printerName = settings.PrinterName
+	}
+
+	// If still no printer specified, try to auto-detect first available printer
+	if printerName == "" {
+		installedPrinters, err := s.GetInstalledPrinters()
+		if err != nil {
+			return "", fmt.Errorf("tidak ada printer yang dikonfigurasi. Silakan atur printer di menu Pengaturan > Pengaturan Struk")
+		}
+		if len(installedPrinters) == 0 {
+			return "", fmt.Errorf("tidak ada printer yang terdeteksi di sistem. Silakan install printer terlebih dahulu")
+		}
+		// Use first available printer
+		printerName = installedPrinters[0].Name
+	}
+
+	return printerName, nil
+}
+
+// PrintLaporanSesiKas prints the X-report or Z-report of a cash drawer session
+func (s *PrinterService) PrintLaporanSesiKas(req *models.PrintLaporanSesiKasRequest) error {
+	laporan, err := s.sesiKasService.GetLaporan(req.SesiID, req.Jenis)
+	if err != nil {
+		return err
+	}
+
+	settings, err := s.repo.GetPrintSettings()
+	if err != nil {
+		settings = s.repo.GetDefaultSettings()
+	}
+
+	printerName, err := s.resolvePrinterName(req.PrinterName, settings)
+	if err != nil {
+		return err
+	}
+
+	content := s.generateLaporanSesiKasContent(laporan, settings)
+
+	if runtime.GOOS == "windows" {
+		err = printRaw(printerName, content)
+	} else {
+		err = s.printContentFallback(printerName, content)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal mencetak ke printer '%s': %v", printerName, err)
+	}
+
+	return nil
+}
+
+// generateLaporanSesiKasContent creates X/Z report content with ESC/POS
+func (s *PrinterService) generateLaporanSesiKasContent(laporan *models.LaporanSesiKas, settings *models.PrintSettings) string {
+	var content string
+
+	content += initPrinter()
+	content += setCodePage(16) // Windows Latin-1
+
+	paperWidth := settings.PaperWidth
+	if paperWidth == 0 {
+		paperWidth = 40 // Default 40 untuk 58mm
+		if settings.PaperSize == "80mm" {
+			paperWidth = 48 // Default 48 untuk 80mm
+		}
+	}
+
+	effectiveWidth := paperWidth - settings.LeftMargin
+	if effectiveWidth < 10 {
+		effectiveWidth = 10 // Minimum width
+	}
+
+	dashLine := strings.Repeat(settings.DashLineChar, effectiveWidth)
+	sesi := laporan.Sesi
+
+	// === HEADER ===
+	content += setAlignment(ALIGN_CENTER)
+	content += setTextSize(1, 1)
+	content += settings.HeaderText + "\n"
+	content += "\n"
+
+	// === TITLE ===
+	content += setTextSize(2, 2)
+	content += "LAPORAN " + laporan.Jenis + "\n"
+	content += setTextSize(1, 1)
+	if laporan.Jenis == "X" {
+		content += "(Sementara - sesi masih buka)\n"
+	} else {
+		content += "(Final - sesi ditutup)\n"
+	}
+	content += "\n"
+
+	// === INFO SESI ===
+	var bodyContent string
+	bodyContent += setAlignment(ALIGN_LEFT)
+	bodyContent += dashLine + "\n"
+	bodyContent += formatLine("Sesi:", fmt.Sprintf("#%d", sesi.ID), effectiveWidth)
+	if sesi.TerminalID != "" {
+		bodyContent += formatLine("Terminal:", sesi.TerminalID, effectiveWidth)
+	}
+	bodyContent += formatLine("Kasir:", sesi.StaffNama, effectiveWidth)
+	bodyContent += formatLine("Dibuka:", formatTimeInWIB(sesi.DibukaAt), effectiveWidth)
+	if sesi.DitutupAt != nil {
+		bodyContent += formatLine("Ditutup:", formatTimeInWIB(*sesi.DitutupAt), effectiveWidth)
+	}
+	bodyContent += formatLine("Dicetak:", getTimeInWIB().Format("02/01/2006 15:04"), effectiveWidth)
+	bodyContent += dashLine + "\n"
+
+	// === RINGKASAN KAS ===
+	bodyContent += formatLine("Modal Awal:", formatRupiah(float64(sesi.ModalAwal)), effectiveWidth)
+	bodyContent += formatLine("Penjualan Tunai:", formatRupiah(float64(sesi.PenjualanTunai)), effectiveWidth)
+	if sesi.PelunasanTunai != 0 {
+		bodyContent += formatLine("Pelunasan Piutang:", formatRupiah(float64(sesi.PelunasanTunai)), effectiveWidth)
+	}
+	if sesi.TopUpTunai != 0 {
+		bodyContent += formatLine("Top Up Saldo:", formatRupiah(float64(sesi.TopUpTunai)), effectiveWidth)
+	}
+	if sesi.RefundTunai != 0 {
+		bodyContent += formatLine("Refund Retur:", "-"+formatRupiah(float64(sesi.RefundTunai)), effectiveWidth)
+	}
+	bodyContent += formatLine("Kas Masuk:", formatRupiah(float64(sesi.KasMasuk)), effectiveWidth)
+	bodyContent += formatLine("Kas Keluar:", "-"+formatRupiah(float64(sesi.KasKeluar)), effectiveWidth)
+	bodyContent += dashLine + "\n"
+	bodyContent += formatLine("Kas Diharapkan:", formatRupiah(float64(sesi.KasDiharapkan)), effectiveWidth)
+
+	// === MUTASI KAS ===
+	if len(laporan.Mutasi) > 0 {
+		bodyContent += dashLine + "\n"
+		bodyContent += "MUTASI KAS\n"
+		for _, m := range laporan.Mutasi {
+			jumlah := m.Jumlah
+			if m.Tipe == "keluar" {
+				jumlah = -jumlah
+			}
+			bodyContent += formatLine(m.Alasan, formatRupiahSigned(jumlah), effectiveWidth)
+		}
+	}
+
+	// === HITUNG TUTUP (Z) ===
+	if laporan.Jenis == "Z" {
+		bodyContent += dashLine + "\n"
+		bodyContent += "HITUNG KAS\n"
+		for _, p := range sesi.Pecahan {
+			bodyContent += formatLine(fmt.Sprintf("%s x %d", formatRupiah(float64(p.Nilai)), p.Jumlah), formatRupiah(float64(p.Nilai*p.Jumlah)), effectiveWidth)
+		}
+		bodyContent += dashLine + "\n"
+		bodyContent += formatLine("Kas Dihitung:", formatRupiah(float64(sesi.KasDihitung)), effectiveWidth)
+		bodyContent += formatLine("Selisih:", formatRupiahSigned(sesi.Selisih), effectiveWidth)
+		if sesi.CatatanTutup != "" {
+			bodyContent += "Catatan: " + sesi.CatatanTutup + "\n"
+		}
+	}
+	bodyContent += dashLine + "\n"
+
+	content += applyLeftMarginToSection(bodyContent, settings.LeftMargin)
+	content += "\n\n"
+
+	content += cutPaper()
+
+	return content
+}
+
 // generateReceiptContent creates receipt content from transaction data with ESC/POS
 func (s *PrinterService) generateReceiptContent(transaksi *models.TransaksiDetail, settings *models.PrintSettings) string {
 	var content string
@@ -716,6 +869,10 @@ func (s *PrinterService) generateReceiptContent(transaksi *models.TransaksiDetai
 	if transaksi.Transaksi.Diskon > 0 {
 		bodyContent += formatLine("Diskon:", formatRupiah(float64(transaksi.Transaksi.Diskon)), effectiveWidth)
 	}
+	// Tax added on top of the price is part of the total
+	if transaksi.Transaksi.ModePajak == "exclusive" && transaksi.Transaksi.TotalPajak > 0 {
+		bodyContent += formatPajakSummary(transaksi, "", effectiveWidth)
+	}
 
 	doubleLine := strings.Repeat(settings.DoubleLineChar, effectiveWidth)
 	bodyContent += doubleLine + "\n"
@@ -723,8 +880,24 @@ func (s *PrinterService) generateReceiptContent(transaksi *models.TransaksiDetai
 	bodyContent += formatLine("TOTAL:", formatRupiah(float64(transaksi.Transaksi.Total)), effectiveWidth)
 	bodyContent += setEmphasized(false)
 
+	// Cash rounding and rounding donation
+	if transaksi.Transaksi.Pembulatan != 0 || transaksi.Transaksi.Donasi > 0 {
+		if transaksi.Transaksi.Pembulatan != 0 {
+			bodyContent += formatLine("Pembulatan:", formatRupiahSigned(transaksi.Transaksi.Pembulatan), effectiveWidth)
+		}
+		if transaksi.Transaksi.Donasi > 0 {
+			bodyContent += formatLine("Donasi:", formatRupiah(float64(transaksi.Transaksi.Donasi)), effectiveWidth)
+		}
+		totalTagihan := transaksi.Transaksi.Total + transaksi.Transaksi.Pembulatan + transaksi.Transaksi.Donasi
+		bodyContent += formatLine("Total Bayar:", formatRupiah(float64(totalTagihan)), effectiveWidth)
+	}
+
 	bodyContent += formatLine("Dibayar:", formatRupiah(float64(transaksi.Transaksi.TotalBayar)), effectiveWidth)
 	bodyContent += formatLine("Kembalian:", formatRupiah(float64(transaksi.Transaksi.Kembalian)), effectiveWidth)
+	// Tax already included in the price is shown for information only
+	if transaksi.Transaksi.ModePajak == "inclusive" && transaksi.Transaksi.TotalPajak > 0 {
+		bodyContent += formatPajakSummary(transaksi, "Termasuk ", effectiveWidth)
+	}
 	bodyContent += "\n"
 
 	// Payment methods
@@ -947,6 +1120,45 @@ func formatRupiah(amount float64) string {
 	return "Rp " + string(result)
 }
 
+// formatPajakSummary prints the tax base (DPP) and tax per tax rate of a transaction
+func formatPajakSummary(transaksi *models.TransaksiDetail, prefix string, width int) string {
+	namaPajak := "PPN"
+	if settings, err := repository.NewSettingsRepository().GetPajakSettings(); err == nil && settings.NamaPajak != "" {
+		namaPajak = settings.NamaPajak
+	}
+
+	dppPerTarif := make(map[float64]int)
+	pajakPerTarif := make(map[float64]int)
+	var tarifs []float64
+	for _, item := range transaksi.Items {
+		if item.Pajak == 0 {
+			continue
+		}
+		if _, ok := pajakPerTarif[item.TarifPajak]; !ok {
+			tarifs = append(tarifs, item.TarifPajak)
+		}
+		dppPerTarif[item.TarifPajak] += item.DPP
+		pajakPerTarif[item.TarifPajak] += item.Pajak
+	}
+	sort.Float64s(tarifs)
+
+	content := ""
+	for _, tarif := range tarifs {
+		label := fmt.Sprintf("%s%s %s%%:", prefix, namaPajak, strconv.FormatFloat(tarif, 'f', -1, 64))
+		content += formatLine("DPP:", formatRupiah(float64(dppPerTarif[tarif])), width)
+		content += formatLine(label, formatRupiah(float64(pajakPerTarif[tarif])), width)
+	}
+	return content
+}
+
+// formatRupiahSigned formats an adjustment with an explicit sign, e.g. "-Rp 50" or "+Rp 50"
+func formatRupiahSigned(amount int) string {
+	if amount < 0 {
+		return "-" + formatRupiah(float64(-amount))
+	}
+	return "+" + formatRupiah(float64(amount))
+}
+
 // GetPrintSettings retrieves current print settings
 func (s *PrinterService) GetPrintSettings() (*models.PrintSettings, error) {
 	return s.repo.GetPrintSettings()
@@ -4,28 +4,35 @@ import (
 	"fmt"
 	"ritel-app/internal/models"
 	"ritel-app/internal/repository"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // ProdukService handles business logic for products
 type ProdukService struct {
-	produkRepo    *repository.ProdukRepository
-	keranjangRepo *repository.KeranjangRepository
-	batchService  *BatchService
+	produkRepo       *repository.ProdukRepository
+	keranjangRepo    *repository.KeranjangRepository
+	batchService     *BatchService
+	otorisasiService *OtorisasiService
+	auditService     *AuditService
+	pembelianService *PembelianService
 }
 
 // NewProdukService creates a new instance
 func NewProdukService() *ProdukService {
 	return &ProdukService{
-		produkRepo:    repository.NewProdukRepository(),
-		keranjangRepo: repository.NewKeranjangRepository(),
-		batchService:  NewBatchService(),
+		produkRepo:       repository.NewProdukRepository(),
+		keranjangRepo:    repository.NewKeranjangRepository(),
+		batchService:     NewBatchService(),
+		otorisasiService: NewOtorisasiService(),
+		auditService:     NewAuditService(),
+		pembelianService: NewPembelianService(),
 	}
 }
 
 // CreateProduk creates a new product with validation
-func (s *ProdukService) CreateProduk(produk *models.Produk) error {
+func (s *ProdukService) CreateProduk(produk *models.Produk, aktor models.Aktor) error {
 	// Validate required fields
 	if strings.TrimSpace(produk.SKU) == "" {
 		return fmt.Errorf("SKU is required")
@@ -36,6 +43,9 @@ func (s *ProdukService) CreateProduk(produk *models.Produk) error {
 	if produk.HargaJual <= 0 {
 		return fmt.Errorf("selling price must be greater than 0")
 	}
+	if err := ValidateTarifPajak(produk.TarifPajak); err != nil {
+		return err
+	}
 
 	// Validate that notification days does not exceed shelf life
 	if produk.HariPemberitahuanKadaluarsa > produk.MasaSimpanHari {
@@ -72,6 +82,8 @@ func (s *ProdukService) CreateProduk(produk *models.Produk) error {
 		return fmt.Errorf("failed to create product: %w", err)
 	}
 
+	s.auditService.Catat(aktor, models.AuditEntitasProduk, strconv.Itoa(produk.ID), models.AuditAksiCreate, nil, produk, "")
+
 	// AUTO-CREATE INITIAL BATCH if product has initial stock and shelf life
 	// This ensures batch warning works immediately for new products
 	if produk.Stok > 0 && produk.MasaSimpanHari > 0 {
@@ -151,32 +163,23 @@ func (s *ProdukService) GetKeranjang() ([]*models.KeranjangItem, error) {
 	return s.keranjangRepo.GetAll()
 }
 
-// ProcessKeranjang processes cart items and updates stock
-func (s *ProdukService) ProcessKeranjang() error {
+// ProcessKeranjang receives the scanned cart as a goods receipt: stock, expiry batches,
+// stock history and the cart clearing are saved together in one transaction.
+func (s *ProdukService) ProcessKeranjang(req *models.ProsesKeranjangRequest, aktor models.Aktor) (*models.PenerimaanBarang, error) {
 	// Get all cart items
 	items, err := s.keranjangRepo.GetAll()
 	if err != nil {
-		return fmt.Errorf("failed to get cart items: %w", err)
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
 	}
 
 	if len(items) == 0 {
-		return fmt.Errorf("cart is empty")
+		return nil, fmt.Errorf("cart is empty")
 	}
 
-	// Update stock for each item
-	for _, item := range items {
-		newStok := item.Produk.Stok + float64(item.Jumlah)
-		if err := s.produkRepo.UpdateStok(item.Produk.ID, newStok); err != nil {
-			return fmt.Errorf("failed to update stock for product %s: %w", item.Produk.Nama, err)
-		}
+	if req == nil {
+		req = &models.ProsesKeranjangRequest{}
 	}
-
-	// Clear cart
-	if err := s.keranjangRepo.Clear(); err != nil {
-		return fmt.Errorf("failed to clear cart: %w", err)
-	}
-
-	return nil
+	return s.pembelianService.TerimaKeranjang(req, items, aktor)
 }
 
 // ClearKeranjang clears the cart
@@ -198,7 +201,8 @@ func (s *ProdukService) UpdateKeranjangJumlah(id int, jumlah int) error {
 	return s.keranjangRepo.UpdateJumlah(id, jumlah)
 }
 
-func (s *ProdukService) UpdateProduk(produk *models.Produk) error {
+// UpdateProduk validates and saves a product, recording the changed fields in the audit log
+func (s *ProdukService) UpdateProduk(produk *models.Produk, aktor models.Aktor) error {
 
 	// Validate required fields
 	if strings.TrimSpace(produk.SKU) == "" {
@@ -210,6 +214,9 @@ func (s *ProdukService) UpdateProduk(produk *models.Produk) error {
 	if produk.HargaJual <= 0 {
 		return fmt.Errorf("selling price must be greater than 0")
 	}
+	if err := ValidateTarifPajak(produk.TarifPajak); err != nil {
+		return err
+	}
 
 	// Validate that notification days does not exceed shelf life
 	if produk.HariPemberitahuanKadaluarsa > produk.MasaSimpanHari {
@@ -263,10 +270,30 @@ func (s *ProdukService) UpdateProduk(produk *models.Produk) error {
 		}
 	}
 
+	// Diff against the stored row so fields the update does not touch are not reported as changed
+	updated, err := s.produkRepo.GetByID(produk.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get updated product: %w", err)
+	}
+	s.auditService.Catat(aktor, models.AuditEntitasProduk, strconv.Itoa(produk.ID), models.AuditAksiUpdate, existing, updated, "")
+
 	return nil
 }
 
-func (s *ProdukService) DeleteProduk(id int) error {
+// HargaBerubah reports whether an update changes the selling or purchase price of a product,
+// which needs the price.change permission on top of product.edit
+func (s *ProdukService) HargaBerubah(produk *models.Produk) (bool, error) {
+	existing, err := s.produkRepo.GetByID(produk.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing product: %w", err)
+	}
+	if existing == nil {
+		return false, nil
+	}
+	return existing.HargaJual != produk.HargaJual || existing.HargaBeli != produk.HargaBeli, nil
+}
+
+func (s *ProdukService) DeleteProduk(id int, aktor models.Aktor) error {
 	// Validate ID
 	if id <= 0 {
 		return fmt.Errorf("ID produk tidak valid")
@@ -289,11 +316,13 @@ func (s *ProdukService) DeleteProduk(id int) error {
 		return err
 	}
 
+	s.auditService.Catat(aktor, models.AuditEntitasProduk, strconv.Itoa(id), models.AuditAksiDelete, existing, nil, "")
+
 	return nil
 }
 
 // UpdateStok updates product stock with history tracking
-func (s *ProdukService) UpdateStok(req *models.UpdateStokRequest) error {
+func (s *ProdukService) UpdateStok(req *models.UpdateStokRequest, aktor models.Aktor) error {
 	// Validate
 	if req.StokBaru < 0 {
 		return fmt.Errorf("stock cannot be negative")
@@ -308,6 +337,20 @@ func (s *ProdukService) UpdateStok(req *models.UpdateStokRequest) error {
 		return fmt.Errorf("product not found")
 	}
 
+	// Manual adjustments need a supervisor override for this product when enabled in settings
+	disetujuiOleh := ""
+	otorisasiSettings, err := s.otorisasiService.GetSettings()
+	if err != nil {
+		return err
+	}
+	if otorisasiSettings.WajibPenyesuaianStok && req.StokBaru != currentProduk.Stok {
+		otorisasi, err := s.otorisasiService.Pakai(req.OverrideToken, models.AksiPenyesuaianStok, strconv.Itoa(req.ProdukID))
+		if err != nil {
+			return err
+		}
+		disetujuiOleh = otorisasi.SupervisorUsername
+	}
+
 	// Update stock
 	if err := s.produkRepo.UpdateStok(req.ProdukID, req.StokBaru); err != nil {
 		return fmt.Errorf("failed to update stock: %w", err)
@@ -343,6 +386,7 @@ func (s *ProdukService) UpdateStok(req *models.UpdateStokRequest) error {
 		Keterangan:     req.Keterangan,
 		TipeKerugian:   req.TipeKerugian,
 		NilaiKerugian:  req.NilaiKerugian,
+		DisetujuiOleh:  disetujuiOleh,
 		CreatedAt:      time.Now(),
 	}
 
@@ -350,11 +394,13 @@ func (s *ProdukService) UpdateStok(req *models.UpdateStokRequest) error {
 		// Log error but don't fail the update
 	}
 
+	s.catatStok(aktor, req, currentProduk.Stok, req.StokBaru)
+
 	return nil
 }
 
 // UpdateStokIncrement updates stock by increment/decrement
-func (s *ProdukService) UpdateStokIncrement(req *models.UpdateStokRequest) error {
+func (s *ProdukService) UpdateStokIncrement(req *models.UpdateStokRequest, aktor models.Aktor) error {
 	// Validate
 	if req.Perubahan == 0 {
 		return fmt.Errorf("perubahan stok tidak boleh 0")
@@ -415,9 +461,21 @@ func (s *ProdukService) UpdateStokIncrement(req *models.UpdateStokRequest) error
 	if err := s.produkRepo.CreateStokHistory(history); err != nil {
 	}
 
+	s.catatStok(aktor, req, currentProduk.Stok, newStock)
+
 	return nil
 }
 
+// catatStok records a manual stock change of a product in the audit log
+func (s *ProdukService) catatStok(aktor models.Aktor, req *models.UpdateStokRequest, sebelum, sesudah float64) {
+	keterangan := req.Jenis
+	if strings.TrimSpace(req.Keterangan) != "" {
+		keterangan += ": " + strings.TrimSpace(req.Keterangan)
+	}
+	s.auditService.Catat(aktor, models.AuditEntitasProduk, strconv.Itoa(req.ProdukID), models.AuditAksiUpdate,
+		map[string]float64{"stok": sebelum}, map[string]float64{"stok": sesudah}, keterangan)
+}
+
 // GetStokHistory retrieves stock change history for a product
 func (s *ProdukService) GetStokHistory(produkID int) ([]*models.StokHistory, error) {
 	return s.produkRepo.GetStokHistory(produkID)
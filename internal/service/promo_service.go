@@ -741,8 +741,16 @@ func (s *PromoService) CalculateDiscount(promo *models.Promo, subtotal int, item
 }
 
 func (s *PromoService) CalculateTotalDiscount(subtotal int, totalQuantity int, promoKode string, pelangganID int64, items []models.TransaksiItemRequest) (int, int, error) {
+	totalDiskon, promoDiskon, _, err := s.CalculateTotalDiscountWithBreakdown(subtotal, totalQuantity, promoKode, pelangganID, items)
+	return totalDiskon, promoDiskon, err
+}
+
+// CalculateTotalDiscountWithBreakdown works like CalculateTotalDiscount but also returns
+// how each promo discount is spread over the items in the request
+func (s *PromoService) CalculateTotalDiscountWithBreakdown(subtotal int, totalQuantity int, promoKode string, pelangganID int64, items []models.TransaksiItemRequest) (int, int, []models.ItemPromoAllocation, error) {
 	var promoDiskon int
 	var customerDiskon int
+	var breakdown []models.ItemPromoAllocation
 
 	// Apply promo discount if provided
 	if promoKode != "" {
@@ -771,14 +779,15 @@ func (s *PromoService) CalculateTotalDiscount(subtotal int, totalQuantity int, p
 			})
 
 			if err != nil {
-				return 0, 0, fmt.Errorf("gagal validasi promo '%s': %w", code, err)
+				return 0, 0, nil, fmt.Errorf("gagal validasi promo '%s': %w", code, err)
 			}
 
 			if promoResponse.Success {
 				promoDiskon += promoResponse.DiskonJumlah
+				breakdown = append(breakdown, s.allocatePromoDiscount(promoResponse.Promo, promoResponse.DiskonJumlah, items)...)
 			} else {
 				// Jika promo validation fails, return error
-				return 0, 0, fmt.Errorf("promo '%s' tidak valid: %s", code, promoResponse.Message)
+				return 0, 0, nil, fmt.Errorf("promo '%s' tidak valid: %s", code, promoResponse.Message)
 			}
 		}
 	}
@@ -788,7 +797,137 @@ func (s *PromoService) CalculateTotalDiscount(subtotal int, totalQuantity int, p
 	_ = customerDiskon // unused, keep for future if needed
 
 	totalDiskon := promoDiskon
-	return totalDiskon, promoDiskon, nil
+	return totalDiskon, promoDiskon, breakdown, nil
+}
+
+// allocatePromoDiscount membagi diskon satu promo ke item yang memenuhi syarat,
+// proporsional terhadap subtotal item. Sisa pembulatan dibebankan ke item terakhir.
+func (s *PromoService) allocatePromoDiscount(promo *models.Promo, diskon int, items []models.TransaksiItemRequest) []models.ItemPromoAllocation {
+	if promo == nil || diskon <= 0 || len(items) == 0 {
+		return nil
+	}
+
+	eligible := s.promoEligibleItems(promo, items)
+	if len(eligible) == 0 {
+		// Fallback: bebankan ke semua item agar total breakdown tetap sama dengan diskon
+		for i := range items {
+			eligible = append(eligible, i)
+		}
+	}
+
+	eligibleSubtotal := 0
+	for _, idx := range eligible {
+		eligibleSubtotal += itemRequestSubtotal(items[idx])
+	}
+
+	allocations := make([]models.ItemPromoAllocation, 0, len(eligible))
+	sisa := diskon
+	for n, idx := range eligible {
+		porsi := sisa
+		if n < len(eligible)-1 {
+			if eligibleSubtotal > 0 {
+				porsi = diskon * itemRequestSubtotal(items[idx]) / eligibleSubtotal
+			} else {
+				porsi = diskon / len(eligible)
+			}
+		}
+		sisa -= porsi
+
+		allocations = append(allocations, models.ItemPromoAllocation{
+			ItemIndex: idx,
+			ProdukID:  items[idx].ProdukID,
+			PromoID:   promo.ID,
+			PromoKode: promo.Kode,
+			PromoNama: promo.Nama,
+			TipePromo: promo.TipePromo,
+			Diskon:    porsi,
+		})
+	}
+
+	return allocations
+}
+
+// promoEligibleItems returns the indexes of items that the promo discount was calculated from
+func (s *PromoService) promoEligibleItems(promo *models.Promo, items []models.TransaksiItemRequest) []int {
+	var eligible []int
+
+	switch promo.TipePromo {
+	case "diskon_produk":
+		promoProducts, err := s.promoRepo.GetPromoProducts(promo.ID)
+		if err != nil {
+			return nil
+		}
+		promoProductIDs := make(map[int]bool)
+		for _, p := range promoProducts {
+			promoProductIDs[p.ID] = true
+		}
+
+		for i, item := range items {
+			produk, err := s.produkRepo.GetByID(item.ProdukID)
+			if err != nil || produk == nil {
+				continue
+			}
+			isCurah := produk.Satuan == "kg"
+
+			if len(promoProducts) > 0 {
+				if !promoProductIDs[item.ProdukID] {
+					continue
+				}
+			} else if !(promo.TipeProduk == "semua" ||
+				(promo.TipeProduk == "curah" && isCurah) ||
+				(promo.TipeProduk == "satuan" && !isCurah)) {
+				continue
+			}
+
+			// Sama dengan calculateProductDiscount: item di bawah minimum tidak ikut dihitung
+			if isCurah {
+				if promo.MinGramasi > 0 && item.BeratGram < float64(promo.MinGramasi) {
+					continue
+				}
+			} else if promo.MinQuantity > 0 && item.Jumlah < promo.MinQuantity {
+				continue
+			}
+			eligible = append(eligible, i)
+		}
+
+	case "bundling":
+		promoProducts, err := s.promoRepo.GetPromoProducts(promo.ID)
+		if err != nil {
+			return nil
+		}
+		promoProductIDs := make(map[int]bool)
+		for _, p := range promoProducts {
+			promoProductIDs[p.ID] = true
+		}
+		for i, item := range items {
+			if promoProductIDs[item.ProdukID] {
+				eligible = append(eligible, i)
+			}
+		}
+
+	case "buy_x_get_y":
+		// Diskon buy X get Y adalah nilai item gratis: produk X untuk tipe "sama", produk Y untuk "beda"
+		targetID := promo.ProdukXID
+		if promo.TipeBuyGet == "beda" {
+			targetID = promo.ProdukYID
+		}
+		for i, item := range items {
+			if item.ProdukID == targetID {
+				eligible = append(eligible, i)
+				break
+			}
+		}
+	}
+
+	return eligible
+}
+
+// itemRequestSubtotal menghitung subtotal satu item request (support berat or quantity)
+func itemRequestSubtotal(item models.TransaksiItemRequest) int {
+	if item.BeratGram > 0 {
+		return int((item.BeratGram / 1000.0) * float64(item.HargaSatuan))
+	}
+	return item.HargaSatuan * item.Jumlah
 }
 
 func (s *PromoService) calculateProductDiscount(promo *models.Promo, subtotal int, items []models.TransaksiItemRequest) int {
@@ -820,7 +959,7 @@ func (s *PromoService) calculateProductDiscount(promo *models.Promo, subtotal in
 				if produk.Satuan == "kg" {
 					// Untuk produk curah, cek MinGramasi
 					if promo.MinGramasi > 0 && float64(item.BeratGram) < float64(promo.MinGramasi) {
-						fmt.Printf("[DISCOUNT SKIP] Item %s excluded: Weight %.2fg < Min %.2fg\n",
+						fmt.Printf("[DISCOUNT SKIP] Item %s excluded: Weight %.2fg < Min %dg\n",
 							produk.Nama, item.BeratGram, promo.MinGramasi)
 						continue
 					}
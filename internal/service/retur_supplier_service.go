@@ -0,0 +1,224 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// ReturSupplierService handles returning expired or damaged goods to suppliers
+type ReturSupplierService struct {
+	repo         *repository.ReturSupplierRepository
+	supplierRepo *repository.SupplierRepository
+	produkRepo   *repository.ProdukRepository
+	batchRepo    *repository.BatchRepository
+}
+
+// NewReturSupplierService creates a new instance
+func NewReturSupplierService() *ReturSupplierService {
+	return &ReturSupplierService{
+		repo:         repository.NewReturSupplierRepository(),
+		supplierRepo: repository.NewSupplierRepository(),
+		produkRepo:   repository.NewProdukRepository(),
+		batchRepo:    repository.NewBatchRepository(),
+	}
+}
+
+// GetAllRetur retrieves supplier returns, optionally filtered by status
+func (s *ReturSupplierService) GetAllRetur(status string) ([]*models.ReturSupplier, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "", models.StatusReturSupplierMenunggu, models.StatusReturSupplierSelesai, models.StatusReturSupplierBatal:
+	default:
+		return nil, fmt.Errorf("status retur supplier '%s' tidak dikenal", status)
+	}
+	return s.repo.GetAllRetur(status)
+}
+
+// GetReturByID retrieves a supplier return with its lines
+func (s *ReturSupplierService) GetReturByID(id int64) (*models.ReturSupplier, error) {
+	retur, err := s.repo.GetReturByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if retur == nil {
+		return nil, fmt.Errorf("retur supplier tidak ditemukan")
+	}
+	return retur, nil
+}
+
+// CreateRetur takes the picked batches out of stock and records what the supplier owes.
+// Lines are valued at the batch cost, or the product cost for products without batches.
+func (s *ReturSupplierService) CreateRetur(req *models.ReturSupplierRequest, aktor models.Aktor) (*models.ReturSupplier, error) {
+	supplier, err := s.supplierRepo.GetByID(req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, fmt.Errorf("supplier tidak ditemukan")
+	}
+
+	alasan := strings.ToLower(strings.TrimSpace(req.Alasan))
+	if alasan != "kadaluarsa" && alasan != "rusak" {
+		return nil, fmt.Errorf("alasan retur harus 'kadaluarsa' atau 'rusak'")
+	}
+
+	jenisKompensasi := strings.ToLower(strings.TrimSpace(req.JenisKompensasi))
+	if jenisKompensasi == "" {
+		jenisKompensasi = models.KompensasiNotaKredit
+	}
+	if jenisKompensasi != models.KompensasiNotaKredit && jenisKompensasi != models.KompensasiGantiBarang {
+		return nil, fmt.Errorf("kompensasi yang diharapkan harus 'nota_kredit' atau 'ganti_barang'")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("retur supplier harus memiliki minimal satu item")
+	}
+
+	retur := &models.ReturSupplier{
+		SupplierID:      supplier.ID,
+		SupplierNama:    supplier.Nama,
+		Alasan:          alasan,
+		JenisKompensasi: jenisKompensasi,
+		Catatan:         strings.TrimSpace(req.Catatan),
+		DibuatOleh:      aktor.Nama,
+	}
+
+	dipilih := make(map[string]bool, len(req.Items))
+	for _, line := range req.Items {
+		item, err := s.buildItem(line)
+		if err != nil {
+			return nil, err
+		}
+
+		kunci := item.BatchID
+		if kunci == "" {
+			kunci = fmt.Sprintf("produk-%d", item.ProdukID)
+		}
+		if dipilih[kunci] {
+			return nil, fmt.Errorf("%s tercantum lebih dari sekali", item.ProdukNama)
+		}
+		dipilih[kunci] = true
+
+		retur.Items = append(retur.Items, item)
+		retur.TotalNilai += item.Subtotal
+	}
+
+	if err := s.repo.CreateRetur(retur); err != nil {
+		return nil, err
+	}
+	return retur, nil
+}
+
+// buildItem resolves a picked batch or product into a valued return line
+func (s *ReturSupplierService) buildItem(line models.ReturSupplierItemRequest) (*models.ReturSupplierItem, error) {
+	if line.Qty <= 0 {
+		return nil, fmt.Errorf("jumlah retur harus lebih dari 0")
+	}
+
+	item := &models.ReturSupplierItem{Qty: line.Qty}
+	produkID := line.ProdukID
+
+	if batchID := strings.TrimSpace(line.BatchID); batchID != "" {
+		batch, err := s.batchRepo.GetBatchByID(batchID)
+		if err != nil {
+			return nil, err
+		}
+		if batch == nil {
+			return nil, fmt.Errorf("batch %s tidak ditemukan", batchID)
+		}
+		if line.Qty > batch.QtyTersisa+0.0001 {
+			return nil, fmt.Errorf("jumlah retur (%g) melebihi sisa batch (%g)", line.Qty, batch.QtyTersisa)
+		}
+		item.BatchID = batch.ID
+		item.HargaBeli = batch.HargaBeli
+		item.TanggalKadaluarsa = batch.TanggalKadaluarsa.Format("2006-01-02")
+		produkID = batch.ProdukID
+	} else if produkID == 0 {
+		return nil, fmt.Errorf("pilih batch atau produk yang diretur")
+	}
+
+	produk, err := s.produkRepo.GetByID(produkID)
+	if err != nil {
+		return nil, err
+	}
+	if produk == nil {
+		return nil, fmt.Errorf("produk ID %d tidak ditemukan", produkID)
+	}
+	item.ProdukID = produk.ID
+	item.ProdukNama = produk.Nama
+	if item.HargaBeli == 0 {
+		item.HargaBeli = produk.HargaBeli
+	}
+	item.Subtotal = int(math.Round(item.Qty * float64(item.HargaBeli)))
+
+	return item, nil
+}
+
+// SelesaikanRetur records the credit note or replacement received for a waiting return. The
+// compensated value is taken off the loss of the return in the sales report.
+func (s *ReturSupplierService) SelesaikanRetur(id int64, req *models.PenyelesaianReturSupplierRequest, aktor models.Aktor) (*models.ReturSupplier, error) {
+	retur, err := s.GetReturByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if retur.Status != models.StatusReturSupplierMenunggu {
+		return nil, fmt.Errorf("retur supplier berstatus %s tidak dapat diselesaikan", retur.Status)
+	}
+
+	jenisKompensasi := strings.ToLower(strings.TrimSpace(req.JenisKompensasi))
+	if jenisKompensasi == "" {
+		jenisKompensasi = retur.JenisKompensasi
+	}
+
+	switch jenisKompensasi {
+	case models.KompensasiNotaKredit:
+		if req.NilaiKredit < 0 {
+			return nil, fmt.Errorf("nilai nota kredit tidak boleh negatif")
+		}
+		if req.NilaiKredit > retur.TotalNilai {
+			return nil, fmt.Errorf("nilai nota kredit melebihi nilai retur (%d)", retur.TotalNilai)
+		}
+		retur.NilaiKompensasi = req.NilaiKredit
+		if retur.NilaiKompensasi == 0 {
+			retur.NilaiKompensasi = retur.TotalNilai
+		}
+		retur.NoNotaKredit = strings.TrimSpace(req.NoNotaKredit)
+	case models.KompensasiGantiBarang:
+		retur.NilaiKompensasi = retur.TotalNilai
+	case models.KompensasiDitolak:
+		retur.NilaiKompensasi = 0
+	default:
+		return nil, fmt.Errorf("kompensasi '%s' tidak dikenal", req.JenisKompensasi)
+	}
+
+	retur.JenisKompensasi = jenisKompensasi
+	if catatan := strings.TrimSpace(req.Catatan); catatan != "" {
+		retur.Catatan = catatan
+	}
+	retur.DiselesaikanOleh = aktor.Nama
+
+	if err := s.repo.SelesaikanRetur(retur); err != nil {
+		return nil, err
+	}
+	return s.GetReturByID(id)
+}
+
+// BatalkanRetur cancels a waiting return and puts the goods back into their batches
+func (s *ReturSupplierService) BatalkanRetur(id int64, aktor models.Aktor) (*models.ReturSupplier, error) {
+	retur, err := s.GetReturByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if retur.Status != models.StatusReturSupplierMenunggu {
+		return nil, fmt.Errorf("retur supplier berstatus %s tidak dapat dibatalkan", retur.Status)
+	}
+
+	if err := s.repo.BatalkanRetur(retur, aktor.Nama); err != nil {
+		return nil, err
+	}
+	return s.GetReturByID(id)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"ritel-app/internal/models"
@@ -10,19 +11,23 @@ import (
 
 // ReturnService handles business logic for returns
 type ReturnService struct {
-	returnRepo    *repository.ReturnRepository
-	transaksiRepo *repository.TransaksiRepository
-	produkService *ProdukService
-	pelangganRepo *repository.PelangganRepository
+	returnRepo       *repository.ReturnRepository
+	transaksiRepo    *repository.TransaksiRepository
+	produkService    *ProdukService
+	pelangganRepo    *repository.PelangganRepository
+	saldoRepo        *repository.SaldoPelangganRepository
+	otorisasiService *OtorisasiService
 }
 
 // NewReturnService creates a new instance
 func NewReturnService() *ReturnService {
 	return &ReturnService{
-		returnRepo:    repository.NewReturnRepository(),
-		transaksiRepo: repository.NewTransaksiRepository(),
-		produkService: NewProdukService(),
-		pelangganRepo: repository.NewPelangganRepository(),
+		returnRepo:       repository.NewReturnRepository(),
+		transaksiRepo:    repository.NewTransaksiRepository(),
+		produkService:    NewProdukService(),
+		pelangganRepo:    repository.NewPelangganRepository(),
+		saldoRepo:        repository.NewSaldoPelangganRepository(),
+		otorisasiService: NewOtorisasiService(),
 	}
 }
 
@@ -64,6 +69,11 @@ func (s *ReturnService) CreateReturn(req *models.CreateReturnRequest) error {
 		return fmt.Errorf("transaction not found")
 	}
 
+	// Voided or fully returned sales have nothing left to return
+	if transaksi.Transaksi.Status != "selesai" && transaksi.Transaksi.Status != "partial_return" {
+		return fmt.Errorf("transaction with status '%s' cannot be returned", transaksi.Transaksi.Status)
+	}
+
 	// Set TransaksiID if not provided
 	if req.TransaksiID == 0 {
 		req.TransaksiID = transaksi.Transaksi.ID
@@ -72,6 +82,16 @@ func (s *ReturnService) CreateReturn(req *models.CreateReturnRequest) error {
 		req.NoTransaksi = transaksi.Transaksi.NomorTransaksi
 	}
 
+	// Store credit refunds go to the customer of the original transaction
+	if req.RefundMethod == models.MetodeSaldo {
+		if req.Type != "refund" {
+			return fmt.Errorf("refund to store credit is only available for refund returns")
+		}
+		if transaksi.Transaksi.PelangganID == 0 {
+			return fmt.Errorf("refund to store credit requires a registered customer on the transaction")
+		}
+	}
+
 	// Validate return window (max 30 days)
 	returnWindowDays := 30
 	transaksiDate := transaksi.Transaksi.Tanggal
@@ -139,6 +159,26 @@ func (s *ReturnService) CreateReturn(req *models.CreateReturnRequest) error {
 		return fmt.Errorf("failed to calculate refund amount: %w", err)
 	}
 
+	// Refunds above the configured limit need a supervisor override for this transaction
+	// The token is spent when the return is saved
+	disetujuiOleh := ""
+	var pemakaianOtorisasi *models.PemakaianOtorisasi
+	otorisasiSettings, err := s.otorisasiService.GetSettings()
+	if err != nil {
+		return err
+	}
+	if refundAmount > 0 && refundAmount > otorisasiSettings.BatasRefund {
+		otorisasi, pemakaian, err := s.otorisasiService.Cek(req.OverrideToken, models.AksiRetur, req.NoTransaksi)
+		if err != nil {
+			return fmt.Errorf("refund Rp %d melebihi batas Rp %d: %w", refundAmount, otorisasiSettings.BatasRefund, err)
+		}
+		disetujuiOleh = otorisasi.SupervisorUsername
+		pemakaianOtorisasi = pemakaian
+	}
+
+	// Tax reversed by this return, reported against the tax collected
+	pajakRetur := s.calculateReturnedTax(transaksi, req.Products)
+
 	// Parse return date
 	returnDate, err := time.Parse(time.RFC3339, req.ReturnDate)
 	if err != nil {
@@ -154,9 +194,13 @@ func (s *ReturnService) CreateReturn(req *models.CreateReturnRequest) error {
 		Type:                 req.Type,
 		ReplacementProductID: req.ReplacementProductID,
 		RefundAmount:         refundAmount,
+		TotalPajak:           pajakRetur,
 		RefundMethod:         req.RefundMethod,
 		RefundStatus:         "pending",
 		Notes:                req.Notes,
+		DisetujuiOleh:        disetujuiOleh,
+		StaffID:              req.StaffID,
+		Otorisasi:            pemakaianOtorisasi,
 	}
 
 	if err := s.returnRepo.Create(returnData); err != nil {
@@ -301,7 +345,7 @@ func (s *ReturnService) CreateReturn(req *models.CreateReturnRequest) error {
 			Jenis:      "exchange",
 			Keterangan: fmt.Sprintf("Tukar barang untuk return %s", req.NoTransaksi),
 		}
-		if err := s.produkService.UpdateStokIncrement(stockReq); err != nil {
+		if err := s.produkService.UpdateStokIncrement(stockReq, models.Aktor{ID: req.StaffID, Nama: req.StaffNama}); err != nil {
 			return fmt.Errorf("failed to deduct replacement product stock: %w", err)
 		}
 	}
@@ -320,6 +364,22 @@ func (s *ReturnService) CreateReturn(req *models.CreateReturnRequest) error {
 		}
 	}
 
+	// Credit the refund to the customer's balance
+	if req.RefundMethod == models.MetodeSaldo && refundAmount > 0 {
+		mutasi := &models.SaldoPelangganHistory{
+			PelangganID: transaksi.Transaksi.PelangganID,
+			Tipe:        "refund",
+			Jumlah:      refundAmount,
+			TransaksiID: transaksi.Transaksi.ID,
+			ReturnID:    returnData.ID,
+			Referensi:   req.NoTransaksi,
+			Keterangan:  fmt.Sprintf("Refund return transaksi %s", req.NoTransaksi),
+		}
+		if err := s.saldoRepo.Mutasi(mutasi); err != nil {
+			return fmt.Errorf("failed to credit customer balance: %w", err)
+		}
+	}
+
 	// Mark refund as completed if method is provided
 	if req.RefundMethod != "" {
 		if err := s.returnRepo.UpdateRefundStatus(returnData.ID, "completed"); err != nil {
@@ -369,6 +429,11 @@ func (s *ReturnService) CalculateRefundAmount(transaksi *models.TransaksiDetail,
 	// Do NOT apply discounts for refund - refund is based on cost price
 	// Customer gets back the modal/cost, not the discounted selling price
 
+	// Tax charged on top of the price (exclusive mode) is returned proportionally
+	if transaksi.Transaksi.ModePajak == "exclusive" {
+		refundAmount += s.calculateReturnedTax(transaksi, returnProducts)
+	}
+
 	// Ensure refund amount is not negative
 	if refundAmount < 0 {
 		refundAmount = 0
@@ -377,6 +442,26 @@ func (s *ReturnService) CalculateRefundAmount(transaksi *models.TransaksiDetail,
 	return refundAmount, nil
 }
 
+// calculateReturnedTax returns the share of each item's tax that belongs to the returned quantity
+func (s *ReturnService) calculateReturnedTax(transaksi *models.TransaksiDetail, returnProducts []models.ReturnProductRequest) int {
+	pajak := 0
+	for _, returnProduct := range returnProducts {
+		for _, item := range transaksi.Items {
+			if item.ProdukID != nil && *item.ProdukID == returnProduct.ProductID {
+				if item.Pajak > 0 && item.Jumlah > 0 {
+					qty := returnProduct.Quantity
+					if qty > item.Jumlah {
+						qty = item.Jumlah
+					}
+					pajak += int(math.Round(float64(item.Pajak) * float64(qty) / float64(item.Jumlah)))
+				}
+				break
+			}
+		}
+	}
+	return pajak
+}
+
 // calculateTransactionStatus determines the new status of the transaction after return
 func (s *ReturnService) calculateTransactionStatus(transaksi *models.TransaksiDetail, transaksiID int64) string {
 	// Get all returned items for this transaction
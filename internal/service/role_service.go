@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// RoleService manages configurable roles and their permissions
+type RoleService struct {
+	repo         *repository.RoleRepository
+	auditService *AuditService
+}
+
+// NewRoleService creates a new instance
+func NewRoleService() *RoleService {
+	return &RoleService{
+		repo:         repository.NewRoleRepository(),
+		auditService: NewAuditService(),
+	}
+}
+
+// EnsureDefaultRoles creates the built-in admin and staff roles when they are missing
+func (s *RoleService) EnsureDefaultRoles() error {
+	defaults := []*models.Role{
+		{Nama: "admin", Deskripsi: "Administrator, semua hak akses", Permissions: []string{}, Sistem: true},
+		{Nama: "staff", Deskripsi: "Kasir", Permissions: models.PermissionStaffDefault, Sistem: true},
+	}
+	for _, role := range defaults {
+		existing, err := s.repo.GetByNama(role.Nama)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			continue
+		}
+		if err := s.repo.Create(role); err != nil {
+			return fmt.Errorf("failed to create default role %s: %w", role.Nama, err)
+		}
+	}
+	return nil
+}
+
+// GetDaftarPermission returns every permission a role can be granted
+func (s *RoleService) GetDaftarPermission() []models.Permission {
+	return models.DaftarPermission
+}
+
+// GetAllRole returns all roles
+func (s *RoleService) GetAllRole() ([]*models.Role, error) {
+	roles, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		s.lengkapi(role)
+	}
+	return roles, nil
+}
+
+// GetPermissions returns the permissions of a role name; unknown roles have none
+func (s *RoleService) GetPermissions(nama string) ([]string, error) {
+	role, err := s.repo.GetByNama(nama)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return []string{}, nil
+	}
+	s.lengkapi(role)
+	return role.Permissions, nil
+}
+
+// RoleExists reports whether a role name can be assigned to a user
+func (s *RoleService) RoleExists(nama string) (bool, error) {
+	role, err := s.repo.GetByNama(nama)
+	if err != nil {
+		return false, err
+	}
+	return role != nil, nil
+}
+
+// CreateRole validates and saves a new role
+func (s *RoleService) CreateRole(role *models.Role, aktor models.Aktor) error {
+	role.Nama = strings.ToLower(strings.TrimSpace(role.Nama))
+	if role.Nama == "" {
+		return fmt.Errorf("nama role tidak boleh kosong")
+	}
+	existing, err := s.repo.GetByNama(role.Nama)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("role '%s' sudah ada", role.Nama)
+	}
+	if err := validatePermissions(role.Permissions); err != nil {
+		return err
+	}
+
+	role.Sistem = false
+	if err := s.repo.Create(role); err != nil {
+		return err
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasRole, strconv.FormatInt(role.ID, 10), models.AuditAksiCreate, nil, role, "")
+	return nil
+}
+
+// UpdateRole saves the description and permissions of a role
+func (s *RoleService) UpdateRole(role *models.Role, aktor models.Aktor) error {
+	existing, err := s.repo.GetByID(role.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("role tidak ditemukan")
+	}
+	if existing.Nama == "admin" {
+		return fmt.Errorf("role admin selalu memiliki semua hak akses dan tidak dapat diubah")
+	}
+	if err := validatePermissions(role.Permissions); err != nil {
+		return err
+	}
+
+	role.Nama = existing.Nama
+	role.Sistem = existing.Sistem
+	role.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(role); err != nil {
+		return err
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasRole, strconv.FormatInt(role.ID, 10), models.AuditAksiUpdate, existing, role, "")
+	return nil
+}
+
+// DeleteRole removes a custom role that no user has
+func (s *RoleService) DeleteRole(id int64, aktor models.Aktor) error {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("role tidak ditemukan")
+	}
+	if existing.Sistem {
+		return fmt.Errorf("role bawaan tidak dapat dihapus")
+	}
+
+	count, err := s.repo.CountUsers(existing.Nama)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("role masih dipakai oleh %d user", count)
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasRole, strconv.FormatInt(id, 10), models.AuditAksiDelete, existing, nil, "")
+	return nil
+}
+
+// lengkapi lists every permission on the admin role, which is granted everything implicitly
+func (s *RoleService) lengkapi(role *models.Role) {
+	if role.Nama != "admin" {
+		return
+	}
+	role.Permissions = make([]string, 0, len(models.DaftarPermission))
+	for _, p := range models.DaftarPermission {
+		role.Permissions = append(role.Permissions, p.Kode)
+	}
+}
+
+func validatePermissions(permissions []string) error {
+	for _, perm := range permissions {
+		valid := false
+		for _, p := range models.DaftarPermission {
+			if p.Kode == perm {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("permission '%s' tidak dikenal", perm)
+		}
+	}
+	return nil
+}
@@ -11,28 +11,36 @@ import (
 
 // SalesReportService handles comprehensive sales reporting
 type SalesReportService struct {
-	transaksiRepo *repository.TransaksiRepository
-	produkRepo    *repository.ProdukRepository
-	returnRepo    *repository.ReturnRepository
+	transaksiRepo     *repository.TransaksiRepository
+	produkRepo        *repository.ProdukRepository
+	returnRepo        *repository.ReturnRepository
+	returSupplierRepo *repository.ReturSupplierRepository
 }
 
 // NewSalesReportService creates a new sales report service
 func NewSalesReportService() *SalesReportService {
 	return &SalesReportService{
-		transaksiRepo: repository.NewTransaksiRepository(),
-		produkRepo:    repository.NewProdukRepository(),
-		returnRepo:    repository.NewReturnRepository(),
+		transaksiRepo:     repository.NewTransaksiRepository(),
+		produkRepo:        repository.NewProdukRepository(),
+		returnRepo:        repository.NewReturnRepository(),
+		returSupplierRepo: repository.NewReturSupplierRepository(),
 	}
 }
 
 // GetComprehensiveSalesReport generates a complete sales report for a date range
 func (s *SalesReportService) GetComprehensiveSalesReport(startDate, endDate time.Time) (*models.ComprehensiveSalesReport, error) {
 	// Get all transactions in the date range
-	transaksiList, err := s.transaksiRepo.GetByDateRange(startDate, endDate)
+	allTransaksi, err := s.transaksiRepo.GetByDateRange(startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
+	// Voided transactions are reported separately, not as sales
+	transaksiList, voidSummary := splitVoidTransactions(allTransaksi)
+
+	// Cash rounding and donations are totalled separately from omset
+	pembulatanSummary := summarizePembulatan(transaksiList)
+
 	// Get detailed transactions for current period
 	currentDetailedTransactions := make([]*models.TransaksiDetail, len(transaksiList))
 	for i, t := range transaksiList {
@@ -48,7 +56,7 @@ func (s *SalesReportService) GetComprehensiveSalesReport(startDate, endDate time
 	prevStartDate := startDate.Add(-duration)
 	prevEndDate := startDate.AddDate(0, 0, -1)
 
-	prevTransaksiList, err := s.transaksiRepo.GetByDateRange(prevStartDate, prevEndDate)
+	prevTransaksiList, err := s.transaksiRepo.GetSalesByDateRange(prevStartDate, prevEndDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get previous transactions: %w", err)
 	}
@@ -152,12 +160,82 @@ func (s *SalesReportService) GetComprehensiveSalesReport(startDate, endDate time
 		DiscountTypeBreakdown:  discountTypeBreakdown,
 		PaymentMethodBreakdown: paymentMethodBreakdown,
 		LossAnalysis:           lossAnalysis,
+		VoidSummary:            voidSummary,
+		PembulatanSummary:      pembulatanSummary,
 		StartDate:              startDate,
 		EndDate:                endDate,
 		GeneratedAt:            time.Now(),
 	}, nil
 }
 
+// GetLaporanPajak generates the output tax (PPN) report for a period, net of tax reversed by returns
+func (s *SalesReportService) GetLaporanPajak(startDate, endDate time.Time) (*models.LaporanPajak, error) {
+	perTarif, jumlahTransaksi, err := s.transaksiRepo.GetPajakByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	pajakRetur, err := s.returnRepo.GetTotalPajakByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	namaPajak := "PPN"
+	if settings, err := repository.NewSettingsRepository().GetPajakSettings(); err == nil && settings.NamaPajak != "" {
+		namaPajak = settings.NamaPajak
+	}
+
+	laporan := &models.LaporanPajak{
+		NamaPajak:       namaPajak,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		PerTarif:        perTarif,
+		JumlahTransaksi: jumlahTransaksi,
+		PajakRetur:      pajakRetur,
+		GeneratedAt:     time.Now(),
+	}
+	for _, row := range perTarif {
+		laporan.TotalDPP += row.DPP
+		laporan.TotalPajak += row.Pajak
+	}
+	laporan.PajakBersih = laporan.TotalPajak - laporan.PajakRetur
+
+	return laporan, nil
+}
+
+// splitVoidTransactions separates voided transactions from sales
+func splitVoidTransactions(transaksiList []*models.Transaksi) ([]*models.Transaksi, *models.VoidSummary) {
+	sales := make([]*models.Transaksi, 0, len(transaksiList))
+	summary := &models.VoidSummary{}
+
+	for _, t := range transaksiList {
+		if t.Status == "void" {
+			summary.TotalVoid += t.Total
+			summary.JumlahVoid++
+			continue
+		}
+		sales = append(sales, t)
+	}
+
+	return sales, summary
+}
+
+// summarizePembulatan totals cash rounding and rounding donations of the given sales
+func summarizePembulatan(transaksiList []*models.Transaksi) *models.PembulatanSummary {
+	summary := &models.PembulatanSummary{}
+
+	for _, t := range transaksiList {
+		if t.Pembulatan == 0 && t.Donasi == 0 {
+			continue
+		}
+		summary.TotalPembulatan += t.Pembulatan
+		summary.TotalDonasi += t.Donasi
+		summary.JumlahTransaksi++
+	}
+
+	return summary
+}
+
 // calculateSummary calculates overall sales summary with trends
 func (s *SalesReportService) calculateSummary(currentDetailed, previousDetailed []*models.TransaksiDetail, currentProductsSold, prevProductsSold int, currentReturnImpact, prevReturnImpact *models.ReturnImpact) *models.SalesSummaryResponse {
 	// Current period totals
@@ -786,6 +864,32 @@ func (s *SalesReportService) calculateLossAnalysis(startDate, endDate time.Time)
 		totalLoss += loss
 	}
 
+	// Goods returned to a supplier are a loss until the supplier compensates them. They are
+	// counted from the return documents, net of credit notes and replacements, so settling
+	// a return lowers the loss; cancelled returns are left out.
+	kerugianRetur, err := s.returSupplierRepo.GetKerugian(startDate, endDate)
+	if err != nil {
+		fmt.Printf("[ERROR] Failed to query supplier return losses: %v\n", err)
+	}
+	for _, k := range kerugianRetur {
+		if k.Nilai <= 0 {
+			continue
+		}
+		tipe := k.Alasan
+		if _, exists := typeLabels[tipe]; !exists {
+			tipe = "other"
+		}
+
+		item, exists := lossMap[tipe]
+		if !exists {
+			item = &models.LossBreakdownItem{Type: tipe, Label: typeLabels[tipe]}
+			lossMap[tipe] = item
+		}
+		item.TotalLoss += k.Nilai
+		item.Count += k.Jumlah
+		totalLoss += k.Nilai
+	}
+
 	// Automatic calculation of 'expired' status batches removed.
 	// Now we only count losses based on ACTUAL manual deletion (confirmed by user),
 	// which creates a 'stok_history' record with type 'kadaluarsa'.
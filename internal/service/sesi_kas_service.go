@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// SesiKasService handles cash drawer sessions: opening float, paid-in/out, closing count and X/Z reports
+type SesiKasService struct {
+	repo      *repository.SesiKasRepository
+	shiftRepo *repository.ShiftRepository
+}
+
+// NewSesiKasService creates a new instance
+func NewSesiKasService() *SesiKasService {
+	return &SesiKasService{
+		repo:      repository.NewSesiKasRepository(),
+		shiftRepo: repository.NewShiftRepository(),
+	}
+}
+
+// BukaSesi opens a drawer session; a staff member can only have one open session
+func (s *SesiKasService) BukaSesi(req *models.BukaSesiKasRequest) (*models.SesiKas, error) {
+	if req.StaffID == 0 {
+		return nil, fmt.Errorf("staff harus login untuk membuka sesi kas")
+	}
+	if req.ModalAwal < 0 {
+		return nil, fmt.Errorf("modal awal tidak boleh negatif")
+	}
+
+	aktif, err := s.repo.GetAktifByStaff(req.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	if aktif != nil {
+		return nil, fmt.Errorf("masih ada sesi kas yang terbuka sejak %s", formatTimeInWIB(aktif.DibukaAt))
+	}
+
+	// The session belongs to the chosen shift, or the shift running at opening time
+	var shift *models.ShiftSetting
+	if req.ShiftID != 0 {
+		shift, err = s.shiftRepo.GetByID(req.ShiftID)
+		if err != nil {
+			return nil, err
+		}
+		if shift == nil {
+			return nil, fmt.Errorf("shift tidak ditemukan")
+		}
+	} else {
+		shift, err = s.shiftRepo.FindByTime(time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sesi := &models.SesiKas{
+		TerminalID: strings.TrimSpace(req.TerminalID),
+		StaffID:    req.StaffID,
+		StaffNama:  req.StaffNama,
+		ModalAwal:  req.ModalAwal,
+		Pecahan:    []models.PecahanKas{},
+	}
+	if shift != nil {
+		sesi.ShiftID = shift.ID
+	}
+	if err := s.repo.Create(sesi); err != nil {
+		return nil, err
+	}
+
+	return sesi, nil
+}
+
+// CatatMutasi records a paid-in (masuk) or paid-out (keluar) entry with its reason
+func (s *SesiKasService) CatatMutasi(req *models.MutasiKasRequest) (*models.MutasiKas, error) {
+	if req.Tipe != "masuk" && req.Tipe != "keluar" {
+		return nil, fmt.Errorf("tipe kas harus 'masuk' atau 'keluar'")
+	}
+	if req.Jumlah <= 0 {
+		return nil, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	req.Alasan = strings.TrimSpace(req.Alasan)
+	if req.Alasan == "" {
+		return nil, fmt.Errorf("alasan kas %s harus diisi", req.Tipe)
+	}
+
+	sesi, err := s.sesiMilikStaff(req.SesiID, req.StaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Tipe == "keluar" {
+		if err := s.repo.HitungKas(sesi, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		if req.Jumlah > sesi.KasDiharapkan {
+			return nil, fmt.Errorf("kas keluar melebihi kas di laci (%s)", formatRupiah(float64(sesi.KasDiharapkan)))
+		}
+	}
+
+	mutasi := &models.MutasiKas{
+		SesiID:    sesi.ID,
+		Tipe:      req.Tipe,
+		Jumlah:    req.Jumlah,
+		Alasan:    req.Alasan,
+		StaffID:   req.StaffID,
+		StaffNama: req.StaffNama,
+	}
+	if err := s.repo.CreateMutasi(mutasi); err != nil {
+		return nil, err
+	}
+
+	return mutasi, nil
+}
+
+// TutupSesi closes a session with a denomination count and freezes expected cash, counted cash and variance
+func (s *SesiKasService) TutupSesi(req *models.TutupSesiKasRequest) (*models.LaporanSesiKas, error) {
+	sesi, err := s.sesiMilikStaff(req.SesiID, req.StaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	pecahan := make([]models.PecahanKas, 0, len(req.Pecahan))
+	dihitung := 0
+	for _, p := range req.Pecahan {
+		if p.Nilai <= 0 || p.Jumlah < 0 {
+			return nil, fmt.Errorf("pecahan kas tidak valid")
+		}
+		if p.Jumlah == 0 {
+			continue
+		}
+		pecahan = append(pecahan, p)
+		dihitung += p.Nilai * p.Jumlah
+	}
+
+	if err := s.repo.HitungKas(sesi, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	sesi.Pecahan = pecahan
+	sesi.KasDihitung = dihitung
+	sesi.Selisih = dihitung - sesi.KasDiharapkan
+	sesi.CatatanTutup = strings.TrimSpace(req.Catatan)
+	sesi.DitutupOleh = req.StaffNama
+
+	if err := s.repo.Tutup(sesi); err != nil {
+		return nil, err
+	}
+
+	return s.GetLaporan(sesi.ID, "Z")
+}
+
+// GetSesiAktif retrieves the open session of a staff member, or nil when none is open
+func (s *SesiKasService) GetSesiAktif(staffID int64) (*models.SesiKas, error) {
+	sesi, err := s.repo.GetAktifByStaff(staffID)
+	if err != nil || sesi == nil {
+		return sesi, err
+	}
+	if err := s.repo.HitungKas(sesi, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return sesi, nil
+}
+
+// GetAllSesi lists sessions, optionally per staff and status
+func (s *SesiKasService) GetAllSesi(staffID int64, status string, limit int) ([]*models.SesiKas, error) {
+	if status != "" && status != "buka" && status != "tutup" {
+		return nil, fmt.Errorf("status sesi kas tidak valid")
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.GetAll(staffID, status, limit)
+}
+
+// GetLaporan builds an X-report (running totals of an open session) or a Z-report (frozen totals of a closed session)
+func (s *SesiKasService) GetLaporan(sesiID int64, jenis string) (*models.LaporanSesiKas, error) {
+	jenis = strings.ToUpper(strings.TrimSpace(jenis))
+	if jenis != "X" && jenis != "Z" {
+		return nil, fmt.Errorf("jenis laporan harus X atau Z")
+	}
+
+	sesi, err := s.repo.GetByID(sesiID)
+	if err != nil {
+		return nil, err
+	}
+	if sesi == nil {
+		return nil, fmt.Errorf("sesi kas tidak ditemukan")
+	}
+
+	now := time.Now().UTC()
+	switch jenis {
+	case "X":
+		if sesi.Status != "buka" {
+			return nil, fmt.Errorf("laporan X hanya untuk sesi yang masih terbuka, gunakan laporan Z")
+		}
+		if err := s.repo.HitungKas(sesi, now); err != nil {
+			return nil, err
+		}
+	case "Z":
+		if sesi.Status != "tutup" {
+			return nil, fmt.Errorf("laporan Z hanya tersedia setelah sesi kas ditutup")
+		}
+	}
+
+	mutasi, err := s.repo.GetMutasi(sesi.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mutasi == nil {
+		mutasi = []*models.MutasiKas{}
+	}
+
+	return &models.LaporanSesiKas{
+		Jenis:    jenis,
+		Sesi:     sesi,
+		Mutasi:   mutasi,
+		DibuatAt: now,
+	}, nil
+}
+
+// sesiMilikStaff loads an open session and makes sure it belongs to the staff member
+func (s *SesiKasService) sesiMilikStaff(sesiID, staffID int64) (*models.SesiKas, error) {
+	sesi, err := s.repo.GetByID(sesiID)
+	if err != nil {
+		return nil, err
+	}
+	if sesi == nil {
+		return nil, fmt.Errorf("sesi kas tidak ditemukan")
+	}
+	if sesi.Status != "buka" {
+		return nil, fmt.Errorf("sesi kas sudah ditutup")
+	}
+	if staffID != 0 && sesi.StaffID != staffID {
+		return nil, fmt.Errorf("sesi kas milik staff lain")
+	}
+	return sesi, nil
+}
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"ritel-app/internal/models"
 	"ritel-app/internal/repository"
+	"strings"
 )
 
 type SettingsService struct {
 	settingsRepo *repository.SettingsRepository
+	auditService *AuditService
 }
 
 func NewSettingsService() *SettingsService {
 	return &SettingsService{
 		settingsRepo: repository.NewSettingsRepository(),
+		auditService: NewAuditService(),
 	}
 }
 
@@ -26,7 +29,7 @@ func (s *SettingsService) GetPoinSettings() (*models.PoinSettings, error) {
 }
 
 // UpdatePoinSettings updates point system settings dengan validasi lengkap
-func (s *SettingsService) UpdatePoinSettings(req *models.UpdatePoinSettingsRequest) (*models.PoinSettings, error) {
+func (s *SettingsService) UpdatePoinSettings(req *models.UpdatePoinSettingsRequest, aktor models.Aktor) (*models.PoinSettings, error) {
 	fmt.Printf("[SETTINGS SERVICE] Received Update Request: %+v\n", req)
 	// VALIDASI LENGKAP
 	if req.PointValue <= 0 {
@@ -57,11 +60,402 @@ func (s *SettingsService) UpdatePoinSettings(req *models.UpdatePoinSettingsReque
 		Level3MinSpending:       req.Level3MinSpending, // Legacy, tetap disimpan
 	}
 
+	sebelum, err := s.settingsRepo.GetPoinSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poin settings: %w", err)
+	}
+
 	// Update ke database
-	err := s.settingsRepo.UpdatePoinSettings(settings)
+	err = s.settingsRepo.UpdatePoinSettings(settings)
 	if err != nil {
 		return nil, fmt.Errorf("gagal update pengaturan poin: %w", err)
 	}
 
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "poin", models.AuditAksiUpdate, sebelum, settings, "")
+
+	return settings, nil
+}
+
+// GetPenomoranSettings retrieves the transaction numbering scheme of this terminal
+func (s *SettingsService) GetPenomoranSettings() (*models.PenomoranSettings, error) {
+	settings, err := s.settingsRepo.GetPenomoranSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penomoran settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdatePenomoranSettings validates and saves the transaction numbering scheme
+func (s *SettingsService) UpdatePenomoranSettings(req *models.PenomoranSettings, aktor models.Aktor) (*models.PenomoranSettings, error) {
+	settings := &models.PenomoranSettings{
+		KodeToko:      strings.ToUpper(strings.TrimSpace(req.KodeToko)),
+		KodeTerminal:  strings.ToUpper(strings.TrimSpace(req.KodeTerminal)),
+		Pemisah:       req.Pemisah,
+		FormatTanggal: req.FormatTanggal,
+		PanjangUrutan: req.PanjangUrutan,
+		ResetHarian:   req.ResetHarian,
+	}
+
+	if err := ValidateKodePenomoran(settings.KodeToko, "kode toko"); err != nil {
+		return nil, err
+	}
+	if err := ValidateKodePenomoran(settings.KodeTerminal, "kode terminal"); err != nil {
+		return nil, err
+	}
+	if settings.Pemisah != "-" && settings.Pemisah != "/" && settings.Pemisah != "." {
+		return nil, fmt.Errorf("pemisah harus '-', '/' atau '.'")
+	}
+	if settings.FormatTanggal != "20060102" && settings.FormatTanggal != "060102" && settings.FormatTanggal != "" {
+		return nil, fmt.Errorf("format tanggal tidak didukung")
+	}
+	if settings.PanjangUrutan < 3 || settings.PanjangUrutan > 10 {
+		return nil, fmt.Errorf("panjang nomor urut harus antara 3 dan 10 digit")
+	}
+
+	sebelum, err := s.settingsRepo.GetPenomoranSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penomoran settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdatePenomoranSettings(settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan penomoran: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "penomoran", models.AuditAksiUpdate, sebelum, settings, "")
+
+	return settings, nil
+}
+
+// GetPembulatanSettings retrieves the cash rounding policy
+func (s *SettingsService) GetPembulatanSettings() (*models.PembulatanSettings, error) {
+	settings, err := s.settingsRepo.GetPembulatanSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pembulatan settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdatePembulatanSettings validates and saves the cash rounding policy
+func (s *SettingsService) UpdatePembulatanSettings(req *models.PembulatanSettings, aktor models.Aktor) (*models.PembulatanSettings, error) {
+	settings := &models.PembulatanSettings{
+		Aktif:       req.Aktif,
+		Kelipatan:   req.Kelipatan,
+		Mode:        strings.ToLower(strings.TrimSpace(req.Mode)),
+		DonasiAktif: req.DonasiAktif,
+	}
+
+	if settings.Kelipatan <= 0 || settings.Kelipatan > 10000 {
+		return nil, fmt.Errorf("kelipatan pembulatan harus antara 1 dan 10000")
+	}
+	if settings.Mode != "nearest" && settings.Mode != "down" && settings.Mode != "up" {
+		return nil, fmt.Errorf("mode pembulatan harus 'nearest', 'down' atau 'up'")
+	}
+
+	sebelum, err := s.settingsRepo.GetPembulatanSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pembulatan settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdatePembulatanSettings(settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan pembulatan: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "pembulatan", models.AuditAksiUpdate, sebelum, settings, "")
+
+	return settings, nil
+}
+
+// GetPajakSettings retrieves the store tax (PPN) settings
+func (s *SettingsService) GetPajakSettings() (*models.PajakSettings, error) {
+	settings, err := s.settingsRepo.GetPajakSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pajak settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdatePajakSettings validates and saves the store tax (PPN) settings
+func (s *SettingsService) UpdatePajakSettings(req *models.PajakSettings, aktor models.Aktor) (*models.PajakSettings, error) {
+	settings := &models.PajakSettings{
+		Aktif:        req.Aktif,
+		NamaPajak:    strings.TrimSpace(req.NamaPajak),
+		TarifDefault: req.TarifDefault,
+		Mode:         strings.ToLower(strings.TrimSpace(req.Mode)),
+	}
+	if settings.NamaPajak == "" {
+		settings.NamaPajak = "PPN"
+	}
+
+	if err := ValidateTarifPajak(&settings.TarifDefault); err != nil {
+		return nil, err
+	}
+	if settings.Mode != "exclusive" && settings.Mode != "inclusive" {
+		return nil, fmt.Errorf("mode pajak harus 'exclusive' atau 'inclusive'")
+	}
+
+	sebelum, err := s.settingsRepo.GetPajakSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pajak settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdatePajakSettings(settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan pajak: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "pajak", models.AuditAksiUpdate, sebelum, settings, "")
+
+	return settings, nil
+}
+
+// GetLaciKasSettings retrieves the cash drawer settings of this terminal
+func (s *SettingsService) GetLaciKasSettings() (*models.LaciKasSettings, error) {
+	settings, err := s.settingsRepo.GetLaciKasSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get laci kas settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateLaciKasSettings validates and saves the cash drawer settings of this terminal
+func (s *SettingsService) UpdateLaciKasSettings(req *models.LaciKasSettings, aktor models.Aktor) (*models.LaciKasSettings, error) {
+	settings := &models.LaciKasSettings{
+		Aktif:       req.Aktif,
+		Driver:      strings.ToLower(strings.TrimSpace(req.Driver)),
+		PrinterName: strings.TrimSpace(req.PrinterName),
+		Port:        strings.TrimSpace(req.Port),
+		BaudRate:    req.BaudRate,
+		Pin:         req.Pin,
+	}
+	if settings.BaudRate == 0 {
+		settings.BaudRate = 9600
+	}
+
+	if settings.Driver != "printer" && settings.Driver != "serial" {
+		return nil, fmt.Errorf("driver laci kas harus 'printer' atau 'serial'")
+	}
+	if settings.Driver == "serial" && settings.Aktif && settings.Port == "" {
+		return nil, fmt.Errorf("port serial laci kas harus diisi")
+	}
+	if settings.BaudRate < 0 {
+		return nil, fmt.Errorf("baud rate tidak valid")
+	}
+	if settings.Pin != 0 && settings.Pin != 1 {
+		return nil, fmt.Errorf("pin laci kas harus 0 (pin 2) atau 1 (pin 5)")
+	}
+
+	sebelum, err := s.settingsRepo.GetLaciKasSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get laci kas settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdateLaciKasSettings(settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan laci kas: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "laci_kas", models.AuditAksiUpdate, sebelum, settings, "")
+
+	return settings, nil
+}
+
+// GetOtorisasiSettings retrieves the thresholds for supervisor overrides
+func (s *SettingsService) GetOtorisasiSettings() (*models.OtorisasiSettings, error) {
+	settings, err := s.settingsRepo.GetOtorisasiSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otorisasi settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateOtorisasiSettings validates and saves the thresholds for supervisor overrides
+func (s *SettingsService) UpdateOtorisasiSettings(req *models.OtorisasiSettings, aktor models.Aktor) (*models.OtorisasiSettings, error) {
+	settings := *req
+	if settings.MasaBerlakuDetik == 0 {
+		settings.MasaBerlakuDetik = 120
+	}
+
+	if settings.BatasDiskonManual < 0 {
+		return nil, fmt.Errorf("batas diskon manual tidak boleh negatif")
+	}
+	if settings.BatasRefund < 0 {
+		return nil, fmt.Errorf("batas refund tidak boleh negatif")
+	}
+	if settings.MasaBerlakuDetik < 30 || settings.MasaBerlakuDetik > 900 {
+		return nil, fmt.Errorf("masa berlaku token otorisasi harus 30-900 detik")
+	}
+
+	sebelum, err := s.settingsRepo.GetOtorisasiSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get otorisasi settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdateOtorisasiSettings(&settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan otorisasi: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "otorisasi", models.AuditAksiUpdate, sebelum, &settings, "")
+
+	return &settings, nil
+}
+
+// GetKeamananSettings retrieves the account and terminal lock settings
+func (s *SettingsService) GetKeamananSettings() (*models.KeamananSettings, error) {
+	settings, err := s.settingsRepo.GetKeamananSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keamanan settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateKeamananSettings validates and saves the account and terminal lock settings
+func (s *SettingsService) UpdateKeamananSettings(req *models.KeamananSettings, aktor models.Aktor) (*models.KeamananSettings, error) {
+	settings := *req
+
+	if settings.AutoLockMenit < 0 || settings.AutoLockMenit > 240 {
+		return nil, fmt.Errorf("auto lock harus 0-240 menit")
+	}
+	if settings.MaksGagalLogin < 3 || settings.MaksGagalLogin > 20 {
+		return nil, fmt.Errorf("maksimal percobaan login harus 3-20 kali")
+	}
+	if settings.DurasiKunciMenit < 1 || settings.DurasiKunciMenit > 1440 {
+		return nil, fmt.Errorf("durasi kunci akun harus 1-1440 menit")
+	}
+	if settings.DurasiKunciMaksMenit < settings.DurasiKunciMenit || settings.DurasiKunciMaksMenit > 10080 {
+		return nil, fmt.Errorf("durasi kunci maksimal harus antara durasi kunci dan 10080 menit")
+	}
+	if settings.PanjangMinPassword < 6 || settings.PanjangMinPassword > 64 {
+		return nil, fmt.Errorf("panjang minimal password harus 6-64 karakter")
+	}
+	if settings.RiwayatPassword < 0 || settings.RiwayatPassword > 24 {
+		return nil, fmt.Errorf("riwayat password harus 0-24")
+	}
+
+	sebelum, err := s.settingsRepo.GetKeamananSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keamanan settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdateKeamananSettings(&settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan keamanan: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "keamanan", models.AuditAksiUpdate, sebelum, &settings, "")
+
+	return &settings, nil
+}
+
+// GetPembelianSettings retrieves how product costs are updated on goods receipts
+func (s *SettingsService) GetPembelianSettings() (*models.PembelianSettings, error) {
+	settings, err := s.settingsRepo.GetPembelianSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pembelian settings: %w", err)
+	}
 	return settings, nil
 }
+
+// UpdatePembelianSettings validates and saves the product cost update method
+func (s *SettingsService) UpdatePembelianSettings(req *models.PembelianSettings, aktor models.Aktor) (*models.PembelianSettings, error) {
+	settings := *req
+	settings.MetodeHargaBeli = strings.ToLower(strings.TrimSpace(settings.MetodeHargaBeli))
+
+	switch settings.MetodeHargaBeli {
+	case models.MetodeHargaBeliTetap, models.MetodeHargaBeliTerakhir, models.MetodeHargaBeliRataRata:
+	default:
+		return nil, fmt.Errorf("metode harga beli harus 'tetap', 'terakhir' atau 'rata_rata'")
+	}
+
+	sebelum, err := s.settingsRepo.GetPembelianSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pembelian settings: %w", err)
+	}
+
+	if err := s.settingsRepo.UpdatePembelianSettings(&settings); err != nil {
+		return nil, fmt.Errorf("gagal update pengaturan pembelian: %w", err)
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasSettings, "pembelian", models.AuditAksiUpdate, sebelum, &settings, "")
+
+	return &settings, nil
+}
+
+// ValidateTarifPajak ensures a tax rate, when set, is a percentage between 0 and 100
+func ValidateTarifPajak(tarif *float64) error {
+	if tarif == nil {
+		return nil
+	}
+	if *tarif < 0 || *tarif > 100 {
+		return fmt.Errorf("tarif pajak harus antara 0 dan 100 persen")
+	}
+	return nil
+}
+
+// PreviewPembulatan returns the total the register collects when the part of total that is
+// not covered by nonTunai is paid in cash. With donasi the customer rounds up and the
+// difference is recorded as a donation. CreateTransaksi uses the same calculation.
+func (s *SettingsService) PreviewPembulatan(total, nonTunai int, donasi bool) (*models.PembulatanPreview, error) {
+	settings, err := s.GetPembulatanSettings()
+	if err != nil {
+		return nil, err
+	}
+	if donasi && !settings.DonasiAktif {
+		return nil, fmt.Errorf("donasi pembulatan tidak diaktifkan")
+	}
+
+	preview := &models.PembulatanPreview{
+		Total:       total,
+		NonTunai:    nonTunai,
+		DonasiAktif: settings.DonasiAktif && settings.Kelipatan > 1,
+	}
+	sisaTunai := total - nonTunai
+	if donasi {
+		if settings.Kelipatan > 1 && sisaTunai > 0 {
+			preview.Donasi = selisihPembulatan(sisaTunai, settings.Kelipatan, "up")
+		}
+	} else {
+		preview.Pembulatan = HitungPembulatan(sisaTunai, settings)
+	}
+	preview.TotalTagihan = total + preview.Pembulatan + preview.Donasi
+	return preview, nil
+}
+
+// HitungPembulatan returns the adjustment that rounds amount to a multiple of the configured increment.
+// A negative result means the amount is rounded down.
+func HitungPembulatan(amount int, settings *models.PembulatanSettings) int {
+	if settings == nil || !settings.Aktif || settings.Kelipatan <= 1 || amount <= 0 {
+		return 0
+	}
+	return selisihPembulatan(amount, settings.Kelipatan, settings.Mode)
+}
+
+func selisihPembulatan(amount, kelipatan int, mode string) int {
+	sisa := amount % kelipatan
+	if sisa == 0 {
+		return 0
+	}
+	switch mode {
+	case "down":
+		return -sisa
+	case "up":
+		return kelipatan - sisa
+	default:
+		if sisa*2 >= kelipatan {
+			return kelipatan - sisa
+		}
+		return -sisa
+	}
+}
+
+// ValidateKodePenomoran ensures a store/terminal code only contains letters and digits
+func ValidateKodePenomoran(kode, label string) error {
+	if kode == "" {
+		return fmt.Errorf("%s tidak boleh kosong", label)
+	}
+	if len(kode) > 10 {
+		return fmt.Errorf("%s maksimal 10 karakter", label)
+	}
+	for _, c := range kode {
+		if !(c >= 'A' && c <= 'Z') && !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') {
+			return fmt.Errorf("%s hanya boleh berisi huruf dan angka", label)
+		}
+	}
+	return nil
+}
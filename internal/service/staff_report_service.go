@@ -17,6 +17,10 @@ type StaffReportService struct {
 	produkRepo    *repository.ProdukRepository
 	returnRepo    *repository.ReturnRepository
 	shiftRepo     *repository.ShiftRepository
+	piutangRepo   *repository.PiutangRepository
+	sesiKasRepo   *repository.SesiKasRepository
+	absensiRepo   *repository.AbsensiRepository
+	targetService *TargetService
 }
 
 // NewStaffReportService creates a new staff report service
@@ -27,9 +31,63 @@ func NewStaffReportService() *StaffReportService {
 		produkRepo:    repository.NewProdukRepository(),
 		returnRepo:    repository.NewReturnRepository(),
 		shiftRepo:     repository.NewShiftRepository(),
+		piutangRepo:   repository.NewPiutangRepository(),
+		sesiKasRepo:   repository.NewSesiKasRepository(),
+		absensiRepo:   repository.NewAbsensiRepository(),
+		targetService: NewTargetService(),
 	}
 }
 
+// shiftResolver assigns transactions and cash sessions to a shift key ("shift1", "shift2").
+// Transactions linked to a cash session follow the session's shift, so a cashier who stays late
+// or works overlapping hours stays in their own shift; older transactions fall back to clock time.
+type shiftResolver struct {
+	service   *StaffReportService
+	shiftKeys map[int]string
+	sesiShift map[int64]string
+}
+
+func (s *StaffReportService) newShiftResolver() *shiftResolver {
+	r := &shiftResolver{
+		service:   s,
+		shiftKeys: make(map[int]string),
+		sesiShift: make(map[int64]string),
+	}
+	if shifts, err := s.shiftRepo.GetAll(); err == nil {
+		for _, shift := range shifts {
+			r.shiftKeys[shift.ID] = shift.Key()
+		}
+	}
+	return r
+}
+
+// sesi returns the shift of a cash session: its assigned shift, or the shift running when it was opened
+func (r *shiftResolver) sesi(sesi *models.SesiKas) string {
+	if key, ok := r.shiftKeys[sesi.ShiftID]; ok {
+		return key
+	}
+	return r.service.shiftRepo.DetermineShift(sesi.DibukaAt)
+}
+
+// transaksi returns the shift of a transaction
+func (r *shiftResolver) transaksi(t *models.Transaksi) string {
+	if t.ShiftSessionID == nil {
+		return r.service.shiftRepo.DetermineShift(t.Tanggal)
+	}
+	if key, ok := r.sesiShift[*t.ShiftSessionID]; ok {
+		return key
+	}
+
+	sesi, err := r.service.sesiKasRepo.GetByID(*t.ShiftSessionID)
+	if err != nil || sesi == nil {
+		// Session not synced to this device yet
+		return r.service.shiftRepo.DetermineShift(t.Tanggal)
+	}
+	key := r.sesi(sesi)
+	r.sesiShift[sesi.ID] = key
+	return key
+}
+
 // GetShiftSettings returns current shift configurations
 func (s *StaffReportService) GetShiftSettings() ([]models.ShiftSetting, error) {
 	return s.shiftRepo.GetAll()
@@ -64,9 +122,18 @@ func (s *StaffReportService) GetStaffReport(staffID int64, startDate, endDate ti
 	totalProfit := 0
 	totalDiskon := 0
 	totalItemTerjual := 0
+	totalVoid := 0
+	totalVoidCount := 0
 	productHPPCache := make(map[int]int) // Cache for product HPP
 
 	for _, t := range transaksiList {
+		// Voided sales are reported separately
+		if t.Status == "void" {
+			totalVoid += t.Total
+			totalVoidCount++
+			continue
+		}
+
 		// Only count completed transactions
 		if t.Status != "selesai" {
 			continue
@@ -109,6 +176,9 @@ func (s *StaffReportService) GetStaffReport(staffID int64, startDate, endDate ti
 		}
 	}
 
+	// Voided transactions are not counted as sales
+	totalTransaksi -= totalVoidCount
+
 	// Get comprehensive return impact for this staff
 	returnImpact, err := s.returnRepo.GetReturnImpactByStaffAndDateRange(staffID, startDate, endDate)
 	if err != nil {
@@ -146,6 +216,8 @@ func (s *StaffReportService) GetStaffReport(staffID int64, startDate, endDate ti
 		TotalItemTerjual: totalItemTerjual,               // Gross items sold
 		TotalRefund:      returnImpact.TotalSaleReturned, // Total refund (sale price)
 		TotalReturnCount: returnImpact.ReturnCount,       // Number of returns
+		TotalVoid:        totalVoid,                      // Voided sales (not in TotalPenjualan)
+		TotalVoidCount:   totalVoidCount,                 // Number of voided transactions
 		PeriodeMulai:     startDate,
 		PeriodeSelesai:   endDate,
 	}
@@ -280,6 +352,83 @@ func (s *StaffReportService) GetAllStaffReports(startDate, endDate time.Time) ([
 	return reports, nil
 }
 
+// GetLaporanAbsensi summarizes the attendance of a staff member for one month
+func (s *StaffReportService) GetLaporanAbsensi(staffID int64, tahun, bulan int) (*models.LaporanAbsensi, error) {
+	if bulan < 1 || bulan > 12 {
+		return nil, fmt.Errorf("bulan tidak valid")
+	}
+
+	staff, err := s.userRepo.GetByID(staffID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staff: %w", err)
+	}
+	if staff == nil {
+		return nil, fmt.Errorf("staff tidak ditemukan")
+	}
+
+	awal := time.Date(tahun, time.Month(bulan), 1, 0, 0, 0, 0, time.Local)
+	akhir := awal.AddDate(0, 1, -1)
+	list, err := s.absensiRepo.GetByStaffAndTanggal(staffID, awal.Format("2006-01-02"), akhir.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	laporan := &models.LaporanAbsensi{
+		StaffID:   staff.ID,
+		StaffNama: staff.NamaLengkap,
+		Tahun:     tahun,
+		Bulan:     bulan,
+		Absensi:   []*models.Absensi{},
+	}
+	hadir := make(map[string]bool)
+	for _, a := range list {
+		istirahat, err := s.absensiRepo.GetIstirahat(a.ID)
+		if err != nil {
+			return nil, err
+		}
+		if istirahat == nil {
+			istirahat = []*models.AbsensiIstirahat{}
+		}
+		a.Istirahat = istirahat
+		laporan.Absensi = append(laporan.Absensi, a)
+
+		hadir[a.Tanggal] = true
+		if a.TerlambatMenit > 0 {
+			laporan.JumlahTerlambat++
+			laporan.TotalTerlambatMenit += a.TerlambatMenit
+		}
+		if a.PulangCepatMenit > 0 {
+			laporan.JumlahPulangCepat++
+			laporan.TotalPulangCepatMenit += a.PulangCepatMenit
+		}
+		laporan.TotalLemburMenit += a.LemburMenit
+		laporan.TotalIstirahatMenit += a.IstirahatMenit
+		laporan.TotalKerjaMenit += a.KerjaMenit
+	}
+	laporan.HariHadir = len(hadir)
+
+	return laporan, nil
+}
+
+// GetAllLaporanAbsensi summarizes the monthly attendance of all staff
+func (s *StaffReportService) GetAllLaporanAbsensi(tahun, bulan int) ([]*models.LaporanAbsensi, error) {
+	staffList, err := s.userRepo.GetAllTransactionStaff()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staff list: %w", err)
+	}
+
+	var reports []*models.LaporanAbsensi
+	for _, staff := range staffList {
+		report, err := s.GetLaporanAbsensi(staff.ID, tahun, bulan)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
 // GetStaffReportWithTrend gets report with trend comparison vs previous period
 func (s *StaffReportService) GetStaffReportWithTrend(staffID int64, startDate, endDate time.Time) (*models.StaffReportWithTrend, error) {
 
@@ -330,9 +479,20 @@ func (s *StaffReportService) GetStaffReportWithTrend(staffID int64, startDate, e
 		TrendPenjualan: trendPenjualan,
 		TrendTransaksi: trendTransaksi,
 		PercentChange:  percentChange,
+		Target:         s.pencapaianStaff(currentReport, startDate, endDate),
 	}, nil
 }
 
+// pencapaianStaff computes the attainment of a staff report against the staff targets
+func (s *StaffReportService) pencapaianStaff(report *models.StaffReport, startDate, endDate time.Time) []*models.PencapaianTarget {
+	realisasi := realisasiTarget(float64(report.TotalPenjualan), report.TotalTransaksi, report.TotalItemTerjual)
+	pencapaian, err := s.targetService.Pencapaian("staff", 0, report.StaffID, realisasi, startDate, endDate)
+	if err != nil {
+		return []*models.PencapaianTarget{}
+	}
+	return pencapaian
+}
+
 // GetStaffHistoricalData gets historical data for charts
 func (s *StaffReportService) GetStaffHistoricalData(staffID int64) (*models.StaffHistoricalData, error) {
 	now := time.Now()
@@ -585,6 +745,7 @@ func (s *StaffReportService) GetStaffReportWithMonthlyTrend(staffID int64, start
 		TrendPenjualan: trendPenjualan,
 		TrendTransaksi: trendTransaksi,
 		PercentChange:  percentChange,
+		Target:         s.pencapaianStaff(currentReport, startDate, endDate),
 	}, nil
 }
 
@@ -819,10 +980,9 @@ func (s *StaffReportService) GetShiftReports(dateStr string) (*models.ShiftRepor
 		return nil, err
 	}
 
-	// Helper to categorize transaction into shift
-	getShift := func(t *models.Transaksi) string {
-		return s.shiftRepo.DetermineShift(t.Tanggal)
-	}
+	// Helper to categorize transaction into shift (by cash session, clock time for older data)
+	resolver := s.newShiftResolver()
+	getShift := resolver.transaksi
 
 	// Helper to categorize return into shift
 	getReturnShift := func(r *models.Return) string {
@@ -844,6 +1004,14 @@ func (s *StaffReportService) GetShiftReports(dateStr string) (*models.ShiftRepor
 
 		// Process Transactions (Sales)
 		for _, t := range trans {
+			if t.Status == "void" {
+				if shift := getShift(t); shift != "" {
+					stats[shift].TotalVoid += float64(t.Total)
+					stats[shift].TotalVoidCount++
+				}
+				continue
+			}
+
 			if t.Status != "selesai" {
 				continue
 			}
@@ -969,6 +1137,18 @@ func (s *StaffReportService) GetShiftReports(dateStr string) (*models.ShiftRepor
 	resp.Shift2.TrendDiskon = calculateTrend(todayStats["shift2"].TotalDiskon, yesterdayStats["shift2"].TotalDiskon)
 	resp.Shift2.TrendProduk = calculateTrend(float64(todayStats["shift2"].TotalItemTerjual), float64(yesterdayStats["shift2"].TotalItemTerjual))
 
+	// Attainment against the shift targets
+	for shiftID, key := range resolver.shiftKeys {
+		stats, ok := todayStats[key]
+		if !ok {
+			continue
+		}
+		realisasi := realisasiTarget(stats.TotalPenjualan, stats.TotalTransaksi, stats.TotalItemTerjual)
+		if pencapaian, err := s.targetService.Pencapaian("shift", shiftID, 0, realisasi, today, today); err == nil {
+			stats.Target = pencapaian
+		}
+	}
+
 	return resp, nil
 }
 
@@ -987,8 +1167,25 @@ func (s *StaffReportService) GetShiftCashiers(shift string) ([]*models.ShiftCash
 	processedStaff := make(map[int64]bool)
 	var cashiers []*models.ShiftCashier
 
+	// Staff who opened a cash session for this shift today
+	sessions, err := s.sesiKasRepo.GetByDibukaRange(today, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	resolver := s.newShiftResolver()
+	for _, sesi := range sessions {
+		if resolver.sesi(sesi) != shift || processedStaff[sesi.StaffID] {
+			continue
+		}
+		processedStaff[sesi.StaffID] = true
+		cashiers = append(cashiers, &models.ShiftCashier{
+			ID:   sesi.StaffID,
+			Nama: sesi.StaffNama,
+		})
+	}
+
 	for _, t := range trans {
-		currentShift := s.shiftRepo.DetermineShift(t.Tanggal)
+		currentShift := resolver.transaksi(t)
 
 		if currentShift == shift && t.StaffID != nil && *t.StaffID != 0 {
 			if !processedStaff[*t.StaffID] {
@@ -1078,10 +1275,11 @@ func (s *StaffReportService) GetShiftDetail(shift string, dateStr string) (*mode
 	}
 
 	response := &models.ShiftDetailResponse{
-		Transactions:     make([]models.ShiftTransaction, 0),
-		TopProducts:      make([]models.ShiftProduct, 0),
-		HourlyData:       make([]models.ShiftHourlyData, 0),
-		StaffPerformance: make([]models.ShiftStaffPerf, 0),
+		Transactions:       make([]models.ShiftTransaction, 0),
+		TopProducts:        make([]models.ShiftProduct, 0),
+		HourlyData:         make([]models.ShiftHourlyData, 0),
+		StaffPerformance:   make([]models.ShiftStaffPerf, 0),
+		ReceivablePayments: make([]models.ShiftReceivablePayment, 0),
 	}
 
 	productStats := make(map[string]*models.ShiftProduct)
@@ -1095,6 +1293,8 @@ func (s *StaffReportService) GetShiftDetail(shift string, dateStr string) (*mode
 
 	fmt.Printf("[DEBUG DETAIL] Processing %d transactions for shift %s...\n", len(trans), shift)
 
+	getShift := s.newShiftResolver().transaksi
+
 	for _, t := range trans {
 		// Debug Log
 		fmt.Printf("TRX %s: Status=%s, Time=%s\n", t.NomorTransaksi, t.Status, t.Tanggal.Format("15:04"))
@@ -1103,10 +1303,9 @@ func (s *StaffReportService) GetShiftDetail(shift string, dateStr string) (*mode
 			continue
 		}
 
-		// Determine Shift using dynamic helper
-		// Need to check specific transaction time against shift settings
-		// Use DetermineShift helper which handles settings and local time conversion
-		currentShift := s.shiftRepo.DetermineShift(t.Tanggal)
+		// Determine Shift from the transaction's cash session, falling back to
+		// DetermineShift (settings and local time conversion) for older data
+		currentShift := getShift(t)
 
 		fmt.Printf("TRX %s: Determined Shift=%s (Expected=%s)\n", t.NomorTransaksi, currentShift, shift)
 
@@ -1132,6 +1331,13 @@ func (s *StaffReportService) GetShiftDetail(shift string, dateStr string) (*mode
 
 		detail, err := s.transaksiRepo.GetByID(t.ID)
 		if err == nil && detail != nil {
+			// Bagian yang dibayar tempo tidak masuk laci kas
+			for _, p := range detail.Pembayaran {
+				if p.Metode == models.MetodeTempo {
+					response.CreditSales += float64(p.Jumlah)
+				}
+			}
+
 			for _, item := range detail.Items {
 				itemCount += item.Jumlah
 				productNames = append(productNames, fmt.Sprintf("%s (%dx)", item.ProdukNama, item.Jumlah))
@@ -1218,6 +1424,25 @@ func (s *StaffReportService) GetShiftDetail(shift string, dateStr string) (*mode
 		staffStats[staffName].ProductsSold += itemCount
 	}
 
+	// Pelunasan piutang adalah pergerakan kas tersendiri di shift saat diterima
+	repayments, err := s.piutangRepo.GetPembayaranByDateRange(startOfDay, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range repayments {
+		if s.shiftRepo.DetermineShift(p.CreatedAt) != shift {
+			continue
+		}
+		response.ReceivablePayments = append(response.ReceivablePayments, models.ShiftReceivablePayment{
+			NomorTransaksi: p.NomorTransaksi,
+			Time:           p.CreatedAt,
+			Cashier:        p.StaffNama,
+			Method:         p.Metode,
+			Amount:         float64(p.Jumlah),
+		})
+		response.ReceivablePaymentTotal += float64(p.Jumlah)
+	}
+
 	var products []models.ShiftProduct
 	for _, p := range productStats {
 		products = append(products, *p)
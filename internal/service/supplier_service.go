@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// SupplierService handles supplier master data
+type SupplierService struct {
+	repo *repository.SupplierRepository
+}
+
+// NewSupplierService creates a new instance
+func NewSupplierService() *SupplierService {
+	return &SupplierService{
+		repo: repository.NewSupplierRepository(),
+	}
+}
+
+// GetAllSupplier retrieves all suppliers, optionally only the active ones
+func (s *SupplierService) GetAllSupplier(aktifSaja bool) ([]*models.Supplier, error) {
+	return s.repo.GetAll(aktifSaja)
+}
+
+// GetSupplierByID retrieves a supplier by ID
+func (s *SupplierService) GetSupplierByID(id int64) (*models.Supplier, error) {
+	supplier, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, fmt.Errorf("supplier tidak ditemukan")
+	}
+	return supplier, nil
+}
+
+// CreateSupplier validates and saves a new supplier
+func (s *SupplierService) CreateSupplier(supplier *models.Supplier) error {
+	if err := s.validateSupplier(supplier); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByKode(supplier.Kode)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("kode supplier '%s' sudah dipakai", supplier.Kode)
+	}
+
+	return s.repo.Create(supplier)
+}
+
+// UpdateSupplier validates and saves changes to a supplier
+func (s *SupplierService) UpdateSupplier(supplier *models.Supplier) error {
+	if err := s.validateSupplier(supplier); err != nil {
+		return err
+	}
+
+	existing, err := s.GetSupplierByID(supplier.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing.Kode != supplier.Kode {
+		other, err := s.repo.GetByKode(supplier.Kode)
+		if err != nil {
+			return err
+		}
+		if other != nil {
+			return fmt.Errorf("kode supplier '%s' sudah dipakai", supplier.Kode)
+		}
+	}
+
+	supplier.CreatedAt = existing.CreatedAt
+	return s.repo.Update(supplier)
+}
+
+// DeleteSupplier removes a supplier that no purchase document refers to
+func (s *SupplierService) DeleteSupplier(id int64) error {
+	existing, err := s.GetSupplierByID(id)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.repo.CountUsage(id)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return fmt.Errorf("supplier '%s' sudah dipakai di %d dokumen pembelian, nonaktifkan saja", existing.Nama, used)
+	}
+
+	return s.repo.Delete(id)
+}
+
+func (s *SupplierService) validateSupplier(supplier *models.Supplier) error {
+	supplier.Kode = strings.ToUpper(strings.TrimSpace(supplier.Kode))
+	supplier.Nama = strings.TrimSpace(supplier.Nama)
+	supplier.Kontak = strings.TrimSpace(supplier.Kontak)
+	supplier.Telepon = strings.TrimSpace(supplier.Telepon)
+	supplier.Email = strings.TrimSpace(supplier.Email)
+	supplier.Alamat = strings.TrimSpace(supplier.Alamat)
+
+	if supplier.Kode == "" {
+		return fmt.Errorf("kode supplier tidak boleh kosong")
+	}
+	if len(supplier.Kode) > 20 {
+		return fmt.Errorf("kode supplier maksimal 20 karakter")
+	}
+	if supplier.Nama == "" {
+		return fmt.Errorf("nama supplier tidak boleh kosong")
+	}
+	if supplier.Email != "" && !strings.Contains(supplier.Email, "@") {
+		return fmt.Errorf("format email supplier tidak valid")
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// metrikTarget lists the target metrics in display order
+var metrikTarget = []string{"omzet", "transaksi", "item", "rata_rata_belanja"}
+
+// TargetService manages sales targets and computes attainment for the reports
+type TargetService struct {
+	repo      *repository.TargetRepository
+	shiftRepo *repository.ShiftRepository
+	userRepo  *repository.UserRepository
+}
+
+// NewTargetService creates a new instance
+func NewTargetService() *TargetService {
+	return &TargetService{
+		repo:      repository.NewTargetRepository(),
+		shiftRepo: repository.NewShiftRepository(),
+		userRepo:  repository.NewUserRepository(),
+	}
+}
+
+// GetAllTarget returns all targets, optionally of one scope
+func (s *TargetService) GetAllTarget(lingkup string) ([]*models.TargetPenjualan, error) {
+	return s.repo.GetAll(lingkup)
+}
+
+// CreateTarget validates and saves a new target
+func (s *TargetService) CreateTarget(t *models.TargetPenjualan) error {
+	if err := s.validateTarget(t); err != nil {
+		return err
+	}
+	return s.repo.Create(t)
+}
+
+// UpdateTarget validates and saves changes to a target
+func (s *TargetService) UpdateTarget(t *models.TargetPenjualan) error {
+	existing, err := s.repo.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("target tidak ditemukan")
+	}
+	if err := s.validateTarget(t); err != nil {
+		return err
+	}
+	t.CreatedAt = existing.CreatedAt
+	return s.repo.Update(t)
+}
+
+// DeleteTarget removes a target
+func (s *TargetService) DeleteTarget(id int64) error {
+	return s.repo.Delete(id)
+}
+
+// Pencapaian computes the attainment of a scope over [dari, sampai] from the realized values
+// per metric. Per metric, a target of the specific shift/staff wins over the generic one, and
+// a daily target wins over weekly and monthly ones.
+func (s *TargetService) Pencapaian(lingkup string, shiftID int, staffID int64, realisasi map[string]float64, dari, sampai time.Time) ([]*models.PencapaianTarget, error) {
+	targets, err := s.repo.GetAktif(lingkup, shiftID, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	awal := time.Date(dari.Year(), dari.Month(), dari.Day(), 0, 0, 0, 0, dari.Location())
+	akhir := time.Date(sampai.Year(), sampai.Month(), sampai.Day(), 0, 0, 0, 0, dari.Location())
+	hari := int(math.Round(akhir.Sub(awal).Hours()/24)) + 1
+	if hari < 1 {
+		hari = 1
+	}
+	hariSebulan := time.Date(awal.Year(), awal.Month()+1, 0, 0, 0, 0, 0, awal.Location()).Day()
+
+	hasil := []*models.PencapaianTarget{}
+	for _, metrik := range metrikTarget {
+		var terpilih *models.TargetPenjualan
+		for _, t := range targets {
+			if t.Metrik == metrik && lebihSpesifik(t, terpilih) {
+				terpilih = t
+			}
+		}
+		if terpilih == nil {
+			continue
+		}
+
+		nilai := terpilih.Nilai
+		if metrik != "rata_rata_belanja" {
+			switch terpilih.Periode {
+			case "harian":
+				nilai *= float64(hari)
+			case "mingguan":
+				nilai = nilai * float64(hari) / 7
+			case "bulanan":
+				nilai = nilai * float64(hari) / float64(hariSebulan)
+			}
+		}
+
+		p := &models.PencapaianTarget{
+			TargetID:  terpilih.ID,
+			Metrik:    metrik,
+			Periode:   terpilih.Periode,
+			Target:    math.Round(nilai),
+			Realisasi: realisasi[metrik],
+		}
+		if p.Target > 0 {
+			p.Persen = math.Round(p.Realisasi/p.Target*10000) / 100
+		}
+		hasil = append(hasil, p)
+	}
+
+	return hasil, nil
+}
+
+// realisasiTarget builds the realized values per metric from report totals
+func realisasiTarget(omzet float64, transaksi, item int) map[string]float64 {
+	realisasi := map[string]float64{
+		"omzet":     omzet,
+		"transaksi": float64(transaksi),
+		"item":      float64(item),
+	}
+	if transaksi > 0 {
+		realisasi["rata_rata_belanja"] = math.Round(omzet / float64(transaksi))
+	}
+	return realisasi
+}
+
+// lebihSpesifik reports whether t should be used instead of the current choice
+func lebihSpesifik(t, current *models.TargetPenjualan) bool {
+	if current == nil {
+		return true
+	}
+	tKhusus := t.ShiftID != 0 || t.StaffID != 0
+	currentKhusus := current.ShiftID != 0 || current.StaffID != 0
+	if tKhusus != currentKhusus {
+		return tKhusus
+	}
+	return urutanPeriode(t.Periode) < urutanPeriode(current.Periode)
+}
+
+func urutanPeriode(periode string) int {
+	switch periode {
+	case "harian":
+		return 0
+	case "mingguan":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (s *TargetService) validateTarget(t *models.TargetPenjualan) error {
+	switch t.Periode {
+	case "harian", "mingguan", "bulanan":
+	default:
+		return fmt.Errorf("periode target harus 'harian', 'mingguan' atau 'bulanan'")
+	}
+
+	valid := false
+	for _, m := range metrikTarget {
+		if t.Metrik == m {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("metrik target tidak valid")
+	}
+	if t.Nilai <= 0 {
+		return fmt.Errorf("nilai target harus lebih dari 0")
+	}
+
+	switch t.Lingkup {
+	case "toko":
+		t.ShiftID = 0
+		t.StaffID = 0
+	case "shift":
+		t.StaffID = 0
+		if t.ShiftID != 0 {
+			shift, err := s.shiftRepo.GetByID(t.ShiftID)
+			if err != nil {
+				return err
+			}
+			if shift == nil {
+				return fmt.Errorf("shift tidak ditemukan")
+			}
+		}
+	case "staff":
+		t.ShiftID = 0
+		if t.StaffID != 0 {
+			staff, err := s.userRepo.GetByID(t.StaffID)
+			if err != nil {
+				return err
+			}
+			if staff == nil {
+				return fmt.Errorf("staff tidak ditemukan")
+			}
+		}
+	default:
+		return fmt.Errorf("lingkup target harus 'toko', 'shift' atau 'staff'")
+	}
+
+	// One target per period, metric and scope
+	existing, err := s.repo.GetAll(t.Lingkup)
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.ID != t.ID && e.Periode == t.Periode && e.Metrik == t.Metrik && e.ShiftID == t.ShiftID && e.StaffID == t.StaffID {
+			return fmt.Errorf("target %s %s untuk lingkup ini sudah ada", t.Metrik, t.Periode)
+		}
+	}
+
+	return nil
+}
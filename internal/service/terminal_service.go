@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"ritel-app/internal/models"
+	"ritel-app/internal/repository"
+)
+
+// TerminalService tracks which user is active on each shared terminal.
+// State is kept in memory: a restart simply requires the next cashier to sign in again.
+type TerminalService struct {
+	mu           sync.Mutex
+	terminal     map[string]*models.StatusTerminal
+	settingsRepo *repository.SettingsRepository
+}
+
+// NewTerminalService creates a new instance
+func NewTerminalService() *TerminalService {
+	return &TerminalService{
+		terminal:     make(map[string]*models.StatusTerminal),
+		settingsRepo: repository.NewSettingsRepository(),
+	}
+}
+
+// KodeTerminal normalizes a terminal code, falling back to this device's code from penomoran settings
+func (s *TerminalService) KodeTerminal(terminalID string) (string, error) {
+	kode := strings.ToUpper(strings.TrimSpace(terminalID))
+	if kode == "" {
+		penomoran, err := s.settingsRepo.GetPenomoranSettings()
+		if err != nil {
+			return "", fmt.Errorf("failed to get penomoran settings: %w", err)
+		}
+		kode = penomoran.KodeTerminal
+	}
+	if err := ValidateKodePenomoran(kode, "kode terminal"); err != nil {
+		return "", err
+	}
+	return kode, nil
+}
+
+// Masuk makes user the active user on a terminal and unlocks it
+func (s *TerminalService) Masuk(terminalID string, user *models.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.terminal[terminalID] = &models.StatusTerminal{
+		TerminalID:        terminalID,
+		UserID:            user.ID,
+		Username:          user.Username,
+		NamaLengkap:       user.NamaLengkap,
+		AktivitasTerakhir: time.Now(),
+	}
+}
+
+// Kunci locks a terminal; the next user must sign in with password or PIN
+func (s *TerminalService) Kunci(terminalID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.terminal[terminalID]
+	if !ok {
+		return fmt.Errorf("terminal %s belum digunakan", terminalID)
+	}
+	status.Terkunci = true
+	return nil
+}
+
+// Status returns the lock screen state of a terminal, applying the auto-lock first
+func (s *TerminalService) Status(terminalID string) (*models.StatusTerminal, error) {
+	autoLock := s.autoLockMenit()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.terminal[terminalID]
+	if !ok {
+		return &models.StatusTerminal{TerminalID: terminalID, Terkunci: true, AutoLockMenit: autoLock}, nil
+	}
+	if menganggur(status, autoLock) {
+		status.Terkunci = true
+	}
+
+	result := *status
+	result.AutoLockMenit = autoLock
+	return &result, nil
+}
+
+// Sentuh records activity by userID on a terminal. It fails when the terminal is locked,
+// another user has taken over, or the terminal has been idle past the auto-lock limit.
+func (s *TerminalService) Sentuh(terminalID string, userID int64) error {
+	autoLock := s.autoLockMenit()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.terminal[terminalID]
+	if !ok {
+		// Token issued before a restart: adopt its user as the active one
+		s.terminal[terminalID] = &models.StatusTerminal{
+			TerminalID:        terminalID,
+			UserID:            userID,
+			AktivitasTerakhir: time.Now(),
+		}
+		return nil
+	}
+
+	if status.Terkunci {
+		return fmt.Errorf("terminal terkunci, silakan login kembali")
+	}
+	if status.UserID != userID {
+		return fmt.Errorf("user aktif di terminal ini sudah berganti")
+	}
+	if menganggur(status, autoLock) {
+		status.Terkunci = true
+		return fmt.Errorf("terminal terkunci otomatis karena tidak ada aktivitas selama %d menit", autoLock)
+	}
+
+	status.AktivitasTerakhir = time.Now()
+	return nil
+}
+
+func (s *TerminalService) autoLockMenit() int {
+	settings, err := s.settingsRepo.GetKeamananSettings()
+	if err != nil {
+		return repository.DefaultKeamananSettings().AutoLockMenit
+	}
+	return settings.AutoLockMenit
+}
+
+func menganggur(status *models.StatusTerminal, autoLockMenit int) bool {
+	if autoLockMenit <= 0 {
+		return false
+	}
+	return time.Since(status.AktivitasTerakhir) > time.Duration(autoLockMenit)*time.Minute
+}
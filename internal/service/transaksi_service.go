@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,13 @@ type TransaksiService struct {
 	pelangganService *PelangganService
 	promoService     *PromoService
 	settingsService  *SettingsService
+	userService      *UserService
+	pajakService     *PajakService
+	metodeService    *MetodePembayaranService
+	sesiKasRepo      *repository.SesiKasRepository
+	laciKasService   *LaciKasService
+	otorisasiService *OtorisasiService
+	produkRepo       *repository.ProdukRepository
 }
 
 func NewTransaksiService() *TransaksiService {
@@ -23,12 +31,30 @@ func NewTransaksiService() *TransaksiService {
 		pelangganService: NewPelangganService(),
 		promoService:     NewPromoService(),
 		settingsService:  NewSettingsService(),
+		userService:      NewUserService(),
+		pajakService:     NewPajakService(),
+		metodeService:    NewMetodePembayaranService(),
+		sesiKasRepo:      repository.NewSesiKasRepository(),
+		laciKasService:   NewLaciKasService(),
+		otorisasiService: NewOtorisasiService(),
+		produkRepo:       repository.NewProdukRepository(),
 	}
 }
 
 func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (*models.TransaksiResponse, error) {
 	fmt.Printf("[TRANSACTION SERVICE] Starting transaction creation\n")
 
+	// 0. IDEMPOTENCY: kunci yang sama tidak boleh membuat transaksi kedua
+	if req.IdempotencyKey != "" {
+		existing, err := s.repo.GetByIdempotencyKey(req.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return replayTransaksi(existing), nil
+		}
+	}
+
 	// 1. VALIDASI DASAR
 	if err := s.validateCreateRequest(req); err != nil {
 		return &models.TransaksiResponse{
@@ -37,117 +63,74 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 		}, nil
 	}
 
-	// 2. HITUNG SUBTOTAL (support berat or quantity)
-	subtotal := 0
-	for _, item := range req.Items {
-		var itemSubtotal int
-		if item.BeratGram > 0 {
-			// Perhitungan berdasarkan berat: (berat_gram / 1000) * harga_per_1000g
-			itemSubtotal = int((item.BeratGram / 1000.0) * float64(item.HargaSatuan))
-		} else {
-			// Perhitungan biasa untuk backward compatibility
-			itemSubtotal = item.HargaSatuan * item.Jumlah
-		}
-		subtotal += itemSubtotal
+	// 1a. CEK METODE PEMBAYARAN TERDAFTAR & AKTIF
+	metodeList, err := s.resolveMetodePembayaran(req.Pembayaran)
+	if err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
 	}
-	fmt.Printf("[TRANSACTION SERVICE] Subtotal: %d\n", subtotal)
-
-	// 3. PROSES PELANGGAN & POIN (JIKA ADA)
-	var pelanggan *models.Pelanggan
-	poinDipakai := 0
-	diskonPoin := 0
-
-	// DEBUG: Log pelangganID value
-	fmt.Printf("[TRANSACTION SERVICE] DEBUG - PelangganID received: %d\n", req.PelangganID)
 
-	// PENTING: Support offline ID (negative values) dari SQLite dual-mode
-	// Jadi check != 0, bukan > 0
-	if req.PelangganID != 0 {
-		fmt.Printf("[TRANSACTION SERVICE] Processing registered customer ID: %d\n", req.PelangganID)
-		var err error
-		pelanggan, err = s.pelangganService.GetPelangganByID(req.PelangganID)
-		if err != nil {
-			return &models.TransaksiResponse{
-				Success: false,
-				Message: fmt.Sprintf("Pelanggan tidak ditemukan: %v", err),
-			}, nil
-		}
-
-		// Auto-fill customer details
-		req.PelangganNama = pelanggan.Nama
-		req.PelangganTelp = pelanggan.Telepon
-		fmt.Printf("[TRANSACTION SERVICE] Customer: %s, Current Points: %d\n", pelanggan.Nama, pelanggan.Poin)
-
-		// 3a. PROSES PENUKARAN POIN (JIKA ADA)
-		if req.PoinDitukar > 0 {
-			fmt.Printf("[TRANSACTION SERVICE] Processing point redemption request: %d points\n", req.PoinDitukar)
-
-			// Get point settings
-			settings, err := s.settingsService.GetPoinSettings()
-			if err != nil {
-				return &models.TransaksiResponse{
-					Success: false,
-					Message: fmt.Sprintf("Gagal mengambil pengaturan sistem poin: %v", err),
-				}, nil
-			}
-
-			// VALIDASI DAN PENYESUAIAN POIN OTOMATIS
-			poinDipakai, diskonPoin = s.CalculatePointsDiscount(
-				subtotal,
-				req.PoinDitukar,
-				pelanggan.Poin,
-				settings.PointValue,
-			)
-
-			fmt.Printf("[TRANSACTION SERVICE] Point adjustment - Requested: %d, Actual: %d, Discount: %d\n",
-				req.PoinDitukar, poinDipakai, diskonPoin)
-
-			// Validasi minimum exchange
-			if poinDipakai > 0 && poinDipakai < settings.MinExchange {
-				return &models.TransaksiResponse{
-					Success: false,
-					Message: fmt.Sprintf("Poin yang dapat ditukarkan (%d) kurang dari minimum (%d). Poin tersedia: %d",
-						poinDipakai, settings.MinExchange, pelanggan.Poin),
-				}, nil
-			}
-		}
-	} else {
-		// Guest transaction - tidak boleh pakai poin
-		req.PelangganNama = ""
-		req.PelangganTelp = ""
-		if req.PoinDitukar > 0 {
-			return &models.TransaksiResponse{
-				Success: false,
-				Message: "Penukaran poin hanya tersedia untuk pelanggan terdaftar",
-			}, nil
-		}
-		fmt.Printf("[TRANSACTION SERVICE] Guest transaction (no customer)\n")
+	// 2-5. HITUNG SUBTOTAL, DISKON, PAJAK & TOTAL AKHIR DI SERVER
+	hitung, err := s.hitungTransaksi(req)
+	if err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	subtotal := hitung.subtotal
+	diskonPromo := hitung.diskonPromo
+	promoBreakdown := hitung.promoBreakdown
+	pelanggan := hitung.pelanggan
+	poinDipakai := hitung.poinDipakai
+	diskonPoin := hitung.diskonPoin
+	totalDiskon := hitung.totalDiskon
+	diskonPelanggan := 0 // Tidak ada diskon level, hanya dari poin
+	totalPajak := hitung.totalPajak
+	modePajak := hitung.modePajak
+	totalAkhir := hitung.totalAkhir
+
+	// Tolak jika perhitungan client berbeda dengan server
+	if req.Diskon != totalDiskon {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: fmt.Sprintf("Diskon tidak sesuai. Dikirim: Rp %d, seharusnya: Rp %d (promo Rp %d + poin Rp %d + manual Rp %d)",
+				req.Diskon, totalDiskon, diskonPromo, diskonPoin, req.DiskonManual),
+		}, nil
+	}
+	if req.Total != totalAkhir {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: fmt.Sprintf("Total tidak sesuai. Dikirim: Rp %d, seharusnya: Rp %d (subtotal Rp %d, diskon Rp %d, PPN Rp %d %s)",
+				req.Total, totalAkhir, subtotal, totalDiskon, totalPajak, modePajak),
+		}, nil
 	}
 
-	// 4. HITUNG TOTAL DISKON (PROMO + POIN)
-	// Diskon hanya dari promo dan poin, tidak ada diskon berdasarkan level pelanggan
-	totalDiskon := req.Diskon // Sudah include promo + poin dari frontend
-	diskonPelanggan := 0      // Tidak ada diskon level, hanya dari poin
-
-	fmt.Printf("[TRANSACTION SERVICE] Total discount: %d (promo + points)\n", totalDiskon)
-
-	// 5. HITUNG TOTAL AKHIR & VALIDASI
-	totalAkhir := subtotal - totalDiskon
-
-	// Validasi total tidak negatif (seharusnya sudah di-handle oleh CalculatePointsDiscount)
-	if totalAkhir < 0 {
+	// 5a. PEMBULATAN TUNAI & DONASI
+	pembulatan, donasi, err := s.hitungPembulatanTunai(totalAkhir, req, metodeList)
+	if err != nil {
 		return &models.TransaksiResponse{
 			Success: false,
-			Message: "Total transaksi tidak valid setelah diskon poin",
+			Message: err.Error(),
 		}, nil
 	}
+	if pembulatan != 0 || donasi != 0 {
+		fmt.Printf("[TRANSACTION SERVICE] Cash rounding: %d, donation: %d\n", pembulatan, donasi)
+	}
 
-	// Validasi pembayaran
+	// Validasi pembayaran (split tender: kembalian hanya boleh dari tunai)
 	totalPembayaran := 0
-	for _, payment := range req.Pembayaran {
+	totalNonTunai := 0
+	for i, payment := range req.Pembayaran {
 		totalPembayaran += payment.Jumlah
+		if !metodeList[i].IsTunai() {
+			totalNonTunai += payment.Jumlah
+		}
 	}
-	kembalian := totalPembayaran - totalAkhir
+	totalTagihan := totalAkhir + pembulatan + donasi
+	kembalian := totalPembayaran - totalTagihan
 
 	if kembalian < 0 {
 		return &models.TransaksiResponse{
@@ -155,6 +138,45 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 			Message: fmt.Sprintf("Pembayaran tidak mencukupi. Kurang: Rp %d", -kembalian),
 		}, nil
 	}
+	if totalNonTunai > totalTagihan {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: fmt.Sprintf("Pembayaran non-tunai melebihi tagihan sebesar Rp %d; kembalian hanya dari tunai", totalNonTunai-totalTagihan),
+		}, nil
+	}
+	if err := s.validatePembayaranSaldo(req, metodeList); err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	if err := s.validatePembayaranTempo(req, metodeList); err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// 5b. OTORISASI SUPERVISOR: diskon manual di atas batas dan harga yang diubah
+	disetujuiOleh, pemakaianOtorisasi, err := s.cekOtorisasi(req)
+	if err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// 5c. SESI SHIFT: transaksi dicatat pada sesi kas kasir yang sedang terbuka
+	var shiftSessionID int64
+	if req.StaffID != 0 {
+		sesi, err := s.sesiKasRepo.GetAktifByStaff(req.StaffID)
+		if err != nil {
+			return nil, err
+		}
+		if sesi != nil {
+			shiftSessionID = sesi.ID
+		}
+	}
 
 	fmt.Printf("[TRANSACTION SERVICE] Final calculation - Subtotal: %d, Discount: %d, Total: %d, Payment: %d, Change: %d\n",
 		subtotal, totalDiskon, totalAkhir, totalPembayaran, kembalian)
@@ -167,13 +189,27 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 		Items:           req.Items,
 		Pembayaran:      req.Pembayaran,
 		PoinDitukar:     poinDipakai, // Gunakan poin yang sudah disesuaikan
+		PromoKode:       req.PromoKode,
 		Diskon:          totalDiskon,
-		DiskonPromo:     req.Diskon,      // Diskon promo dari frontend
+		DiskonPromo:     diskonPromo,     // Diskon promo hasil hitung server
 		DiskonPelanggan: diskonPelanggan, // Diskon level pelanggan dari backend
+		DiskonPoin:      diskonPoin,
+		PromoBreakdown:  promoBreakdown,
+		Pembulatan:      pembulatan,
+		Donasi:          donasi,
+		TotalPajak:      totalPajak,
+		ModePajak:       modePajak,
+		JatuhTempo:      req.JatuhTempo,
+		ShiftSessionID:  shiftSessionID,
+		DiskonManual:    req.DiskonManual,
+		DisetujuiOleh:   disetujuiOleh,
+		Otorisasi:       pemakaianOtorisasi,
 		Catatan:         req.Catatan,
 		Kasir:           req.Kasir,
 		StaffID:         req.StaffID,
 		StaffNama:       req.StaffNama,
+		TerminalID:      strings.ToUpper(strings.TrimSpace(req.TerminalID)),
+		IdempotencyKey:  req.IdempotencyKey,
 	}
 
 	fmt.Printf("[TRANSACTION SERVICE] Creating transaction with StaffID: %d, StaffNama: %s\n", req.StaffID, req.StaffNama)
@@ -188,6 +224,13 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 			break
 		}
 
+		// The same key was committed by a concurrent or earlier attempt; never insert twice
+		if req.IdempotencyKey != "" {
+			if existing, _ := s.repo.GetByIdempotencyKey(req.IdempotencyKey); existing != nil {
+				return replayTransaksi(existing), nil
+			}
+		}
+
 		// Check if it's a connection error that needs retry
 		if strings.Contains(createErr.Error(), "connection was reset") ||
 			strings.Contains(createErr.Error(), "bad connection") ||
@@ -198,6 +241,14 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 			continue
 		}
 
+		// Another terminal reserved the same counter concurrently; retry with the next number
+		if strings.Contains(createErr.Error(), "could not serialize") ||
+			strings.Contains(createErr.Error(), "database is locked") {
+			fmt.Printf("[TRANSACTION SERVICE] Numbering conflict detected, retrying transaction (attempt %d)...\n", retry+2)
+			time.Sleep(time.Millisecond * 100 * time.Duration(retry+1))
+			continue
+		}
+
 		// If it's not a connection error, break immediately
 		break
 	}
@@ -212,6 +263,23 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 	fmt.Printf("[TRANSACTION SERVICE] Transaction created successfully: %s\n",
 		transaksiDetail.Transaksi.NomorTransaksi)
 
+	// 6a. BUKA LACI KAS untuk metode yang dikonfigurasi.
+	// Hanya laci di perangkat ini; pengaturan printer dan port serial di sini bukan milik terminal lain.
+	peringatanLaci := ""
+	if req.LaciLokal {
+		laciEntry := &models.LogLaciKas{
+			TransaksiID:    transaksiDetail.Transaksi.ID,
+			NomorTransaksi: transaksiDetail.Transaksi.NomorTransaksi,
+			SesiID:         shiftSessionID,
+			StaffID:        req.StaffID,
+			StaffNama:      req.StaffNama,
+		}
+		if err := s.laciKasService.BukaUntukTransaksi(laciEntry, metodeList); err != nil {
+			fmt.Printf("[WARNING] Failed to open cash drawer: %v\n", err)
+			peringatanLaci = err.Error()
+		}
+	}
+
 	// 7. UPDATE POIN PELANGGAN (JIKA REGISTERED CUSTOMER)
 	// Support offline IDs (negative values)
 	if req.PelangganID != 0 {
@@ -247,6 +315,9 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 			// Update poin pelanggan
 			if err := s.pelangganService.UpdatePoin(req.PelangganID, poinAkhir); err != nil {
 				fmt.Printf("[WARNING] Failed to update customer points: %v\n", err)
+			} else if err := s.repo.UpdatePoinDidapat(transaksiDetail.Transaksi.ID, poinReward); err != nil {
+				// Dicatat agar poin reward bisa dibatalkan tepat saat transaksi di-void
+				fmt.Printf("[WARNING] Failed to record earned points: %v\n", err)
 			}
 
 			// BARU: Update total transaksi dan total belanja
@@ -275,12 +346,291 @@ func (s *TransaksiService) CreateTransaksi(req *models.CreateTransaksiRequest) (
 	}
 
 	return &models.TransaksiResponse{
-		Success:   true,
-		Message:   message,
-		Transaksi: transaksiDetail,
+		Success:        true,
+		Message:        message,
+		Transaksi:      transaksiDetail,
+		PeringatanLaci: peringatanLaci,
+	}, nil
+}
+
+// perhitunganTransaksi holds the server-side totals of a cart
+type perhitunganTransaksi struct {
+	subtotal       int
+	diskonPromo    int
+	promoBreakdown []models.ItemPromoAllocation
+	pelanggan      *models.Pelanggan
+	poinDipakai    int
+	diskonPoin     int
+	totalDiskon    int
+	totalPajak     int
+	modePajak      string
+	totalAkhir     int
+}
+
+// hitungTransaksi calculates the subtotal, promo, point and manual discounts, tax and final
+// total of a cart. Client-side amounts are never trusted; CreateTransaksi and PreviewTransaksi share it.
+func (s *TransaksiService) hitungTransaksi(req *models.CreateTransaksiRequest) (*perhitunganTransaksi, error) {
+	hitung := &perhitunganTransaksi{}
+
+	// 2. HITUNG SUBTOTAL (support berat or quantity)
+	for _, item := range req.Items {
+		hitung.subtotal += itemRequestSubtotal(item)
+	}
+	fmt.Printf("[TRANSACTION SERVICE] Subtotal: %d\n", hitung.subtotal)
+
+	// 2a. HITUNG ULANG DISKON PROMO DI SERVER
+	// Jangan percaya diskon dari client; hitung ulang dari kode promo yang dikirim
+	totalQty := 0
+	for _, item := range req.Items {
+		totalQty += item.Jumlah
+	}
+	_, diskonPromo, promoBreakdown, err := s.promoService.CalculateTotalDiscountWithBreakdown(
+		hitung.subtotal, totalQty, req.PromoKode, req.PelangganID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	hitung.diskonPromo = diskonPromo
+	hitung.promoBreakdown = promoBreakdown
+	fmt.Printf("[TRANSACTION SERVICE] Promo discount (server): %d from %d allocation(s)\n", diskonPromo, len(promoBreakdown))
+
+	// 3. PROSES PELANGGAN & POIN (JIKA ADA)
+	// PENTING: Support offline ID (negative values) dari SQLite dual-mode
+	// Jadi check != 0, bukan > 0
+	if req.PelangganID != 0 {
+		fmt.Printf("[TRANSACTION SERVICE] Processing registered customer ID: %d\n", req.PelangganID)
+		pelanggan, err := s.pelangganService.GetPelangganByID(req.PelangganID)
+		if err != nil {
+			return nil, fmt.Errorf("Pelanggan tidak ditemukan: %v", err)
+		}
+		hitung.pelanggan = pelanggan
+
+		// Auto-fill customer details
+		req.PelangganNama = pelanggan.Nama
+		req.PelangganTelp = pelanggan.Telepon
+		fmt.Printf("[TRANSACTION SERVICE] Customer: %s, Current Points: %d\n", pelanggan.Nama, pelanggan.Poin)
+
+		// 3a. PROSES PENUKARAN POIN (JIKA ADA)
+		if req.PoinDitukar > 0 {
+			fmt.Printf("[TRANSACTION SERVICE] Processing point redemption request: %d points\n", req.PoinDitukar)
+
+			settings, err := s.settingsService.GetPoinSettings()
+			if err != nil {
+				return nil, fmt.Errorf("Gagal mengambil pengaturan sistem poin: %v", err)
+			}
+
+			// VALIDASI DAN PENYESUAIAN POIN OTOMATIS
+			// Poin hanya boleh menutup sisa belanja setelah diskon promo
+			hitung.poinDipakai, hitung.diskonPoin = s.CalculatePointsDiscount(
+				hitung.subtotal-diskonPromo,
+				req.PoinDitukar,
+				pelanggan.Poin,
+				settings.PointValue,
+			)
+
+			fmt.Printf("[TRANSACTION SERVICE] Point adjustment - Requested: %d, Actual: %d, Discount: %d\n",
+				req.PoinDitukar, hitung.poinDipakai, hitung.diskonPoin)
+
+			// Validasi minimum exchange
+			if hitung.poinDipakai > 0 && hitung.poinDipakai < settings.MinExchange {
+				return nil, fmt.Errorf("Poin yang dapat ditukarkan (%d) kurang dari minimum (%d). Poin tersedia: %d",
+					hitung.poinDipakai, settings.MinExchange, pelanggan.Poin)
+			}
+		}
+	} else {
+		// Guest transaction - tidak boleh pakai poin
+		req.PelangganNama = ""
+		req.PelangganTelp = ""
+		if req.PoinDitukar > 0 {
+			return nil, fmt.Errorf("Penukaran poin hanya tersedia untuk pelanggan terdaftar")
+		}
+		fmt.Printf("[TRANSACTION SERVICE] Guest transaction (no customer)\n")
+	}
+
+	// 4. HITUNG TOTAL DISKON (PROMO + POIN + MANUAL)
+	// Tidak ada diskon berdasarkan level pelanggan. Diskon manual kasir hanya boleh
+	// menutup sisa belanja setelah promo dan poin, dan diotorisasi di langkah 5b.
+	if req.DiskonManual < 0 || req.DiskonManual > hitung.subtotal-diskonPromo-hitung.diskonPoin {
+		return nil, fmt.Errorf("Diskon manual tidak valid: Rp %d", req.DiskonManual)
+	}
+	hitung.totalDiskon = diskonPromo + hitung.diskonPoin + req.DiskonManual
+	fmt.Printf("[TRANSACTION SERVICE] Total discount: %d (promo + points + manual)\n", hitung.totalDiskon)
+
+	// 4a. HITUNG PAJAK (PPN) PER ITEM
+	// Dasar pajak adalah harga setelah diskon promo item dan bagian diskon poin dan manual
+	itemDiskon := AlokasiDiskonItem(req.Items, promoBreakdown, hitung.diskonPoin+req.DiskonManual)
+	hitung.totalPajak, hitung.modePajak, err = s.pajakService.HitungPajakTransaksi(req.Items, itemDiskon)
+	if err != nil {
+		return nil, fmt.Errorf("Gagal menghitung pajak: %v", err)
+	}
+	if hitung.modePajak != "" {
+		fmt.Printf("[TRANSACTION SERVICE] Tax (%s): %d\n", hitung.modePajak, hitung.totalPajak)
+	}
+
+	// 5. HITUNG TOTAL AKHIR & VALIDASI
+	hitung.totalAkhir = hitung.subtotal - hitung.totalDiskon
+	if hitung.modePajak == "exclusive" {
+		// Pajak ditambahkan di atas harga jual
+		hitung.totalAkhir += hitung.totalPajak
+	}
+
+	// Validasi total tidak negatif (seharusnya sudah di-handle oleh CalculatePointsDiscount)
+	if hitung.totalAkhir < 0 {
+		return nil, fmt.Errorf("Total transaksi tidak valid setelah diskon poin")
+	}
+
+	return hitung, nil
+}
+
+// PreviewTransaksi calculates the totals of a cart exactly as CreateTransaksi would,
+// without saving anything, so the register collects the amount the server expects.
+func (s *TransaksiService) PreviewTransaksi(req *models.CreateTransaksiRequest) (*models.TransaksiPreview, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("transaksi harus memiliki minimal 1 item")
+	}
+
+	hitung, err := s.hitungTransaksi(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TransaksiPreview{
+		Subtotal:    hitung.subtotal,
+		DiskonPromo: hitung.diskonPromo,
+		DiskonPoin:  hitung.diskonPoin,
+		PoinDipakai: hitung.poinDipakai,
+		Diskon:      hitung.totalDiskon,
+		TotalPajak:  hitung.totalPajak,
+		ModePajak:   hitung.modePajak,
+		Total:       hitung.totalAkhir,
 	}, nil
 }
 
+// hitungPembulatanTunai calculates the cash rounding and the optional rounding donation.
+// Rounding only applies to the part of the total that is paid in cash.
+func (s *TransaksiService) hitungPembulatanTunai(totalAkhir int, req *models.CreateTransaksiRequest, metodeList []*models.MetodePembayaran) (int, int, error) {
+	adaTunai := false
+	nonTunai := 0
+	for i, payment := range req.Pembayaran {
+		if metodeList[i].IsTunai() {
+			adaTunai = true
+		} else {
+			nonTunai += payment.Jumlah
+		}
+	}
+
+	if !adaTunai {
+		if req.DonasiPembulatan {
+			return 0, 0, fmt.Errorf("donasi pembulatan hanya untuk pembayaran tunai")
+		}
+		return 0, 0, nil
+	}
+
+	// Same calculation the register previews, see SettingsService.PreviewPembulatan
+	preview, err := s.settingsService.PreviewPembulatan(totalAkhir, nonTunai, req.DonasiPembulatan)
+	if err != nil {
+		return 0, 0, err
+	}
+	return preview.Pembulatan, preview.Donasi, nil
+}
+
+// resolveMetodePembayaran looks up the configured method of every payment and
+// normalizes the method code. Non-cash methods that need a reference must carry one.
+func (s *TransaksiService) resolveMetodePembayaran(payments []models.PembayaranRequest) ([]*models.MetodePembayaran, error) {
+	metodeList := make([]*models.MetodePembayaran, len(payments))
+	for i := range payments {
+		metode, err := s.metodeService.GetMetodeByKode(payments[i].Metode)
+		if err != nil {
+			return nil, err
+		}
+		if metode == nil {
+			return nil, fmt.Errorf("pembayaran %d: metode '%s' tidak terdaftar", i+1, payments[i].Metode)
+		}
+		if !metode.Aktif {
+			return nil, fmt.Errorf("pembayaran %d: metode %s tidak aktif", i+1, metode.Nama)
+		}
+
+		payments[i].Metode = metode.Kode
+		payments[i].Referensi = strings.TrimSpace(payments[i].Referensi)
+		if metode.PerluReferensi && !metode.IsTunai() && payments[i].Referensi == "" {
+			return nil, fmt.Errorf("pembayaran %d: nomor referensi %s harus diisi", i+1, metode.Nama)
+		}
+
+		metodeList[i] = metode
+	}
+	return metodeList, nil
+}
+
+// validatePembayaranSaldo checks that store credit payments belong to a registered customer with enough balance.
+// The balance is deducted again atomically when the transaction is saved.
+func (s *TransaksiService) validatePembayaranSaldo(req *models.CreateTransaksiRequest, metodeList []*models.MetodePembayaran) error {
+	saldoDipakai := 0
+	for i, payment := range req.Pembayaran {
+		if metodeList[i].Kode == models.MetodeSaldo {
+			saldoDipakai += payment.Jumlah
+		}
+	}
+	if saldoDipakai == 0 {
+		return nil
+	}
+
+	if req.PelangganID == 0 {
+		return fmt.Errorf("pembayaran saldo hanya untuk pelanggan terdaftar")
+	}
+	pelanggan, err := s.pelangganService.GetPelangganByID(req.PelangganID)
+	if err != nil {
+		return err
+	}
+	if pelanggan.Saldo < saldoDipakai {
+		return fmt.Errorf("saldo pelanggan tidak mencukupi. Saldo: Rp %d", pelanggan.Saldo)
+	}
+	return nil
+}
+
+// validatePembayaranTempo checks the credit limit of the customer and sets the due date of the credit part.
+// The limit is checked again atomically when the receivable is saved.
+func (s *TransaksiService) validatePembayaranTempo(req *models.CreateTransaksiRequest, metodeList []*models.MetodePembayaran) error {
+	tempo := 0
+	for i, payment := range req.Pembayaran {
+		if metodeList[i].Kode == models.MetodeTempo {
+			tempo += payment.Jumlah
+		}
+	}
+	if tempo == 0 {
+		return nil
+	}
+
+	if req.PelangganID == 0 {
+		return fmt.Errorf("pembayaran tempo hanya untuk pelanggan terdaftar")
+	}
+	pelanggan, err := s.pelangganService.GetPelangganByID(req.PelangganID)
+	if err != nil {
+		return err
+	}
+	if pelanggan.LimitKredit <= 0 {
+		return fmt.Errorf("pelanggan %s belum memiliki limit kredit", pelanggan.Nama)
+	}
+	if pelanggan.Piutang+tempo > pelanggan.LimitKredit {
+		return fmt.Errorf("limit kredit terlampaui. Sisa limit: Rp %d", pelanggan.LimitKredit-pelanggan.Piutang)
+	}
+
+	tempoHari := pelanggan.TempoHari
+	if tempoHari <= 0 {
+		tempoHari = 30
+	}
+	req.JatuhTempo = time.Now().AddDate(0, 0, tempoHari).UTC()
+	return nil
+}
+
+// replayTransaksi builds the response for a transaction that was already created with the same idempotency key
+func replayTransaksi(existing *models.TransaksiDetail) *models.TransaksiResponse {
+	fmt.Printf("[TRANSACTION SERVICE] Idempotency key already used, returning %s\n", existing.Transaksi.NomorTransaksi)
+	return &models.TransaksiResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Transaksi %s berhasil dibuat", existing.Transaksi.NomorTransaksi),
+		Transaksi: existing,
+	}
+}
+
 func (s *TransaksiService) CalculatePointsDiscount(subtotal int, poinDitukar int, saldoPoin int, pointValue int) (int, int) {
 	// ATURAN 1: Tidak boleh lebih dari saldo poin
 	poinMaksimumBerdasarSaldo := poinDitukar
@@ -301,6 +651,57 @@ func (s *TransaksiService) CalculatePointsDiscount(subtotal int, poinDitukar int
 	return poinMaksimumBerdasarSaldo, diskonPoin
 }
 
+// cekOtorisasi checks the override tokens a sale needs: one for a manual discount above the
+// configured limit and one per line whose price differs from the product's selling price.
+// The tokens are spent by the repository inside the transaction that saves the sale.
+// Returns the approving supervisors.
+func (s *TransaksiService) cekOtorisasi(req *models.CreateTransaksiRequest) (string, []models.PemakaianOtorisasi, error) {
+	settings, err := s.otorisasiService.GetSettings()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var supervisor []string
+	var pemakaian []models.PemakaianOtorisasi
+	catat := func(o *models.Otorisasi, p *models.PemakaianOtorisasi) {
+		pemakaian = append(pemakaian, *p)
+		for _, username := range supervisor {
+			if username == o.SupervisorUsername {
+				return
+			}
+		}
+		supervisor = append(supervisor, o.SupervisorUsername)
+	}
+
+	if req.DiskonManual > 0 && req.DiskonManual > settings.BatasDiskonManual {
+		otorisasi, p, err := s.otorisasiService.Cek(req.OverrideToken, models.AksiDiskonManual, "")
+		if err != nil {
+			return "", nil, fmt.Errorf("diskon manual Rp %d: %w", req.DiskonManual, err)
+		}
+		catat(otorisasi, p)
+	}
+
+	for i, item := range req.Items {
+		produk, err := s.produkRepo.GetByID(item.ProdukID)
+		if err != nil {
+			return "", nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		if produk == nil {
+			return "", nil, fmt.Errorf("item %d: produk tidak ditemukan", i+1)
+		}
+		if item.HargaSatuan == produk.HargaJual {
+			continue
+		}
+		otorisasi, p, err := s.otorisasiService.Cek(item.OverrideToken, models.AksiUbahHarga, strconv.Itoa(item.ProdukID))
+		if err != nil {
+			return "", nil, fmt.Errorf("harga %s diubah dari Rp %d ke Rp %d: %w", produk.Nama, produk.HargaJual, item.HargaSatuan, err)
+		}
+		catat(otorisasi, p)
+	}
+
+	return strings.Join(supervisor, ", "), pemakaian, nil
+}
+
 // validateCreateRequest validates the create transaction request
 func (s *TransaksiService) validateCreateRequest(req *models.CreateTransaksiRequest) error {
 	// Validasi items
@@ -308,6 +709,17 @@ func (s *TransaksiService) validateCreateRequest(req *models.CreateTransaksiRequ
 		return fmt.Errorf("transaksi harus memiliki minimal 1 item")
 	}
 
+	if len(req.IdempotencyKey) > 255 {
+		return fmt.Errorf("idempotency key terlalu panjang")
+	}
+
+	// Validasi kode terminal (dipakai di nomor transaksi)
+	if terminal := strings.TrimSpace(req.TerminalID); terminal != "" {
+		if err := ValidateKodePenomoran(terminal, "kode terminal"); err != nil {
+			return err
+		}
+	}
+
 	for i, item := range req.Items {
 		if item.ProdukID <= 0 {
 			return fmt.Errorf("item %d: produk ID tidak valid", i+1)
@@ -355,6 +767,112 @@ func (s *TransaksiService) validateCreateRequest(req *models.CreateTransaksiRequ
 	return nil
 }
 
+// VoidTransaksi cancels a completed transaction after an admin approves it.
+// Stock goes back to the exact batches used, and customer points/stats are reversed.
+func (s *TransaksiService) VoidTransaksi(req *models.VoidTransaksiRequest) (*models.TransaksiResponse, error) {
+	fmt.Printf("[TRANSACTION SERVICE] Void requested for transaction ID: %d\n", req.TransaksiID)
+
+	// 1. VALIDASI DASAR
+	if req.TransaksiID == 0 {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: "ID transaksi tidak valid",
+		}, nil
+	}
+	alasan := strings.TrimSpace(req.Alasan)
+	if alasan == "" {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: "Alasan void wajib diisi",
+		}, nil
+	}
+
+	existing, err := s.repo.GetByID(req.TransaksiID)
+	if err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: fmt.Sprintf("Transaksi tidak ditemukan: %v", err),
+		}, nil
+	}
+	trx := existing.Transaksi
+
+	// 2. VERIFIKASI SUPERVISOR (FAKTOR KEDUA): token otorisasi atau username + PIN
+	// The token is spent by the repository together with the void
+	var approverUsername string
+	var pemakaianOtorisasi *models.PemakaianOtorisasi
+	if req.OverrideToken != "" {
+		otorisasi, pemakaian, err := s.otorisasiService.Cek(req.OverrideToken, models.AksiVoid, trx.NomorTransaksi)
+		if err != nil {
+			return &models.TransaksiResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		approverUsername = otorisasi.SupervisorUsername
+		pemakaianOtorisasi = pemakaian
+	} else {
+		approver, err := s.userService.VerifyApprover(req.ApproverUsername, req.ApproverSecret)
+		if err != nil {
+			return &models.TransaksiResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		approverUsername = approver.Username
+	}
+
+	poinDidapat, err := s.repo.GetPoinDidapat(trx.ID)
+	if err != nil {
+		fmt.Printf("[WARNING] Failed to get earned points: %v\n", err)
+	}
+
+	// 3. KEMBALIKAN STOK & TANDAI VOID (SATU TRANSAKSI DATABASE)
+	voidBy := req.StaffNama
+	if voidBy == "" {
+		voidBy = trx.StaffNama
+	}
+	if err := s.repo.Void(trx.ID, alasan, voidBy, approverUsername, pemakaianOtorisasi); err != nil {
+		return &models.TransaksiResponse{
+			Success: false,
+			Message: fmt.Sprintf("Gagal void transaksi: %v", err),
+		}, nil
+	}
+
+	// 4. BALIKKAN POIN & STATISTIK PELANGGAN
+	if trx.PelangganID != 0 {
+		pelanggan, err := s.pelangganService.GetPelangganByID(trx.PelangganID)
+		if err != nil {
+			fmt.Printf("[WARNING] Failed to get customer for void: %v\n", err)
+		} else {
+			// Poin yang ditukar dikembalikan, poin reward ditarik kembali
+			poinAkhir := pelanggan.Poin + trx.PoinDitukar - poinDidapat
+			if poinAkhir < 0 {
+				poinAkhir = 0
+			}
+			if err := s.pelangganService.UpdatePoin(trx.PelangganID, poinAkhir); err != nil {
+				fmt.Printf("[WARNING] Failed to reverse customer points: %v\n", err)
+			}
+			if err := s.pelangganService.DecrementStats(trx.PelangganID, trx.Total); err != nil {
+				fmt.Printf("[WARNING] Failed to reverse customer stats: %v\n", err)
+			}
+
+			fmt.Printf("[TRANSACTION SERVICE] Points reversed - Start: %d, Refunded: %d, Withdrawn: %d, Final: %d\n",
+				pelanggan.Poin, trx.PoinDitukar, poinDidapat, poinAkhir)
+		}
+	}
+
+	fmt.Printf("[TRANSACTION SERVICE] Transaction %s voided by %s, approved by %s\n",
+		trx.NomorTransaksi, voidBy, approverUsername)
+
+	transaksiDetail, _ := s.repo.GetByID(trx.ID)
+
+	return &models.TransaksiResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Transaksi %s berhasil di-void", trx.NomorTransaksi),
+		Transaksi: transaksiDetail,
+	}, nil
+}
+
 // GetTransaksiByID retrieves a transaction by ID
 func (s *TransaksiService) GetTransaksiByID(id int64) (*models.TransaksiDetail, error) {
 	fmt.Printf("[SERVICE] GetTransaksiByID called with id: %d\n", id)
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,18 +14,42 @@ import (
 
 // UserService handles business logic for user management
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo       *repository.UserRepository
+	roleService    *RoleService
+	settingsRepo   *repository.SettingsRepository
+	loginEventRepo *repository.LoginEventRepository
+	auditService   *AuditService
 }
 
 // NewUserService creates a new user service
 func NewUserService() *UserService {
 	return &UserService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:       repository.NewUserRepository(),
+		roleService:    NewRoleService(),
+		settingsRepo:   repository.NewSettingsRepository(),
+		loginEventRepo: repository.NewLoginEventRepository(),
+		auditService:   NewAuditService(),
 	}
 }
 
-// Login authenticates a user
+// Login authenticates a user and records the attempt in the login audit
 func (s *UserService) Login(req *models.LoginRequest) (*models.LoginResponse, error) {
+	response, err := s.loginPassword(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.catatLogin(&models.LoginEvent{
+		Username:   req.Username,
+		Metode:     models.MetodeLoginPassword,
+		IPAddress:  req.IPAddress,
+		TerminalID: req.TerminalID,
+		UserAgent:  req.UserAgent,
+	}, response)
+	return response, nil
+}
+
+func (s *UserService) loginPassword(req *models.LoginRequest) (*models.LoginResponse, error) {
 	// Validate input
 	if strings.TrimSpace(req.Username) == "" {
 		return &models.LoginResponse{
@@ -59,20 +86,136 @@ func (s *UserService) Login(req *models.LoginRequest) (*models.LoginResponse, er
 		}, nil
 	}
 
+	pesan, err := s.cekTerkunci(user)
+	if err != nil {
+		return nil, err
+	}
+	if pesan != "" {
+		return &models.LoginResponse{
+			Success: false,
+			Message: pesan,
+		}, nil
+	}
+
 	// Verify password
 	if err := s.userRepo.VerifyPassword(user.Password, req.Password); err != nil {
+		pesan, err := s.catatGagalLogin(user, "Username atau password salah")
+		if err != nil {
+			return nil, err
+		}
 		return &models.LoginResponse{
 			Success: false,
-			Message: "Username atau password salah",
+			Message: pesan,
 		}, nil
 	}
 
-	// Don't send password in response
-	user.Password = ""
+	return s.loginBerhasil(user)
+}
+
+// LoginPin authenticates a user with the numeric PIN, for fast user switching on a shared terminal.
+// Failed PINs count toward the same lockout as failed passwords.
+func (s *UserService) LoginPin(req *models.PinLoginRequest) (*models.LoginResponse, error) {
+	response, err := s.loginPin(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.catatLogin(&models.LoginEvent{
+		Username:   req.Username,
+		Metode:     models.MetodeLoginPin,
+		IPAddress:  req.IPAddress,
+		TerminalID: req.TerminalID,
+		UserAgent:  req.UserAgent,
+	}, response)
+	return response, nil
+}
+
+func (s *UserService) loginPin(req *models.PinLoginRequest) (*models.LoginResponse, error) {
+	if strings.TrimSpace(req.Username) == "" {
+		return &models.LoginResponse{
+			Success: false,
+			Message: "Username tidak boleh kosong",
+		}, nil
+	}
+
+	if req.Pin == "" {
+		return &models.LoginResponse{
+			Success: false,
+			Message: "PIN tidak boleh kosong",
+		}, nil
+	}
+
+	user, err := s.userRepo.GetByUsername(req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user == nil {
+		return &models.LoginResponse{
+			Success: false,
+			Message: "Username atau PIN salah",
+		}, nil
+	}
+
+	if user.Status != "active" {
+		return &models.LoginResponse{
+			Success: false,
+			Message: "Akun Anda tidak aktif. Hubungi administrator",
+		}, nil
+	}
+
+	pesan, err := s.cekTerkunci(user)
+	if err != nil {
+		return nil, err
+	}
+	if pesan != "" {
+		return &models.LoginResponse{
+			Success: false,
+			Message: pesan,
+		}, nil
+	}
+
+	pinHash, err := s.userRepo.GetPinHash(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pinHash == "" {
+		return &models.LoginResponse{
+			Success: false,
+			Message: "PIN belum diatur. Masuk dengan password atau minta admin mengatur PIN",
+		}, nil
+	}
+
+	if err := s.userRepo.VerifyPassword(pinHash, req.Pin); err != nil {
+		pesan, err := s.catatGagalLogin(user, "Username atau PIN salah")
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResponse{
+			Success: false,
+			Message: pesan,
+		}, nil
+	}
+
+	return s.loginBerhasil(user)
+}
+
+// loginBerhasil clears the failed attempts and fills the role permissions of a signed-in user
+func (s *UserService) loginBerhasil(user *models.User) (*models.LoginResponse, error) {
+	if err := s.userRepo.ResetGagalLogin(user.ID); err != nil {
+		return nil, err
+	}
 
 	// Don't send password in response
 	user.Password = ""
 
+	// Permissions of the role go into the token and the desktop session
+	permissions, err := s.roleService.GetPermissions(user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	user.Permissions = permissions
+
 	response := &models.LoginResponse{
 		Success: true,
 		Message: "Login berhasil",
@@ -82,8 +225,88 @@ func (s *UserService) Login(req *models.LoginRequest) (*models.LoginResponse, er
 	return response, nil
 }
 
+// cekTerkunci returns a message when the user is locked out after too many failed attempts
+func (s *UserService) cekTerkunci(user *models.User) (string, error) {
+	terkunciSampai, err := s.userRepo.GetTerkunciSampai(user.ID)
+	if err != nil {
+		return "", err
+	}
+	if terkunciSampai != nil && time.Now().Before(*terkunciSampai) {
+		return fmt.Sprintf("Akun terkunci karena terlalu banyak percobaan gagal. Coba lagi pukul %s",
+			terkunciSampai.Local().Format("15:04")), nil
+	}
+	return "", nil
+}
+
+// catatGagalLogin counts a failed password or PIN toward the lockout and returns the message to show
+func (s *UserService) catatGagalLogin(user *models.User, pesan string) (string, error) {
+	settings, err := s.settingsRepo.GetKeamananSettings()
+	if err != nil {
+		return "", err
+	}
+
+	gagal, jumlahKunci, err := s.userRepo.CatatGagalLogin(user.ID)
+	if err != nil {
+		return "", err
+	}
+	if settings.MaksGagalLogin <= 0 || gagal < settings.MaksGagalLogin {
+		return pesan, nil
+	}
+
+	menit := durasiKunciMenit(settings, jumlahKunci)
+	if err := s.userRepo.KunciAkun(user.ID, time.Now().Add(time.Duration(menit)*time.Minute)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Akun dikunci %d menit karena terlalu banyak percobaan gagal", menit), nil
+}
+
+// durasiKunciMenit doubles the lock duration for every lockout since the last successful login,
+// up to DurasiKunciMaksMenit
+func durasiKunciMenit(settings *models.KeamananSettings, jumlahKunci int) int {
+	menit := settings.DurasiKunciMenit
+	for i := 0; i < jumlahKunci && menit < settings.DurasiKunciMaksMenit; i++ {
+		menit *= 2
+	}
+	if settings.DurasiKunciMaksMenit > 0 && menit > settings.DurasiKunciMaksMenit {
+		menit = settings.DurasiKunciMaksMenit
+	}
+	return menit
+}
+
+// catatLogin writes one attempt to the login audit. A failure to record is logged but never blocks the login.
+func (s *UserService) catatLogin(event *models.LoginEvent, response *models.LoginResponse) {
+	if strings.TrimSpace(event.Username) == "" {
+		return
+	}
+
+	event.Berhasil = response.Success
+	if response.Success {
+		event.UserID = response.User.ID
+	} else {
+		event.Alasan = response.Message
+		if user, err := s.userRepo.GetByUsername(event.Username); err == nil && user != nil {
+			event.UserID = user.ID
+		}
+	}
+
+	if err := s.loginEventRepo.Create(event); err != nil {
+		log.Printf("[USER] Failed to record login event for %s: %v", event.Username, err)
+	}
+}
+
+// GetLoginHistory retrieves the latest login attempts of a user
+func (s *UserService) GetLoginHistory(userID int64, limit int) ([]*models.LoginEvent, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("ID user tidak valid")
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.loginEventRepo.GetByUser(userID, limit)
+}
+
 // CreateUser creates a new user (staff or admin)
-func (s *UserService) CreateUser(req *models.CreateUserRequest) error {
+func (s *UserService) CreateUser(req *models.CreateUserRequest, aktor models.Aktor) error {
 	// Validate input
 	if strings.TrimSpace(req.Username) == "" {
 		return fmt.Errorf("username tidak boleh kosong")
@@ -93,16 +316,22 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) error {
 		return fmt.Errorf("password tidak boleh kosong")
 	}
 
-	if len(req.Password) < 6 {
-		return fmt.Errorf("password minimal 6 karakter")
+	if err := s.validasiPassword(0, req.Password); err != nil {
+		return err
 	}
 
 	if strings.TrimSpace(req.NamaLengkap) == "" {
 		return fmt.Errorf("nama lengkap tidak boleh kosong")
 	}
 
-	if req.Role != "admin" && req.Role != "staff" {
-		return fmt.Errorf("role harus 'admin' atau 'staff'")
+	if err := s.validateRole(req.Role); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(req.Pin) != "" {
+		if err := validatePin(req.Pin); err != nil {
+			return err
+		}
 	}
 
 	// Check if username already exists
@@ -130,11 +359,22 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) error {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
+	keterangan := ""
+	if strings.TrimSpace(req.Pin) != "" {
+		if err := s.userRepo.UpdatePin(user.ID, req.Pin); err != nil {
+			return fmt.Errorf("failed to set pin: %w", err)
+		}
+		keterangan = "PIN diatur"
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasUser, strconv.FormatInt(user.ID, 10), models.AuditAksiCreate,
+		nil, user, keterangan)
+
 	return nil
 }
 
-// UpdateUser updates user information
-func (s *UserService) UpdateUser(req *models.UpdateUserRequest) error {
+// UpdateUser updates user information and records the change in the audit log
+func (s *UserService) UpdateUser(req *models.UpdateUserRequest, aktor models.Aktor) error {
 	// Validate input
 	if req.ID <= 0 {
 		return fmt.Errorf("ID user tidak valid")
@@ -148,14 +388,20 @@ func (s *UserService) UpdateUser(req *models.UpdateUserRequest) error {
 		return fmt.Errorf("nama lengkap tidak boleh kosong")
 	}
 
-	if req.Role != "admin" && req.Role != "staff" {
-		return fmt.Errorf("role harus 'admin' atau 'staff'")
+	if err := s.validateRole(req.Role); err != nil {
+		return err
 	}
 
 	if req.Status != "active" && req.Status != "inactive" {
 		return fmt.Errorf("status harus 'active' atau 'inactive'")
 	}
 
+	if strings.TrimSpace(req.Password) != "" {
+		if err := s.validasiPassword(req.ID, req.Password); err != nil {
+			return err
+		}
+	}
+
 	// Check if user exists
 	existing, err := s.userRepo.GetByID(req.ID)
 	if err != nil {
@@ -193,24 +439,186 @@ func (s *UserService) UpdateUser(req *models.UpdateUserRequest) error {
 
 	// Update password if provided
 	if strings.TrimSpace(req.Password) != "" {
-		if len(req.Password) < 6 {
-			return fmt.Errorf("password minimal 6 karakter")
+		if err := s.gantiPassword(req.ID, req.Password); err != nil {
+			return err
 		}
+	}
 
-		if err := s.userRepo.UpdatePassword(req.ID, req.Password); err != nil {
-			return fmt.Errorf("failed to update password: %w", err)
+	// Update PIN if provided
+	if strings.TrimSpace(req.Pin) != "" {
+		if err := validatePin(req.Pin); err != nil {
+			return err
 		}
 
-		if err := s.userRepo.UpdatePassword(req.ID, req.Password); err != nil {
-			return fmt.Errorf("failed to update password: %w", err)
+		if err := s.userRepo.UpdatePin(req.ID, req.Pin); err != nil {
+			return fmt.Errorf("failed to update pin: %w", err)
 		}
 	}
 
+	updated, err := s.userRepo.GetByID(req.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get updated user: %w", err)
+	}
+
+	// Password and PIN hashes never go into the log, only the fact that they changed
+	var diubah []string
+	if strings.TrimSpace(req.Password) != "" {
+		diubah = append(diubah, "password diubah")
+	}
+	if strings.TrimSpace(req.Pin) != "" {
+		diubah = append(diubah, "PIN diubah")
+	}
+	s.auditService.Catat(aktor, models.AuditEntitasUser, strconv.FormatInt(req.ID, 10), models.AuditAksiUpdate,
+		existing, updated, strings.Join(diubah, ", "))
+
+	return nil
+}
+
+// VerifyApprover checks supervisor credentials used as a second factor
+// for sensitive actions. The secret may be the supervisor's password or PIN,
+// and the supervisor's role must grant override.approve.
+func (s *UserService) VerifyApprover(username, secret string) (*models.User, error) {
+	user, err := s.verifyApprover(username, secret)
+
+	response := &models.LoginResponse{Success: err == nil, User: user}
+	if err != nil {
+		response.Message = err.Error()
+	}
+	s.catatLogin(&models.LoginEvent{Username: username, Metode: models.MetodeLoginOtorisasi}, response)
+
+	return user, err
+}
+
+func (s *UserService) verifyApprover(username, secret string) (*models.User, error) {
+	if strings.TrimSpace(username) == "" || secret == "" {
+		return nil, fmt.Errorf("kredensial supervisor wajib diisi")
+	}
+
+	user, err := s.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user == nil || user.Status != "active" {
+		return nil, fmt.Errorf("kredensial supervisor tidak valid")
+	}
+
+	permissions, err := s.roleService.GetPermissions(user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	if !models.HasPermission(user.Role, permissions, models.PermOverrideApprove) {
+		return nil, fmt.Errorf("user %s tidak berwenang memberi otorisasi", user.Username)
+	}
+
+	pesan, err := s.cekTerkunci(user)
+	if err != nil {
+		return nil, err
+	}
+	if pesan != "" {
+		return nil, errors.New(pesan)
+	}
+
+	if err := s.userRepo.VerifyPassword(user.Password, secret); err == nil {
+		return user, s.userRepo.ResetGagalLogin(user.ID)
+	}
+
+	pinHash, err := s.userRepo.GetPinHash(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pinHash != "" && s.userRepo.VerifyPassword(pinHash, secret) == nil {
+		return user, s.userRepo.ResetGagalLogin(user.ID)
+	}
+
+	// Wrong supervisor PINs count toward the same lockout as logins
+	pesan, err = s.catatGagalLogin(user, "kredensial supervisor tidak valid")
+	if err != nil {
+		return nil, err
+	}
+	return nil, errors.New(pesan)
+}
+
+// VerifyPin checks a staff member's username and PIN for the time clock. Wrong PINs count
+// toward the same lockout as PIN login.
+func (s *UserService) VerifyPin(username, pin string) (*models.User, error) {
+	user, err := s.verifyPin(username, pin)
+
+	response := &models.LoginResponse{Success: err == nil, User: user}
+	if err != nil {
+		response.Message = err.Error()
+	}
+	s.catatLogin(&models.LoginEvent{Username: username, Metode: models.MetodeLoginAbsensi}, response)
+
+	return user, err
+}
+
+func (s *UserService) verifyPin(username, pin string) (*models.User, error) {
+	if strings.TrimSpace(username) == "" || pin == "" {
+		return nil, fmt.Errorf("username dan PIN wajib diisi")
+	}
+
+	user, err := s.userRepo.GetByUsername(strings.TrimSpace(username))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Status != "active" {
+		return nil, fmt.Errorf("username atau PIN salah")
+	}
+
+	pesan, err := s.cekTerkunci(user)
+	if err != nil {
+		return nil, err
+	}
+	if pesan != "" {
+		return nil, errors.New(pesan)
+	}
+
+	pinHash, err := s.userRepo.GetPinHash(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pinHash == "" {
+		return nil, fmt.Errorf("%s belum memiliki PIN", user.NamaLengkap)
+	}
+	if s.userRepo.VerifyPassword(pinHash, pin) == nil {
+		return user, s.userRepo.ResetGagalLogin(user.ID)
+	}
+
+	pesan, err = s.catatGagalLogin(user, "username atau PIN salah")
+	if err != nil {
+		return nil, err
+	}
+	return nil, errors.New(pesan)
+}
+
+// validateRole ensures a role name exists
+func (s *UserService) validateRole(role string) error {
+	exists, err := s.roleService.RoleExists(role)
+	if err != nil {
+		return fmt.Errorf("failed to check role: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("role '%s' tidak ditemukan", role)
+	}
+	return nil
+}
+
+// validatePin ensures a PIN is 4-6 digits
+func validatePin(pin string) error {
+	if len(pin) < 4 || len(pin) > 6 {
+		return fmt.Errorf("PIN harus 4-6 digit")
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("PIN hanya boleh berisi angka")
+		}
+	}
 	return nil
 }
 
 // DeleteUser soft deletes a user
-func (s *UserService) DeleteUser(id int64) error {
+func (s *UserService) DeleteUser(id int64, aktor models.Aktor) error {
 	if id <= 0 {
 		return fmt.Errorf("ID user tidak valid")
 	}
@@ -242,6 +650,8 @@ func (s *UserService) DeleteUser(id int64) error {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
+	s.auditService.Catat(aktor, models.AuditEntitasUser, strconv.FormatInt(id, 10), models.AuditAksiDelete, user, nil, "")
+
 	return nil
 }
 
@@ -292,6 +702,30 @@ func (s *UserService) GetUserByID(id int64) (*models.User, error) {
 	return user, nil
 }
 
+// HasPermission reports whether the user's current role grants at least one of perms.
+// It reads the user and role from the store, so role edits and role changes apply to
+// sessions and tokens issued before them.
+func (s *UserService) HasPermission(userID int64, perms ...string) (bool, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Status != "active" {
+		return false, nil
+	}
+
+	permissions, err := s.roleService.GetPermissions(user.Role)
+	if err != nil {
+		return false, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	for _, perm := range perms {
+		if models.HasPermission(user.Role, permissions, perm) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ChangePassword changes user password
 func (s *UserService) ChangePassword(req *models.ChangePasswordRequest) error {
 	// Validate input
@@ -307,10 +741,6 @@ func (s *UserService) ChangePassword(req *models.ChangePasswordRequest) error {
 		return fmt.Errorf("password baru tidak boleh kosong")
 	}
 
-	if len(req.NewPassword) < 6 {
-		return fmt.Errorf("password baru minimal 6 karakter")
-	}
-
 	// Get user
 	user, err := s.userRepo.GetByID(req.UserID)
 	if err != nil {
@@ -327,15 +757,11 @@ func (s *UserService) ChangePassword(req *models.ChangePasswordRequest) error {
 	}
 
 	// Update password
-	if err := s.userRepo.UpdatePassword(req.UserID, req.NewPassword); err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
-	}
-
-	return nil
+	return s.gantiPassword(req.UserID, req.NewPassword)
 }
 
 // AdminChangePassword allows admin to change password without old password
-func (s *UserService) AdminChangePassword(userID int64, newPassword string) error {
+func (s *UserService) AdminChangePassword(userID int64, newPassword string, aktor models.Aktor) error {
 	// Validate input
 	if userID <= 0 {
 		return fmt.Errorf("ID user tidak valid")
@@ -345,10 +771,6 @@ func (s *UserService) AdminChangePassword(userID int64, newPassword string) erro
 		return fmt.Errorf("password baru tidak boleh kosong")
 	}
 
-	if len(newPassword) < 6 {
-		return fmt.Errorf("password baru minimal 6 karakter")
-	}
-
 	// Get user to verify exists
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
@@ -360,10 +782,63 @@ func (s *UserService) AdminChangePassword(userID int64, newPassword string) erro
 	}
 
 	// Update password directly (no old password check)
-	if err := s.userRepo.UpdatePassword(userID, newPassword); err != nil {
+	if err := s.gantiPassword(userID, newPassword); err != nil {
+		return err
+	}
+
+	s.auditService.Catat(aktor, models.AuditEntitasUser, strconv.FormatInt(userID, 10), models.AuditAksiUpdate,
+		nil, nil, "password direset oleh admin")
+	return nil
+}
+
+// validasiPassword checks a new password against the password policy in keamanan settings.
+// userID 0 skips the reuse check for a user that does not exist yet.
+func (s *UserService) validasiPassword(userID int64, password string) error {
+	settings, err := s.settingsRepo.GetKeamananSettings()
+	if err != nil {
+		return err
+	}
+
+	if len(password) < settings.PanjangMinPassword {
+		return fmt.Errorf("password minimal %d karakter", settings.PanjangMinPassword)
+	}
+
+	if userID == 0 || settings.RiwayatPassword <= 0 {
+		return nil
+	}
+
+	hashes, err := s.userRepo.GetPasswordHashes(userID, settings.RiwayatPassword)
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if s.userRepo.VerifyPassword(hash, password) == nil {
+			return fmt.Errorf("password tidak boleh sama dengan %d password terakhir", settings.RiwayatPassword)
+		}
+	}
+	return nil
+}
+
+// gantiPassword validates and sets a new password, keeping the replaced hash in the password history
+func (s *UserService) gantiPassword(userID int64, password string) error {
+	if err := s.validasiPassword(userID, password); err != nil {
+		return err
+	}
+
+	hashes, err := s.userRepo.GetPasswordHashes(userID, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, password); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
+	if len(hashes) > 0 {
+		if err := s.userRepo.SimpanRiwayatPassword(userID, hashes[0]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -605,7 +605,7 @@ func (s *SyncEngine) upsertRemote(tableName string, data map[string]interface{})
 
 			// Extract nomor_transaksi from values (it's in the data map, but we need to find it safely)
 			if nomorTrx, ok := data["nomor_transaksi"].(string); ok && nomorTrx != "" {
-				if resolveErr := s.resolveTransactionConflict(nomorTrx); resolveErr != nil {
+				if resolveErr := s.resolveTransactionConflict(nomorTrx, data); resolveErr != nil {
 					log.Printf("[SYNC] Failed to resolve conflict for %s: %v", nomorTrx, resolveErr)
 					return err
 				} else {
@@ -687,33 +687,71 @@ func (s *SyncEngine) Stop() {
 	}
 }
 
-// resolveTransactionConflict deletes a conflicting transaction and its dependencies from remote
-func (s *SyncEngine) resolveTransactionConflict(nomorTransaksi string) error {
-	// Find the ID of the conflicting transaction
-	var remoteID int64
-	query := "SELECT id FROM transaksi WHERE nomor_transaksi = $1"
-	err := s.remoteDB.QueryRow(query, nomorTransaksi).Scan(&remoteID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil // No conflict found?
-		}
-		return fmt.Errorf("failed to find conflicting transaction: %w", err)
+// resolveTransactionConflict deletes a conflicting transaction and its dependencies from remote.
+// Only a stale copy of the same sale, recognised by the client's idempotency key, is replaced;
+// a different sale that happens to share the number (e.g. two terminals configured with the
+// same code) is left untouched and reported. All deletes run in one remote transaction.
+func (s *SyncEngine) resolveTransactionConflict(nomorTransaksi string, data map[string]interface{}) error {
+	localKey, _ := data["idempotency_key"].(string)
+	if localKey == "" {
+		return fmt.Errorf("nomor transaksi %s sudah dipakai di server dan transaksi lokal tidak memiliki idempotency key untuk dicocokkan", nomorTransaksi)
 	}
 
-	log.Printf("[SYNC-FIX] Deleting conflicting remote transaction ID=%d (%s) to allow sync...", remoteID, nomorTransaksi)
-
-	// Delete dependencies first (manual cascade)
-	s.remoteDB.Exec("DELETE FROM pembayaran WHERE transaksi_id = $1", remoteID)
-	s.remoteDB.Exec("DELETE FROM transaksi_item WHERE transaksi_id = $1", remoteID)
-	s.remoteDB.Exec("DELETE FROM transaksi_batch WHERE transaksi_id = $1", remoteID)
-	s.remoteDB.Exec("DELETE FROM returns WHERE transaksi_id = $1", remoteID)
+	tx, err := s.remoteDB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	// Delete the transaction itself
-	_, err = s.remoteDB.Exec("DELETE FROM transaksi WHERE id = $1", remoteID)
+	// Find the conflicting rows: the same number or the same checkout
+	rows, err := tx.Query(
+		"SELECT id, COALESCE(idempotency_key, '') FROM transaksi WHERE nomor_transaksi = $1 OR idempotency_key = $2 FOR UPDATE",
+		nomorTransaksi, localKey,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to delete conflicting transaction: %w", err)
+		return fmt.Errorf("failed to find conflicting transaction: %w", err)
+	}
+	var remoteIDs []int64
+	for rows.Next() {
+		var remoteID int64
+		var remoteKey string
+		if err := rows.Scan(&remoteID, &remoteKey); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan conflicting transaction: %w", err)
+		}
+		if remoteKey != localKey {
+			rows.Close()
+			return fmt.Errorf("nomor transaksi %s sudah dipakai transaksi lain di server (periksa kode terminal)", nomorTransaksi)
+		}
+		remoteIDs = append(remoteIDs, remoteID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("failed to read conflicting transaction: %w", err)
+	}
+	rows.Close()
+
+	// Dependencies first (manual cascade), then the transaction itself
+	deletes := []string{
+		"DELETE FROM pembayaran WHERE transaksi_id = $1",
+		"DELETE FROM transaksi_item WHERE transaksi_id = $1",
+		"DELETE FROM transaksi_batch WHERE transaksi_id = $1",
+		"DELETE FROM transaksi_item_promo WHERE transaksi_id = $1",
+		"DELETE FROM returns WHERE transaksi_id = $1",
+		"DELETE FROM transaksi WHERE id = $1",
+	}
+	for _, remoteID := range remoteIDs {
+		log.Printf("[SYNC-FIX] Deleting stale remote copy ID=%d (%s) to allow sync...", remoteID, nomorTransaksi)
+		for _, query := range deletes {
+			if _, err := tx.Exec(query, remoteID); err != nil {
+				return fmt.Errorf("failed to delete conflicting transaction %d: %w", remoteID, err)
+			}
+		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conflict resolution: %w", err)
+	}
 	return nil
 }
 
@@ -783,7 +821,8 @@ func (s *SyncEngine) listLocalTables() ([]string, error) {
 			return nil, err
 		}
 		switch name {
-		case "migrations", "sync_queue", "sync_meta", "print_settings", "schema_migrations", "shift_settings", "shift_cashier":
+		case "migrations", "sync_queue", "sync_meta", "print_settings", "schema_migrations", "shift_settings", "shift_cashier", "shift_staff",
+			"penomoran_settings", "nomor_transaksi_counter", "idempotency_keys", "laci_kas_settings", "otorisasi":
 			continue
 		default:
 			tables = append(tables, name)